@@ -8,6 +8,7 @@ import (
 	"github.com/salsaflow/salsaflow/app/appflags"
 	"github.com/salsaflow/salsaflow/errs"
 	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/output"
 	"github.com/salsaflow/salsaflow/repo"
 )
 
@@ -27,6 +28,15 @@ func Init(force bool) error {
 func InitLogging() {
 	// Set up logging.
 	log.SetV(log.MustStringToLevel(appflags.FlagLog.Value()))
+
+	// Set up the output format.
+	output.Init()
+
+	// Set up the trace log, if enabled.
+	if err := initTrace(); err != nil {
+		errs.LogError("Enable the trace log", err)
+		log.Warn("Failed to enable the trace log, continuing anyway ...")
+	}
 }
 
 func InitOrDie() {
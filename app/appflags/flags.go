@@ -9,13 +9,25 @@ import (
 	"github.com/salsaflow/salsaflow/log"
 )
 
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+)
+
 var (
 	FlagConfig string
+	FlagTrace  bool
 	FlagLog    *flags.StringEnumFlag = flags.NewStringEnumFlag(
 		log.LevelStrings(), log.MustLevelToString(log.Info))
+	FlagOutput *flags.StringEnumFlag = flags.NewStringEnumFlag(
+		[]string{OutputText, OutputJSON}, OutputText)
+	FlagSet *flags.KeyValueFlag = flags.NewKeyValueFlag()
 )
 
 func RegisterGlobalFlags(flags *flag.FlagSet) {
 	flags.StringVar(&FlagConfig, "config", FlagConfig, "set custom global configuration file")
 	flags.Var(FlagLog, "log", "set logging verbosity; {trace|debug|verbose|info|off}")
+	flags.Var(FlagOutput, "output", "set output format; {text|json}")
+	flags.Var(FlagSet, "set", "override configuration value; KEY=VALUE, can be repeated")
+	flags.BoolVar(&FlagTrace, "trace", FlagTrace, "write a trace log into .git/salsaflow/logs")
 }
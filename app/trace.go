@@ -0,0 +1,39 @@
+package app
+
+import (
+	// Stdlib
+	"os"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/app/metadata"
+	"github.com/salsaflow/salsaflow/git"
+	"github.com/salsaflow/salsaflow/git/gitutil"
+	"github.com/salsaflow/salsaflow/trace"
+)
+
+// GitConfigKeyTrace can be set to true in git config
+// to enable the trace log for every command invocation.
+const GitConfigKeyTrace = "salsaflow.trace"
+
+func initTrace() error {
+	// The trace log is enabled either by -trace or in git config.
+	enabled := appflags.FlagTrace
+	if !enabled {
+		value, err := git.GetConfigString(GitConfigKeyTrace)
+		if err != nil {
+			return err
+		}
+		enabled = value == "true"
+	}
+	if !enabled {
+		return nil
+	}
+
+	// The trace log is written into the .git directory.
+	gitDir, err := gitutil.GitDirectoryAbsolutePath()
+	if err != nil {
+		return err
+	}
+	return trace.Enable(trace.LogDirectory(gitDir), os.Args[1:], metadata.Version)
+}
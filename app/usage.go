@@ -0,0 +1,21 @@
+package app
+
+import (
+	// Internal
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/output"
+
+	// Other
+	"gopkg.in/tchap/gocli.v2"
+)
+
+// ExitWithUsage prints the command usage into stderr and exits
+// with the usage exit code.
+//
+// The output is set up before the usage is printed so that in the JSON mode
+// the result written by errs.Terminate is the only thing printed to stdout.
+func ExitWithUsage(cmd *gocli.Command) {
+	output.Init()
+	cmd.Usage()
+	errs.Terminate(errs.ErrInvalidUsage)
+}
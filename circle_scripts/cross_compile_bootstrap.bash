@@ -18,7 +18,7 @@ fi
 
 #goVersion=$(go version | awk '{ print $3 }')
 #goVersion=${goVersion#go}
-goVersion='1.4.3'
+goVersion='1.8.7'
 
 echo "Go version: $goVersion"
 
@@ -32,16 +32,16 @@ gonativeExe="$gonativeSrc/gonative"
 
 #--- Download Go 1.4.3 to bootstrap the compiler for gonative
 
-#pkgUrl="https://storage.googleapis.com/golang/go1.4.3.${GOLANG_GOOS}-${GOLANG_GOARCH}.tar.gz"
-#pkgPath="$GARBAGE/go1.4.3.tag.gz"
-#pkgDst="$GARBAGE/go1.4.3"
-#
-#curl -o "$pkgPath" "$pkgUrl"
-#
-#[ ! -d "$pkgDst" ] && mkdir -p "$pkgDst"
-#tar -C "$pkgDst" -xzf "$pkgPath"
-#
-#export GOROOT_BOOTSTRAP="$pkgDst/go"
+pkgUrl="https://storage.googleapis.com/golang/go1.4.3.${GOLANG_GOOS}-${GOLANG_GOARCH}.tar.gz"
+pkgPath="$GARBAGE/go1.4.3.tag.gz"
+pkgDst="$GARBAGE/go1.4.3"
+
+curl -o "$pkgPath" "$pkgUrl"
+
+[ ! -d "$pkgDst" ] && mkdir -p "$pkgDst"
+tar -C "$pkgDst" -xzf "$pkgPath"
+
+export GOROOT_BOOTSTRAP="$pkgDst/go"
 
 #--- Build gonative
 
@@ -3,7 +3,6 @@ package cherrypickCmd
 import (
 	// Stdlib
 	"fmt"
-	"os"
 
 	// Internal
 	"github.com/salsaflow/salsaflow/app"
@@ -52,8 +51,7 @@ func init() {
 
 func run(cmd *gocli.Command, args []string) {
 	if len(args) == 0 {
-		cmd.Usage()
-		os.Exit(2)
+		app.ExitWithUsage(cmd)
 	}
 
 	app.InitOrDie()
@@ -0,0 +1,36 @@
+package configCmd
+
+import (
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/commands/config/edit"
+	"github.com/salsaflow/salsaflow/commands/config/get"
+	"github.com/salsaflow/salsaflow/commands/config/migrate"
+	"github.com/salsaflow/salsaflow/commands/config/secrets"
+	"github.com/salsaflow/salsaflow/commands/config/set"
+	"github.com/salsaflow/salsaflow/commands/config/show"
+	"github.com/salsaflow/salsaflow/commands/config/validate"
+
+	"gopkg.in/tchap/gocli.v2"
+)
+
+var Command = &gocli.Command{
+	UsageLine: "config",
+	Short:     "inspect and modify configuration",
+	Long: `
+  Inspect and modify SalsaFlow configuration. See the subcommands.
+	`,
+}
+
+func init() {
+	// Register global flags.
+	appflags.RegisterGlobalFlags(&Command.Flags)
+
+	// Register subcommands.
+	Command.MustRegisterSubcommand(editCmd.Command)
+	Command.MustRegisterSubcommand(getCmd.Command)
+	Command.MustRegisterSubcommand(migrateCmd.Command)
+	Command.MustRegisterSubcommand(secretsCmd.Command)
+	Command.MustRegisterSubcommand(setCmd.Command)
+	Command.MustRegisterSubcommand(showCmd.Command)
+	Command.MustRegisterSubcommand(validateCmd.Command)
+}
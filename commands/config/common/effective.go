@@ -0,0 +1,173 @@
+package common
+
+import (
+	// Stdlib
+	"encoding/json"
+	"sort"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/config"
+	"github.com/salsaflow/salsaflow/config/loader"
+)
+
+// Sources of configuration values as printed by Sources.
+const (
+	SourceGlobal = "global configuration file"
+	SourceLocal  = "local configuration file"
+)
+
+// Overrides returns the overrides applicable to the given configuration key,
+// i.e. the values set using environment variables or -set flag.
+func (files *Files) Overrides(configKey string) ([]*loader.Override, error) {
+	spec := files.Spec(configKey)
+	if spec == nil {
+		return nil, nil
+	}
+	return loader.Overrides(spec)
+}
+
+// EffectiveRecord returns the configuration record for the given configuration
+// key as it is seen by SalsaFlow, i.e. the global record merged with the local
+// record with the overrides applied on top. Nil is returned in case the record
+// is not present anywhere.
+func (files *Files) EffectiveRecord(configKey string) (interface{}, error) {
+	var merged interface{}
+	for _, section := range files.sections() {
+		record, err := section.RecordValue(configKey)
+		if err != nil {
+			if _, ok := err.(*config.ErrConfigRecordNotFound); ok {
+				continue
+			}
+			return nil, err
+		}
+		merged = mergeValues(merged, record)
+	}
+
+	overrides, err := files.Overrides(configKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(overrides) == 0 {
+		return merged, nil
+	}
+
+	if merged == nil {
+		merged = make(map[string]interface{})
+	}
+	raw, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	section := &config.ConfigurationsSection{
+		Records: make(map[string]*json.RawMessage, 1),
+	}
+	section.SetConfigRecord(configKey, raw)
+	for _, override := range overrides {
+		if err := section.SetValue(override.Key, override.Value); err != nil {
+			return nil, err
+		}
+	}
+	return section.RecordValue(configKey)
+}
+
+// EffectiveValue returns the value for the given key as it is seen by SalsaFlow.
+func (files *Files) EffectiveValue(key *config.Key) (interface{}, error) {
+	record, err := files.EffectiveRecord(key.ConfigKey)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, &config.ErrKeyNotSet{Key: key.ConfigKey}
+	}
+
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	section := &config.ConfigurationsSection{
+		Records: make(map[string]*json.RawMessage, 1),
+	}
+	section.SetConfigRecord(key.ConfigKey, raw)
+	return section.Value(key)
+}
+
+// Sources returns where the values for the given configuration key come from.
+// The keys of the map returned are the keys of the values stored,
+// the values are the sources, e.g. SourceLocal.
+func (files *Files) Sources(configKey string) (map[string]string, error) {
+	sources := make(map[string]string)
+	for i, section := range files.sections() {
+		record, err := section.RecordValue(configKey)
+		if err != nil {
+			if _, ok := err.(*config.ErrConfigRecordNotFound); ok {
+				continue
+			}
+			return nil, err
+		}
+		source := SourceGlobal
+		if i == 1 {
+			source = SourceLocal
+		}
+		for _, key := range leafKeys(&config.Key{ConfigKey: configKey}, record) {
+			sources[key] = source
+		}
+	}
+
+	overrides, err := files.Overrides(configKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, override := range overrides {
+		for _, key := range leafKeys(override.Key, override.Value) {
+			sources[key] = override.Source
+		}
+	}
+	return sources, nil
+}
+
+// sections returns the global and the local section, in this order.
+func (files *Files) sections() []*config.ConfigurationsSection {
+	return []*config.ConfigurationsSection{
+		files.Global.ConfigurationsSection,
+		files.Local.ConfigurationsSection,
+	}
+}
+
+// leafKeys returns the keys of all non-object values stored in the given value,
+// which is expected to be located at the given key.
+func leafKeys(key *config.Key, value interface{}) []string {
+	object, ok := value.(map[string]interface{})
+	if !ok || len(object) == 0 {
+		return []string{key.String()}
+	}
+
+	var keys []string
+	for field, v := range object {
+		path := make([]string, len(key.Path), len(key.Path)+1)
+		copy(path, key.Path)
+		fieldKey := &config.Key{ConfigKey: key.ConfigKey, Path: append(path, field)}
+		keys = append(keys, leafKeys(fieldKey, v)...)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func mergeValues(base, override interface{}) interface{} {
+	baseObject, ok := base.(map[string]interface{})
+	if !ok {
+		return override
+	}
+	overrideObject, ok := override.(map[string]interface{})
+	if !ok {
+		return override
+	}
+
+	merged := make(map[string]interface{}, len(baseObject)+len(overrideObject))
+	for k, v := range baseObject {
+		merged[k] = v
+	}
+	for k, v := range overrideObject {
+		merged[k] = mergeValues(merged[k], v)
+	}
+	return merged
+}
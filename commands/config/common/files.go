@@ -0,0 +1,121 @@
+package common
+
+import (
+	// Stdlib
+	"fmt"
+	"os"
+	"sort"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/config"
+	"github.com/salsaflow/salsaflow/config/loader"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/modules"
+)
+
+// Files represents both the local and the global configuration file
+// together with the config specs relevant for the current repository.
+type Files struct {
+	Local  *config.LocalConfig
+	Global *config.GlobalConfig
+	Specs  []loader.ConfigSpec
+}
+
+// ReadFiles reads both configuration files.
+// A missing configuration file is treated as an empty one.
+func ReadFiles() (*Files, error) {
+	local, err := config.ReadLocalConfig()
+	if err != nil {
+		if !os.IsNotExist(errs.RootCause(err)) {
+			return nil, err
+		}
+		local = config.NewEmptyLocalConfig()
+		local.EnabledTimestamp = nil
+	}
+
+	global, err := config.ReadGlobalConfig()
+	if err != nil {
+		if !os.IsNotExist(errs.RootCause(err)) {
+			return nil, err
+		}
+		global = config.NewEmptyGlobalConfig()
+	}
+
+	// The active modules are only known when the local config file exists.
+	specs, err := modules.ConfigSpecs()
+	if err != nil {
+		specs = loader.BootstrapConfigSpecs()
+	}
+
+	return &Files{local, global, specs}, nil
+}
+
+// ConfigKeys returns all configuration keys known,
+// i.e. the keys used in the files and the keys of the config specs.
+func (files *Files) ConfigKeys() []string {
+	set := make(map[string]struct{})
+	for _, key := range files.Local.ConfigKeys() {
+		set[key] = struct{}{}
+	}
+	for _, key := range files.Global.ConfigKeys() {
+		set[key] = struct{}{}
+	}
+	for _, spec := range files.Specs {
+		set[spec.ConfigKey()] = struct{}{}
+	}
+
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ParseKey parses the given key using the configuration keys known.
+func (files *Files) ParseKey(key string) (*config.Key, error) {
+	task := fmt.Sprintf("Parse configuration key '%v'", key)
+	k, err := config.ParseKey(key, files.ConfigKeys())
+	if err != nil {
+		hint := `
+The key must start with a configuration key, e.g. salsaflow.core.git,
+followed by the path of the JSON fields as stored in the configuration file.
+Use 'config show' to list the keys available.
+
+`
+		return nil, errs.NewErrorWithHint(task, err, hint)
+	}
+	return k, nil
+}
+
+// Spec returns the config spec for the given configuration key, if available.
+func (files *Files) Spec(configKey string) loader.ConfigSpec {
+	for _, spec := range files.Specs {
+		if spec.ConfigKey() == configKey {
+			return spec
+		}
+	}
+	return nil
+}
+
+// Value returns the value for the given key, looking into the local
+// configuration file first, then into the global configuration file.
+// The configuration files that are not to be searched can be skipped.
+func (files *Files) Value(key *config.Key, skipLocal, skipGlobal bool) (interface{}, error) {
+	var err error
+	if !skipLocal {
+		var v interface{}
+		v, err = files.Local.Value(key)
+		if err == nil {
+			return v, nil
+		}
+	}
+	if !skipGlobal {
+		var v interface{}
+		v, err = files.Global.Value(key)
+		if err == nil {
+			return v, nil
+		}
+	}
+	return nil, err
+}
@@ -0,0 +1,29 @@
+package common
+
+import (
+	// Stdlib
+	"fmt"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/config/loader"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/output"
+)
+
+// Validate checks the configuration for all the config specs available.
+// The errors are logged, the configuration keys that failed are returned.
+func Validate(files *Files) (invalid []string) {
+	for _, spec := range files.Specs {
+		configKey := spec.ConfigKey()
+		log.Run(fmt.Sprintf("Validate configuration for '%v'", configKey))
+		if err := loader.CheckConfig(spec); err != nil {
+			errs.Log(err)
+			output.SetValue(configKey, "invalid")
+			invalid = append(invalid, configKey)
+			continue
+		}
+		output.SetValue(configKey, "valid")
+	}
+	return invalid
+}
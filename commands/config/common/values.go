@@ -0,0 +1,82 @@
+package common
+
+import (
+	// Stdlib
+	"bytes"
+	"encoding/json"
+	"regexp"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/config"
+)
+
+// FormatValue returns the string representation of the given value.
+// Strings are returned as they are, other values are formatted as JSON.
+func FormatValue(value interface{}) (string, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	raw, err := config.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+// ParseValue parses the given string as JSON. Numbers are kept as json.Number.
+func ParseValue(value string) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(value)))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// MaskedValue is used to replace secret values.
+const MaskedValue = "********"
+
+var secretFieldPattern = regexp.MustCompile("(?i)token|secret|password")
+
+// LooksLikeSecret returns true when the given field name suggests
+// that the field contains a secret, e.g. an access token.
+func LooksLikeSecret(field string) bool {
+	return secretFieldPattern.MatchString(field)
+}
+
+// MaskSecrets replaces the values of the given secret keys in the given
+// configuration record value. The values of the fields that look like secrets
+// are replaced as well, just in case the config spec is not available.
+func MaskSecrets(configKey string, value interface{}, secretKeys []*config.Key) interface{} {
+	secrets := make(map[string]struct{}, len(secretKeys))
+	for _, key := range secretKeys {
+		secrets[key.String()] = struct{}{}
+	}
+	return maskSecrets(&config.Key{ConfigKey: configKey}, value, secrets)
+}
+
+func maskSecrets(key *config.Key, value interface{}, secrets map[string]struct{}) interface{} {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+
+	masked := make(map[string]interface{}, len(object))
+	for field, v := range object {
+		path := make([]string, len(key.Path), len(key.Path)+1)
+		copy(path, key.Path)
+		fieldKey := &config.Key{ConfigKey: key.ConfigKey, Path: append(path, field)}
+
+		_, isSecret := secrets[fieldKey.String()]
+		if _, isString := v.(string); isString && LooksLikeSecret(field) {
+			isSecret = true
+		}
+		if isSecret {
+			masked[field] = MaskedValue
+		} else {
+			masked[field] = maskSecrets(fieldKey, v, secrets)
+		}
+	}
+	return masked
+}
@@ -0,0 +1,115 @@
+package editCmd
+
+import (
+	// Stdlib
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app"
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/commands/config/common"
+	"github.com/salsaflow/salsaflow/config"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/git"
+	"github.com/salsaflow/salsaflow/log"
+
+	// Other
+	"gopkg.in/tchap/gocli.v2"
+)
+
+var Command = &gocli.Command{
+	UsageLine: "edit [-global]",
+	Short:     "edit a configuration file",
+	Long: `
+  Open the local configuration file in the editor.
+  Use -global to edit the global configuration file instead.
+
+  The editor is chosen the same way git chooses it,
+  i.e. core.editor, GIT_EDITOR, VISUAL and EDITOR are checked.
+
+  The configuration is validated once the editor exits.
+	`,
+	Action: run,
+}
+
+var flagGlobal bool
+
+func init() {
+	// Register flags.
+	Command.Flags.BoolVar(&flagGlobal, "global", flagGlobal,
+		"edit the global configuration file")
+
+	// Register global flags.
+	appflags.RegisterGlobalFlags(&Command.Flags)
+}
+
+func run(cmd *gocli.Command, args []string) {
+	if len(args) != 0 {
+		app.ExitWithUsage(cmd)
+	}
+
+	app.InitLogging()
+
+	if err := runMain(); err != nil {
+		errs.Fatal(err)
+	}
+}
+
+func runMain() error {
+	// Get the configuration file path.
+	var (
+		path string
+		err  error
+	)
+	if flagGlobal {
+		path, err = config.GlobalConfigFileAbsolutePath()
+	} else {
+		path, err = config.LocalConfigFileAbsolutePath()
+	}
+	if err != nil {
+		return err
+	}
+
+	// Get the editor.
+	task := "Get the editor to use"
+	stdout, err := git.Run("var", "GIT_EDITOR")
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+	editor := strings.TrimSpace(stdout.String())
+
+	// Run the editor. The editor string can contain arguments,
+	// so it is passed to the shell the same way git does it.
+	task = fmt.Sprintf("Edit file '%v'", path)
+	log.Run(task)
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", editor+` "`+path+`"`)
+	} else {
+		cmd = exec.Command("sh", "-c", editor+` "$@"`, editor, path)
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return errs.NewError(task, err)
+	}
+
+	// Validate the configuration.
+	files, err := common.ReadFiles()
+	if err != nil {
+		return err
+	}
+	if invalid := common.Validate(files); len(invalid) != 0 {
+		return errs.NewError("Validate the configuration", fmt.Errorf(
+			"invalid configuration: %v", invalid))
+	}
+	if !flagGlobal {
+		log.Warn("Local configuration file modified, please commit it.")
+	}
+	return nil
+}
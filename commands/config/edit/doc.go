@@ -0,0 +1,17 @@
+/*
+Open a configuration file in the editor.
+
+  salsaflow config edit [-global]
+
+Description
+
+Open the local configuration file in the editor.
+Use -global to edit the global configuration file instead.
+
+The editor is chosen the same way git chooses it,
+i.e. core.editor, GIT_EDITOR, VISUAL and EDITOR are checked.
+
+The configuration is validated once the editor exits,
+the same way the validate subcommand does it.
+*/
+package editCmd
@@ -0,0 +1,99 @@
+package getCmd
+
+import (
+	// Stdlib
+	"fmt"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app"
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/commands/config/common"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/output"
+
+	// Other
+	"gopkg.in/tchap/gocli.v2"
+)
+
+var Command = &gocli.Command{
+	UsageLine: "get [-local|-global] KEY",
+	Short:     "print a configuration value",
+	Long: `
+  Print the value stored in the configuration for the given key.
+
+  KEY consists of the configuration key, e.g. salsaflow.core.git,
+  followed by the path of the JSON fields leading to the value,
+  all joined by dots, e.g. salsaflow.core.git.trunk_branch.
+
+  The value printed is the effective value, i.e. the value overridden using
+  an environment variable or -set flag, otherwise the value stored in the local
+  configuration file or in the global configuration file, in this order.
+  Use -local or -global to only search the given configuration file.
+
+  String values are printed as they are, other values are printed as JSON.
+	`,
+	Action: run,
+}
+
+var (
+	flagLocal  bool
+	flagGlobal bool
+)
+
+func init() {
+	// Register flags.
+	Command.Flags.BoolVar(&flagLocal, "local", flagLocal,
+		"only search the local configuration file")
+	Command.Flags.BoolVar(&flagGlobal, "global", flagGlobal,
+		"only search the global configuration file")
+
+	// Register global flags.
+	appflags.RegisterGlobalFlags(&Command.Flags)
+}
+
+func run(cmd *gocli.Command, args []string) {
+	if len(args) != 1 || (flagLocal && flagGlobal) {
+		app.ExitWithUsage(cmd)
+	}
+
+	app.InitLogging()
+
+	if err := runMain(args[0]); err != nil {
+		errs.Fatal(err)
+	}
+}
+
+func runMain(keyString string) error {
+	// Read the configuration files.
+	files, err := common.ReadFiles()
+	if err != nil {
+		return err
+	}
+
+	// Parse the key.
+	key, err := files.ParseKey(keyString)
+	if err != nil {
+		return err
+	}
+
+	// Get the value.
+	task := fmt.Sprintf("Get the value for key '%v'", key)
+	var value interface{}
+	if flagLocal || flagGlobal {
+		value, err = files.Value(key, flagGlobal, flagLocal)
+	} else {
+		value, err = files.EffectiveValue(key)
+	}
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+
+	// Print the value.
+	formatted, err := common.FormatValue(value)
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+	fmt.Println(formatted)
+	output.SetValue(key.String(), formatted)
+	return nil
+}
@@ -0,0 +1,21 @@
+/*
+Print a configuration value.
+
+  salsaflow config get [-local|-global] KEY
+
+Description
+
+Print the value stored in the configuration for the given key.
+
+KEY consists of the configuration key, e.g. salsaflow.core.git,
+followed by the path of the JSON fields leading to the value,
+all joined by dots, e.g. salsaflow.core.git.trunk_branch.
+
+The value printed is the effective value, i.e. the value overridden using
+an environment variable or -set flag, otherwise the value stored in the local
+configuration file or in the global configuration file, in this order.
+Use -local or -global to only search the given configuration file.
+
+String values are printed as they are, other values are printed as JSON.
+*/
+package getCmd
@@ -0,0 +1,193 @@
+package migrateCmd
+
+import (
+	// Stdlib
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app"
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/commands/config/common"
+	"github.com/salsaflow/salsaflow/config"
+	"github.com/salsaflow/salsaflow/config/loader"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/shell"
+
+	// Other
+	"gopkg.in/tchap/gocli.v2"
+)
+
+var Command = &gocli.Command{
+	UsageLine: "migrate [-dry_run]",
+	Short:     "upgrade configuration to the current schema",
+	Long: `
+  Upgrade the configuration records stored in the configuration files
+  to the current schema version as understood by this SalsaFlow binary.
+
+  The changes being made are printed as a diff for every configuration file
+  that is modified. Use -dry_run to only print the changes without writing
+  the configuration files.
+	`,
+	Action: run,
+}
+
+var flagDryRun bool
+
+func init() {
+	// Register flags.
+	Command.Flags.BoolVar(&flagDryRun, "dry_run", flagDryRun,
+		"print the changes, do not write the files")
+
+	// Register global flags.
+	appflags.RegisterGlobalFlags(&Command.Flags)
+}
+
+func run(cmd *gocli.Command, args []string) {
+	if len(args) != 0 {
+		app.ExitWithUsage(cmd)
+	}
+
+	app.InitLogging()
+
+	if err := runMain(); err != nil {
+		errs.Fatal(err)
+	}
+}
+
+type configFile interface {
+	SaveChanges() error
+}
+
+func runMain() error {
+	// Read the configuration files.
+	files, err := common.ReadFiles()
+	if err != nil {
+		return err
+	}
+
+	// Migrate the global configuration file.
+	globalModified, err := migrateFile(
+		files.Global, files.Global.ConfigurationsSection, loader.ConfigKindGlobal)
+	if err != nil {
+		return err
+	}
+
+	// Migrate the local configuration file.
+	localModified, err := migrateFile(
+		files.Local, files.Local.ConfigurationsSection, loader.ConfigKindLocal)
+	if err != nil {
+		return err
+	}
+
+	if !globalModified && !localModified {
+		log.Log("Configuration is up to date, nothing to migrate")
+		return nil
+	}
+	if localModified && !flagDryRun {
+		log.Warn("Local configuration file modified, please commit it.")
+	}
+	return nil
+}
+
+func migrateFile(
+	file configFile,
+	section *config.ConfigurationsSection,
+	kind loader.ConfigKind,
+) (modified bool, err error) {
+
+	task := fmt.Sprintf("Migrate the %v configuration file", kind)
+
+	before, err := config.Marshal(file)
+	if err != nil {
+		return false, errs.NewError(task, err)
+	}
+
+	// Migrate all records stored in the file.
+	for _, configKey := range section.ConfigKeys() {
+		from := section.SchemaVersion(configKey)
+		applied, err := loader.MigrateRecord(section, configKey, kind)
+		if err != nil {
+			return false, errs.NewError(task, err)
+		}
+		if len(applied) == 0 {
+			continue
+		}
+
+		log.Run(fmt.Sprintf("Migrate %v configuration for '%v' from version %v to version %v",
+			kind, configKey, from, section.SchemaVersion(configKey)))
+		for _, description := range applied {
+			log.NewLine("- " + description)
+		}
+		modified = true
+	}
+	if !modified {
+		return false, nil
+	}
+
+	// Print the diff.
+	after, err := config.Marshal(file)
+	if err != nil {
+		return false, errs.NewError(task, err)
+	}
+	var path string
+	if kind == loader.ConfigKindGlobal {
+		path, err = config.GlobalConfigFileAbsolutePath()
+	} else {
+		path, err = config.LocalConfigFileAbsolutePath()
+	}
+	if err != nil {
+		return false, errs.NewError(task, err)
+	}
+	if err := printDiff(path, before, after); err != nil {
+		return false, errs.NewError(task, err)
+	}
+
+	// Write the file.
+	if flagDryRun {
+		return true, nil
+	}
+	if err := file.SaveChanges(); err != nil {
+		return false, errs.NewError(task, err)
+	}
+	return true, nil
+}
+
+// printDiff prints the unified diff between before and after using git.
+func printDiff(path string, before, after []byte) error {
+	dir, err := ioutil.TempDir("", "salsaflow-config-migrate-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	var (
+		beforePath = filepath.Join(dir, "before")
+		afterPath  = filepath.Join(dir, "after")
+	)
+	if err := ioutil.WriteFile(beforePath, before, 0600); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(afterPath, after, 0600); err != nil {
+		return err
+	}
+
+	// git diff --no-index exits with 1 when there are differences.
+	stdout, stderr, err := shell.Run(
+		"git", "diff", "--no-index", "--no-prefix", "--", beforePath, afterPath)
+	if err != nil && stdout.Len() == 0 {
+		return errs.NewErrorWithHint("Run 'git diff'", err, stderr.String())
+	}
+
+	diff := stdout.String()
+	diff = strings.Replace(diff, strings.TrimPrefix(beforePath, "/"), path, -1)
+	diff = strings.Replace(diff, strings.TrimPrefix(afterPath, "/"), path, -1)
+	fmt.Println()
+	fmt.Print(diff)
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,24 @@
+/*
+Upgrade configuration to the current schema.
+
+  salsaflow config migrate [-dry_run]
+
+Description
+
+Upgrade the configuration records stored in the configuration files
+to the current schema version as understood by this SalsaFlow binary.
+
+Every configuration record has a schema version, which is stored in
+schema_versions in the configuration file. When the layout of a record
+changes, the associated module registers a migration that upgrades the
+record to the new layout. Outdated records are upgraded in memory
+automatically when the configuration is being loaded, but a warning
+is printed until the configuration files are upgraded using this command.
+SalsaFlow refuses to load a record that uses a newer schema version
+than the binary understands.
+
+The changes being made are printed as a diff for every configuration file
+that is modified. Use -dry_run to only print the changes without writing
+the configuration files.
+*/
+package migrateCmd
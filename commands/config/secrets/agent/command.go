@@ -0,0 +1,48 @@
+package agentCmd
+
+import (
+	// Stdlib
+	"os"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app"
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/secrets"
+
+	// Other
+	"gopkg.in/tchap/gocli.v2"
+)
+
+var Command = &gocli.Command{
+	UsageLine: "agent [-timeout=DURATION]",
+	Short:     "run the secrets agent",
+	Long: `
+  Run the agent caching the key used to decrypt the secrets file.
+
+  This command is started automatically in the background when the passphrase
+  is entered, it is not supposed to be run manually. The key is read from stdin.
+	`,
+	Action: run,
+}
+
+var flagTimeout = secrets.DefaultAgentTimeout
+
+func init() {
+	// Register flags.
+	Command.Flags.DurationVar(&flagTimeout, "timeout", flagTimeout,
+		"exit after the given duration")
+
+	// Register global flags.
+	appflags.RegisterGlobalFlags(&Command.Flags)
+}
+
+func run(cmd *gocli.Command, args []string) {
+	if len(args) != 0 {
+		app.ExitWithUsage(cmd)
+	}
+
+	if err := secrets.RunAgent(os.Stdin, flagTimeout); err != nil {
+		errs.Fatal(errs.NewError("Run the secrets agent", err))
+	}
+}
@@ -0,0 +1,15 @@
+/*
+Run the secrets agent.
+
+  salsaflow config secrets agent [-timeout=DURATION]
+
+Description
+
+Run the agent caching the key used to decrypt the secrets file.
+
+This command is started automatically in the background when the passphrase
+is entered, it is not supposed to be run manually. The key is read from stdin.
+The agent listens on a unix socket accessible only by the current user
+and it exits once the timeout elapses, forgetting the key.
+*/
+package agentCmd
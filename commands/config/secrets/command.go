@@ -0,0 +1,29 @@
+package secretsCmd
+
+import (
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/commands/config/secrets/agent"
+	"github.com/salsaflow/salsaflow/commands/config/secrets/import"
+	"github.com/salsaflow/salsaflow/commands/config/secrets/lock"
+
+	"gopkg.in/tchap/gocli.v2"
+)
+
+var Command = &gocli.Command{
+	UsageLine: "secrets",
+	Short:     "manage the secret store",
+	Long: `
+  Manage the secret store, which is used to keep the secrets such as access
+  tokens outside of the global configuration file. See the subcommands.
+	`,
+}
+
+func init() {
+	// Register global flags.
+	appflags.RegisterGlobalFlags(&Command.Flags)
+
+	// Register subcommands.
+	Command.MustRegisterSubcommand(agentCmd.Command)
+	Command.MustRegisterSubcommand(importCmd.Command)
+	Command.MustRegisterSubcommand(lockCmd.Command)
+}
@@ -0,0 +1,158 @@
+package importCmd
+
+import (
+	// Stdlib
+	"fmt"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app"
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/commands/config/common"
+	"github.com/salsaflow/salsaflow/config"
+	"github.com/salsaflow/salsaflow/config/loader"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/modules"
+	"github.com/salsaflow/salsaflow/secrets"
+
+	// Other
+	"gopkg.in/tchap/gocli.v2"
+)
+
+var Command = &gocli.Command{
+	UsageLine: "import",
+	Short:     "move secrets from the global configuration file into the secret store",
+	Long: `
+  Move the secrets stored in the global configuration file as plain text
+  into the secret store. The secrets are replaced by references.
+
+  The fields tagged as secret by SalsaFlow modules are moved,
+  as well as the fields that look like secrets, e.g. fields named token.
+
+  The secret store must be configured first, e.g. by running
+
+    $ salsaflow config set -global salsaflow.core.secrets.backend encrypted
+	`,
+	Action: run,
+}
+
+func init() {
+	// Register global flags.
+	appflags.RegisterGlobalFlags(&Command.Flags)
+}
+
+func run(cmd *gocli.Command, args []string) {
+	if len(args) != 0 {
+		app.ExitWithUsage(cmd)
+	}
+
+	app.InitLogging()
+
+	if err := runMain(); err != nil {
+		errs.Fatal(err)
+	}
+}
+
+func runMain() error {
+	// Get the secret store backend.
+	task := "Get the secret store"
+	cfg, err := secrets.LoadConfig()
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+	backend, err := secrets.NewBackend(cfg)
+	if err != nil {
+		if err == secrets.ErrDisabled {
+			hint := `
+Configure the secret store first, e.g. by running
+
+  $ salsaflow config set -global salsaflow.core.secrets.backend encrypted
+
+`
+			return errs.NewErrorWithHint(task, err, hint)
+		}
+		return errs.NewError(task, err)
+	}
+
+	// Read the global configuration file.
+	files, err := common.ReadFiles()
+	if err != nil {
+		return err
+	}
+	global := files.Global
+
+	// Collect the keys tagged as secret by all the available config specs.
+	specs := loader.BootstrapConfigSpecs()
+	for _, module := range modules.AvailableModules() {
+		specs = append(specs, module.ConfigSpec())
+	}
+	secretKeys := make(map[string]struct{})
+	for _, spec := range specs {
+		for _, key := range loader.SecretKeys(spec) {
+			secretKeys[key.String()] = struct{}{}
+		}
+	}
+
+	// Move the secrets.
+	var imported int
+	for _, configKey := range global.ConfigKeys() {
+		record, err := global.RecordValue(configKey)
+		if err != nil {
+			return errs.NewError("Read the global configuration file", err)
+		}
+
+		for _, key := range plainSecrets(&config.Key{ConfigKey: configKey}, record, secretKeys) {
+			task := fmt.Sprintf("Move secret '%v' into the secret store", key)
+			log.Run(task)
+
+			value, err := global.Value(key)
+			if err != nil {
+				return errs.NewError(task, err)
+			}
+			if err := backend.Set(key.String(), value.(string)); err != nil {
+				return errs.NewError(task, err)
+			}
+			if err := global.SetValue(key, loader.SecretRefPrefix+key.String()); err != nil {
+				return errs.NewError(task, err)
+			}
+			imported++
+		}
+	}
+
+	if imported == 0 {
+		log.Log("No secrets found in the global configuration file")
+		return nil
+	}
+
+	// Write the global configuration file.
+	if err := global.SaveChanges(); err != nil {
+		return errs.NewError("Write the global configuration file", err)
+	}
+	return nil
+}
+
+// plainSecrets returns the keys of the secrets stored as plain text
+// in the given value, which is expected to be located at the given key.
+func plainSecrets(key *config.Key, value interface{}, secretKeys map[string]struct{}) []*config.Key {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		var keys []*config.Key
+		for field, v := range value {
+			path := make([]string, len(key.Path), len(key.Path)+1)
+			copy(path, key.Path)
+			fieldKey := &config.Key{ConfigKey: key.ConfigKey, Path: append(path, field)}
+			keys = append(keys, plainSecrets(fieldKey, v, secretKeys)...)
+		}
+		return keys
+
+	case string:
+		if value == "" || loader.IsSecretRef(value) || len(key.Path) == 0 {
+			return nil
+		}
+		_, isSecret := secretKeys[key.String()]
+		if isSecret || common.LooksLikeSecret(key.Path[len(key.Path)-1]) {
+			return []*config.Key{key}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+/*
+Move secrets from the global configuration file into the secret store.
+
+  salsaflow config secrets import
+
+Description
+
+Move the secrets stored in the global configuration file as plain text
+into the secret store. The secrets are replaced by references,
+i.e. by strings of the form secret:KEY.
+
+The fields tagged as secret by SalsaFlow modules are moved,
+as well as the fields that look like secrets, e.g. fields named token.
+
+The secret store must be configured first, see the Secret Store section
+in the main README file.
+*/
+package importCmd
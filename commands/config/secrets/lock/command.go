@@ -0,0 +1,42 @@
+package lockCmd
+
+import (
+	// Internal
+	"github.com/salsaflow/salsaflow/app"
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/secrets"
+
+	// Other
+	"gopkg.in/tchap/gocli.v2"
+)
+
+var Command = &gocli.Command{
+	UsageLine: "lock",
+	Short:     "forget the cached secrets passphrase",
+	Long: `
+  Stop the secrets agent so that the passphrase is asked for again
+  next time the encrypted secrets file is accessed.
+	`,
+	Action: run,
+}
+
+func init() {
+	// Register global flags.
+	appflags.RegisterGlobalFlags(&Command.Flags)
+}
+
+func run(cmd *gocli.Command, args []string) {
+	if len(args) != 0 {
+		app.ExitWithUsage(cmd)
+	}
+
+	app.InitLogging()
+
+	task := "Stop the secrets agent"
+	log.Run(task)
+	if err := secrets.StopAgent(); err != nil {
+		errs.Fatal(errs.NewError(task, err))
+	}
+}
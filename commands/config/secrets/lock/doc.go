@@ -0,0 +1,11 @@
+/*
+Forget the cached secrets passphrase.
+
+  salsaflow config secrets lock
+
+Description
+
+Stop the secrets agent so that the passphrase is asked for again
+next time the encrypted secrets file is accessed.
+*/
+package lockCmd
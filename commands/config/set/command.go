@@ -0,0 +1,165 @@
+package setCmd
+
+import (
+	// Stdlib
+	"errors"
+	"fmt"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app"
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/commands/config/common"
+	"github.com/salsaflow/salsaflow/config"
+	"github.com/salsaflow/salsaflow/config/loader"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/log"
+
+	// Other
+	"gopkg.in/tchap/gocli.v2"
+)
+
+var Command = &gocli.Command{
+	UsageLine: "set [-local|-global] [-json] KEY VALUE",
+	Short:     "set a configuration value",
+	Long: `
+  Set the configuration value for the given key.
+
+  KEY consists of the configuration key, e.g. salsaflow.core.git,
+  followed by the path of the JSON fields leading to the value,
+  all joined by dots, e.g. salsaflow.core.git.trunk_branch.
+
+  The value is written into the configuration file where the key is already
+  set, the local configuration file being preferred. In case the key is not set
+  yet, the file containing the configuration record for the configuration key
+  is used. Use -local or -global to choose the configuration file explicitly.
+
+  VALUE is treated as a string unless the current value is not a string
+  or -json is set, in which case VALUE is parsed as JSON.
+
+  The configuration is checked after the value is written
+  and a warning is printed in case it is not valid.
+	`,
+	Action: run,
+}
+
+var (
+	flagLocal  bool
+	flagGlobal bool
+	flagJSON   bool
+)
+
+func init() {
+	// Register flags.
+	Command.Flags.BoolVar(&flagLocal, "local", flagLocal,
+		"write into the local configuration file")
+	Command.Flags.BoolVar(&flagGlobal, "global", flagGlobal,
+		"write into the global configuration file")
+	Command.Flags.BoolVar(&flagJSON, "json", flagJSON,
+		"parse VALUE as JSON")
+
+	// Register global flags.
+	appflags.RegisterGlobalFlags(&Command.Flags)
+}
+
+func run(cmd *gocli.Command, args []string) {
+	if len(args) != 2 || (flagLocal && flagGlobal) {
+		app.ExitWithUsage(cmd)
+	}
+
+	app.InitLogging()
+
+	if err := runMain(args[0], args[1]); err != nil {
+		errs.Fatal(err)
+	}
+}
+
+type configFile interface {
+	Value(key *config.Key) (interface{}, error)
+	SetValue(key *config.Key, value interface{}) error
+	SaveChanges() error
+}
+
+func runMain(keyString, valueString string) error {
+	// Read the configuration files.
+	files, err := common.ReadFiles()
+	if err != nil {
+		return err
+	}
+
+	// Parse the key.
+	key, err := files.ParseKey(keyString)
+	if err != nil {
+		return err
+	}
+	if len(key.Path) == 0 {
+		return errs.NewError(
+			fmt.Sprintf("Set the value for key '%v'", key),
+			errors.New("replacing the whole configuration record is not supported"))
+	}
+
+	// Choose the file to be modified.
+	task := fmt.Sprintf("Choose the configuration file to write key '%v' into", key)
+	var (
+		isLocal bool
+		current interface{}
+	)
+	switch {
+	case flagLocal:
+		isLocal = true
+	case flagGlobal:
+		isLocal = false
+	default:
+		if v, err := files.Local.Value(key); err == nil {
+			isLocal, current = true, v
+		} else if v, err := files.Global.Value(key); err == nil {
+			isLocal, current = false, v
+		} else if _, ok := files.Local.Records[key.ConfigKey]; ok {
+			isLocal = true
+		} else if _, ok := files.Global.Records[key.ConfigKey]; ok {
+			isLocal = false
+		} else {
+			hint := "\nUse -local or -global to choose the configuration file.\n\n"
+			return errs.NewErrorWithHint(task, errors.New("configuration record not found"), hint)
+		}
+	}
+
+	var file configFile = files.Global
+	if isLocal {
+		file = files.Local
+	}
+	if flagLocal || flagGlobal {
+		current, _ = file.Value(key)
+	}
+
+	// Parse the value.
+	task = fmt.Sprintf("Parse the value for key '%v'", key)
+	var value interface{} = valueString
+	if _, isString := current.(string); flagJSON || (current != nil && !isString) {
+		value, err = common.ParseValue(valueString)
+		if err != nil {
+			return errs.NewError(task, err)
+		}
+	}
+
+	// Write the value.
+	task = fmt.Sprintf("Set the value for key '%v'", key)
+	if err := file.SetValue(key, value); err != nil {
+		return errs.NewError(task, err)
+	}
+	if err := file.SaveChanges(); err != nil {
+		return errs.NewError(task, err)
+	}
+	if isLocal {
+		log.Warn("Local configuration file modified, please commit it.")
+	}
+
+	// Check the configuration, but only warn in case it is not valid.
+	// The configuration can be fixed by running more 'config set' commands.
+	if spec := files.Spec(key.ConfigKey); spec != nil {
+		if err := loader.CheckConfig(spec); err != nil {
+			errs.Log(err)
+			log.Warn(fmt.Sprintf("Configuration for '%v' is not valid yet.", key.ConfigKey))
+		}
+	}
+	return nil
+}
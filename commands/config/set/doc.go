@@ -0,0 +1,25 @@
+/*
+Set a configuration value.
+
+  salsaflow config set [-local|-global] [-json] KEY VALUE
+
+Description
+
+Set the configuration value for the given key.
+
+KEY consists of the configuration key, e.g. salsaflow.core.git,
+followed by the path of the JSON fields leading to the value,
+all joined by dots, e.g. salsaflow.core.git.trunk_branch.
+
+The value is written into the configuration file where the key is already
+set, the local configuration file being preferred. In case the key is not set
+yet, the file containing the configuration record for the configuration key
+is used. Use -local or -global to choose the configuration file explicitly.
+
+VALUE is treated as a string unless the current value is not a string
+or -json is set, in which case VALUE is parsed as JSON.
+
+The configuration is checked after the value is written
+and a warning is printed in case it is not valid.
+*/
+package setCmd
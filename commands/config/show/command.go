@@ -0,0 +1,194 @@
+package showCmd
+
+import (
+	// Stdlib
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app"
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/commands/config/common"
+	"github.com/salsaflow/salsaflow/config"
+	"github.com/salsaflow/salsaflow/config/loader"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/output"
+
+	// Other
+	"gopkg.in/tchap/gocli.v2"
+)
+
+var Command = &gocli.Command{
+	UsageLine: "show [-sources] [CONFIG_KEY]",
+	Short:     "print the effective configuration",
+	Long: `
+  Print the effective configuration, i.e. the global configuration
+  merged with the local configuration, the local values taking precedence.
+  The values overridden using environment variables or -set flag
+  are applied on top.
+
+  When CONFIG_KEY is specified, only the configuration record
+  for the given configuration key is printed.
+
+  When -sources is set, every value is printed on a separate line
+  together with the source the value comes from, i.e. the configuration
+  file, the environment variable or -set flag.
+
+  The secret values such as access tokens are masked.
+	`,
+	Action: run,
+}
+
+var flagSources bool
+
+func init() {
+	// Register flags.
+	Command.Flags.BoolVar(&flagSources, "sources", flagSources,
+		"print where the values come from")
+
+	// Register global flags.
+	appflags.RegisterGlobalFlags(&Command.Flags)
+}
+
+func run(cmd *gocli.Command, args []string) {
+	if len(args) > 1 {
+		app.ExitWithUsage(cmd)
+	}
+
+	app.InitLogging()
+
+	var configKey string
+	if len(args) == 1 {
+		configKey = args[0]
+	}
+
+	if err := runMain(configKey); err != nil {
+		errs.Fatal(err)
+	}
+}
+
+func runMain(configKey string) error {
+	// Read the configuration files.
+	files, err := common.ReadFiles()
+	if err != nil {
+		return err
+	}
+
+	configKeys := files.ConfigKeys()
+	if configKey != "" {
+		key, err := files.ParseKey(configKey)
+		if err != nil {
+			return err
+		}
+		configKeys = []string{key.ConfigKey}
+	}
+
+	// Get the effective configuration records.
+	task := "Get the effective configuration"
+	records := make(map[string]interface{}, len(configKeys))
+	for _, configKey := range configKeys {
+		record, err := files.EffectiveRecord(configKey)
+		if err != nil {
+			return errs.NewError(task, err)
+		}
+		if record == nil {
+			continue
+		}
+
+		var secretKeys []*config.Key
+		if spec := files.Spec(configKey); spec != nil {
+			secretKeys = loader.SecretKeys(spec)
+		}
+		records[configKey] = common.MaskSecrets(configKey, record, secretKeys)
+	}
+
+	// Print the result.
+	if flagSources {
+		return printSources(files, configKeys, records)
+	}
+	return printRecords(files, records)
+}
+
+func printRecords(files *common.Files, records map[string]interface{}) error {
+	task := "Print the effective configuration"
+
+	content := struct {
+		EnabledTimestamp interface{} `json:"salsaflow_enabled_timestamp,omitempty"`
+		Modules          interface{} `json:"active_modules,omitempty"`
+		Records          interface{} `json:"configuration"`
+	}{
+		Records: records,
+	}
+	if files.Local.EnabledTimestamp != nil {
+		content.EnabledTimestamp = files.Local.EnabledTimestamp
+		content.Modules = files.Local.Modules
+	}
+
+	raw, err := config.Marshal(&content)
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+	fmt.Println(string(raw))
+
+	for configKey := range records {
+		formatted, err := common.FormatValue(records[configKey])
+		if err != nil {
+			return errs.NewError(task, err)
+		}
+		output.SetValue(configKey, formatted)
+	}
+	return nil
+}
+
+func printSources(files *common.Files, configKeys []string, records map[string]interface{}) error {
+	task := "Print the configuration sources"
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	io.WriteString(tw, "KEY\tVALUE\tSOURCE\n")
+	for _, configKey := range configKeys {
+		if _, ok := records[configKey]; !ok {
+			continue
+		}
+
+		sources, err := files.Sources(configKey)
+		if err != nil {
+			return errs.NewError(task, err)
+		}
+
+		keys := make([]string, 0, len(sources))
+		for key := range sources {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		// Use the masked records to get the values to print.
+		section := &config.ConfigurationsSection{
+			Records: make(map[string]*json.RawMessage, 1),
+		}
+		if err := section.SetValue(&config.Key{ConfigKey: configKey}, records[configKey]); err != nil {
+			return errs.NewError(task, err)
+		}
+
+		for _, keyString := range keys {
+			key, err := config.ParseKey(keyString, []string{configKey})
+			if err != nil {
+				return errs.NewError(task, err)
+			}
+			value, err := section.Value(key)
+			if err != nil {
+				return errs.NewError(task, err)
+			}
+			formatted, err := common.FormatValue(value)
+			if err != nil {
+				return errs.NewError(task, err)
+			}
+			fmt.Fprintf(tw, "%v\t%v\t%v\n", keyString, formatted, sources[keyString])
+			output.SetValue(keyString, sources[keyString])
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,22 @@
+/*
+Print the effective configuration.
+
+  salsaflow config show [-sources] [CONFIG_KEY]
+
+Description
+
+Print the effective configuration, i.e. the global configuration
+merged with the local configuration, the local values taking precedence.
+The values overridden using environment variables or -set flag
+are applied on top.
+
+When CONFIG_KEY is specified, only the configuration record
+for the given configuration key is printed.
+
+When -sources is set, every value is printed on a separate line
+together with the source the value comes from, i.e. the configuration
+file, the environment variable or -set flag.
+
+The secret values such as access tokens are masked.
+*/
+package showCmd
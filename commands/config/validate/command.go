@@ -0,0 +1,59 @@
+package validateCmd
+
+import (
+	// Stdlib
+	"fmt"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app"
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/commands/config/common"
+	"github.com/salsaflow/salsaflow/errs"
+
+	// Other
+	"gopkg.in/tchap/gocli.v2"
+)
+
+var Command = &gocli.Command{
+	UsageLine: "validate",
+	Short:     "validate the configuration",
+	Long: `
+  Check that the configuration is complete and valid
+  for the core and all the modules active in the current repository.
+
+  No configuration dialog is run, the configuration files are never modified.
+	`,
+	Action: run,
+}
+
+func init() {
+	// Register global flags.
+	appflags.RegisterGlobalFlags(&Command.Flags)
+}
+
+func run(cmd *gocli.Command, args []string) {
+	if len(args) != 0 {
+		app.ExitWithUsage(cmd)
+	}
+
+	app.InitLogging()
+
+	if err := runMain(); err != nil {
+		errs.Fatal(err)
+	}
+}
+
+func runMain() error {
+	// Read the configuration files.
+	files, err := common.ReadFiles()
+	if err != nil {
+		return err
+	}
+
+	// Validate the configuration.
+	if invalid := common.Validate(files); len(invalid) != 0 {
+		return errs.NewError("Validate the configuration", fmt.Errorf(
+			"invalid configuration: %v", invalid))
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+/*
+Validate the configuration.
+
+  salsaflow config validate
+
+Description
+
+Check that the configuration is complete and valid
+for the core and all the modules active in the current repository.
+
+No configuration dialog is run, the configuration files are never modified.
+The command exits with a non-zero exit code when the configuration is not valid.
+*/
+package validateCmd
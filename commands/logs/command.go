@@ -0,0 +1,209 @@
+package logsCmd
+
+import (
+	// Stdlib
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app"
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/git/gitutil"
+	"github.com/salsaflow/salsaflow/trace"
+
+	// Other
+	"gopkg.in/tchap/gocli.v2"
+)
+
+var Command = &gocli.Command{
+	UsageLine: "logs [-n=N] [-raw] [RUN]",
+	Short:     "browse the trace logs of recent runs",
+	Long: `
+  Browse the trace logs of recent SalsaFlow runs.
+
+  The trace log is written into .git/salsaflow/logs for every command
+  invocation when -trace is passed to the command or when git config
+  key salsaflow.trace is set to true.
+
+  When RUN is not specified, the list of recent runs is printed.
+  Use -n to limit the number of runs being printed.
+
+  When RUN is specified, the events recorded for the given run are printed.
+  RUN can be either the index as printed in the list of runs or the run name.
+  Use -raw to print the JSON lines as they are stored in the log file.
+	`,
+	Action: run,
+}
+
+var (
+	flagCount = 20
+	flagRaw   bool
+)
+
+func init() {
+	// Register flags.
+	Command.Flags.IntVar(&flagCount, "n", flagCount, "number of runs to list")
+	Command.Flags.BoolVar(&flagRaw, "raw", flagRaw, "print the raw JSON lines")
+
+	// Register global flags.
+	appflags.RegisterGlobalFlags(&Command.Flags)
+}
+
+func run(cmd *gocli.Command, args []string) {
+	if len(args) > 1 {
+		app.ExitWithUsage(cmd)
+	}
+
+	app.InitLogging()
+
+	var err error
+	if len(args) == 0 {
+		err = listRuns()
+	} else {
+		err = showRun(args[0])
+	}
+	if err != nil {
+		errs.Fatal(err)
+	}
+}
+
+func loadRuns() ([]*trace.Run, error) {
+	task := "Load the trace logs"
+	gitDir, err := gitutil.GitDirectoryAbsolutePath()
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+	runs, err := trace.ListRuns(trace.LogDirectory(gitDir))
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+
+	// Drop the run for this very command invocation.
+	current := trace.CurrentLogFile()
+	filtered := make([]*trace.Run, 0, len(runs))
+	for _, run := range runs {
+		if run.Path != current {
+			filtered = append(filtered, run)
+		}
+	}
+	return filtered, nil
+}
+
+func listRuns() error {
+	runs, err := loadRuns()
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		fmt.Print("\nNo trace logs found. Use -trace to record one.\n\n")
+		return nil
+	}
+	if flagCount > 0 && len(runs) > flagCount {
+		runs = runs[:flagCount]
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, '\t', 0)
+	io.WriteString(tw, "\nIndex\tName\tExit Code\tCommand\n")
+	io.WriteString(tw, "=====\t====\t=========\t=======\n")
+	for i, run := range runs {
+		var (
+			exitCode = "-"
+			command  string
+		)
+		if exit := run.Exit(); exit != nil && exit.ExitCode != nil {
+			exitCode = strconv.Itoa(*exit.ExitCode)
+		}
+		if start := run.Start(); start != nil {
+			command = strings.Join(start.Args, " ")
+		}
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\n", i, run.Name(), exitCode, command)
+	}
+	io.WriteString(tw, "\n")
+	return tw.Flush()
+}
+
+func showRun(runId string) error {
+	runs, err := loadRuns()
+	if err != nil {
+		return err
+	}
+
+	// Find the run.
+	task := fmt.Sprintf("Find run '%v'", runId)
+	var selected *trace.Run
+	if index, err := strconv.Atoi(runId); err == nil {
+		if index >= 0 && index < len(runs) {
+			selected = runs[index]
+		}
+	} else {
+		for _, run := range runs {
+			if run.Name() == runId {
+				selected = run
+				break
+			}
+		}
+	}
+	if selected == nil {
+		return errs.NewError(task, errors.New("run not found"))
+	}
+
+	// Print the raw file when requested.
+	if flagRaw {
+		task := "Print the raw log file"
+		file, err := os.Open(selected.Path)
+		if err != nil {
+			return errs.NewError(task, err)
+		}
+		defer file.Close()
+		if _, err := io.Copy(os.Stdout, file); err != nil {
+			return errs.NewError(task, err)
+		}
+		return nil
+	}
+
+	// Otherwise print the events in a human-readable way.
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, '\t', 0)
+	io.WriteString(tw, "\n")
+	for _, event := range selected.Events {
+		fmt.Fprintf(tw, "%v\t%v\t%v\n",
+			event.Time.Format("15:04:05.000"), event.Type, formatEvent(event))
+	}
+	io.WriteString(tw, "\n")
+	return tw.Flush()
+}
+
+func formatEvent(event *trace.Event) string {
+	var details []string
+	if event.Duration != nil {
+		details = append(details, fmt.Sprintf("%vms", *event.Duration))
+	}
+	if event.ExitCode != nil {
+		details = append(details, fmt.Sprintf("exit code %v", *event.ExitCode))
+	}
+	if event.Error != "" {
+		details = append(details, "error: "+event.Error)
+	}
+	var suffix string
+	if len(details) != 0 {
+		suffix = fmt.Sprintf(" (%v)", strings.Join(details, ", "))
+	}
+
+	switch event.Type {
+	case trace.EventTypeStart:
+		return fmt.Sprintf("salsaflow %v [version %v]", strings.Join(event.Args, " "), event.Version)
+	case trace.EventTypeGit:
+		return "git " + strings.Join(event.Args, " ") + suffix
+	case trace.EventTypeHTTP:
+		return fmt.Sprintf("%v %v -> %v%v", event.Method, event.URL, event.Status, suffix)
+	case trace.EventTypeTask:
+		return fmt.Sprintf("[%v] %v", event.Tag, event.Message)
+	default:
+		return strings.TrimSpace(suffix)
+	}
+}
@@ -0,0 +1,24 @@
+/*
+Browse the trace logs of recent SalsaFlow runs.
+
+  salsaflow logs [-n=N] [-raw] [RUN]
+
+Description
+
+The trace log is written into .git/salsaflow/logs for every command
+invocation when -trace is passed to the command or when git config
+key salsaflow.trace is set to true. Every log file contains JSON lines
+describing the git commands being run (args, duration, exit code),
+the HTTP requests being sent (method, URL, status, latency) and the tasks
+logged as [RUN] or [ROLLBACK]. Secrets are redacted from all URLs,
+including the URLs passed to git.
+Only the most recent 100 log files are kept.
+
+When RUN is not specified, the list of recent runs is printed.
+Use -n to limit the number of runs being printed.
+
+When RUN is specified, the events recorded for the given run are printed.
+RUN can be either the index as printed in the list of runs or the run name.
+Use -raw to print the JSON lines as they are stored in the log file.
+*/
+package logsCmd
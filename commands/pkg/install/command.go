@@ -3,9 +3,9 @@ package installCmd
 import (
 	// Stdlib
 	"fmt"
-	"os"
 
 	// Internal
+	"github.com/salsaflow/salsaflow/app"
 	"github.com/salsaflow/salsaflow/app/appflags"
 	"github.com/salsaflow/salsaflow/errs"
 	"github.com/salsaflow/salsaflow/log"
@@ -49,10 +49,11 @@ func init() {
 
 func run(cmd *gocli.Command, args []string) {
 	if len(args) != 1 {
-		cmd.Usage()
-		os.Exit(2)
+		app.ExitWithUsage(cmd)
 	}
 
+	app.InitLogging()
+
 	if err := runMain(args[0]); err != nil {
 		if err == pkg.ErrAborted {
 			fmt.Println("\nYour wish is my command, exiting now!")
@@ -3,9 +3,9 @@ package upgradeCmd
 import (
 	// Stdlib
 	"fmt"
-	"os"
 
 	// Internal
+	"github.com/salsaflow/salsaflow/app"
 	"github.com/salsaflow/salsaflow/app/appflags"
 	"github.com/salsaflow/salsaflow/asciiart"
 	"github.com/salsaflow/salsaflow/errs"
@@ -44,10 +44,11 @@ func init() {
 
 func run(cmd *gocli.Command, args []string) {
 	if len(args) != 0 {
-		cmd.Usage()
-		os.Exit(2)
+		app.ExitWithUsage(cmd)
 	}
 
+	app.InitLogging()
+
 	upgraded, err := pkg.Upgrade(&pkg.InstallOptions{
 		GitHubOwner: flagOwner,
 		GitHubRepo:  flagRepo,
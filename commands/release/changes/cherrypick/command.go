@@ -48,8 +48,7 @@ func init() {
 
 func run(cmd *gocli.Command, args []string) {
 	if len(args) != 0 {
-		cmd.Usage()
-		os.Exit(2)
+		app.ExitWithUsage(cmd)
 	}
 
 	app.InitOrDie()
@@ -3,7 +3,6 @@ package deployCmd
 import (
 	// Stdlib
 	"fmt"
-	"os"
 
 	// Internal
 	"github.com/salsaflow/salsaflow/action"
@@ -15,9 +14,12 @@ import (
 	"github.com/salsaflow/salsaflow/log"
 	"github.com/salsaflow/salsaflow/modules"
 	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/output"
 	"github.com/salsaflow/salsaflow/prompt"
+	"github.com/salsaflow/salsaflow/releases"
 	"github.com/salsaflow/salsaflow/releases/commands"
 	"github.com/salsaflow/salsaflow/releases/notes"
+	"github.com/salsaflow/salsaflow/scripts"
 	"github.com/salsaflow/salsaflow/version"
 
 	// Other
@@ -41,6 +43,9 @@ var Command = &gocli.Command{
     5) The staging branch is reset to the current release branch
        in case there is already another release started.
     6) Everything is pushed to the remote repository.
+
+  The pre_release_deploy and post_release_deploy lifecycle hooks are run
+  in case the relevant scripts are available in .salsaflow/scripts.
 	`,
 	Action: run,
 }
@@ -58,8 +63,7 @@ func init() {
 
 func run(cmd *gocli.Command, args []string) {
 	if len(args) != 0 {
-		cmd.Usage()
-		os.Exit(2)
+		app.ExitWithUsage(cmd)
 	}
 
 	app.InitOrDie()
@@ -151,6 +155,33 @@ func runMain() (err error) {
 		return err
 	}
 
+	// Get the stable branch version.
+	stableVersion, err := stagingVersion.ToStableVersion()
+	if err != nil {
+		return err
+	}
+
+	// Run the pre_release_deploy hook.
+	hookCtx := scripts.NewHookContext()
+	hookCtx.SetVersion("staging", stagingVersion)
+	hookCtx.SetVersion("stable", stableVersion)
+	hookCtx.SetBranch("staging", stagingBranch)
+	hookCtx.SetBranch("stable", stableBranch)
+	hookCtx.Tag = stableVersion.ReleaseTagString()
+	hookCtx.Load = func(ctx *scripts.HookContext) error {
+		stories, err := issueTrackerRelease.Stories()
+		if err != nil {
+			return err
+		}
+		for _, story := range stories {
+			ctx.AddStory(story)
+		}
+		return nil
+	}
+	if err := scripts.RunHook(scripts.HookPreReleaseDeploy, hookCtx); err != nil {
+		return err
+	}
+
 	// Reset the stable branch to point to stage.
 	task = fmt.Sprintf("Reset branch '%v' to point to branch '%v'", stableBranch, stagingBranch)
 	log.Run(task)
@@ -161,11 +192,6 @@ func runMain() (err error) {
 	defer action.RollbackTaskOnError(&err, task, act)
 
 	// Bump version for the stable branch.
-	stableVersion, err := stagingVersion.ToStableVersion()
-	if err != nil {
-		return err
-	}
-
 	task = fmt.Sprintf("Bump version (branch '%v' -> %v)", stableBranch, stableVersion)
 	log.Run(task)
 	act, err = version.SetForBranch(stableVersion, stableBranch)
@@ -248,6 +274,15 @@ func runMain() (err error) {
 		}
 	}
 
+	// Record what happened for the JSON output.
+	output.SetValue("deployed_version", stableVersion.String())
+	output.AddBranch(stableBranch)
+	output.AddTag(tag)
+	releases.AddStoriesToOutput(issueTrackerRelease)
+
+	// Run the post_release_deploy hook.
+	scripts.RunPostHook(scripts.HookPostReleaseDeploy, hookCtx)
+
 	// Tell the user we succeeded.
 	color.Green("\n-----> Release %v deployed successfully!\n\n", stableVersion)
 	color.Cyan("Let's check whether the next release branch can be staged already.\n")
@@ -19,5 +19,13 @@ This command goes through the following steps:
   5. The staging branch is reset to the current release branch
      in case there is already another release started.
   6. Everything is pushed to the remote repository.
+
+Hooks
+
+The pre_release_deploy hook is run once the release is known to be closable,
+before any branch is modified. The command is aborted in case the hook fails.
+The post_release_deploy hook is run once the stable branch and the release tag
+are pushed. The hooks are only run in case the relevant scripts are available in
+.salsaflow/scripts, see the Scripts section of the SalsaFlow README.
 */
 package deployCmd
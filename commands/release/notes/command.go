@@ -47,8 +47,7 @@ func init() {
 
 func run(cmd *gocli.Command, args []string) {
 	if len(args) != 1 {
-		cmd.Usage()
-		os.Exit(2)
+		app.ExitWithUsage(cmd)
 	}
 
 	app.InitOrDie()
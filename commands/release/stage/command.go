@@ -1,9 +1,6 @@
 package stageCmd
 
 import (
-	// Stdlib
-	"os"
-
 	// Internal
 	"github.com/salsaflow/salsaflow/app"
 	"github.com/salsaflow/salsaflow/app/appflags"
@@ -27,6 +24,9 @@ var Command = &gocli.Command{
     3) Delete the release branch.
     4) Bump the version for the staging branch.
     5) Push the changes.
+
+  The pre_release_stage and post_release_stage lifecycle hooks are run in
+  case the relevant scripts are available in .salsaflow/scripts.
 	`,
 	Action: run,
 }
@@ -38,8 +38,7 @@ func init() {
 
 func run(cmd *gocli.Command, args []string) {
 	if len(args) != 0 {
-		cmd.Usage()
-		os.Exit(2)
+		app.ExitWithUsage(cmd)
 	}
 
 	app.InitOrDie()
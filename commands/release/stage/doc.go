@@ -23,5 +23,13 @@ This command goes through the following steps:
   5. Reset the staging branch to point to the newly created tag.
   6. Push to the remote repository to delete the release branch, update
      the staging branch and create the release tag.
+
+Hooks
+
+The pre_release_stage hook is run once the release is known to be stageable,
+before any branch is modified. The command is aborted in case the hook fails.
+The post_release_stage hook is run once all branches are pushed. The hooks are
+only run in case the relevant scripts are available in .salsaflow/scripts, see
+the Scripts section of the SalsaFlow README.
 */
 package stageCmd
@@ -3,7 +3,6 @@ package startCmd
 import (
 	// Stdlib
 	"fmt"
-	"os"
 
 	// Internal
 	"github.com/salsaflow/salsaflow/action"
@@ -13,6 +12,9 @@ import (
 	"github.com/salsaflow/salsaflow/git"
 	"github.com/salsaflow/salsaflow/log"
 	"github.com/salsaflow/salsaflow/modules"
+	"github.com/salsaflow/salsaflow/output"
+	"github.com/salsaflow/salsaflow/releases"
+	"github.com/salsaflow/salsaflow/scripts"
 	"github.com/salsaflow/salsaflow/version"
 
 	// Vendor
@@ -36,6 +38,9 @@ var Command = &gocli.Command{
   into the trunk branch. By default the new version is taken from
   the previous one by auto-incrementing the minor version.
   However, -next_trunk_version can be used to overwrite this behaviour.
+
+  The pre_release_start and post_release_start lifecycle hooks are run in
+  case the relevant scripts are available in .salsaflow/scripts.
 	`,
 	Action: run,
 }
@@ -56,8 +61,7 @@ func init() {
 
 func run(cmd *gocli.Command, args []string) {
 	if len(args) != 0 {
-		cmd.Usage()
-		os.Exit(2)
+		app.ExitWithUsage(cmd)
 	}
 
 	app.InitOrDie()
@@ -183,6 +187,23 @@ The relevant version strings are:
 	}
 	fmt.Println()
 
+	// Get the release branch version.
+	testingVersion, err := trunkVersion.ToTestingVersion()
+	if err != nil {
+		return err
+	}
+
+	// Run the pre_release_start hook.
+	hookCtx := scripts.NewHookContext()
+	hookCtx.SetVersion("trunk", trunkVersion)
+	hookCtx.SetVersion("next_trunk", nextTrunkVersion)
+	hookCtx.SetVersion("release", testingVersion)
+	hookCtx.SetBranch("trunk", trunkBranch)
+	hookCtx.SetBranch("release", releaseBranch)
+	if err := scripts.RunHook(scripts.HookPreReleaseStart, hookCtx); err != nil {
+		return err
+	}
+
 	// Create the release branch on top of the trunk branch.
 	task = fmt.Sprintf("Create branch '%v' on top of branch '%v'", releaseBranch, trunkBranch)
 	log.Run(task)
@@ -198,11 +219,6 @@ The relevant version strings are:
 	}))
 
 	// Bump the release branch version.
-	testingVersion, err := trunkVersion.ToTestingVersion()
-	if err != nil {
-		return err
-	}
-
 	task = fmt.Sprintf("Bump version (branch '%v' -> %v)", releaseBranch, testingVersion)
 	log.Run(task)
 	_, err = version.SetForBranch(testingVersion, releaseBranch)
@@ -247,5 +263,25 @@ The relevant version strings are:
 		return errs.NewError(task, err)
 	}
 
+	// Record what happened for the JSON output.
+	output.SetValue("release_version", testingVersion.String())
+	output.SetValue("next_trunk_version", nextTrunkVersion.String())
+	output.AddBranch(releaseBranch)
+	output.AddBranch(trunkBranch)
+	releases.AddStoriesToOutput(tracker.RunningRelease(trunkVersion))
+
+	// Run the post_release_start hook.
+	hookCtx.Load = func(ctx *scripts.HookContext) error {
+		stories, err := tracker.RunningRelease(trunkVersion).Stories()
+		if err != nil {
+			return err
+		}
+		for _, story := range stories {
+			ctx.AddStory(story)
+		}
+		return nil
+	}
+	scripts.RunPostHook(scripts.HookPostReleaseStart, hookCtx)
+
 	return nil
 }
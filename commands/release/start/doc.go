@@ -25,5 +25,13 @@ This command goes through the following steps:
   7. Mark the release as started in the issue tracker. This again depends on
      the module that is being used.
   8. All modified branches are pushed.
+
+Hooks
+
+The pre_release_start hook is run once the user confirms the release, before any
+branch is modified. The command is aborted in case the hook fails. The
+post_release_start hook is run once all branches are pushed. The hooks are only
+run in case the relevant scripts are available in .salsaflow/scripts, see the
+Scripts section of the SalsaFlow README.
 */
 package startCmd
@@ -13,9 +13,11 @@ import (
 	"github.com/salsaflow/salsaflow/config"
 	"github.com/salsaflow/salsaflow/config/loader"
 	"github.com/salsaflow/salsaflow/errs"
+	flags "github.com/salsaflow/salsaflow/flag"
 	"github.com/salsaflow/salsaflow/log"
 	"github.com/salsaflow/salsaflow/modules"
 	"github.com/salsaflow/salsaflow/prompt"
+	"github.com/salsaflow/salsaflow/skeleton"
 
 	// Other
 	"gopkg.in/tchap/gocli.v2"
@@ -23,7 +25,7 @@ import (
 
 var Command = &gocli.Command{
 	UsageLine: `
-  bootstrap -skeleton=SKELETON [-skeleton_only]
+  bootstrap -skeleton=SKELETON [-var KEY=VALUE ...] [-skeleton_only]
 
   bootstrap -no_skeleton`,
 	Short: "bootstrap repository for SalsaFlow",
@@ -35,17 +37,31 @@ var Command = &gocli.Command{
 
   The user is prompted for all necessary data.
 
-  The -skeleton flag can be used to specify the repository to be used
-  for custom scripts. It expects a string of "$OWNER/$REPO" and then uses
-  the repository located at github.com/$OWNER/$REPO. It clones the repository
-  and copies the scripts directory into the local configuration directory.
+  The -skeleton flag can be used to specify the skeleton to be poured into
+  the local configuration directory. It expects a string of SOURCE[@REF],
+  where SOURCE is one of the following:
+
+    * $OWNER/$REPO, meaning the repository located at github.com/$OWNER/$REPO
+    * any git repository URL accepted by git clone
+    * a local directory
+
+  REF can be used to pin the skeleton to a branch, a tag or a commit.
+  The default branch is used otherwise.
+
+  In case the skeleton contains .salsaflow directory, the directory content
+  is copied into the local configuration directory. Otherwise only
+  the scripts directory is copied. Files ending with .tmpl are rendered
+  as templates using the variables declared in skeleton.json.
+  The user is prompted for the variable values unless -var is used.
+  The skeleton source and the commit used are recorded in the local
+  configuration file so that 'repo skeleton update' can be used later.
 
   In case no skeleton is to be used to bootstrap the repository,
   -no_skeleton must be specified explicitly.
 
   In case the repository is bootstrapped, but the skeleton is missing,
   it can be added by specifying -skeleton=SKELETON -skeleton_only.
-  That will skip the configuration file generation step.
+  That will skip the configuration dialog.
 	`,
 	Action: run,
 }
@@ -54,6 +70,7 @@ var (
 	flagNoSkeleton   bool
 	flagSkeleton     string
 	flagSkeletonOnly bool
+	flagVars         = flags.NewKeyValueFlag()
 )
 
 func init() {
@@ -64,6 +81,8 @@ func init() {
 		"skeleton to be used to bootstrap the repository")
 	Command.Flags.BoolVar(&flagSkeletonOnly, "skeleton_only", flagSkeletonOnly,
 		"skip the config dialog and only install the skeleton")
+	Command.Flags.Var(flagVars, "var",
+		"set skeleton template variable; KEY=VALUE, can be repeated")
 
 	// Register global flags.
 	appflags.RegisterGlobalFlags(&Command.Flags)
@@ -71,8 +90,7 @@ func init() {
 
 func run(cmd *gocli.Command, args []string) {
 	if len(args) != 0 {
-		cmd.Usage()
-		os.Exit(2)
+		app.ExitWithUsage(cmd)
 	}
 
 	app.InitLogging()
@@ -106,6 +124,18 @@ func runMain(cmd *gocli.Command) (err error) {
 	}
 	defer action.RollbackOnError(&err, act)
 
+	// Install the skeleton into the local config directory if desired.
+	// This happens first so that the skeleton can ship the local config file.
+	var record *config.SkeletonRecord
+	if spec := flagSkeleton; spec != "" {
+		var act action.Action
+		record, act, err = getAndPourSkeleton(spec)
+		if err != nil {
+			return err
+		}
+		defer action.RollbackOnError(&err, act)
+	}
+
 	// Set up the global and local configuration file unless -skeleton_only.
 	if !flagSkeletonOnly {
 		if err := assembleAndWriteConfig(); err != nil {
@@ -113,9 +143,9 @@ func runMain(cmd *gocli.Command) (err error) {
 		}
 	}
 
-	// Install the skeleton into the local config directory if desired.
-	if skeleton := flagSkeleton; skeleton != "" {
-		if err := getAndPourSkeleton(skeleton); err != nil {
+	// Record the skeleton used.
+	if record != nil {
+		if err := skeleton.WriteRecord(record); err != nil {
 			return err
 		}
 	}
@@ -204,30 +234,3 @@ func assembleAndWriteConfig() error {
 
 	return nil
 }
-
-func getAndPourSkeleton(skeleton string) error {
-	// Get or update given skeleton.
-	task := fmt.Sprintf("Get or update skeleton '%v'", skeleton)
-	log.Run(task)
-	if err := getOrUpdateSkeleton(flagSkeleton); err != nil {
-		return errs.NewError(task, err)
-	}
-
-	// Move the skeleton files into place.
-	task = "Copy the skeleton into the configuration directory"
-	log.Go(task)
-
-	localConfigDir, err := config.LocalConfigDirectoryAbsolutePath()
-	if err != nil {
-		return errs.NewError(task, err)
-	}
-
-	log.NewLine("")
-	if err := pourSkeleton(flagSkeleton, localConfigDir); err != nil {
-		return errs.NewError(task, err)
-	}
-	log.NewLine("")
-	log.Ok(task)
-
-	return nil
-}
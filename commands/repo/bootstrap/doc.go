@@ -1,7 +1,7 @@
 /*
 Bootstrap the repository for SalsaFlow.
 
-  salsaflow repo bootstrap -skeleton=SKELETON [-skeleton_only]
+  salsaflow repo bootstrap -skeleton=SKELETON [-var KEY=VALUE ...] [-skeleton_only]
 
   salsaflow repo bootstrap -no_skeleton
 
@@ -12,27 +12,43 @@ for SalsaFlow (the directory that is then committed into the repository).
 
 The user is prompted for all necessary data.
 
-The -skeleton flag can be used to specify the repository to be used
-for custom scripts. It expects a string of "$OWNER/$REPO" and then uses
-the repository located at github.com/$OWNER/$REPO. It clones the repository
-and copies 'scripts' directory into the local configuration directory.
+The -skeleton flag can be used to specify the skeleton to be poured into
+the local configuration directory. It expects a string of SOURCE[@REF],
+where SOURCE is one of the following:
+
+  * $OWNER/$REPO, meaning the repository located at github.com/$OWNER/$REPO
+  * any git repository URL accepted by git clone
+  * a local directory
+
+REF can be used to pin the skeleton to a branch, a tag or a commit.
+The default branch is used otherwise.
+
+In case the skeleton contains .salsaflow directory, the directory content
+is copied into the local configuration directory. Otherwise only
+the scripts directory is copied. Files ending with .tmpl are rendered
+as templates using the variables declared in skeleton.json.
+The user is prompted for the variable values unless -var is used.
+The skeleton source and the commit used are recorded in the local
+configuration file so that 'repo skeleton update' can be used later.
 
 In case no skeleton is to be used to bootstrap the repository,
 -no_skeleton must be specified explicitly.
 
 In case the repository is bootstrapped, but the skeleton is missing,
 it can be added by specifying -skeleton=SKELETON -skeleton_only.
-That will skip the configuration file generation step.
+That will skip the configuration dialog.
 
 Steps
 
 This command goes through the following steps:
 
-  1. In case -skeleton_only is not specified, prompt the user
+  1. In case -skeleton is specified, fetch the given skeleton. Git repositories
+     are cloned into the local cache, which is located in the current user's
+     home directory, or fetched in case they are cached already. The pinned ref
+     is checked out. Once the content is available, it is copied into
+     the local metadata directory, .salsaflow.
+  2. In case -skeleton_only is not specified, prompt the user
      for all necessary config and save it into the local config directory.
-  2. In case -skeleton is specified, clone the given skeleton repository
-     into the local cache, which is located in the current user's home directory.
-	 The repository is just pulled in case it already exists. Once the content is available,
-	 it is copied into the local metadata directory, .salsaflow.
+  3. In case -skeleton is specified, record the skeleton in the local config.
 */
 package bootstrapCmd
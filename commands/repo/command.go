@@ -3,8 +3,10 @@ package repoCmd
 import (
 	"github.com/salsaflow/salsaflow/app/appflags"
 	"github.com/salsaflow/salsaflow/commands/repo/bootstrap"
+	"github.com/salsaflow/salsaflow/commands/repo/doctor"
 	"github.com/salsaflow/salsaflow/commands/repo/init"
 	"github.com/salsaflow/salsaflow/commands/repo/prune"
+	"github.com/salsaflow/salsaflow/commands/repo/skeleton"
 
 	"gopkg.in/tchap/gocli.v2"
 )
@@ -23,6 +25,8 @@ func init() {
 
 	// Register subcommands.
 	Command.MustRegisterSubcommand(bootstrapCmd.Command)
+	Command.MustRegisterSubcommand(doctorCmd.Command)
 	Command.MustRegisterSubcommand(initCmd.Command)
 	Command.MustRegisterSubcommand(pruneCmd.Command)
+	Command.MustRegisterSubcommand(skeletonCmd.Command)
 }
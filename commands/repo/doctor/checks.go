@@ -0,0 +1,418 @@
+package doctorCmd
+
+import (
+	// Stdlib
+	"fmt"
+	"strings"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app/metadata"
+	"github.com/salsaflow/salsaflow/commands/config/common"
+	"github.com/salsaflow/salsaflow/config"
+	"github.com/salsaflow/salsaflow/config/loader"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/git"
+	"github.com/salsaflow/salsaflow/hooks"
+	"github.com/salsaflow/salsaflow/modules"
+	modcommon "github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/repo"
+	"github.com/salsaflow/salsaflow/scripts"
+)
+
+// checks returns all the checks to be run, in the order they are run.
+func checks() []*check {
+	cs := []*check{
+		{id: "git_version", title: "Git version", run: checkGitVersion},
+		{id: "local_config", title: "Configuration", run: checkConfig},
+		{id: "remote", title: "Remote", run: checkRemote},
+		{id: "initialised", title: "Repository initialised", run: checkInitialised},
+		{
+			id:       "branch_trunk",
+			title:    "Trunk branch",
+			run:      checkCoreBranch(trunkBranch),
+			requires: []string{"local_config", "remote"},
+		},
+		{
+			id:       "branch_stable",
+			title:    "Stable branch",
+			run:      checkCoreBranch(stableBranch),
+			requires: []string{"local_config", "remote"},
+		},
+	}
+	for _, hookType := range hooks.HookTypes {
+		cs = append(cs, &check{
+			id:    "hook_" + strings.Replace(string(hookType), "-", "_", -1),
+			title: fmt.Sprintf("Git %v hook", hookType),
+			run:   checkHook(hookType),
+		})
+	}
+	return append(cs, []*check{
+		{
+			id:    "script_get_version",
+			title: "Script get_version",
+			run:   checkScript(scripts.ScriptNameGetVersion),
+		},
+		{
+			id:    "script_set_version",
+			title: "Script set_version",
+			run:   checkScript(scripts.ScriptNameSetVersion),
+		},
+		{
+			id:       "issue_tracker",
+			title:    "Issue tracker",
+			run:      checkIssueTracker,
+			requires: []string{"local_config"},
+		},
+		{
+			id:       "code_review_tool",
+			title:    "Code review tool",
+			run:      checkCodeReviewTool,
+			requires: []string{"local_config"},
+		},
+		{
+			id:       "release_notes_manager",
+			title:    "Release notes manager",
+			run:      checkReleaseNotesManager,
+			requires: []string{"local_config"},
+		},
+	}...)
+}
+
+// Git -------------------------------------------------------------------------
+
+func checkGitVersion() *result {
+	gitVersion, err := repo.CheckGitVersion()
+	if err != nil {
+		return failed(err)
+	}
+	return pass(gitVersion)
+}
+
+func checkInitialised() *result {
+	initialisedBy, err := git.GetConfigString(repo.GitConfigKeyInitialised)
+	if err != nil {
+		return failed(err)
+	}
+
+	reinit := func() error {
+		return repo.Init(true)
+	}
+	switch initialisedBy {
+	case metadata.Version:
+		return pass("initialised by the current version")
+	case "":
+		return fail("repository not initialised",
+			"Run any SalsaFlow command or 'repo init' to initialise the repository.").
+			withFix("Initialise the repository", reinit)
+	default:
+		return warn(fmt.Sprintf("initialised by SalsaFlow %v, running %v",
+			initialisedBy, metadata.Version),
+			"Run 'repo init -force' to initialise the repository again.").
+			withFix("Initialise the repository again", reinit)
+	}
+}
+
+func checkRemote() *result {
+	remote, err := git.GetConfigString(git.GitConfigKeyRemote)
+	if err != nil {
+		return failed(err)
+	}
+
+	stdout, err := git.Run("remote")
+	if err != nil {
+		return failed(err)
+	}
+	remotes := strings.Fields(stdout.String())
+	exists := func(name string) bool {
+		for _, r := range remotes {
+			if r == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	if remote == "" {
+		if !exists(git.DefaultRemoteName) {
+			return fail(
+				fmt.Sprintf("%v not set and remote '%v' not found",
+					git.GitConfigKeyRemote, git.DefaultRemoteName),
+				fmt.Sprintf("Run 'git config %v REMOTE' to set the remote to be used.",
+					git.GitConfigKeyRemote))
+		}
+		return warn(
+			fmt.Sprintf("%v not set, using '%v'", git.GitConfigKeyRemote, git.DefaultRemoteName),
+			fmt.Sprintf("Run 'git config %v %v' to make the choice explicit.",
+				git.GitConfigKeyRemote, git.DefaultRemoteName)).
+			withFix(fmt.Sprintf("Set %v to '%v'", git.GitConfigKeyRemote, git.DefaultRemoteName),
+				func() error {
+					return git.SetConfigString(git.GitConfigKeyRemote, git.DefaultRemoteName)
+				})
+	}
+
+	if !exists(remote) {
+		return fail(fmt.Sprintf("remote '%v' not found", remote),
+			fmt.Sprintf("Make sure %v points to an existing remote.", git.GitConfigKeyRemote))
+	}
+	return pass(fmt.Sprintf("using remote '%v'", remote))
+}
+
+type branchKind int
+
+const (
+	trunkBranch branchKind = iota
+	stableBranch
+)
+
+func checkCoreBranch(kind branchKind) func() *result {
+	return func() *result {
+		gitConfig, err := git.LoadConfig()
+		if err != nil {
+			return failed(err)
+		}
+		var (
+			remote = gitConfig.RemoteName
+			branch = gitConfig.TrunkBranchName
+		)
+		if kind == stableBranch {
+			branch = gitConfig.StableBranchName
+		}
+		remoteBranch := remote + "/" + branch
+
+		// The remote branch must exist.
+		remoteExists, err := git.RemoteBranchExists(branch, remote)
+		if err != nil {
+			return failed(err)
+		}
+		if !remoteExists {
+			return fail(fmt.Sprintf("branch '%v' not found", remoteBranch),
+				fmt.Sprintf("Run 'git fetch %v' or push branch '%v'.", remote, branch))
+		}
+
+		// The local branch must exist.
+		localExists, err := git.LocalBranchExists(branch)
+		if err != nil {
+			return failed(err)
+		}
+		if !localExists {
+			return fail(fmt.Sprintf("branch '%v' not found", branch), "").
+				withFix(fmt.Sprintf("Create branch '%v' tracking '%v'", branch, remoteBranch),
+					func() error {
+						return git.CreateTrackingBranch(branch, remote)
+					})
+		}
+
+		// The local branch must track the remote branch.
+		trackedRemote, err := git.GetConfigString(fmt.Sprintf("branch.%v.remote", branch))
+		if err != nil {
+			return failed(err)
+		}
+		trackedBranch, err := git.GetConfigString(fmt.Sprintf("branch.%v.merge", branch))
+		if err != nil {
+			return failed(err)
+		}
+		if trackedRemote != remote || trackedBranch != "refs/heads/"+branch {
+			return warn(fmt.Sprintf("branch '%v' not tracking '%v'", branch, remoteBranch), "").
+				withFix(fmt.Sprintf("Set branch '%v' to track '%v'", branch, remoteBranch),
+					func() error {
+						return git.Branch("--set-upstream-to="+remoteBranch, branch)
+					})
+		}
+
+		// The local branch should be up to date.
+		upToDate, err := git.IsBranchSynchronized(branch, remote)
+		if err != nil {
+			return failed(err)
+		}
+		if !upToDate {
+			return warn(fmt.Sprintf("branch '%v' not in sync with '%v'", branch, remoteBranch),
+				"Branches that cannot be fast-forwarded must be synchronized manually.").
+				withFix(fmt.Sprintf("Fast-forward branch '%v'", branch), func() error {
+					return git.EnsureBranchSynchronized(branch, remote)
+				})
+		}
+
+		return pass(fmt.Sprintf("branch '%v' tracking '%v'", branch, remoteBranch))
+	}
+}
+
+func checkHook(hookType hooks.HookType) func() *result {
+	return func() *result {
+		installedVersion, err := hooks.InstalledVersion(hookType)
+		if err != nil {
+			return failed(err)
+		}
+
+		fix := func() error {
+			return hooks.CheckAndUpsert(hookType, false)
+		}
+		switch {
+		case installedVersion == nil:
+			return fail("SalsaFlow hook not installed", "").
+				withFix("Install the hook", fix)
+		case installedVersion.String() != metadata.Version:
+			return warn(fmt.Sprintf("version %v installed, running %v",
+				installedVersion, metadata.Version), "").
+				withFix("Upgrade the hook", fix)
+		default:
+			return pass("version " + installedVersion.String())
+		}
+	}
+}
+
+// Configuration ---------------------------------------------------------------
+
+func checkConfig() *result {
+	if _, err := config.ReadLocalConfig(); err != nil {
+		return fail("local configuration file not readable",
+			"Run 'repo bootstrap' to set up the local configuration.")
+	}
+
+	files, err := common.ReadFiles()
+	if err != nil {
+		return failed(err)
+	}
+	var invalid []string
+	for _, spec := range files.Specs {
+		if err := loader.CheckConfig(spec); err != nil {
+			invalid = append(invalid, spec.ConfigKey())
+		}
+	}
+	if len(invalid) != 0 {
+		return fail("invalid configuration: "+strings.Join(invalid, ", "),
+			"Run 'config validate' to see the details.")
+	}
+	return pass("valid")
+}
+
+// Scripts ---------------------------------------------------------------------
+
+func checkScript(scriptName string) func() *result {
+	return func() *result {
+		cmd, err := scripts.Command(scriptName)
+		if err != nil {
+			return fail(err.Error(), fmt.Sprintf(`
+Make sure there is a script called %v_PLATFORM.RUNNER or %v.RUNNER
+available in %v/%v for the current platform.`,
+				scriptName, scriptName, config.LocalConfigDirname, scripts.ScriptDirname))
+		}
+		return pass(strings.Join(cmd.Args, " "))
+	}
+}
+
+// Modules ---------------------------------------------------------------------
+
+func checkIssueTracker() *result {
+	tracker, err := modules.GetIssueTracker()
+	if err != nil {
+		return failed(err)
+	}
+	if err := modcommon.CheckIssueTrackerHealth(tracker); err != nil {
+		return fail(fmt.Sprintf("%v not accessible: %v", tracker.ServiceName(), rootCause(err)),
+			"Make sure the access token is valid and the project is accessible.")
+	}
+	return pass(tracker.ServiceName() + " accessible")
+}
+
+func checkCodeReviewTool() *result {
+	tool, err := modules.GetCodeReviewTool()
+	if err != nil {
+		return failed(err)
+	}
+	return checkHealth(tool)
+}
+
+func checkReleaseNotesManager() *result {
+	manager, err := modules.GetReleaseNotesManager()
+	if err != nil {
+		if _, ok := errs.RootCause(err).(*modules.ErrModuleNotSet); ok {
+			return pass("no module active")
+		}
+		return failed(err)
+	}
+	return checkHealth(manager)
+}
+
+func checkHealth(service interface{}) *result {
+	checker, ok := service.(modcommon.HealthChecker)
+	if !ok {
+		return pass("configured")
+	}
+	if err := checker.CheckHealth(); err != nil {
+		return fail("not accessible: "+rootCause(err),
+			"Make sure the access token is valid and the project is accessible.")
+	}
+	return pass("accessible")
+}
+
+// Results ---------------------------------------------------------------------
+
+type status string
+
+const (
+	statusPass status = "pass"
+	statusWarn status = "warn"
+	statusFail status = "fail"
+	statusSkip status = "skip"
+)
+
+type check struct {
+	id    string
+	title string
+	run   func() *result
+
+	// requires lists the checks that must not fail for this check to be run.
+	// This prevents the configuration dialog from being run, for example.
+	requires []string
+}
+
+type result struct {
+	status  status
+	message string
+	hint    string
+
+	fixTitle string
+	fix      func() error
+}
+
+func pass(message string) *result {
+	return &result{status: statusPass, message: message}
+}
+
+func warn(message, hint string) *result {
+	return &result{status: statusWarn, message: message, hint: hint}
+}
+
+func fail(message, hint string) *result {
+	return &result{status: statusFail, message: message, hint: hint}
+}
+
+// failed turns the given error into a failed result,
+// using the error hint as the result hint.
+func failed(err error) *result {
+	var hint string
+	for e := err; e != nil; {
+		ex, ok := e.(errs.Err)
+		if !ok {
+			break
+		}
+		if h := strings.TrimSpace(ex.Hint()); h != "" {
+			hint = h
+		}
+		e = ex.Err()
+	}
+	return fail(rootCause(err), hint)
+}
+
+func (res *result) withFix(title string, fix func() error) *result {
+	res.fixTitle = title
+	res.fix = fix
+	return res
+}
+
+func rootCause(err error) string {
+	if cause := errs.RootCause(err); cause != nil {
+		return cause.Error()
+	}
+	return "unknown error"
+}
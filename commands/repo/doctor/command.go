@@ -0,0 +1,170 @@
+package doctorCmd
+
+import (
+	// Stdlib
+	"errors"
+	"fmt"
+	"strings"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app"
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/output"
+	"github.com/salsaflow/salsaflow/prompt"
+
+	// Other
+	"gopkg.in/tchap/gocli.v2"
+)
+
+var Command = &gocli.Command{
+	UsageLine: "doctor [-fix]",
+	Short:     "check the repository setup",
+	Long: `
+  Check whether the repository is set up correctly for SalsaFlow.
+
+  The following is checked:
+
+    * the git version installed is supported
+    * the repository is initialised by the running version of SalsaFlow
+    * the configuration is complete and valid
+    * salsaflow.remote is set and the remote exists
+    * the core branches exist and track the remote branches
+    * the SalsaFlow git hooks are installed and up to date
+    * the version scripts are available for the current platform
+    * the issue tracker is accessible using the credentials configured,
+      the same for the code review tool and the release notes manager
+      in case the modules support it
+
+  Every check either passes, issues a warning or fails, the warnings
+  and failures are accompanied by a hint on how to fix the issue.
+  The checks depending on a check that failed are skipped.
+
+  Some issues can be fixed automatically. Use -fix to do so.
+  The checks are run again once the fix is applied.
+	`,
+	Action: run,
+}
+
+var flagFix bool
+
+func init() {
+	// Register flags.
+	Command.Flags.BoolVar(&flagFix, "fix", flagFix,
+		"fix the issues that can be fixed automatically")
+
+	// Register global flags.
+	appflags.RegisterGlobalFlags(&Command.Flags)
+}
+
+func run(cmd *gocli.Command, args []string) {
+	if len(args) != 0 {
+		app.ExitWithUsage(cmd)
+	}
+
+	app.InitLogging()
+
+	defer prompt.RecoverCancel()
+
+	if err := runMain(); err != nil {
+		errs.Fatal(err)
+	}
+}
+
+func runMain() error {
+	var (
+		results  = make(map[string]*result)
+		failed   int
+		warnings int
+		fixable  int
+	)
+	for _, c := range checks() {
+		res := runCheck(c, results)
+
+		// Apply the fix if desired.
+		if res.fix != nil {
+			if flagFix {
+				res = applyFix(c, res, results)
+			} else {
+				fixable++
+			}
+		}
+
+		results[c.id] = res
+		printResult(c, res)
+		output.SetValue(c.id, string(res.status))
+
+		switch res.status {
+		case statusFail:
+			failed++
+		case statusWarn:
+			warnings++
+		}
+	}
+
+	fmt.Println()
+	if fixable != 0 {
+		log.Log(fmt.Sprintf("%v issue(s) can be fixed automatically, run with -fix", fixable))
+	}
+	if failed != 0 {
+		return errs.NewError("Check the repository setup", fmt.Errorf(
+			"%v check(s) failed, %v warning(s)", failed, warnings))
+	}
+	if warnings != 0 {
+		log.Warn(fmt.Sprintf("All checks passed, %v warning(s)", warnings))
+		return nil
+	}
+	log.Log("All checks passed")
+	return nil
+}
+
+func runCheck(c *check, results map[string]*result) *result {
+	for _, id := range c.requires {
+		if res, ok := results[id]; ok && (res.status == statusFail || res.status == statusSkip) {
+			return &result{
+				status:  statusSkip,
+				message: fmt.Sprintf("skipped, check '%v' did not pass", id),
+			}
+		}
+	}
+	return c.run()
+}
+
+func applyFix(c *check, res *result, results map[string]*result) *result {
+	task := fmt.Sprintf("%v: %v", c.title, res.fixTitle)
+	log.Run(task)
+	if err := res.fix(); err != nil {
+		errs.LogError(task, err)
+		return res
+	}
+	return runCheck(c, results)
+}
+
+func printResult(c *check, res *result) {
+	msg := fmt.Sprintf("%v: %v", c.title, res.message)
+	switch res.status {
+	case statusPass:
+		log.Ok(msg)
+	case statusWarn:
+		log.Warn(msg)
+	case statusFail:
+		log.Fail(msg)
+	case statusSkip:
+		log.V(log.Info).Skip(msg)
+	default:
+		panic(errors.New("unknown check status: " + string(res.status)))
+	}
+
+	if res.status == statusPass || res.status == statusSkip {
+		return
+	}
+	if res.hint != "" {
+		for _, line := range strings.Split(strings.TrimSpace(res.hint), "\n") {
+			log.NewLine(line)
+		}
+	}
+	if res.fix != nil && !flagFix {
+		log.NewLine(fmt.Sprintf("(fixable with -fix: %v)", res.fixTitle))
+	}
+}
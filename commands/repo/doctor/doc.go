@@ -0,0 +1,30 @@
+/*
+Check the repository setup.
+
+  salsaflow repo doctor [-fix]
+
+Description
+
+Check whether the repository is set up correctly for SalsaFlow.
+
+The following is checked:
+
+  * the git version installed is supported
+  * the repository is initialised by the running version of SalsaFlow
+  * the configuration is complete and valid
+  * salsaflow.remote is set and the remote exists
+  * the core branches exist and track the remote branches
+  * the SalsaFlow git hooks are installed and up to date
+  * the version scripts are available for the current platform
+  * the issue tracker is accessible using the credentials configured,
+    the same for the code review tool and the release notes manager
+    in case the modules support it
+
+Every check either passes, issues a warning or fails, the warnings
+and failures are accompanied by a hint on how to fix the issue.
+The checks depending on a check that failed are skipped.
+
+Some issues can be fixed automatically. Use -fix to do so.
+The checks are run again once the fix is applied.
+*/
+package doctorCmd
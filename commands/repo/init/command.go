@@ -1,9 +1,6 @@
 package initCmd
 
 import (
-	// Stdlib
-	"os"
-
 	// Internal
 	"github.com/salsaflow/salsaflow/app"
 	"github.com/salsaflow/salsaflow/app/appflags"
@@ -36,8 +33,7 @@ func init() {
 
 func run(cmd *gocli.Command, args []string) {
 	if len(args) != 0 {
-		cmd.Usage()
-		os.Exit(2)
+		app.ExitWithUsage(cmd)
 	}
 
 	if err := app.Init(flagForce); err != nil {
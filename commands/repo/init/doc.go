@@ -22,7 +22,7 @@ This command goes through the following steps:
   2. Make sure that the git hooks are installed (install them if not).
      This is done by running the hook executables with special flags.
      Only the SF git hooks know these flags.
-  3. Check the git version being used, SF requires 1.9.0+.
+  3. Check the git version being used, SF requires 2.5.0+.
   4. Perform other registered checks, e.g. the Review Board module
      checks that RBTools 0.6.x are installed.
   5. Mark the repository as initialised.
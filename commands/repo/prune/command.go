@@ -4,7 +4,6 @@ import (
 	// Stdlib
 	"errors"
 	"fmt"
-	"os"
 	"strings"
 
 	// Internal
@@ -93,8 +92,7 @@ func allowedStoryStates() map[common.StoryState]struct{} {
 
 func run(cmd *gocli.Command, args []string) {
 	if len(args) != 0 {
-		cmd.Usage()
-		os.Exit(2)
+		app.ExitWithUsage(cmd)
 	}
 
 	app.InitOrDie()
@@ -0,0 +1,24 @@
+package skeletonCmd
+
+import (
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/commands/repo/skeleton/update"
+
+	"gopkg.in/tchap/gocli.v2"
+)
+
+var Command = &gocli.Command{
+	UsageLine: "skeleton",
+	Short:     "manage the repository skeleton",
+	Long: `
+  Manage the skeleton the repository was bootstrapped from. See the subcommands.
+	`,
+}
+
+func init() {
+	// Register global flags.
+	appflags.RegisterGlobalFlags(&Command.Flags)
+
+	// Register subcommands.
+	Command.MustRegisterSubcommand(updateCmd.Command)
+}
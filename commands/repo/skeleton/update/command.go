@@ -0,0 +1,290 @@
+package updateCmd
+
+import (
+	// Stdlib
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app"
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/config"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/fileutil"
+	flags "github.com/salsaflow/salsaflow/flag"
+	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/prompt"
+	"github.com/salsaflow/salsaflow/shell"
+	"github.com/salsaflow/salsaflow/skeleton"
+
+	// Other
+	"gopkg.in/tchap/gocli.v2"
+)
+
+var Command = &gocli.Command{
+	UsageLine: `
+  update [-skeleton=SKELETON | -ref=REF] [-var KEY=VALUE ...] [-dry_run]`,
+	Short: "re-pour the repository skeleton",
+	Long: `
+  Update the local configuration directory to the current version
+  of the skeleton the repository was bootstrapped from.
+
+  The skeleton recorded in the local configuration file is fetched again
+  and poured into the local configuration directory. The changes being made
+  are printed as a diff. Use -dry_run to only print the changes.
+
+  The skeleton pinned to a branch is updated to the current branch head.
+  Use -ref to pin the skeleton to another ref, or -skeleton to switch
+  to another skeleton altogether. -skeleton accepts the same SOURCE[@REF]
+  string as 'repo bootstrap'.
+
+  The template variables recorded in the local configuration file are used
+  again to render the skeleton templates. -var can be used to change them.
+  The user is prompted for the values of the variables not known yet.
+
+  The files removed from the skeleton are not deleted from the local
+  configuration directory.
+	`,
+	Action: run,
+}
+
+var (
+	flagDryRun   bool
+	flagRef      string
+	flagSkeleton string
+	flagVars     = flags.NewKeyValueFlag()
+)
+
+func init() {
+	// Register flags.
+	Command.Flags.BoolVar(&flagDryRun, "dry_run", flagDryRun,
+		"print the changes, do not write the files")
+	Command.Flags.StringVar(&flagRef, "ref", flagRef,
+		"pin the skeleton to the given branch, tag or commit")
+	Command.Flags.StringVar(&flagSkeleton, "skeleton", flagSkeleton,
+		"switch to the given skeleton")
+	Command.Flags.Var(flagVars, "var",
+		"set skeleton template variable; KEY=VALUE, can be repeated")
+
+	// Register global flags.
+	appflags.RegisterGlobalFlags(&Command.Flags)
+}
+
+func run(cmd *gocli.Command, args []string) {
+	if len(args) != 0 {
+		app.ExitWithUsage(cmd)
+	}
+
+	app.InitLogging()
+
+	defer prompt.RecoverCancel()
+
+	if err := runMain(cmd); err != nil {
+		errs.Fatal(err)
+	}
+}
+
+func runMain(cmd *gocli.Command) error {
+	// Validate CL flags.
+	task := "Check the command line flags"
+	if flagSkeleton != "" && flagRef != "" {
+		cmd.Usage()
+		return errs.NewError(
+			task, errors.New("-skeleton and -ref cannot be used together"))
+	}
+
+	// Read the skeleton record.
+	task = "Read the skeleton record"
+	local, err := config.ReadLocalConfig()
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+	record := local.Skeleton
+	if record == nil && flagSkeleton == "" {
+		hint := `
+No skeleton is recorded in the local configuration file.
+
+Use -skeleton to specify the skeleton to be used,
+or run 'repo bootstrap -skeleton=SKELETON -skeleton_only'.
+
+`
+		return errs.NewErrorWithHint(task, errors.New("no skeleton recorded"), hint)
+	}
+
+	// Get the skeleton source.
+	task = "Parse the skeleton specification"
+	var src *skeleton.Source
+	if flagSkeleton != "" {
+		src, err = skeleton.ParseSource(flagSkeleton)
+	} else {
+		src, err = skeleton.ParseSource(record.Source)
+		if err == nil {
+			src.Ref = record.Ref
+			if flagRef != "" {
+				src.Ref = flagRef
+			}
+		}
+	}
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+
+	// Fetch the skeleton.
+	task = fmt.Sprintf("Get or update skeleton '%v'", src)
+	log.Run(task)
+	snap, err := skeleton.Fetch(src)
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+
+	// Render the skeleton files.
+	task = "Render the skeleton files"
+	known := make(map[string]string)
+	if record != nil {
+		for k, v := range record.Variables {
+			known[k] = v
+		}
+	}
+	for _, kv := range flagVars.Values {
+		known[kv.Key] = kv.Value
+	}
+	vars, err := snap.Variables(known)
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+	files, err := snap.Files(vars)
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+
+	// Print the changes.
+	task = "Print the skeleton changes"
+	localConfigDir, err := config.LocalConfigDirectoryAbsolutePath()
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+	if record != nil {
+		log.Log(fmt.Sprintf("Skeleton commit: %v -> %v",
+			displayCommit(record.Commit), displayCommit(snap.Commit)))
+	}
+	modified, err := printDiff(files, localConfigDir)
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+
+	newRecord := snap.Record(vars)
+	if !modified && record != nil && sameRecord(record, newRecord) {
+		log.Log("The skeleton is up to date, nothing to update")
+		return nil
+	}
+	if flagDryRun {
+		return nil
+	}
+
+	// Pour the skeleton.
+	task = "Pour the skeleton into the local configuration directory"
+	log.Run(task)
+	act, err := skeleton.Pour(files, localConfigDir)
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+	if err := skeleton.WriteRecord(newRecord); err != nil {
+		if ex := act.Rollback(); ex != nil {
+			errs.Log(ex)
+		}
+		return err
+	}
+
+	log.Warn("Local configuration directory modified, please commit it.")
+	return nil
+}
+
+// printDiff pours the skeleton into a copy of the local configuration directory
+// and prints the unified diff between the copy and the original using git.
+func printDiff(files []*skeleton.File, localConfigDir string) (modified bool, err error) {
+	dir, err := ioutil.TempDir("", "salsaflow-skeleton-update-")
+	if err != nil {
+		return false, err
+	}
+	defer os.RemoveAll(dir)
+
+	// Copy the files affected into both before and after.
+	var (
+		beforeDir = filepath.Join(dir, "before")
+		afterDir  = filepath.Join(dir, "after")
+	)
+	for _, d := range []string{beforeDir, afterDir} {
+		if err := os.Mkdir(d, 0700); err != nil {
+			return false, err
+		}
+		for _, file := range files {
+			src := filepath.Join(localConfigDir, file.Path)
+			if _, err := os.Stat(src); err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return false, err
+			}
+			dst := filepath.Join(d, file.Path)
+			if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+				return false, err
+			}
+			if err := fileutil.CopyFile(src, dst); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	// Pour the skeleton into after.
+	if _, err := skeleton.Pour(files, afterDir); err != nil {
+		return false, err
+	}
+
+	// git diff --no-index exits with 1 when there are differences.
+	stdout, stderr, err := shell.Run(
+		"git", "diff", "--no-index", "--no-prefix", "--", beforeDir, afterDir)
+	if err != nil && stdout.Len() == 0 {
+		return false, errs.NewErrorWithHint("Run 'git diff'", err, stderr.String())
+	}
+	if stdout.Len() == 0 {
+		return false, nil
+	}
+
+	diff := stdout.String()
+	diff = strings.Replace(diff, strings.TrimPrefix(beforeDir, "/"), config.LocalConfigDirname, -1)
+	diff = strings.Replace(diff, strings.TrimPrefix(afterDir, "/"), config.LocalConfigDirname, -1)
+	fmt.Println()
+	fmt.Print(diff)
+	fmt.Println()
+	return true, nil
+}
+
+func sameRecord(a, b *config.SkeletonRecord) bool {
+	if a.Source != b.Source || a.Ref != b.Ref || a.Commit != b.Commit {
+		return false
+	}
+	if len(a.Variables) != len(b.Variables) {
+		return false
+	}
+	for k, v := range a.Variables {
+		if bv, ok := b.Variables[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
+func displayCommit(commit string) string {
+	switch {
+	case commit == "":
+		return "unknown"
+	case len(commit) > 8:
+		return commit[:8]
+	default:
+		return commit
+	}
+}
@@ -0,0 +1,27 @@
+/*
+Re-pour the repository skeleton.
+
+  salsaflow repo skeleton update [-skeleton=SKELETON | -ref=REF] [-var KEY=VALUE ...] [-dry_run]
+
+Description
+
+Update the local configuration directory to the current version
+of the skeleton the repository was bootstrapped from.
+
+The skeleton recorded in the local configuration file is fetched again
+and poured into the local configuration directory. The changes being made
+are printed as a diff. Use -dry_run to only print the changes.
+
+The skeleton pinned to a branch is updated to the current branch head.
+Use -ref to pin the skeleton to another ref, or -skeleton to switch
+to another skeleton altogether. -skeleton accepts the same SOURCE[@REF]
+string as 'repo bootstrap'.
+
+The template variables recorded in the local configuration file are used
+again to render the skeleton templates. -var can be used to change them.
+The user is prompted for the values of the variables not known yet.
+
+The files removed from the skeleton are not deleted from the local
+configuration directory.
+*/
+package updateCmd
@@ -0,0 +1,127 @@
+package approveCmd
+
+import (
+	// Stdlib
+	"errors"
+	"fmt"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app"
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/git"
+	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/modules"
+	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/output"
+
+	// Other
+	"gopkg.in/tchap/gocli.v2"
+)
+
+var Command = &gocli.Command{
+	UsageLine: "approve [-review=RRID] [COMMIT...]",
+	Short:     "mark commits as reviewed",
+	Long: `
+  Mark the given commits as reviewed.
+
+  The review request is specified using -review. In case it is not set,
+  the review request the first commit is being reviewed in is used.
+  When no commit is specified, all the commits in the review request
+  specified by -review are marked as reviewed.
+
+  Once all the commits are reviewed and all the review blockers are fixed,
+  the review request is closed and the associated story is marked
+  as reviewed in the issue tracker. The story is left alone unless
+  it is being implemented or implemented.
+	`,
+	Action: run,
+}
+
+var flagReview string
+
+func init() {
+	// Register flags.
+	Command.Flags.StringVar(&flagReview, "review", flagReview,
+		"review request containing the commits")
+
+	// Register global flags.
+	appflags.RegisterGlobalFlags(&Command.Flags)
+}
+
+func run(cmd *gocli.Command, args []string) {
+	if len(args) == 0 && flagReview == "" {
+		app.ExitWithUsage(cmd)
+	}
+
+	app.InitOrDie()
+
+	if err := runMain(args); err != nil {
+		errs.Fatal(err)
+	}
+}
+
+func runMain(revisions []string) error {
+	// Resolve the commit SHAs.
+	commitSHAs := make([]string, 0, len(revisions))
+	for _, revision := range revisions {
+		task := fmt.Sprintf("Resolve revision '%v'", revision)
+		sha, err := git.CommitHexsha(revision)
+		if err != nil {
+			return errs.NewError(task, err)
+		}
+		commitSHAs = append(commitSHAs, sha)
+	}
+
+	// Get the code review tool.
+	tool, err := modules.GetCodeReviewTool()
+	if err != nil {
+		return err
+	}
+	approver, ok := tool.(common.ReviewApprover)
+	if !ok {
+		task := "Get the review approver"
+		return errs.NewError(task, errors.New(
+			"review approval not supported by the active code review module"))
+	}
+
+	// Approve the commits.
+	rr, err := approver.ApproveCommits(flagReview, commitSHAs)
+	if err != nil {
+		return err
+	}
+
+	output.SetValue("review_request", rr.Id)
+	output.AddURL("review_request", rr.URL)
+
+	if !rr.Closed {
+		log.Log(fmt.Sprintf(
+			"Review request %v still open (%v commits not reviewed, %v blockers open)",
+			rr.Id, rr.UnreviewedCommits(), rr.OpenBlockers()))
+		return nil
+	}
+
+	log.Log(fmt.Sprintf("Review request %v closed", rr.Id))
+
+	// Mark the story as reviewed.
+	if rr.StoryTag == "" {
+		return nil
+	}
+	return markStoryAsReviewed(rr.StoryTag)
+}
+
+func markStoryAsReviewed(storyTag string) error {
+	tracker, err := modules.GetIssueTracker()
+	if err != nil {
+		return err
+	}
+
+	story, err := common.MarkStoryAsReviewed(tracker, storyTag)
+	if err != nil {
+		return err
+	}
+	if story != nil {
+		output.AddStory(story)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+/*
+Mark commits as reviewed.
+
+  salsaflow review approve [-review=RRID] [COMMIT...]
+
+Description
+
+Mark the given commits as reviewed.
+
+The review request is specified using -review. In case it is not set,
+the review request the first commit is being reviewed in is used.
+When no commit is specified, all the commits in the review request
+specified by -review are marked as reviewed.
+
+Once all the commits are reviewed and all the review blockers are fixed,
+the review request is closed and the associated story is marked
+as reviewed in the issue tracker. The story is left alone unless
+it is being implemented or implemented.
+*/
+package approveCmd
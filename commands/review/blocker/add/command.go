@@ -0,0 +1,101 @@
+package addCmd
+
+import (
+	// Stdlib
+	"errors"
+	"fmt"
+	"strings"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app"
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/git"
+	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/modules"
+	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/output"
+
+	// Other
+	"gopkg.in/tchap/gocli.v2"
+)
+
+var Command = &gocli.Command{
+	UsageLine: "add COMMIT SUMMARY",
+	Short:     "open a review blocker",
+	Long: `
+  Open a review blocker for the given commit.
+
+  The review request the commit was posted into is found, then a comment
+  containing SUMMARY is created for the commit and the blocker is added
+  into the blocker list of the review request.
+
+  COMMIT can be any git revision resolving to a commit.
+  SUMMARY must be a single line of text.
+	`,
+	Action: run,
+}
+
+func init() {
+	// Register global flags.
+	appflags.RegisterGlobalFlags(&Command.Flags)
+}
+
+func run(cmd *gocli.Command, args []string) {
+	if len(args) != 2 {
+		app.ExitWithUsage(cmd)
+	}
+
+	app.InitOrDie()
+
+	if err := runMain(args[0], args[1]); err != nil {
+		errs.Fatal(err)
+	}
+}
+
+func runMain(revision, summary string) error {
+	// Make sure the summary is valid.
+	task := "Check the review blocker summary"
+	summary = strings.TrimSpace(summary)
+	switch {
+	case summary == "":
+		return errs.NewError(task, errors.New("the summary is empty"))
+	case strings.ContainsAny(summary, "\r\n"):
+		return errs.NewError(task, errors.New("the summary must be a single line"))
+	}
+
+	// Resolve the commit SHA.
+	task = fmt.Sprintf("Resolve revision '%v'", revision)
+	commitSHA, err := git.CommitHexsha(revision)
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+
+	// Get the code review tool.
+	tool, err := modules.GetCodeReviewTool()
+	if err != nil {
+		return err
+	}
+	manager, ok := tool.(common.ReviewBlockerManager)
+	if !ok {
+		task := "Get the review blocker manager"
+		return errs.NewError(task, fmt.Errorf(
+			"review blockers not supported by the active code review module"))
+	}
+
+	// Open the blocker.
+	rr, blocker, err := manager.AddReviewBlocker(commitSHA, summary)
+	if err != nil {
+		return err
+	}
+
+	log.Log(fmt.Sprintf(
+		"Review blocker %v opened in review request %v", blocker.Number, rr.Id))
+	output.SetValue("review_request", rr.Id)
+	output.SetValue("blocker", fmt.Sprint(blocker.Number))
+	output.AddURL("review_request", rr.URL)
+	if blocker.CommentURL != "" {
+		output.AddURL("review_blocker", blocker.CommentURL)
+	}
+	return nil
+}
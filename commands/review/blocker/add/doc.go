@@ -0,0 +1,17 @@
+/*
+Open a review blocker.
+
+  salsaflow review blocker add COMMIT SUMMARY
+
+Description
+
+Open a review blocker for the given commit.
+
+The review request the commit was posted into is found, then a comment
+containing SUMMARY is created for the commit and the blocker is added
+into the blocker list of the review request.
+
+COMMIT can be any git revision resolving to a commit.
+SUMMARY must be a single line of text.
+*/
+package addCmd
@@ -0,0 +1,28 @@
+package blockerCmd
+
+import (
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/commands/review/blocker/add"
+	"github.com/salsaflow/salsaflow/commands/review/blocker/fix"
+	"github.com/salsaflow/salsaflow/commands/review/blocker/list"
+
+	"gopkg.in/tchap/gocli.v2"
+)
+
+var Command = &gocli.Command{
+	UsageLine: "blocker",
+	Short:     "manage review blockers",
+	Long: `
+  Open, list and resolve review blockers. See the subcommands.
+	`,
+}
+
+func init() {
+	// Register global flags.
+	appflags.RegisterGlobalFlags(&Command.Flags)
+
+	// Register subcommands.
+	Command.MustRegisterSubcommand(addCmd.Command)
+	Command.MustRegisterSubcommand(fixCmd.Command)
+	Command.MustRegisterSubcommand(listCmd.Command)
+}
@@ -0,0 +1,113 @@
+package fixCmd
+
+import (
+	// Stdlib
+	"errors"
+	"fmt"
+	"strconv"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app"
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/git"
+	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/modules"
+	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/output"
+
+	// Other
+	"gopkg.in/tchap/gocli.v2"
+)
+
+var Command = &gocli.Command{
+	UsageLine: "fix [-review=RRID] [-fixed_by=COMMIT] N",
+	Short:     "mark a review blocker as fixed",
+	Long: `
+  Mark review blocker N as fixed.
+
+  The review request is specified using -review. In case it is not set,
+  the review request for the story the -fixed_by commit is associated with
+  is used, or the review request the commit was posted into in case
+  the commit has no Story-Id tag. At least one of the two flags must be set.
+
+  In case -fixed_by is set, the fixing commit is mentioned in the comment
+  added to the review request.
+	`,
+	Action: run,
+}
+
+var (
+	flagFixedBy string
+	flagReview  string
+)
+
+func init() {
+	// Register flags.
+	Command.Flags.StringVar(&flagFixedBy, "fixed_by", flagFixedBy,
+		"commit fixing the review blocker")
+	Command.Flags.StringVar(&flagReview, "review", flagReview,
+		"review request containing the review blocker")
+
+	// Register global flags.
+	appflags.RegisterGlobalFlags(&Command.Flags)
+}
+
+func run(cmd *gocli.Command, args []string) {
+	if len(args) != 1 {
+		app.ExitWithUsage(cmd)
+	}
+
+	app.InitOrDie()
+
+	if err := runMain(args[0]); err != nil {
+		errs.Fatal(err)
+	}
+}
+
+func runMain(blockerArg string) error {
+	// Check the arguments.
+	task := "Check the command line arguments"
+	blockerNumber, err := strconv.Atoi(blockerArg)
+	if err != nil || blockerNumber < 1 {
+		return errs.NewError(task, fmt.Errorf("not a valid blocker number: %v", blockerArg))
+	}
+	if flagReview == "" && flagFixedBy == "" {
+		return errs.NewError(task, errors.New("either -review or -fixed_by must be set"))
+	}
+
+	// Resolve the fixing commit SHA.
+	var fixedBy string
+	if flagFixedBy != "" {
+		task := fmt.Sprintf("Resolve revision '%v'", flagFixedBy)
+		fixedBy, err = git.CommitHexsha(flagFixedBy)
+		if err != nil {
+			return errs.NewError(task, err)
+		}
+	}
+
+	// Get the code review tool.
+	tool, err := modules.GetCodeReviewTool()
+	if err != nil {
+		return err
+	}
+	manager, ok := tool.(common.ReviewBlockerManager)
+	if !ok {
+		task := "Get the review blocker manager"
+		return errs.NewError(task, fmt.Errorf(
+			"review blockers not supported by the active code review module"))
+	}
+
+	// Fix the blocker.
+	rr, blocker, err := manager.FixReviewBlocker(flagReview, blockerNumber, fixedBy)
+	if err != nil {
+		return err
+	}
+
+	log.Log(fmt.Sprintf(
+		"Review blocker %v in review request %v marked as fixed", blocker.Number, rr.Id))
+	output.SetValue("review_request", rr.Id)
+	output.SetValue("blocker", fmt.Sprint(blocker.Number))
+	output.AddURL("review_request", rr.URL)
+	return nil
+}
@@ -0,0 +1,18 @@
+/*
+Mark a review blocker as fixed.
+
+  salsaflow review blocker fix [-review=RRID] [-fixed_by=COMMIT] N
+
+Description
+
+Mark review blocker N as fixed.
+
+The review request is specified using -review. In case it is not set,
+the review request for the story the -fixed_by commit is associated with
+is used, or the review request the commit was posted into in case
+the commit has no Story-Id tag. At least one of the two flags must be set.
+
+In case -fixed_by is set, the fixing commit is mentioned in the comment
+added to the review request.
+*/
+package fixCmd
@@ -0,0 +1,139 @@
+package listCmd
+
+import (
+	// Stdlib
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app"
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/modules"
+	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/output"
+
+	// Other
+	"gopkg.in/tchap/gocli.v2"
+)
+
+var Command = &gocli.Command{
+	UsageLine: "list [-all] [STORY]",
+	Short:     "list review blockers",
+	Long: `
+  List the review blockers that are still open.
+
+  In case STORY is specified, only the review request for the given story
+  is checked. Otherwise all open review requests are checked.
+
+  Use -all to list the blockers that have been fixed already as well.
+	`,
+	Action: run,
+}
+
+var flagAll bool
+
+func init() {
+	// Register flags.
+	Command.Flags.BoolVar(&flagAll, "all", flagAll,
+		"list the fixed review blockers as well")
+
+	// Register global flags.
+	appflags.RegisterGlobalFlags(&Command.Flags)
+}
+
+func run(cmd *gocli.Command, args []string) {
+	if len(args) > 1 {
+		app.ExitWithUsage(cmd)
+	}
+
+	app.InitOrDie()
+
+	var storyId string
+	if len(args) == 1 {
+		storyId = args[0]
+	}
+
+	if err := runMain(storyId); err != nil {
+		errs.Fatal(err)
+	}
+}
+
+func runMain(storyId string) error {
+	// Get the code review tool.
+	tool, err := modules.GetCodeReviewTool()
+	if err != nil {
+		return err
+	}
+	manager, ok := tool.(common.ReviewBlockerManager)
+	if !ok {
+		task := "Get the review blocker manager"
+		return errs.NewError(task, fmt.Errorf(
+			"review blockers not supported by the active code review module"))
+	}
+
+	// Get the blockers.
+	rrs, err := manager.ListReviewBlockers(storyId)
+	if err != nil {
+		return err
+	}
+
+	// Print the blockers.
+	fmt.Println()
+	var printed int
+	for _, rr := range rrs {
+		blockers := filterBlockers(rr.Blockers)
+		if len(blockers) == 0 {
+			continue
+		}
+		if printed != 0 {
+			fmt.Println()
+		}
+		printed++
+
+		if err := printBlockers(os.Stdout, rr, blockers); err != nil {
+			return err
+		}
+		output.AddURL("review_request", rr.URL)
+	}
+	if printed == 0 {
+		fmt.Println("No review blockers found.")
+	}
+	fmt.Println()
+	return nil
+}
+
+func filterBlockers(blockers []*common.ReviewBlocker) []*common.ReviewBlocker {
+	if flagAll {
+		return blockers
+	}
+	open := make([]*common.ReviewBlocker, 0, len(blockers))
+	for _, blocker := range blockers {
+		if !blocker.Fixed {
+			open = append(open, blocker)
+		}
+	}
+	return open
+}
+
+func printBlockers(writer io.Writer, rr *common.ReviewRequest, blockers []*common.ReviewBlocker) error {
+	fmt.Fprintf(writer, "%v (%v)\n  %v\n\n", rr.Title, rr.Id, rr.URL)
+
+	tw := tabwriter.NewWriter(writer, 0, 8, 4, '\t', 0)
+	fmt.Fprintln(tw, "  N\tFixed\tCommit SHA\tSummary")
+	fmt.Fprintln(tw, "  =\t=====\t==========\t=======")
+	for _, blocker := range blockers {
+		fixed := "no"
+		if blocker.Fixed {
+			fixed = "yes"
+		}
+		sha := blocker.CommitSHA
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		fmt.Fprintf(tw, "  %v\t%v\t%v\t%v\n", blocker.Number, fixed, sha, blocker.Summary)
+	}
+	return tw.Flush()
+}
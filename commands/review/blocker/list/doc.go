@@ -0,0 +1,15 @@
+/*
+List review blockers.
+
+  salsaflow review blocker list [-all] [STORY]
+
+Description
+
+List the review blockers that are still open.
+
+In case STORY is specified, only the review request for the given story
+is checked. Otherwise all open review requests are checked.
+
+Use -all to list the blockers that have been fixed already as well.
+*/
+package listCmd
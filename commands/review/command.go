@@ -2,7 +2,13 @@ package reviewCmd
 
 import (
 	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/commands/review/approve"
+	"github.com/salsaflow/salsaflow/commands/review/blocker"
 	"github.com/salsaflow/salsaflow/commands/review/post"
+	"github.com/salsaflow/salsaflow/commands/review/show"
+	"github.com/salsaflow/salsaflow/commands/review/stale"
+	"github.com/salsaflow/salsaflow/commands/review/stats"
+	"github.com/salsaflow/salsaflow/commands/review/status"
 
 	"gopkg.in/tchap/gocli.v2"
 )
@@ -20,5 +26,11 @@ func init() {
 	appflags.RegisterGlobalFlags(&Command.Flags)
 
 	// Register subcommands.
+	Command.MustRegisterSubcommand(approveCmd.Command)
+	Command.MustRegisterSubcommand(blockerCmd.Command)
 	Command.MustRegisterSubcommand(postCmd.Command)
+	Command.MustRegisterSubcommand(showCmd.Command)
+	Command.MustRegisterSubcommand(staleCmd.Command)
+	Command.MustRegisterSubcommand(statsCmd.Command)
+	Command.MustRegisterSubcommand(statusCmd.Command)
 }
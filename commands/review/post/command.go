@@ -19,7 +19,9 @@ var Command = &gocli.Command{
   post [-fixes=RRID] [-no_fetch]
        [-no_rebase] [-no_merge] [-merge_no_ff]
        [-ask_once] [-pick]
-       [-reviewer=REVIEWER] [-open] -parent=BRANCH`,
+       [-reviewer=REVIEWER] [-open] -parent=BRANCH
+
+  post [-no_fetch] [-reviewer=REVIEWER] [-open] -stack -parent=BRANCH`,
 	Short: "post code review requests",
 	Long: `
   Post a code review request for each commit specified.
@@ -44,8 +46,23 @@ var Command = &gocli.Command{
   To make sure a merge commit is created, see -merge_no_ff, which ensures
   that git merge is always run with --no-ff flag.
 
+  When -stack is set together with the parent branch, the current branch
+  is treated as the top of a stack of story branches, every branch being
+  based on the branch below it. The stack is formed by the local branches
+  pointing into BRANCH..HEAD. Every branch must contain commits associated
+  with a single story. The branches are pushed, but neither rebased nor merged,
+  and a review request is posted for every branch, linked to the review request
+  for the branch below it.
+
+  When the commits that were posted already are rewritten, e.g. by a rebase,
+  the rewritten commits replace the original commits in the review request,
+  keeping the review state, as long as they share the same Change-Id tag.
+
   When no parent branch nor the revision is specified, the last commit
   on the current branch is selected and posted alone into the code review tool.
+
+  The pre_review_post and post_review_post lifecycle hooks are run in case
+  the relevant scripts are available in .salsaflow/scripts.
   `,
 	Action: run,
 }
@@ -61,6 +78,7 @@ var (
 	flagParent     string
 	flagPick       bool
 	flagReviewer   string
+	flagStack      bool
 	flagStoryIdTag string
 )
 
@@ -86,6 +104,8 @@ func init() {
 		"pick only some of the selected commits for review")
 	Command.Flags.StringVar(&flagReviewer, "reviewer", flagReviewer,
 		"reviewer to assign to the newly created review requests")
+	Command.Flags.BoolVar(&flagStack, "stack", flagStack,
+		"post the stack of story branches between the parent branch and HEAD")
 	Command.Flags.StringVar(&flagStoryIdTag, "story_tag", flagStoryIdTag,
 		"Story-Id tag to use when posting commit missing the tag")
 
@@ -94,6 +114,10 @@ func init() {
 }
 
 func run(cmd *gocli.Command, args []string) {
+	if flagStack && (flagParent == "" || len(args) != 0) {
+		app.ExitWithUsage(cmd)
+	}
+
 	app.InitOrDie()
 
 	defer prompt.RecoverCancel()
@@ -102,6 +126,8 @@ func run(cmd *gocli.Command, args []string) {
 	switch {
 	case len(args) != 0:
 		err = postRevisions(args...)
+	case flagStack:
+		err = postStack(flagParent)
 	case flagParent != "":
 		err = postBranch(flagParent)
 	default:
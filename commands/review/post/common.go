@@ -23,6 +23,7 @@ import (
 	"github.com/salsaflow/salsaflow/modules/common"
 	"github.com/salsaflow/salsaflow/prompt"
 	"github.com/salsaflow/salsaflow/prompt/storyprompt"
+	"github.com/salsaflow/salsaflow/scripts"
 )
 
 var ErrNoCommits = errors.New("no commits selected for code review")
@@ -127,12 +128,6 @@ func rewriteCommits(commits []*git.Commit, firstMissingOffset int) ([]*git.Commi
 		return nil, errs.NewError(storiesTask, err)
 	}
 
-	task := "Fetch the user record from the issue tracker"
-	me, err := tracker.CurrentUser()
-	if err != nil {
-		return nil, errs.NewError(task, err)
-	}
-
 	stories, err := tracker.ReviewableStories()
 	if err != nil {
 		return nil, errs.NewError(storiesTask, err)
@@ -144,28 +139,30 @@ func rewriteCommits(commits []*git.Commit, firstMissingOffset int) ([]*git.Commi
 	}
 
 	// Show only the stories owned by the current user.
-	// Note: Go sucks here, badly.
-	filterStories := func(stories []common.Story, filter func(common.Story) bool) []common.Story {
+	task := "Fetch the user record from the issue tracker"
+	isMine := common.CurrentUserAssignee()
+	filterMine := func(stories []common.Story) ([]common.Story, error) {
 		ss := make([]common.Story, 0, len(stories))
 		for _, story := range stories {
-			if filter(story) {
+			mine, err := isMine(story)
+			if err != nil {
+				return nil, errs.NewError(task, err)
+			}
+			if mine {
 				ss = append(ss, story)
 			}
 		}
-		return ss
+		return ss, nil
 	}
 
-	mine := func(story common.Story) bool {
-		for _, assignee := range story.Assignees() {
-			if assignee.Id() == me.Id() {
-				return true
-			}
-		}
-		return false
+	stories, err = filterMine(stories)
+	if err != nil {
+		return nil, err
+	}
+	reviewedStories, err = filterMine(reviewedStories)
+	if err != nil {
+		return nil, err
 	}
-
-	stories = filterStories(stories, mine)
-	reviewedStories = filterStories(reviewedStories, mine)
 
 	// Tell the user what is happening.
 	log.Run("Prepare a temporary branch to rewrite commit messages")
@@ -357,6 +354,12 @@ func postCommitsForReview(commits []*git.Commit) error {
 		return errs.NewError(task, err)
 	}
 
+	// Run the pre_review_post hook.
+	hookCtx := newHookContext(ctxs)
+	if err := scripts.RunHook(scripts.HookPreReviewPost, hookCtx); err != nil {
+		return err
+	}
+
 	// Mark the stories as implemented, potentially.
 	task = "Mark the stories as implemented, optionally"
 	implemented, act, err := implementedDialog(ctxs)
@@ -371,9 +374,44 @@ func postCommitsForReview(commits []*git.Commit) error {
 		return errs.NewError(task, err)
 	}
 
+	// Run the post_review_post hook.
+	scripts.RunPostHook(scripts.HookPostReviewPost, hookCtx)
 	return nil
 }
 
+func newHookContext(ctxs []*common.ReviewContext) *scripts.HookContext {
+	hookCtx := scripts.NewHookContext()
+	if flagParent != "" {
+		hookCtx.SetBranch("parent", flagParent)
+	}
+
+	seen := make(map[string]struct{}, len(ctxs))
+	for _, ctx := range ctxs {
+		commit := ctx.Commit
+		hookCtx.AddCommit(commit.SHA, commit.MessageTitle, commit.StoryIdTag)
+
+		story := ctx.Story
+		if story == nil {
+			continue
+		}
+		if _, ok := seen[story.Id()]; ok {
+			continue
+		}
+		seen[story.Id()] = struct{}{}
+		hookCtx.AddStory(story)
+	}
+
+	hookCtx.Load = func(ctx *scripts.HookContext) error {
+		currentBranch, err := gitutil.CurrentBranch()
+		if err != nil {
+			return err
+		}
+		ctx.SetBranch("current", currentBranch)
+		return nil
+	}
+	return hookCtx
+}
+
 func selectCommitsForReview(commits []*git.Commit) ([]*git.Commit, error) {
 	cs := make([]*git.Commit, 0, len(commits))
 
@@ -469,19 +507,7 @@ func sendReviewRequests(ctxs []*common.ReviewContext, implemented bool) error {
 	}
 
 	// Collect the command line flags into a map.
-	var postOpts = make(map[string]interface{}, 2)
-	if flagFixes != 0 {
-		postOpts["fixes"] = flagFixes
-	}
-	if flagReviewer != "" {
-		postOpts["reviewer"] = flagReviewer
-	}
-	if flagOpen {
-		postOpts["open"] = true
-	}
-	if implemented {
-		postOpts["implemented"] = true
-	}
+	postOpts := postOptions(implemented)
 
 	// Only post a single commit in case -parent is not being used.
 	// By definition it must be only a single commit anyway.
@@ -508,6 +534,25 @@ func sendReviewRequests(ctxs []*common.ReviewContext, implemented bool) error {
 	return nil
 }
 
+// postOptions collects the command line flags into a map
+// to be passed to the code review module.
+func postOptions(implemented bool) map[string]interface{} {
+	var postOpts = make(map[string]interface{}, 2)
+	if flagFixes != 0 {
+		postOpts["fixes"] = flagFixes
+	}
+	if flagReviewer != "" {
+		postOpts["reviewer"] = flagReviewer
+	}
+	if flagOpen {
+		postOpts["open"] = true
+	}
+	if implemented {
+		postOpts["implemented"] = true
+	}
+	return postOpts
+}
+
 func printFollowup() error {
 	task := "Print the followup message"
 	tool, err := modules.GetCodeReviewTool()
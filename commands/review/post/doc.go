@@ -8,6 +8,9 @@ Post review requests for the specified revisions.
                        [-no_rebase] [-no_merge] [-merge_no_ff]
                        [-ask_once] [-pick] [-reviewer=REVIEWER] [-open]
 
+  salsaflow review post -stack -parent=BRANCH [-no_fetch]
+                        [-reviewer=REVIEWER] [-open]
+
 See the command help page for more details in the flags and such.
 
 Description
@@ -26,6 +29,21 @@ The commits can be specified in multiple ways:
 The overall workflow is explained in more details at
 https://github.com/salsaflow/salsaflow/wiki/SalsaFlow-Workflow.
 
+Stacked Branches
+
+When -stack is set together with -parent, the current branch is treated as
+the top of a stack of story branches, every branch being based on the branch
+below it. The stack is formed by the local branches pointing into BRANCH..HEAD,
+and every branch must contain commits associated with a single story.
+The branches are pushed, but neither rebased nor merged, and a review request
+is posted for every branch, linked to the review request for the branch below.
+
+Rewritten Commits
+
+When the commits that were posted already are rewritten, e.g. by a rebase,
+the rewritten commits replace the original commits in the review request,
+keeping the review state, as long as they share the same Change-Id tag.
+
 Tags
 
 SalsaFlow will not allow you to post review requests without the selected
@@ -62,5 +80,13 @@ For the revision mode, the command goes through the following steps:
   1. Make sure the selected commits are associated with a story by the Story-Id
      tag. Fail in case the tag is not there and -story_tag is not specified.
   2. Post the selected commits for code review.
+
+Hooks
+
+The pre_review_post hook is run once the stories for the selected commits are
+fetched, before the stories are marked as implemented. The command is aborted in
+case the hook fails. The post_review_post hook is run once the review requests
+are posted. The hooks are only run in case the relevant scripts are available in
+.salsaflow/scripts, see the Scripts section of the SalsaFlow README.
 */
 package postCmd
@@ -0,0 +1,320 @@
+package postCmd
+
+import (
+	// Stdlib
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/action"
+	"github.com/salsaflow/salsaflow/asciiart"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/git"
+	"github.com/salsaflow/salsaflow/git/gitutil"
+	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/modules"
+	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/prompt"
+	"github.com/salsaflow/salsaflow/scripts"
+)
+
+// stackLayer represents a story branch in a stack of branches.
+type stackLayer struct {
+	branch  string
+	commits []*git.Commit
+}
+
+func postStack(parentBranch string) (err error) {
+	// Load the git-related config.
+	gitConfig, err := git.LoadConfig()
+	if err != nil {
+		return err
+	}
+	var (
+		remoteName = gitConfig.RemoteName
+	)
+
+	if !flagNoFetch {
+		// Fetch the remote repository.
+		task := "Fetch the remote repository"
+		log.Run(task)
+
+		if err := git.UpdateRemotes(remoteName); err != nil {
+			return errs.NewError(task, err)
+		}
+	}
+
+	// Make sure the parent branch is up to date.
+	task := fmt.Sprintf("Make sure reference '%v' is up to date", parentBranch)
+	log.Run(task)
+	if err := git.EnsureBranchSynchronized(parentBranch, remoteName); err != nil {
+		return errs.NewError(task, err)
+	}
+
+	// Get the branches forming the stack.
+	task = "Get the branches forming the stack"
+	log.Run(task)
+	layers, err := stackLayers(parentBranch)
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+
+	// Make sure the commits comply with the rules.
+	var commits []*git.Commit
+	for _, layer := range layers {
+		commits = append(commits, layer.commits...)
+	}
+	if err := ensureNoMergeCommits(commits); err != nil {
+		return err
+	}
+	if err := ensureSingleStoryPerLayer(layers); err != nil {
+		return err
+	}
+
+	// Prompt the user to confirm.
+	if err := promptUserToConfirmStack(layers); err != nil {
+		return err
+	}
+
+	// Push the branches so that the reviewers can access the commits.
+	for _, layer := range layers {
+		task := fmt.Sprintf("Get data on branch '%v'", layer.branch)
+		remoteExists, err := git.RemoteBranchExists(layer.branch, remoteName)
+		if err != nil {
+			return errs.NewError(task, err)
+		}
+		upToDate, err := git.IsBranchSynchronized(layer.branch, remoteName)
+		if err != nil {
+			return errs.NewError(task, err)
+		}
+		if !remoteExists || !upToDate {
+			if err := push(remoteName, layer.branch); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Post the review requests.
+	if err := postStackForReview(layers); err != nil {
+		return err
+	}
+
+	// In case there is no error, tell the user they can do next.
+	return printFollowup()
+}
+
+// stackLayers returns the local branches between the parent branch and HEAD,
+// ordered from the bottom of the stack, i.e. the branch closest to the parent branch.
+func stackLayers(parentBranch string) ([]*stackLayer, error) {
+	currentBranch, err := gitutil.CurrentBranch()
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, err := git.Run("for-each-ref", "--format=%(refname:short)", "refs/heads")
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect the branches that are contained in parent..HEAD,
+	// i.e. the branches that are reachable from HEAD and not from the parent branch.
+	type candidate struct {
+		branch string
+		hexsha string
+		depth  int
+	}
+	var candidates []*candidate
+	for _, branch := range strings.Fields(stdout.String()) {
+		if branch == parentBranch {
+			continue
+		}
+		depth, err := countCommits(parentBranch + ".." + branch)
+		if err != nil {
+			return nil, err
+		}
+		if depth == 0 {
+			continue
+		}
+		ahead, err := countCommits("HEAD.." + branch)
+		if err != nil {
+			return nil, err
+		}
+		if ahead != 0 {
+			// Not an ancestor of HEAD.
+			continue
+		}
+		hexsha, err := git.BranchHexsha(branch)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, &candidate{branch, hexsha, depth})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].depth < candidates[j].depth
+	})
+
+	// Build the layers, skipping the branches pointing to the same commit.
+	// The current branch wins in case it is one of them.
+	var (
+		layers []*stackLayer
+		base   = parentBranch
+	)
+	for _, c := range candidates {
+		if base == c.hexsha {
+			if c.branch == currentBranch {
+				layers[len(layers)-1].branch = c.branch
+			}
+			continue
+		}
+		commits, err := git.ShowCommitRange(base + ".." + c.branch)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, &stackLayer{c.branch, commits})
+		base = c.hexsha
+	}
+
+	if len(layers) == 0 {
+		return nil, ErrNoCommits
+	}
+	return layers, nil
+}
+
+func countCommits(revisionRange string) (int, error) {
+	stdout, err := git.Run("rev-list", "--count", revisionRange)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(stdout.String()))
+}
+
+// ensureSingleStoryPerLayer makes sure every branch in the stack
+// contains commits associated with exactly one story.
+func ensureSingleStoryPerLayer(layers []*stackLayer) error {
+	var (
+		task = "Make sure every branch in the stack belongs to a single story"
+		hint bytes.Buffer
+		err  error
+	)
+	fmt.Fprintln(&hint)
+	for _, layer := range layers {
+		tags := make(map[string]struct{}, 1)
+		for _, commit := range layer.commits {
+			tag := commit.StoryIdTag
+			if tag == "" || tag == git.StoryIdUnassignedTagValue {
+				fmt.Fprintf(&hint, "Commit %v on branch '%v' is not associated with any story\n",
+					commit.SHA, layer.branch)
+				err = errors.New("commit not associated with a story")
+				continue
+			}
+			tags[tag] = struct{}{}
+		}
+		if len(tags) > 1 {
+			fmt.Fprintf(&hint, "Branch '%v' contains commits associated with multiple stories\n",
+				layer.branch)
+			err = errors.New("branch associated with multiple stories")
+		}
+	}
+	fmt.Fprintln(&hint)
+	if err != nil {
+		return errs.NewErrorWithHint(task, err, hint.String())
+	}
+	return nil
+}
+
+func promptUserToConfirmStack(layers []*stackLayer) error {
+	// Tell the user what is going to happen.
+	fmt.Print(`
+You are about to post the following stack of branches for code review,
+every branch depending on the branch listed before:
+`)
+	for _, layer := range layers {
+		fmt.Printf("\n  Branch '%v':\n\n", layer.branch)
+		mustListCommits(os.Stdout, layer.commits, "    ")
+	}
+
+	// Ask the user for confirmation.
+	task := "Prompt the user for confirmation"
+	confirmed, err := prompt.Confirm("\nYou cool with that?", true)
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+	if !confirmed {
+		prompt.PanicCancel()
+	}
+	fmt.Println()
+	return nil
+}
+
+func postStackForReview(layers []*stackLayer) (err error) {
+	// Get the stacked review poster.
+	tool, err := modules.GetCodeReviewTool()
+	if err != nil {
+		return err
+	}
+	poster, ok := tool.(common.StackedReviewPoster)
+	if !ok {
+		task := "Get the stacked review poster"
+		return errs.NewError(task, errors.New(
+			"stacked review requests not supported by the active code review module"))
+	}
+
+	// Print Snoopy.
+	asciiart.PrintSnoopy()
+
+	// Turn Commits into ReviewContexts.
+	task := "Fetch stories for the commits to be posted for review"
+	log.Run(task)
+	var (
+		ctxsByLayer = make([][]*common.ReviewContext, 0, len(layers))
+		allCtxs     []*common.ReviewContext
+	)
+	for _, layer := range layers {
+		ctxs, err := commitsToReviewContexts(layer.commits)
+		if err != nil {
+			return errs.NewError(task, err)
+		}
+		if ctxs[0].Story == nil {
+			return errs.NewError(task, fmt.Errorf(
+				"story not found for branch '%v'", layer.branch))
+		}
+		ctxsByLayer = append(ctxsByLayer, ctxs)
+		allCtxs = append(allCtxs, ctxs...)
+	}
+
+	// Run the pre_review_post hook.
+	hookCtx := newHookContext(allCtxs)
+	if err := scripts.RunHook(scripts.HookPreReviewPost, hookCtx); err != nil {
+		return err
+	}
+
+	// Mark the stories as implemented, potentially.
+	task = "Mark the stories as implemented, optionally"
+	implemented, act, err := implementedDialog(allCtxs)
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+	defer action.RollbackTaskOnError(&err, task, act)
+
+	// Post the review requests, every one depending on the previous one.
+	postOpts := postOptions(implemented)
+	var dependsOn string
+	for i, ctxs := range ctxsByLayer {
+		task := fmt.Sprintf("Post review request for branch '%v'", layers[i].branch)
+		log.Run(task)
+		rrid, err := poster.PostStackedReviewRequest(ctxs, dependsOn, postOpts)
+		if err != nil {
+			return errs.NewError(task, err)
+		}
+		dependsOn = rrid
+	}
+
+	// Run the post_review_post hook.
+	scripts.RunPostHook(scripts.HookPostReviewPost, hookCtx)
+	return nil
+}
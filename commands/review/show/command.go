@@ -0,0 +1,266 @@
+package showCmd
+
+import (
+	// Stdlib
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app"
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/git"
+	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/modules"
+	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/output"
+
+	// Other
+	"gopkg.in/tchap/gocli.v2"
+)
+
+// emptyTreeHexsha is the hexsha of the empty git tree,
+// used as the base when diffing against the root commit.
+const emptyTreeHexsha = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+var Command = &gocli.Command{
+	UsageLine: "show [-difftool] STORY|RRID",
+	Short:     "show the changes being reviewed",
+	Long: `
+  Show the changes being reviewed in the given review request.
+
+  The argument is treated as a story ID first. In case there is no review
+  request for the story, the argument is treated as the review request ID,
+  i.e. the review issue number for GitHub.
+
+  The commits listed in the review request are resolved in the local
+  repository and their diffs are printed one by one, each preceded by
+  a header saying whether the commit has been reviewed already.
+  The review blockers are listed in the header of the commit they belong to.
+  Make sure the commits are fetched before running the command.
+
+  When -difftool is set, the changes introduced by the commits listed
+  in the review request are opened using git difftool instead.
+  In case there are other commits in between, the commits listed are
+  cherry-picked in a temporary working tree so that only their changes
+  are opened. That requires git 2.5 or newer.
+	`,
+	Action: run,
+}
+
+var flagDifftool bool
+
+func init() {
+	// Register flags.
+	Command.Flags.BoolVar(&flagDifftool, "difftool", flagDifftool,
+		"open the changes using git difftool")
+
+	// Register global flags.
+	appflags.RegisterGlobalFlags(&Command.Flags)
+}
+
+func run(cmd *gocli.Command, args []string) {
+	if len(args) != 1 {
+		app.ExitWithUsage(cmd)
+	}
+
+	app.InitOrDie()
+
+	if err := runMain(args[0]); err != nil {
+		errs.Fatal(err)
+	}
+}
+
+func runMain(arg string) error {
+	// Get the review request.
+	rr, err := getReviewRequest(arg)
+	if err != nil {
+		return err
+	}
+	output.AddURL("review_request", rr.URL)
+
+	// Resolve the commits locally.
+	hexshas := make([]string, len(rr.Commits))
+	var missing []string
+	for i, commit := range rr.Commits {
+		hexsha, err := git.CommitHexsha(commit.SHA)
+		if err != nil {
+			missing = append(missing, commit.SHA)
+			continue
+		}
+		hexshas[i] = hexsha
+	}
+	if len(missing) != 0 {
+		log.Warn(fmt.Sprintf(
+			"Commits not found locally, try to fetch them: %v", strings.Join(missing, ", ")))
+	}
+
+	if flagDifftool {
+		return runDifftool(hexshas)
+	}
+	return printReviewRequest(os.Stdout, rr, hexshas)
+}
+
+// getReviewRequest treats the argument as a story ID first,
+// then as a review request ID.
+func getReviewRequest(arg string) (*common.ReviewRequest, error) {
+	tool, err := modules.GetCodeReviewTool()
+	if err != nil {
+		return nil, err
+	}
+
+	if approver, ok := tool.(common.ReviewApprover); ok {
+		rrs, err := approver.ReviewStatus(arg, "")
+		switch {
+		case err == nil && len(rrs) != 0:
+			return rrs[0], nil
+		case err != nil && !isNotFound(err):
+			return nil, err
+		}
+	}
+
+	getter, ok := tool.(common.ReviewRequestGetter)
+	if !ok {
+		task := "Get the review request"
+		return nil, errs.NewError(task, errors.New(
+			"review show not supported by the active code review module"))
+	}
+	return getter.GetReviewRequest(arg)
+}
+
+func isNotFound(err error) bool {
+	_, ok := errs.RootCause(err).(*common.ErrReviewRequestNotFound)
+	return ok
+}
+
+func printReviewRequest(writer io.Writer, rr *common.ReviewRequest, hexshas []string) error {
+	state := "open"
+	switch {
+	case rr.Closed:
+		state = "closed"
+	case rr.Approved():
+		state = "approved"
+	}
+	fmt.Fprintf(writer, "%v (%v, %v)\n%v\n", rr.Title, rr.Id, state, rr.URL)
+
+	separator := strings.Repeat("=", 80)
+	printed := make(map[*common.ReviewBlocker]bool, len(rr.Blockers))
+
+	for i, commit := range rr.Commits {
+		// Print the commit header.
+		fmt.Fprintf(writer, "\n%v\n%v %v %v", separator, checkbox(commit.Reviewed), commit.SHA, commit.Title)
+		if !commit.Reviewed {
+			fmt.Fprint(writer, "  (NOT REVIEWED)")
+		}
+		fmt.Fprintln(writer)
+
+		// Print the blockers belonging to the commit.
+		for _, blocker := range rr.Blockers {
+			if sameCommit(blocker.CommitSHA, commit.SHA) {
+				printBlocker(writer, blocker)
+				printed[blocker] = true
+			}
+		}
+		fmt.Fprintln(writer, separator)
+
+		// Print the diff.
+		if hexshas[i] == "" {
+			fmt.Fprintln(writer, "\nCommit not available locally.")
+			continue
+		}
+		task := fmt.Sprintf("Show commit %v", commit.SHA)
+		stdout, err := git.Run("show", "--stat", "--patch", hexshas[i])
+		if err != nil {
+			return errs.NewError(task, err)
+		}
+		fmt.Fprintln(writer)
+		if _, err := io.Copy(writer, stdout); err != nil {
+			return errs.NewError(task, err)
+		}
+	}
+
+	// Print the blockers not belonging to any listed commit.
+	var header bool
+	for _, blocker := range rr.Blockers {
+		if printed[blocker] {
+			continue
+		}
+		if !header {
+			fmt.Fprintf(writer, "\n%v\nOther review blockers\n", separator)
+			header = true
+		}
+		printBlocker(writer, blocker)
+	}
+	if header {
+		fmt.Fprintln(writer, separator)
+	}
+	return nil
+}
+
+func printBlocker(writer io.Writer, blocker *common.ReviewBlocker) {
+	fmt.Fprintf(writer, "    %v blocker %v: %v\n        %v\n",
+		checkbox(blocker.Fixed), blocker.Number, blocker.Summary, blocker.CommentURL)
+}
+
+// runDifftool opens the changes introduced by the given commits using git difftool.
+//
+// The commits not sitting on top of each other are cherry-picked onto
+// the first commit in a temporary working tree so that the changes
+// opened do not include the other commits in between.
+func runDifftool(hexshas []string) error {
+	available := make([]string, 0, len(hexshas))
+	for _, hexsha := range hexshas {
+		if hexsha != "" {
+			available = append(available, hexsha)
+		}
+	}
+
+	task := "Open the changes using git difftool"
+	switch {
+	case len(available) == 0:
+		return errs.NewError(task, errors.New("no commits available locally"))
+	case len(available) != len(hexshas):
+		log.Warn("Some commits are not available locally, their changes are not shown")
+	}
+
+	first := available[0]
+	base, err := git.CommitHexsha(first + "^")
+	if err != nil {
+		// The root commit, diff against the empty tree.
+		base = emptyTreeHexsha
+	}
+
+	head, err := git.CherryPickOnto(first, available[1:]...)
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+
+	log.Run(task)
+	cmd := exec.Command("git", "difftool", base, head)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return errs.NewError(task, err)
+	}
+	return nil
+}
+
+// sameCommit returns true when one SHA is a prefix of the other one.
+func sameCommit(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
+
+func checkbox(checked bool) string {
+	if checked {
+		return "[x]"
+	}
+	return "[ ]"
+}
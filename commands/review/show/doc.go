@@ -0,0 +1,26 @@
+/*
+Show the changes being reviewed.
+
+  salsaflow review show [-difftool] STORY|RRID
+
+Description
+
+Show the changes being reviewed in the given review request.
+
+The argument is treated as a story ID first. In case there is no review
+request for the story, the argument is treated as the review request ID,
+i.e. the review issue number for GitHub.
+
+The commits listed in the review request are resolved in the local
+repository and their diffs are printed one by one, each preceded by
+a header saying whether the commit has been reviewed already.
+The review blockers are listed in the header of the commit they belong to.
+Make sure the commits are fetched before running the command.
+
+When -difftool is set, the changes introduced by the commits listed
+in the review request are opened using git difftool instead.
+In case there are other commits in between, the commits listed are
+cherry-picked in a temporary working tree so that only their changes
+are opened. That requires git 2.5 or newer.
+*/
+package showCmd
@@ -0,0 +1,181 @@
+package staleCmd
+
+import (
+	// Stdlib
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app"
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/modules"
+	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/output"
+
+	// Other
+	"gopkg.in/tchap/gocli.v2"
+)
+
+var Command = &gocli.Command{
+	UsageLine: "stale [-older_than=DURATION] [-remind] [-chat]",
+	Short:     "list review requests waiting for too long",
+	Long: `
+  List the open review requests in the current review milestones
+  that were created more than the given duration ago, 48h by default.
+
+  For every review request, its age, the assignee, the number of commits
+  not reviewed yet and the number of review blockers not fixed yet are printed.
+
+  When -remind is set, a reminder comment mentioning the assignee
+  is posted into every stale review request that is assigned.
+
+  When -chat is set, the report is printed as a JSON object
+  of the form {"text": "..."}, which is accepted by the incoming webhooks
+  of the common chat services, e.g.
+
+    $ salsaflow review stale -chat | curl -d @- $SLACK_WEBHOOK_URL
+	`,
+	Action: run,
+}
+
+var (
+	flagChat      bool
+	flagOlderThan = 48 * time.Hour
+	flagRemind    bool
+)
+
+func init() {
+	// Register flags.
+	Command.Flags.BoolVar(&flagChat, "chat", flagChat,
+		"print the report as a chat webhook payload")
+	Command.Flags.DurationVar(&flagOlderThan, "older_than", flagOlderThan,
+		"list the review requests older than the given duration")
+	Command.Flags.BoolVar(&flagRemind, "remind", flagRemind,
+		"post a reminder into every stale review request")
+
+	// Register global flags.
+	appflags.RegisterGlobalFlags(&Command.Flags)
+}
+
+func run(cmd *gocli.Command, args []string) {
+	if len(args) != 0 {
+		app.ExitWithUsage(cmd)
+	}
+
+	app.InitOrDie()
+
+	if err := runMain(); err != nil {
+		errs.Fatal(err)
+	}
+}
+
+func runMain() error {
+	// Get the code review tool.
+	tool, err := modules.GetCodeReviewTool()
+	if err != nil {
+		return err
+	}
+	reporter, ok := tool.(common.StaleReviewReporter)
+	if !ok {
+		task := "Get the stale review reporter"
+		return errs.NewError(task, errors.New(
+			"stale review reports not supported by the active code review module"))
+	}
+
+	// Get the stale review requests.
+	now := time.Now()
+	rrs, err := reporter.ListStaleReviewRequests(now.Add(-flagOlderThan))
+	if err != nil {
+		return err
+	}
+	for _, rr := range rrs {
+		output.AddURL("review_request", rr.URL)
+	}
+
+	// Post the reminders.
+	if flagRemind {
+		for _, rr := range rrs {
+			if rr.Assignee == "" {
+				log.Warn(fmt.Sprintf(
+					"Review request %v not assigned, reminder not posted", rr.Id))
+				continue
+			}
+			if err := reporter.RemindReviewer(rr); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Print the report.
+	if flagChat {
+		return printChatPayload(os.Stdout, rrs, now)
+	}
+
+	fmt.Println()
+	if len(rrs) == 0 {
+		fmt.Printf("No review requests older than %v found.\n\n", flagOlderThan)
+		return nil
+	}
+	if err := printReport(os.Stdout, rrs, now); err != nil {
+		return err
+	}
+	fmt.Println()
+	return nil
+}
+
+func printReport(writer io.Writer, rrs []*common.ReviewRequest, now time.Time) error {
+	tw := tabwriter.NewWriter(writer, 0, 8, 4, '\t', 0)
+	fmt.Fprintln(tw, "  Id\tAge\tAssignee\tUnreviewed\tBlockers\tTitle")
+	fmt.Fprintln(tw, "  ==\t===\t========\t==========\t========\t=====")
+	for _, rr := range rrs {
+		assignee := rr.Assignee
+		if assignee == "" {
+			assignee = "-"
+		}
+		fmt.Fprintf(tw, "  %v\t%v\t%v\t%v\t%v\t%v\n", rr.Id, formatAge(now.Sub(rr.CreatedAt)),
+			assignee, rr.UnreviewedCommits(), rr.OpenBlockers(), rr.Title)
+	}
+	return tw.Flush()
+}
+
+func printChatPayload(writer io.Writer, rrs []*common.ReviewRequest, now time.Time) error {
+	var text string
+	if len(rrs) == 0 {
+		text = fmt.Sprintf("No review requests older than %v.", flagOlderThan)
+	} else {
+		lines := []string{fmt.Sprintf("Review requests older than %v:", flagOlderThan)}
+		for _, rr := range rrs {
+			assignee := "not assigned"
+			if rr.Assignee != "" {
+				assignee = "assigned to " + rr.Assignee
+			}
+			lines = append(lines, fmt.Sprintf(
+				"- %v (%v) - %v old, %v, %v unreviewed, %v open",
+				rr.Title, rr.URL, formatAge(now.Sub(rr.CreatedAt)), assignee,
+				common.Plural(rr.UnreviewedCommits(), "commit"), common.Plural(rr.OpenBlockers(), "blocker")))
+		}
+		text = strings.Join(lines, "\n")
+	}
+
+	return json.NewEncoder(writer).Encode(map[string]string{"text": text})
+}
+
+// formatAge formats the duration as days and hours, e.g. 3d 4h.
+func formatAge(d time.Duration) string {
+	var (
+		days  = int(d / (24 * time.Hour))
+		hours = int(d % (24 * time.Hour) / time.Hour)
+	)
+	if days == 0 {
+		return fmt.Sprintf("%vh", hours)
+	}
+	return fmt.Sprintf("%vd %vh", days, hours)
+}
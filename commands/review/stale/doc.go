@@ -0,0 +1,23 @@
+/*
+List review requests waiting for too long.
+
+  salsaflow review stale [-older_than=DURATION] [-remind] [-chat]
+
+Description
+
+List the open review requests in the current review milestones
+that were created more than the given duration ago, 48h by default.
+
+For every review request, its age, the assignee, the number of commits
+not reviewed yet and the number of review blockers not fixed yet are printed.
+
+When -remind is set, a reminder comment mentioning the assignee
+is posted into every stale review request that is assigned.
+
+When -chat is set, the report is printed as a JSON object
+of the form {"text": "..."}, which is accepted by the incoming webhooks
+of the common chat services, e.g.
+
+  $ salsaflow review stale -chat | curl -d @- $SLACK_WEBHOOK_URL
+*/
+package staleCmd
@@ -0,0 +1,259 @@
+package statsCmd
+
+import (
+	// Stdlib
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app"
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/flag"
+	"github.com/salsaflow/salsaflow/modules"
+	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/output"
+
+	// Other
+	"gopkg.in/tchap/gocli.v2"
+)
+
+const dateFormat = "2006-01-02"
+
+const (
+	formatTable = "table"
+	formatCSV   = "csv"
+	formatJSON  = "json"
+)
+
+var Command = &gocli.Command{
+	UsageLine: "stats [-since=DATE] [-until=DATE] [-format=FORMAT]",
+	Short:     "print review turnaround statistics",
+	Long: `
+  Print the statistics for the review requests created within the given
+  period. The dates are of the form YYYY-MM-DD and both are inclusive.
+  The period defaults to the last 30 days.
+
+  The following metrics are aggregated:
+
+    * time to the first response, i.e. the first reaction of somebody else
+      than the author of the review request or closing the review request
+    * time to close the review request
+    * review blockers per review request
+    * commits per review request
+    * review rounds per review request, i.e. 1 plus the number of follow-ups
+      posted using 'review post -fixes'
+    * reviewer load, i.e. the number of the review requests assigned
+      to every reviewer and how fast they are closed
+
+  Supported formats:
+
+    table - print the summary and the reviewer load as tables (default)
+    csv   - print the metrics for every review request as CSV
+    json  - print the summary, the reviewer load and the period as JSON
+
+  In case -output=json is set, the JSON report is included in the result
+  printed to stdout as "data" and -format only affects what is printed
+  to stderr together with the rest of the human-readable output.
+	`,
+	Action: run,
+}
+
+var (
+	flagFormat = flag.NewStringEnumFlag([]string{formatTable, formatCSV, formatJSON}, formatTable)
+	flagSince  string
+	flagUntil  string
+)
+
+func init() {
+	// Register flags.
+	Command.Flags.Var(flagFormat, "format", "output format")
+	Command.Flags.StringVar(&flagSince, "since", flagSince,
+		"include the review requests created on or after the given date")
+	Command.Flags.StringVar(&flagUntil, "until", flagUntil,
+		"include the review requests created on or before the given date")
+
+	// Register global flags.
+	appflags.RegisterGlobalFlags(&Command.Flags)
+}
+
+func run(cmd *gocli.Command, args []string) {
+	if len(args) != 0 {
+		app.ExitWithUsage(cmd)
+	}
+
+	app.InitOrDie()
+
+	if err := runMain(); err != nil {
+		errs.Fatal(err)
+	}
+}
+
+func runMain() error {
+	// Parse the period.
+	now := time.Now()
+	since, until, err := parsePeriod(now)
+	if err != nil {
+		return err
+	}
+
+	// Get the code review tool.
+	tool, err := modules.GetCodeReviewTool()
+	if err != nil {
+		return err
+	}
+	reporter, ok := tool.(common.ReviewStatsReporter)
+	if !ok {
+		task := "Get the review stats reporter"
+		return errs.NewError(task, errors.New(
+			"review stats not supported by the active code review module"))
+	}
+
+	// Get the review activity.
+	activity, err := reporter.ListReviewActivity(since, until)
+	if err != nil {
+		return err
+	}
+
+	// Include the report in the JSON result.
+	if output.IsJSON() {
+		output.SetData(newReport(activity, since, until))
+	}
+
+	// Print the report.
+	switch flagFormat.Value() {
+	case formatCSV:
+		return printCSV(os.Stdout, activity)
+	case formatJSON:
+		return json.NewEncoder(os.Stdout).Encode(newReport(activity, since, until))
+	default:
+		fmt.Println()
+		if len(activity) == 0 {
+			fmt.Printf("No review requests created between %v and %v found.\n\n",
+				since.Format(dateFormat), until.Add(-time.Second).Format(dateFormat))
+			return nil
+		}
+		if err := printTable(os.Stdout, newReport(activity, since, until)); err != nil {
+			return err
+		}
+		fmt.Println()
+		return nil
+	}
+}
+
+// parsePeriod turns the flags into the time range, until being exclusive.
+func parsePeriod(now time.Time) (since, until time.Time, err error) {
+	task := "Parse the reporting period"
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	since = today.AddDate(0, 0, -30)
+	until = now
+
+	if flagSince != "" {
+		since, err = time.ParseInLocation(dateFormat, flagSince, now.Location())
+		if err != nil {
+			return since, until, errs.NewError(task, fmt.Errorf("invalid -since: %v", err))
+		}
+	}
+	if flagUntil != "" {
+		until, err = time.ParseInLocation(dateFormat, flagUntil, now.Location())
+		if err != nil {
+			return since, until, errs.NewError(task, fmt.Errorf("invalid -until: %v", err))
+		}
+		until = until.AddDate(0, 0, 1)
+	}
+
+	if !since.Before(until) {
+		return since, until, errs.NewError(task, errors.New("-since must precede -until"))
+	}
+	return since, until, nil
+}
+
+func printTable(writer io.Writer, r *report) error {
+	tw := tabwriter.NewWriter(writer, 0, 8, 4, '\t', 0)
+	sum := r.Summary
+	fmt.Fprintln(tw, "  Metric\tValue")
+	fmt.Fprintln(tw, "  ======\t=====")
+	fmt.Fprintf(tw, "  Review requests\t%v (%v closed)\n", sum.Reviews, sum.Closed)
+	fmt.Fprintf(tw, "  Time to first response\t%v\n", formatDurationStats(sum.FirstResponse))
+	fmt.Fprintf(tw, "  Time to close\t%v\n", formatDurationStats(sum.TimeToClose))
+	fmt.Fprintf(tw, "  Blockers per review\t%.1f\n", sum.BlockersPerReview)
+	fmt.Fprintf(tw, "  Commits per review\t%.1f\n", sum.CommitsPerReview)
+	fmt.Fprintf(tw, "  Rounds per review\t%.1f\n", sum.RoundsPerReview)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(writer)
+	tw = tabwriter.NewWriter(writer, 0, 8, 4, '\t', 0)
+	fmt.Fprintln(tw, "  Reviewer\tReviews\tOpen\tBlockers\tTime to close")
+	fmt.Fprintln(tw, "  ========\t=======\t====\t========\t=============")
+	for _, load := range r.Reviewers {
+		fmt.Fprintf(tw, "  %v\t%v\t%v\t%v\t%v\n", load.Reviewer, load.Reviews,
+			load.Open, load.Blockers, formatDurationStats(load.TimeToClose))
+	}
+	return tw.Flush()
+}
+
+func printCSV(writer io.Writer, activity []*common.ReviewActivity) error {
+	cw := csv.NewWriter(writer)
+	cw.Write([]string{
+		"id", "title", "assignee", "created_at", "first_response_hours",
+		"close_hours", "blockers", "commits", "rounds",
+	})
+	for _, a := range activity {
+		rr := a.ReviewRequest
+		cw.Write([]string{
+			rr.Id,
+			rr.Title,
+			rr.Assignee,
+			rr.CreatedAt.Format(time.RFC3339),
+			formatHours(rr.CreatedAt, a.FirstResponseAt),
+			formatHours(rr.CreatedAt, a.ClosedAt),
+			strconv.Itoa(len(rr.Blockers)),
+			strconv.Itoa(len(rr.Commits)),
+			strconv.Itoa(a.Rounds),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatHours returns the hours elapsed between the two times,
+// an empty string in case the event has not happened yet.
+func formatHours(from, to time.Time) string {
+	if to.IsZero() {
+		return ""
+	}
+	return strconv.FormatFloat(to.Sub(from).Hours(), 'f', 1, 64)
+}
+
+func formatDurationStats(stats durationStats) string {
+	if stats.Count == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("median %v, mean %v", formatDuration(stats.Median), formatDuration(stats.Mean))
+}
+
+// formatDuration formats the duration as days and hours, e.g. 3d 4h,
+// the durations shorter than an hour are formatted as minutes.
+func formatDuration(d time.Duration) string {
+	if d < time.Hour {
+		return fmt.Sprintf("%vm", int(d/time.Minute))
+	}
+	var (
+		days  = int(d / (24 * time.Hour))
+		hours = int(d % (24 * time.Hour) / time.Hour)
+	)
+	if days == 0 {
+		return fmt.Sprintf("%vh", hours)
+	}
+	return fmt.Sprintf("%vd %vh", days, hours)
+}
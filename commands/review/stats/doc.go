@@ -0,0 +1,38 @@
+/*
+Print review turnaround statistics.
+
+  salsaflow review stats [-since=DATE] [-until=DATE] [-format=FORMAT]
+
+# Description
+
+Print the statistics for the review requests created within the given
+period. The dates are of the form YYYY-MM-DD and both are inclusive.
+The period defaults to the last 30 days.
+
+The following metrics are aggregated:
+
+  - time to the first response, i.e. the first reaction of somebody else
+    than the author of the review request or closing the review request
+  - time to close the review request
+  - review blockers per review request
+  - commits per review request
+  - review rounds per review request, i.e. 1 plus the number of follow-ups
+    posted using 'review post -fixes'
+  - reviewer load, i.e. the number of the review requests assigned
+    to every reviewer and how fast they are closed
+
+Supported formats:
+
+  table - print the summary and the reviewer load as tables (default)
+  csv   - print the metrics for every review request as CSV
+  json  - print the summary, the reviewer load and the period as JSON
+
+In case -output=json is set, the JSON report is included in the result
+printed to stdout as "data" and -format only affects what is printed
+to stderr together with the rest of the human-readable output.
+
+Example
+
+  $ salsaflow review stats -since=2026-09-01 -format=csv > reviews.csv
+*/
+package statsCmd
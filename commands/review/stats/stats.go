@@ -0,0 +1,162 @@
+package statsCmd
+
+import (
+	// Stdlib
+	"encoding/json"
+	"sort"
+	"time"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/modules/common"
+)
+
+// durationStats summarises a set of durations.
+type durationStats struct {
+	Count  int
+	Mean   time.Duration
+	Median time.Duration
+}
+
+func newDurationStats(ds []time.Duration) durationStats {
+	if len(ds) == 0 {
+		return durationStats{}
+	}
+
+	sorted := make([]time.Duration, len(ds))
+	copy(sorted, ds)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	var sum time.Duration
+	for _, d := range sorted {
+		sum += d
+	}
+
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + median) / 2
+	}
+
+	return durationStats{
+		Count:  len(sorted),
+		Mean:   sum / time.Duration(len(sorted)),
+		Median: median,
+	}
+}
+
+// MarshalJSON encodes the durations as hours.
+func (stats durationStats) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Count       int     `json:"count"`
+		MeanHours   float64 `json:"mean_hours"`
+		MedianHours float64 `json:"median_hours"`
+	}{
+		stats.Count,
+		stats.Mean.Hours(),
+		stats.Median.Hours(),
+	})
+}
+
+// summary contains the metrics aggregated over all the review requests.
+type summary struct {
+	Reviews           int           `json:"reviews"`
+	Closed            int           `json:"closed"`
+	FirstResponse     durationStats `json:"time_to_first_response"`
+	TimeToClose       durationStats `json:"time_to_close"`
+	BlockersPerReview float64       `json:"blockers_per_review"`
+	CommitsPerReview  float64       `json:"commits_per_review"`
+	RoundsPerReview   float64       `json:"rounds_per_review"`
+}
+
+// reviewerLoad contains the metrics for the review requests
+// assigned to a single reviewer.
+type reviewerLoad struct {
+	Reviewer    string        `json:"reviewer"`
+	Reviews     int           `json:"reviews"`
+	Open        int           `json:"open"`
+	Blockers    int           `json:"blockers"`
+	TimeToClose durationStats `json:"time_to_close"`
+}
+
+// report is the complete review statistics report.
+type report struct {
+	Since     time.Time       `json:"since"`
+	Until     time.Time       `json:"until"`
+	Summary   *summary        `json:"summary"`
+	Reviewers []*reviewerLoad `json:"reviewers"`
+}
+
+func newReport(activity []*common.ReviewActivity, since, until time.Time) *report {
+	var (
+		sum            = &summary{Reviews: len(activity)}
+		firstResponses []time.Duration
+		timesToClose   []time.Duration
+		blockers       int
+		commits        int
+		rounds         int
+
+		loads          = make(map[string]*reviewerLoad)
+		reviewerCloses = make(map[string][]time.Duration)
+	)
+
+	for _, a := range activity {
+		rr := a.ReviewRequest
+		blockers += len(rr.Blockers)
+		commits += len(rr.Commits)
+		rounds += a.Rounds
+
+		if !a.FirstResponseAt.IsZero() {
+			firstResponses = append(firstResponses, a.FirstResponseAt.Sub(rr.CreatedAt))
+		}
+
+		reviewer := rr.Assignee
+		if reviewer == "" {
+			reviewer = "-"
+		}
+		load, ok := loads[reviewer]
+		if !ok {
+			load = &reviewerLoad{Reviewer: reviewer}
+			loads[reviewer] = load
+		}
+		load.Reviews++
+		load.Blockers += len(rr.Blockers)
+
+		if a.ClosedAt.IsZero() {
+			load.Open++
+			continue
+		}
+		sum.Closed++
+		timeToClose := a.ClosedAt.Sub(rr.CreatedAt)
+		timesToClose = append(timesToClose, timeToClose)
+		reviewerCloses[reviewer] = append(reviewerCloses[reviewer], timeToClose)
+	}
+
+	sum.FirstResponse = newDurationStats(firstResponses)
+	sum.TimeToClose = newDurationStats(timesToClose)
+	if n := float64(len(activity)); n != 0 {
+		sum.BlockersPerReview = float64(blockers) / n
+		sum.CommitsPerReview = float64(commits) / n
+		sum.RoundsPerReview = float64(rounds) / n
+	}
+
+	// The busiest reviewers go first.
+	reviewers := make([]*reviewerLoad, 0, len(loads))
+	for reviewer, load := range loads {
+		load.TimeToClose = newDurationStats(reviewerCloses[reviewer])
+		reviewers = append(reviewers, load)
+	}
+	sort.Slice(reviewers, func(i, j int) bool {
+		if reviewers[i].Reviews != reviewers[j].Reviews {
+			return reviewers[i].Reviews > reviewers[j].Reviews
+		}
+		return reviewers[i].Reviewer < reviewers[j].Reviewer
+	})
+
+	return &report{
+		Since:     since,
+		Until:     until,
+		Summary:   sum,
+		Reviewers: reviewers,
+	}
+}
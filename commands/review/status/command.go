@@ -0,0 +1,158 @@
+package statusCmd
+
+import (
+	// Stdlib
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app"
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/git"
+	"github.com/salsaflow/salsaflow/modules"
+	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/output"
+
+	// Other
+	"gopkg.in/tchap/gocli.v2"
+)
+
+var Command = &gocli.Command{
+	UsageLine: "status [STORY|COMMIT]",
+	Short:     "show the review status",
+	Long: `
+  Show the review status of the given story or commit.
+
+  For every review request, the commits are listed together with
+  their review state, followed by the review blockers that are still open.
+
+  In case the argument resolves to a git commit, the review request
+  the commit is being reviewed in is shown. Otherwise the argument is
+  treated as a story ID. When no argument is specified, all open review
+  requests are shown.
+	`,
+	Action: run,
+}
+
+func init() {
+	// Register global flags.
+	appflags.RegisterGlobalFlags(&Command.Flags)
+}
+
+func run(cmd *gocli.Command, args []string) {
+	if len(args) > 1 {
+		app.ExitWithUsage(cmd)
+	}
+
+	app.InitOrDie()
+
+	var arg string
+	if len(args) == 1 {
+		arg = args[0]
+	}
+
+	if err := runMain(arg); err != nil {
+		errs.Fatal(err)
+	}
+}
+
+func runMain(arg string) error {
+	// Decide whether the argument is a commit or a story.
+	var storyId, commitSHA string
+	if arg != "" {
+		if sha, err := git.CommitHexsha(arg); err == nil {
+			commitSHA = sha
+		} else {
+			storyId = arg
+		}
+	}
+
+	// Get the code review tool.
+	tool, err := modules.GetCodeReviewTool()
+	if err != nil {
+		return err
+	}
+	approver, ok := tool.(common.ReviewApprover)
+	if !ok {
+		task := "Get the review approver"
+		return errs.NewError(task, fmt.Errorf(
+			"review status not supported by the active code review module"))
+	}
+
+	// Get the review requests.
+	rrs, err := approver.ReviewStatus(storyId, commitSHA)
+	if err != nil {
+		return err
+	}
+
+	// Print the review status.
+	fmt.Println()
+	if len(rrs) == 0 {
+		fmt.Println("No review requests found.")
+	}
+	for i, rr := range rrs {
+		if i != 0 {
+			fmt.Println()
+		}
+		if err := printReviewRequest(os.Stdout, rr); err != nil {
+			return err
+		}
+		output.AddURL("review_request", rr.URL)
+	}
+	fmt.Println()
+	return nil
+}
+
+func printReviewRequest(writer io.Writer, rr *common.ReviewRequest) error {
+	state := "open"
+	switch {
+	case rr.Closed:
+		state = "closed"
+	case rr.Approved():
+		state = "approved"
+	}
+	fmt.Fprintf(writer, "%v (%v, %v)\n  %v\n\n", rr.Title, rr.Id, state, rr.URL)
+
+	tw := tabwriter.NewWriter(writer, 0, 8, 4, '\t', 0)
+	fmt.Fprintln(tw, "  Reviewed\tCommit SHA\tCommit Title")
+	fmt.Fprintln(tw, "  ========\t==========\t============")
+	for _, commit := range rr.Commits {
+		fmt.Fprintf(tw, "  %v\t%v\t%v\n", yesNo(commit.Reviewed), commit.SHA, commit.Title)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	var blockers []*common.ReviewBlocker
+	for _, blocker := range rr.Blockers {
+		if !blocker.Fixed {
+			blockers = append(blockers, blocker)
+		}
+	}
+	if len(blockers) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(writer)
+	tw = tabwriter.NewWriter(writer, 0, 8, 4, '\t', 0)
+	fmt.Fprintln(tw, "  Blocker\tCommit SHA\tSummary")
+	fmt.Fprintln(tw, "  =======\t==========\t=======")
+	for _, blocker := range blockers {
+		sha := blocker.CommitSHA
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		fmt.Fprintf(tw, "  %v\t%v\t%v\n", blocker.Number, sha, blocker.Summary)
+	}
+	return tw.Flush()
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
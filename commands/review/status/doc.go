@@ -0,0 +1,18 @@
+/*
+Show the review status.
+
+  salsaflow review status [STORY|COMMIT]
+
+Description
+
+Show the review status of the given story or commit.
+
+For every review request, the commits are listed together with
+their review state, followed by the review blockers that are still open.
+
+In case the argument resolves to a git commit, the review request
+the commit is being reviewed in is shown. Otherwise the argument is
+treated as a story ID. When no argument is specified, all open review
+requests are shown.
+*/
+package statusCmd
@@ -0,0 +1,167 @@
+package serveCmd
+
+import (
+	// Stdlib
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app"
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/modules"
+	"github.com/salsaflow/salsaflow/modules/common"
+
+	// Other
+	"gopkg.in/tchap/gocli.v2"
+)
+
+// SecretEnvVar is the environment variable the webhook secret is taken from.
+const SecretEnvVar = "SALSAFLOW_WEBHOOK_SECRET"
+
+// shutdownTimeout is how long the requests being served
+// and the webhooks being processed are waited for on exit.
+const shutdownTimeout = 30 * time.Second
+
+var Command = &gocli.Command{
+	UsageLine: "serve [-listen=ADDRESS] [-path=PATH]",
+	Short:     "process code review webhooks",
+	Long: `
+  Start an HTTP server processing the webhooks sent by the code review service
+  to keep the review requests and the issue tracker in sync.
+
+  For GitHub, configure the webhook for the issues, issue_comment and push
+  events to be sent to PATH, using the application/json content type.
+  The webhook secret must be set in SALSAFLOW_WEBHOOK_SECRET, the payloads
+  that are not signed using the secret are rejected.
+
+  The following events are processed:
+
+    issues        - The story is marked as reviewed once its review issue is closed.
+                    The story is left alone when the review is being skipped.
+    issue_comment - The comments starting with "BLOCKER [SHA]: SUMMARY" are recorded
+                    as review blockers. The last commit is used when SHA is omitted.
+    push          - The commits pushed into the trunk branch are added into the review
+                    issues for their stories, the review issues are reopened.
+                    "Fixes-Blocker: N[, M]" commit message tags mark the blockers
+                    in the review issue as fixed.
+
+  The server is expected to run in a clone of the project repository
+  so that the configuration can be loaded the usual way.
+  Use -listen to change the address to listen on, :8080 by default.
+
+  The server stops on SIGINT or SIGTERM. The webhooks accepted already
+  are processed before exiting, but 30 seconds at most.
+	`,
+	Action: run,
+}
+
+var (
+	flagListen = ":8080"
+	flagPath   = "/"
+)
+
+func init() {
+	// Register flags.
+	Command.Flags.StringVar(&flagListen, "listen", flagListen, "network address to listen on")
+	Command.Flags.StringVar(&flagPath, "path", flagPath, "URL path to accept the webhooks at")
+
+	// Register global flags.
+	appflags.RegisterGlobalFlags(&Command.Flags)
+}
+
+func run(cmd *gocli.Command, args []string) {
+	if len(args) != 0 {
+		app.ExitWithUsage(cmd)
+	}
+
+	app.InitOrDie()
+
+	if err := runMain(); err != nil {
+		errs.Fatal(err)
+	}
+}
+
+func runMain() error {
+	task := "Start the webhook server"
+
+	// Get the webhook secret.
+	secret := os.Getenv(SecretEnvVar)
+	if secret == "" {
+		hint := fmt.Sprintf("\nSet %v to the secret configured for the webhook.\n\n", SecretEnvVar)
+		return errs.NewErrorWithHint(task, errors.New("webhook secret not set"), hint)
+	}
+
+	// Get the modules.
+	tracker, err := modules.GetIssueTracker()
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+	tool, err := modules.GetCodeReviewTool()
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+	receiver, ok := tool.(common.WebhookReceiver)
+	if !ok {
+		return errs.NewError(task, errors.New(
+			"webhooks not supported by the active code review module"))
+	}
+
+	handler, err := receiver.WebhookHandler(secret, tracker)
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+
+	// Start the server.
+	mux := http.NewServeMux()
+	mux.Handle(flagPath, handler)
+	server := &http.Server{
+		Addr:    flagListen,
+		Handler: mux,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+	log.Log(fmt.Sprintf("Listening on %v, press Ctrl-C to exit", flagListen))
+
+	// Wait for the signal, then shut down gracefully.
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signalCh)
+
+	select {
+	case err := <-errCh:
+		return errs.NewError(task, err)
+	case <-signalCh:
+	}
+
+	task = "Stop the webhook server"
+	log.Run(task)
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		return errs.NewError(task, err)
+	}
+
+	// Wait for the webhooks accepted to be processed.
+	done := make(chan struct{})
+	go func() {
+		handler.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return errs.NewError(task, errors.New("timed out waiting for the webhooks to be processed"))
+	}
+}
@@ -0,0 +1,34 @@
+/*
+Process code review webhooks.
+
+  salsaflow serve [-listen=ADDRESS] [-path=PATH]
+
+Description
+
+Start an HTTP server processing the webhooks sent by the code review service
+to keep the review requests and the issue tracker in sync.
+
+For GitHub, configure the webhook for the issues, issue_comment and push
+events to be sent to PATH, using the application/json content type.
+The webhook secret must be set in SALSAFLOW_WEBHOOK_SECRET, the payloads
+that are not signed using the secret are rejected.
+
+The following events are processed:
+
+  issues        - The story is marked as reviewed once its review issue is closed.
+                  The story is left alone when the review is being skipped.
+  issue_comment - The comments starting with "BLOCKER [SHA]: SUMMARY" are recorded
+                  as review blockers. The last commit is used when SHA is omitted.
+  push          - The commits pushed into the trunk branch are added into the review
+                  issues for their stories, the review issues are reopened.
+                  "Fixes-Blocker: N[, M]" commit message tags mark the blockers
+                  in the review issue as fixed.
+
+The server is expected to run in a clone of the project repository
+so that the configuration can be loaded the usual way.
+Use -listen to change the address to listen on, :8080 by default.
+
+The server stops on SIGINT or SIGTERM. The webhooks accepted already
+are processed before exiting, but 30 seconds at most.
+*/
+package serveCmd
@@ -3,7 +3,6 @@ package openCmd
 import (
 	// Stdlib
 	"fmt"
-	"os"
 
 	// Internal
 	"github.com/salsaflow/salsaflow/app"
@@ -32,8 +31,7 @@ func init() {
 
 func run(cmd *gocli.Command, args []string) {
 	if len(args) != 1 {
-		cmd.Usage()
-		os.Exit(2)
+		app.ExitWithUsage(cmd)
 	}
 
 	app.InitOrDie()
@@ -4,7 +4,6 @@ import (
 	// Stdlib
 	"errors"
 	"fmt"
-	"os"
 
 	// Internal
 	"github.com/salsaflow/salsaflow/action"
@@ -16,8 +15,10 @@ import (
 	"github.com/salsaflow/salsaflow/log"
 	"github.com/salsaflow/salsaflow/modules"
 	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/output"
 	"github.com/salsaflow/salsaflow/prompt"
 	"github.com/salsaflow/salsaflow/prompt/storyprompt"
+	"github.com/salsaflow/salsaflow/scripts"
 
 	// Other
 	"github.com/extemporalgenome/slug"
@@ -39,6 +40,9 @@ var Command = &gocli.Command{
   The branch of the given name is created on top of the trunk branch
   and checked out. A custom base branch can be set by using -base.
   The story branch is then pushed in case -push is specified.
+
+  The pre_story_start and post_story_start lifecycle hooks are run in case
+  the relevant scripts are available in .salsaflow/scripts.
 	`,
 	Action: run,
 }
@@ -64,8 +68,7 @@ func init() {
 
 func run(cmd *gocli.Command, args []string) {
 	if len(args) != 0 {
-		cmd.Usage()
-		os.Exit(2)
+		app.ExitWithUsage(cmd)
 	}
 
 	app.InitOrDie()
@@ -97,26 +100,22 @@ func runMain() (err error) {
 	// Filter out the stories that are not relevant,
 	// i.e. not owned by the current user or assigned to someone else.
 	task = "Fetch the current user record from the issue tracker"
-	user, err := tracker.CurrentUser()
-	if err != nil {
-		return errs.NewError(task, err)
-	}
+	isMine := common.CurrentUserAssignee()
 
 	var filteredStories []common.Story
-StoryLoop:
 	for _, story := range stories {
-		assignees := story.Assignees()
 		// Include the story in case there is no assignee set yet.
-		if len(assignees) == 0 {
+		if len(story.Assignees()) == 0 {
 			filteredStories = append(filteredStories, story)
-			continue StoryLoop
+			continue
 		}
 		// Include the story in case the current user is assigned.
-		for _, assignee := range assignees {
-			if assignee.Id() == user.Id() {
-				filteredStories = append(filteredStories, story)
-				continue StoryLoop
-			}
+		mine, err := isMine(story)
+		if err != nil {
+			return errs.NewError(task, err)
+		}
+		if mine {
+			filteredStories = append(filteredStories, story)
 		}
 	}
 	stories = filteredStories
@@ -136,6 +135,11 @@ StoryLoop:
 	}
 	fmt.Println()
 
+	// Run the pre_story_start hook.
+	if err := scripts.RunHook(scripts.HookPreStoryStart, newHookContext(story)); err != nil {
+		return err
+	}
+
 	// Create the story branch, optionally.
 	if flagNoBranch {
 		log.Log("Not creating any feature branch")
@@ -150,6 +154,13 @@ StoryLoop:
 	}
 
 	// Add the current user to the list of story assignees.
+	// The user record is taken from the issue tracker the story belongs to.
+	task = "Fetch the current user record from the issue tracker"
+	user, err := story.IssueTracker().CurrentUser()
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+
 	task = "Amend the list of story assignees"
 	log.Run(task)
 	originalAssignees := story.Assignees()
@@ -167,7 +178,31 @@ StoryLoop:
 	// Start the selected story. No need to roll back.
 	task = "Start the selected story"
 	log.Run(task)
-	return errs.Wrap(task, story.Start())
+	if err := story.Start(); err != nil {
+		return errs.NewError(task, err)
+	}
+
+	// Record the story for the JSON output.
+	output.AddStory(story)
+
+	// Run the post_story_start hook.
+	// The current branch is the story branch in case it was created.
+	scripts.RunPostHook(scripts.HookPostStoryStart, newHookContext(story))
+	return nil
+}
+
+func newHookContext(story common.Story) *scripts.HookContext {
+	hookCtx := scripts.NewHookContext()
+	hookCtx.AddStory(story)
+	hookCtx.Load = func(ctx *scripts.HookContext) error {
+		currentBranch, err := gitutil.CurrentBranch()
+		if err != nil {
+			return err
+		}
+		ctx.SetBranch("current", currentBranch)
+		return nil
+	}
+	return hookCtx
 }
 
 func createBranch() (action.Action, error) {
@@ -275,6 +310,9 @@ Insert an empty string to skip the branch creation step: `)
 		}
 	}
 
+	// Record the branch for the JSON output.
+	output.AddBranch(branchName)
+
 	return action.ActionFunc(func() error {
 		// Checkout the original branch.
 		log.Rollback(checkoutTask)
@@ -27,5 +27,13 @@ The command goes through the following steps:
   5. Add the user among the story owners.
   6. Start the story in the issue tracker.
   7. Push the story branch in case -push is set.
+
+Hooks
+
+The pre_story_start hook is run once the user selects the story, before the
+story branch is created. The command is aborted in case the hook fails. The
+post_story_start hook is run once the story is started in the issue tracker. The
+hooks are only run in case the relevant scripts are available in
+.salsaflow/scripts, see the Scripts section of the SalsaFlow README.
 */
 package startCmd
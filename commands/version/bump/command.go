@@ -1,13 +1,12 @@
 package bumpCmd
 
 import (
-	// Stdlib
-	"os"
-
 	// Internal
+	"github.com/salsaflow/salsaflow/app"
 	"github.com/salsaflow/salsaflow/app/appflags"
 	"github.com/salsaflow/salsaflow/errs"
 	"github.com/salsaflow/salsaflow/git/gitutil"
+	"github.com/salsaflow/salsaflow/output"
 	"github.com/salsaflow/salsaflow/version"
 
 	// Other
@@ -41,10 +40,11 @@ func init() {
 
 func run(cmd *gocli.Command, args []string) {
 	if len(args) != 1 {
-		cmd.Usage()
-		os.Exit(2)
+		app.ExitWithUsage(cmd)
 	}
 
+	app.InitLogging()
+
 	if err := runMain(args[0]); err != nil {
 		errs.Fatal(err)
 	}
@@ -63,6 +63,8 @@ and no part of the version string can be omitted.
 		return errs.NewErrorWithHint(task, err, hint)
 	}
 
+	output.SetValue("version", ver.String())
+
 	// In case -commit is set, set and commit the version string.
 	if flagCommit {
 		currentBranch, err := gitutil.CurrentBranch()
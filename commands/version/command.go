@@ -3,12 +3,13 @@ package versionCmd
 import (
 	// Stdlib
 	"fmt"
-	"os"
 
 	// Internal
+	"github.com/salsaflow/salsaflow/app"
 	"github.com/salsaflow/salsaflow/app/appflags"
 	"github.com/salsaflow/salsaflow/commands/version/bump"
 	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/output"
 	"github.com/salsaflow/salsaflow/version"
 
 	// Other
@@ -27,15 +28,17 @@ var Command = &gocli.Command{
 	`,
 	Action: func(cmd *gocli.Command, args []string) {
 		if len(args) != 0 {
-			cmd.Usage()
-			os.Exit(2)
+			app.ExitWithUsage(cmd)
 		}
 
+		app.InitLogging()
+
 		ver, err := version.Get()
 		if err != nil {
 			errs.Fatal(err)
 		}
 
+		output.SetValue("version", ver.String())
 		fmt.Println(ver)
 	},
 }
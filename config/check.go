@@ -12,6 +12,7 @@ import (
 // EnsureValueFilled returns an error in case the value passed in is not set.
 //
 // The function checks structs and slices recursively.
+// Struct fields tagged with `optional:"true"` are allowed to be left unset.
 func EnsureValueFilled(value interface{}, path string) error {
 	logger := log.V(log.Debug)
 
@@ -77,6 +78,11 @@ func ensureStructFilled(v reflect.Value, path string) error {
 			continue
 		}
 
+		// Skip optional fields that are not set.
+		if ft.Tag.Get("optional") != "" && isZero(fv) {
+			continue
+		}
+
 		// Get the field tag.
 		tag := ft.Tag.Get("json")
 		if tag == "" {
@@ -103,3 +109,7 @@ func ensureSliceFilled(v reflect.Value, path string) error {
 	}
 	return nil
 }
+
+func isZero(v reflect.Value) bool {
+	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+}
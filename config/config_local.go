@@ -30,11 +30,61 @@ type LocalConfig struct {
 		IssueTracking string `json:"issue_tracking"`
 		CodeReview    string `json:"code_review"`
 		ReleaseNotes  string `json:"release_notes"`
+
+		// AdditionalIssueTracking lists the issue tracking modules that are active
+		// next to the primary one. Every module claims the Story-Id tags
+		// matching its rule, the primary module takes care of the rest.
+		AdditionalIssueTracking []*IssueTrackerBinding `json:"additional_issue_tracking,omitempty"`
 	} `json:"active_modules,omitempty"`
 
+	// Skeleton records the skeleton the local configuration directory
+	// was bootstrapped from so that it can be updated later.
+	Skeleton *SkeletonRecord `json:"skeleton,omitempty"`
+
+	// Scripts customises the way the custom scripts are run.
+	Scripts *ScriptsSection `json:"scripts,omitempty"`
+
 	*ConfigurationsSection
 }
 
+// IssueTrackerBinding binds an additional issue tracking module
+// to the Story-Id tags the module is supposed to handle.
+//
+// A tag is claimed when it starts with StoryTagPrefix or matches StoryTagPattern.
+// In case neither is set, the tag is claimed when the module can parse it.
+type IssueTrackerBinding struct {
+	ModuleId        string `json:"module"`
+	StoryTagPrefix  string `json:"story_tag_prefix,omitempty"`
+	StoryTagPattern string `json:"story_tag_pattern,omitempty"`
+}
+
+// SkeletonRecord identifies the skeleton version poured into the repository.
+//
+// Source is the skeleton location as specified by the user, Ref is the pinned ref, if any,
+// and Commit is the skeleton commit that was actually used. Variables contains
+// the values that were used to render the skeleton templates.
+type SkeletonRecord struct {
+	Source    string            `json:"source"`
+	Ref       string            `json:"ref,omitempty"`
+	Commit    string            `json:"commit,omitempty"`
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// ScriptsSection customises the way the custom scripts are run.
+//
+// Interpreters maps script file extensions to the interpreter command to be used,
+// e.g. "py" to "python3.6". Timeout is the default script timeout, e.g. "5m",
+// and Timeouts overrides it for particular script names. MakeTargets maps
+// script names to the targets in Makefile, which is the Makefile path
+// relative to the repository root.
+type ScriptsSection struct {
+	Interpreters map[string]string `json:"interpreters,omitempty"`
+	Timeout      string            `json:"timeout,omitempty"`
+	Timeouts     map[string]string `json:"timeouts,omitempty"`
+	Makefile     string            `json:"makefile,omitempty"`
+	MakeTargets  map[string]string `json:"make_targets,omitempty"`
+}
+
 func NewEmptyLocalConfig() *LocalConfig {
 	now := time.Now()
 	return &LocalConfig{
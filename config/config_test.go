@@ -0,0 +1,28 @@
+package config
+
+import (
+	// Stdlib
+	"testing"
+
+	// Vendor - testing framework
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var (
+	BeforeEach = ginkgo.BeforeEach
+	Context    = ginkgo.Context
+	Describe   = ginkgo.Describe
+	It         = ginkgo.It
+
+	BeNil                = gomega.BeNil
+	BeAssignableToTypeOf = gomega.BeAssignableToTypeOf
+	Equal                = gomega.Equal
+	Expect               = gomega.Expect
+	HaveOccurred         = gomega.HaveOccurred
+)
+
+func TestConfig(t *testing.T) {
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "Configuration")
+}
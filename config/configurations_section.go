@@ -13,10 +13,16 @@ import (
 
 type ConfigurationsSection struct {
 	Records map[string]*json.RawMessage `json:"configuration"`
+
+	// SchemaVersions contains the schema versions of the configuration records.
+	// A record that is not listed here uses the initial schema, version 0.
+	SchemaVersions map[string]int `json:"schema_versions,omitempty"`
 }
 
 func newConfigurationsSection() *ConfigurationsSection {
-	return &ConfigurationsSection{make(map[string]*json.RawMessage)}
+	return &ConfigurationsSection{
+		Records: make(map[string]*json.RawMessage),
+	}
 }
 
 func (section *ConfigurationsSection) ConfigRecord(configKey string) (*ConfigRecord, error) {
@@ -36,6 +42,23 @@ func (section *ConfigurationsSection) SetConfigRecord(configKey string, rawConfi
 	section.Records[configKey] = &msg
 }
 
+// SchemaVersion returns the schema version of the record for the given configuration key.
+func (section *ConfigurationsSection) SchemaVersion(configKey string) int {
+	return section.SchemaVersions[configKey]
+}
+
+// SetSchemaVersion sets the schema version of the record for the given configuration key.
+func (section *ConfigurationsSection) SetSchemaVersion(configKey string, version int) {
+	if version == 0 {
+		delete(section.SchemaVersions, configKey)
+		return
+	}
+	if section.SchemaVersions == nil {
+		section.SchemaVersions = make(map[string]int)
+	}
+	section.SchemaVersions[configKey] = version
+}
+
 // ConfigRecord ----------------------------------------------------------------
 
 type ConfigRecord struct {
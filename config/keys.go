@@ -0,0 +1,152 @@
+package config
+
+import (
+	// Stdlib
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Key addresses a value stored in a configuration file.
+//
+// A key consists of the configuration key as returned by ConfigSpec.ConfigKey()
+// and the path of JSON field names leading to the value, all joined by dots.
+// For example, salsaflow.core.git.trunk_branch is parsed into configuration key
+// salsaflow.core.git and path [trunk_branch].
+type Key struct {
+	ConfigKey string
+	Path      []string
+}
+
+// ParseKey parses the given key string. Since configuration keys contain dots
+// as well, the list of known configuration keys is necessary to split the string.
+// The longest matching configuration key is used.
+func ParseKey(key string, configKeys []string) (*Key, error) {
+	var match string
+	for _, configKey := range configKeys {
+		if key != configKey && !strings.HasPrefix(key, configKey+".") {
+			continue
+		}
+		if len(configKey) > len(match) {
+			match = configKey
+		}
+	}
+	if match == "" {
+		return nil, &ErrConfigRecordNotFound{key}
+	}
+
+	var path []string
+	if rest := strings.TrimPrefix(key, match); rest != "" {
+		path = strings.Split(rest[1:], ".")
+	}
+	return &Key{match, path}, nil
+}
+
+func (key *Key) String() string {
+	if len(key.Path) == 0 {
+		return key.ConfigKey
+	}
+	return key.ConfigKey + "." + strings.Join(key.Path, ".")
+}
+
+// ConfigKeys returns the configuration keys stored in the section, sorted.
+func (section *ConfigurationsSection) ConfigKeys() []string {
+	keys := make([]string, 0, len(section.Records))
+	for key := range section.Records {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// RecordValue returns the configuration record for the given configuration key
+// decoded into a generic value, i.e. map[string]interface{} in most cases.
+// Numbers are decoded as json.Number so that they are kept as they are.
+func (section *ConfigurationsSection) RecordValue(configKey string) (interface{}, error) {
+	rawMsgPtr, ok := section.Records[configKey]
+	if !ok || rawMsgPtr == nil {
+		return nil, &ErrConfigRecordNotFound{configKey}
+	}
+	return decodeValue(*rawMsgPtr)
+}
+
+// Value returns the value for the given key.
+func (section *ConfigurationsSection) Value(key *Key) (interface{}, error) {
+	record, err := section.RecordValue(key.ConfigKey)
+	if err != nil {
+		return nil, err
+	}
+
+	value := record
+	for i, field := range key.Path {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, &ErrKeyNotSet{key.String()}
+		}
+		value, ok = object[field]
+		if !ok {
+			return nil, &ErrKeyNotSet{(&Key{key.ConfigKey, key.Path[:i+1]}).String()}
+		}
+	}
+	return value, nil
+}
+
+// SetValue sets the value for the given key.
+// The configuration record as well as the intermediate objects are created
+// when missing, the value must be possible to marshal into JSON.
+func (section *ConfigurationsSection) SetValue(key *Key, value interface{}) error {
+	if len(key.Path) == 0 {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		section.SetConfigRecord(key.ConfigKey, raw)
+		return nil
+	}
+
+	record, err := section.RecordValue(key.ConfigKey)
+	if err != nil {
+		if _, ok := err.(*ErrConfigRecordNotFound); !ok {
+			return err
+		}
+		record = make(map[string]interface{})
+	}
+
+	object, ok := record.(map[string]interface{})
+	if !ok {
+		return &ErrKeyInvalid{key.ConfigKey, record}
+	}
+	root := object
+	for i, field := range key.Path[:len(key.Path)-1] {
+		next, ok := object[field]
+		if !ok {
+			next = make(map[string]interface{})
+			object[field] = next
+		}
+		nextObject, ok := next.(map[string]interface{})
+		if !ok {
+			return &ErrKeyInvalid{(&Key{key.ConfigKey, key.Path[:i+1]}).String(), next}
+		}
+		object = nextObject
+	}
+	object[key.Path[len(key.Path)-1]] = value
+
+	raw, err := json.Marshal(root)
+	if err != nil {
+		return err
+	}
+	section.SetConfigRecord(key.ConfigKey, raw)
+	return nil
+}
+
+func decodeValue(raw []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, fmt.Errorf("failed to decode configuration record: %v", err)
+	}
+	return v, nil
+}
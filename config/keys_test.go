@@ -0,0 +1,78 @@
+package config
+
+import (
+	// Stdlib
+	"encoding/json"
+)
+
+var _ = Describe("parsing configuration keys", func() {
+
+	configKeys := []string{"salsaflow.core", "salsaflow.core.git", "salsaflow.modules.github"}
+
+	It("uses the longest configuration key matching", func() {
+		key, err := ParseKey("salsaflow.core.git.trunk_branch", configKeys)
+		Expect(err).To(BeNil())
+		Expect(key.ConfigKey).To(Equal("salsaflow.core.git"))
+		Expect(key.Path).To(Equal([]string{"trunk_branch"}))
+	})
+
+	It("accepts the configuration key itself", func() {
+		key, err := ParseKey("salsaflow.core", configKeys)
+		Expect(err).To(BeNil())
+		Expect(key.ConfigKey).To(Equal("salsaflow.core"))
+		Expect(key.Path).To(BeNil())
+	})
+
+	It("does not match partial key segments", func() {
+		_, err := ParseKey("salsaflow.modules.githubx.token", configKeys)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("accessing configuration values", func() {
+
+	var section *ConfigurationsSection
+
+	BeforeEach(func() {
+		section = newConfigurationsSection()
+		section.SetConfigRecord("salsaflow.core.git", []byte(`{"trunk_branch":"develop","limits":{"n":10}}`))
+	})
+
+	It("returns the values stored", func() {
+		v, err := section.Value(&Key{"salsaflow.core.git", []string{"limits", "n"}})
+		Expect(err).To(BeNil())
+		Expect(v).To(Equal(json.Number("10")))
+	})
+
+	It("returns an error for missing values", func() {
+		_, err := section.Value(&Key{"salsaflow.core.git", []string{"release_branch"}})
+		Expect(err).To(BeAssignableToTypeOf(&ErrKeyNotSet{}))
+	})
+
+	It("creates the intermediate objects when setting values", func() {
+		key := &Key{"salsaflow.core.git", []string{"remote", "name"}}
+		Expect(section.SetValue(key, "upstream")).To(BeNil())
+
+		v, err := section.Value(key)
+		Expect(err).To(BeNil())
+		Expect(v).To(Equal("upstream"))
+
+		v, err = section.Value(&Key{"salsaflow.core.git", []string{"trunk_branch"}})
+		Expect(err).To(BeNil())
+		Expect(v).To(Equal("develop"))
+	})
+
+	It("creates missing configuration records", func() {
+		key := &Key{"salsaflow.modules.github", []string{"token"}}
+		Expect(section.SetValue(key, "secret")).To(BeNil())
+
+		v, err := section.Value(key)
+		Expect(err).To(BeNil())
+		Expect(v).To(Equal("secret"))
+	})
+
+	It("refuses to descend into non-object values", func() {
+		key := &Key{"salsaflow.core.git", []string{"trunk_branch", "name"}}
+		Expect(section.SetValue(key, "x")).To(BeAssignableToTypeOf(&ErrKeyInvalid{}))
+	})
+})
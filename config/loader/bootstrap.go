@@ -16,3 +16,11 @@ func RegisterBootstrapConfigSpec(spec ConfigSpec) {
 	}
 	bootstrapSpecs = append(bootstrapSpecs, spec)
 }
+
+// BootstrapConfigSpecs returns the config specs registered
+// using RegisterBootstrapConfigSpec.
+func BootstrapConfigSpecs() []ConfigSpec {
+	specs := make([]ConfigSpec, len(bootstrapSpecs))
+	copy(specs, bootstrapSpecs)
+	return specs
+}
@@ -31,7 +31,37 @@ func ActiveModule(local *config.LocalConfig, moduleKind ModuleKind) string {
 	return ""
 }
 
+// AdditionalModules returns the IDs of the modules that are active
+// next to the primary module of the given module kind.
+//
+// Only issue tracking modules can be active at the same time at the moment.
+func AdditionalModules(local *config.LocalConfig, moduleKind ModuleKind) []string {
+	if moduleKind != ModuleKindIssueTracking {
+		return nil
+	}
+
+	bindings := local.Modules.AdditionalIssueTracking
+	ids := make([]string, 0, len(bindings))
+	for _, binding := range bindings {
+		ids = append(ids, binding.ModuleId)
+	}
+	return ids
+}
+
+// ActiveModules returns the IDs of all active modules of the given module kind,
+// the primary module being the first one in the list.
+func ActiveModules(local *config.LocalConfig, moduleKind ModuleKind) []string {
+	var ids []string
+	if id := ActiveModule(local, moduleKind); id != "" {
+		ids = append(ids, id)
+	}
+	return append(ids, AdditionalModules(local, moduleKind)...)
+}
+
 // SetActiveModule can be used to set the active module ID for the given module kind.
+//
+// The local configuration is left untouched in case the module
+// is already active as one of the additional modules of the given kind.
 func SetActiveModule(
 	local *config.LocalConfig,
 	moduleKind ModuleKind,
@@ -44,6 +74,12 @@ func SetActiveModule(
 		numField     = modulesType.NumField()
 		kind         = string(moduleKind)
 	)
+	for _, id := range AdditionalModules(local, moduleKind) {
+		if id == moduleId {
+			return false, nil
+		}
+	}
+
 	for i := 0; i < numField; i++ {
 		var (
 			fieldValue = modulesValue.Field(i)
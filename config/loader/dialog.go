@@ -71,6 +71,15 @@ func RunModuleBootstrapDialog(sections ...*ModuleDialogSection) error {
 				return err
 			}
 		}
+
+		// Run the configuration dialog for the additional modules as well.
+		if localConfig != nil {
+			for _, module := range findAdditionalModules(localConfig, modules) {
+				if err := BootstrapConfig(module.ConfigSpec()); err != nil {
+					return err
+				}
+			}
+		}
 	}
 
 	return nil
@@ -96,6 +105,19 @@ func tryToFindActiveModule(local *config.LocalConfig, modules []Module) Module {
 	return nil
 }
 
+func findAdditionalModules(local *config.LocalConfig, modules []Module) []Module {
+	var additional []Module
+	for _, id := range AdditionalModules(local, modules[0].Kind()) {
+		for _, module := range modules {
+			if module.Id() == id {
+				additional = append(additional, module)
+				break
+			}
+		}
+	}
+	return additional
+}
+
 // Dialog ----------------------------------------------------------------------
 
 var dialogTemplate *template.Template
@@ -0,0 +1,73 @@
+package loader
+
+import (
+	// Stdlib
+	"reflect"
+	"strings"
+)
+
+// configField represents a field of a config container
+// together with the path of JSON field names leading to it.
+type configField struct {
+	Path []string
+	reflect.StructField
+}
+
+// IsLeaf returns true when the field is not a struct,
+// i.e. when it holds a value and not another JSON object.
+func (field *configField) IsLeaf() bool {
+	return derefType(field.Type).Kind() != reflect.Struct
+}
+
+// containerFields returns the fields of the given config container.
+func containerFields(container ConfigContainer) []*configField {
+	if lister, ok := container.(FieldLister); ok {
+		return configFields(reflect.StructOf(lister.ConfigFields()), nil)
+	}
+	return configFields(reflect.TypeOf(container), nil)
+}
+
+// configFields returns the exported fields of the given type,
+// descending into nested structs. Embedded structs are flattened
+// the same way encoding/json flattens them.
+func configFields(t reflect.Type, prefix []string) []*configField {
+	t = derefType(t)
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []*configField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" && field.Anonymous {
+			fields = append(fields, configFields(field.Type, prefix)...)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		path := make([]string, len(prefix), len(prefix)+1)
+		copy(path, prefix)
+		path = append(path, name)
+
+		fields = append(fields, &configField{path, field})
+		fields = append(fields, configFields(field.Type, path)...)
+	}
+	return fields
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
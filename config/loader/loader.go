@@ -59,12 +59,57 @@ please contact the module author to have it fixed.
 		)
 	}
 
+	// Make sure the keys passed using -set are valid for the spec.
+	if err := checkOverrideKeys(spec.ConfigKey(), spec.GlobalConfig(), spec.LocalConfig()); err != nil {
+		return err
+	}
+
 	if err := bootstrapGlobalConfig(spec); err != nil {
 		return err
 	}
 	return loadLocalConfig(spec)
 }
 
+// CheckConfig can be used to check SalsaFlow configuration
+// according to the given configuration specification.
+//
+// Both global and local configuration is checked, the configuration dialog
+// is never run and the configuration files are never modified.
+func CheckConfig(spec ConfigSpec) error {
+	if spec == nil {
+		return errs.NewError(
+			"Check configuration according to the specification",
+			errors.New("nil configuration specification provided"))
+	}
+
+	if err := checkOverrideKeys(spec.ConfigKey(), spec.GlobalConfig(), spec.LocalConfig()); err != nil {
+		return err
+	}
+
+	task := "Check global configuration according to the spec"
+	if err := load(&loadArgs{
+		configKind:      "global",
+		configKey:       spec.ConfigKey(),
+		configContainer: spec.GlobalConfig(),
+		readConfig:      readGlobalConfig,
+		disallowPrompt:  true,
+	}); err != nil {
+		return errs.NewError(task, err)
+	}
+
+	task = "Check local configuration according to the spec"
+	if err := load(&loadArgs{
+		configKind:      "local",
+		configKey:       spec.ConfigKey(),
+		configContainer: spec.LocalConfig(),
+		readConfig:      readLocalConfig,
+		disallowPrompt:  true,
+	}); err != nil {
+		return errs.NewError(task, err)
+	}
+	return nil
+}
+
 func bootstrapGlobalConfig(spec ConfigSpec) error {
 	// Run the common loading function with the right arguments.
 	task := "Bootstrap global config according to the spec"
@@ -154,8 +199,8 @@ func loadLocalConfig(spec ConfigSpec) error {
 }
 
 type configFile interface {
+	recordStore
 	ConfigRecord(configKey string) (*config.ConfigRecord, error)
-	SetConfigRecord(configKey string, rawConfig []byte)
 	SaveChanges() error
 }
 
@@ -196,6 +241,39 @@ func load(args *loadArgs) error {
 		configFile = emptyConfig()
 	}
 
+	// Upgrade the config record to the current schema version.
+	// The record is only upgraded in memory here, the configuration file
+	// is only rewritten by 'config migrate' or when the dialog is run.
+	task := fmt.Sprintf("Migrate %v configuration for '%v'", configKind, configKey)
+	applied, err := migrateRecord(configFile, configKey, ConfigKind(configKind))
+	if err != nil {
+		if _, ok := err.(*ErrSchemaVersionUnsupported); ok {
+			hint := `
+The configuration file was written by a newer version of SalsaFlow.
+Please upgrade SalsaFlow, e.g. by running 'pkg upgrade'.
+
+`
+			return errs.NewErrorWithHint(task, err, hint)
+		}
+		return errs.NewError(task, err)
+	}
+	if len(applied) != 0 {
+		log.Warn(fmt.Sprintf(
+			"%v configuration for '%v' is outdated, run 'config migrate' to upgrade it",
+			strings.Title(configKind), configKey))
+	}
+
+	// Collect the values overridden using environment variables or -set flag.
+	// The configuration dialog is disabled in that case, otherwise the values,
+	// often secrets supplied by CI, would end up in the configuration file.
+	overrides, err := overridesForContainer(configKey, container)
+	if err != nil {
+		return err
+	}
+	if len(overrides) != 0 {
+		disallowPrompt = true
+	}
+
 	prompt := func(err error) error {
 		if disallowPrompt {
 			dialogTask := "Prompt the user for configuration according to the spec"
@@ -211,6 +289,25 @@ configuration file or by re-running 'repo bootstrap' command.
 Don't forget to commit the changes.
 
 `
+			if configKind == "global" {
+				dialogHint = `
+The configuration dialog is disabled at the moment.
+
+Please fix the issues manually, either by manually editing the global
+configuration file or by running 'config set'.
+
+`
+			}
+			if len(overrides) != 0 {
+				dialogHint = `
+The configuration dialog is disabled when configuration values
+are overridden using environment variables or -set flag.
+
+Please make sure all the required values are set, either in the
+configuration files or using the overrides.
+
+`
+			}
 			if ex, ok := err.(errs.Err); ok {
 				err := errs.NewErrorWithHint(dialogTask, dialogErr, dialogHint)
 				return errs.NewErrorWithHint(ex.Task(), err, ex.Hint())
@@ -223,29 +320,63 @@ Don't forget to commit the changes.
 	// Find the config record for the given key.
 	// In case there is an error and the prompt is allowed, prompt the user.
 	section, err := configFile.ConfigRecord(configKey)
+	if len(overrides) != 0 {
+		// In case the record is missing, the overrides are applied to an empty one.
+		section, err = applyOverrides(configKey, section, overrides)
+	}
 	if err != nil {
-		return prompt(err)
+		// In case the record is missing and the dialog cannot be run anyway,
+		// the record is treated as empty when all the values are optional.
+		// This keeps the repositories bootstrapped before the container
+		// was introduced working. During bootstrap the dialog is still run
+		// so that the record is created.
+		_, notFound := errs.RootCause(err).(*config.ErrConfigRecordNotFound)
+		if !notFound || !disallowPrompt || !isEmptyRecordValid(configKey, container) {
+			return prompt(err)
+		}
+		section, err = newRecordSection(configKey, []byte("{}")).ConfigRecord(configKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Replace the secret references with the secrets kept in the secret store.
+	if configKind == string(ConfigKindGlobal) {
+		section, err = resolveSecrets(configKey, section, container)
+		if err != nil {
+			task := fmt.Sprintf("Resolve secrets for '%v'", configKey)
+			hint := `
+The secrets can be also passed using environment variables,
+which is handy in case the secret store cannot be accessed.
+
+`
+			return errs.NewErrorWithHint(task, err, hint)
+		}
 	}
 
 	// Unmarshal the record according to the spec.
 	// In case there is an error and the prompt is allowed, prompt the user.
 	if err := unmarshal(section.RawConfig, container); err != nil {
-		fmt.Println()
-		log.Log(fmt.Sprintf(
-			"Failed to unmarshal %v configuration, will try to run the bootstrap dialog",
-			configKind))
-		log.NewLine(fmt.Sprintf("(err = %v)", err.Error()))
+		if !disallowPrompt {
+			fmt.Println()
+			log.Log(fmt.Sprintf(
+				"Failed to unmarshal %v configuration, will try to run the bootstrap dialog",
+				configKind))
+			log.NewLine(fmt.Sprintf("(err = %v)", err.Error()))
+		}
 		return prompt(err)
 	}
 
 	// Validate the returned object according to the spec.
 	// In case there is an error and the prompt is allowed, prompt the user.
 	if err := validate(container, section.Path()); err != nil {
-		fmt.Println()
-		log.Log(fmt.Sprintf(
-			"%v configuration section invalid, will try to run the bootstrap dialog",
-			strings.Title(configKind)))
-		log.NewLine(fmt.Sprintf("(error = %v)", err.Error()))
+		if !disallowPrompt {
+			fmt.Println()
+			log.Log(fmt.Sprintf(
+				"%v configuration section invalid, will try to run the bootstrap dialog",
+				strings.Title(configKind)))
+			log.NewLine(fmt.Sprintf("(error = %v)", err.Error()))
+		}
 		return prompt(err)
 	}
 
@@ -293,10 +424,19 @@ func promptAndWrite(configFile configFile, args *loadArgs) (err error) {
 		return err
 	}
 
+	// Move the secrets into the secret store in case it is enabled.
+	if configKind == string(ConfigKindGlobal) {
+		raw, err = storeSecrets(configKey, raw, container)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Store the result in the config file object.
 	var modified bool
 	if raw != nil {
 		configFile.SetConfigRecord(configKey, json.RawMessage(raw))
+		configFile.SetSchemaVersion(configKey, SchemaVersion(configKey, ConfigKind(configKind)))
 		modified = true
 	}
 
@@ -389,6 +529,19 @@ func validate(container ConfigContainer, sectionPath string) error {
 	return err
 }
 
+// isEmptyRecordValid returns true when an empty config record
+// is valid for the given container, i.e. when all the values are optional.
+func isEmptyRecordValid(configKey string, container ConfigContainer) bool {
+	record, err := newRecordSection(configKey, []byte("{}")).ConfigRecord(configKey)
+	if err != nil {
+		return false
+	}
+	if err := unmarshal(record.RawConfig, container); err != nil {
+		return false
+	}
+	return validate(container, record.Path()) == nil
+}
+
 func readLocalConfig() (configFile, error) {
 	return config.ReadLocalConfig()
 }
@@ -0,0 +1,80 @@
+package loader
+
+import (
+	// Stdlib
+	"testing"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/config"
+	"github.com/salsaflow/salsaflow/flag"
+
+	// Vendor - testing framework
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var (
+	AfterEach  = ginkgo.AfterEach
+	BeforeEach = ginkgo.BeforeEach
+	Describe   = ginkgo.Describe
+	It         = ginkgo.It
+
+	BeEmpty          = gomega.BeEmpty
+	BeNil            = gomega.BeNil
+	ContainSubstring = gomega.ContainSubstring
+	Equal            = gomega.Equal
+	Expect           = gomega.Expect
+	HaveLen          = gomega.HaveLen
+	HaveOccurred     = gomega.HaveOccurred
+)
+
+func TestConfigLoader(t *testing.T) {
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "Configuration loader")
+}
+
+type testOptionalConfig struct {
+	BaseURL string `json:"base_url,omitempty" optional:"true"`
+}
+
+func (c *testOptionalConfig) PromptUserForConfig() error {
+	return nil
+}
+
+type testRequiredConfig struct {
+	Token string `json:"token"`
+}
+
+func (c *testRequiredConfig) PromptUserForConfig() error {
+	return nil
+}
+
+var _ = Describe("loading a configuration record that is missing", func() {
+
+	const configKey = "salsaflow.modules.releasenotes.test"
+
+	BeforeEach(func() {
+		appflags.FlagSet = flag.NewKeyValueFlag()
+	})
+
+	loadMissing := func(container ConfigContainer) error {
+		return load(&loadArgs{
+			configKind:      "local",
+			configKey:       configKey,
+			configContainer: container,
+			readConfig: func() (configFile, error) {
+				return config.NewEmptyLocalConfig(), nil
+			},
+			disallowPrompt: true,
+		})
+	}
+
+	It("treats the record as empty when all the values are optional", func() {
+		Expect(loadMissing(&testOptionalConfig{})).To(BeNil())
+	})
+
+	It("returns an error when there are required values", func() {
+		Expect(loadMissing(&testRequiredConfig{})).To(HaveOccurred())
+	})
+})
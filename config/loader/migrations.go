@@ -0,0 +1,149 @@
+package loader
+
+import (
+	// Stdlib
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/config"
+)
+
+// ConfigKind specifies the configuration file a configuration record is stored in.
+type ConfigKind string
+
+const (
+	ConfigKindGlobal ConfigKind = "global"
+	ConfigKindLocal  ConfigKind = "local"
+)
+
+// Migration upgrades a configuration record from one schema version to the next one.
+type Migration struct {
+	// Description is a short description of the changes being made.
+	Description string
+
+	// Migrate modifies the given record in place. The record is the raw JSON object
+	// as stored in the configuration file, numbers are represented as json.Number.
+	Migrate func(record map[string]interface{}) error
+}
+
+type migrationKey struct {
+	configKey string
+	kind      ConfigKind
+}
+
+var (
+	migrations     = make(map[migrationKey][]*Migration)
+	migrationsLock sync.Mutex
+)
+
+// RegisterMigrations registers the migrations for the configuration record
+// stored under the given configuration key in the given configuration file.
+//
+// The migrations are numbered by the order they are registered in,
+// the first migration upgrades the record from version 0 to version 1 and so on.
+// The current schema version of the record is thus the number of migrations
+// registered. Config specs are expected to register the migrations in init.
+func RegisterMigrations(configKey string, kind ConfigKind, ms ...*Migration) {
+	migrationsLock.Lock()
+	defer migrationsLock.Unlock()
+
+	key := migrationKey{configKey, kind}
+	migrations[key] = append(migrations[key], ms...)
+}
+
+// SchemaVersion returns the current schema version of the configuration record
+// stored under the given configuration key in the given configuration file.
+func SchemaVersion(configKey string, kind ConfigKind) int {
+	return len(migrationsFor(configKey, kind))
+}
+
+func migrationsFor(configKey string, kind ConfigKind) []*Migration {
+	migrationsLock.Lock()
+	defer migrationsLock.Unlock()
+	return migrations[migrationKey{configKey, kind}]
+}
+
+// ErrSchemaVersionUnsupported is returned when a configuration record
+// was written by a newer version of SalsaFlow than the one being used.
+type ErrSchemaVersionUnsupported struct {
+	ConfigKey string
+	Kind      ConfigKind
+	Version   int
+	Supported int
+}
+
+func (err *ErrSchemaVersionUnsupported) Error() string {
+	return fmt.Sprintf(
+		"%v configuration for '%v' uses schema version %v, only versions up to %v are supported",
+		err.Kind, err.ConfigKey, err.Version, err.Supported)
+}
+
+// recordStore is implemented by config.ConfigurationsSection,
+// which is embedded in both configuration file objects.
+type recordStore interface {
+	RecordValue(configKey string) (interface{}, error)
+	SetConfigRecord(configKey string, rawConfig []byte)
+	SchemaVersion(configKey string) int
+	SetSchemaVersion(configKey string, version int)
+}
+
+// MigrateRecord upgrades the configuration record stored in the given section
+// under the given configuration key to the current schema version.
+// The descriptions of the migrations applied are returned.
+//
+// ErrSchemaVersionUnsupported is returned in case the record
+// uses a schema version that is newer than the current one.
+func MigrateRecord(
+	section *config.ConfigurationsSection,
+	configKey string,
+	kind ConfigKind,
+) ([]string, error) {
+
+	return migrateRecord(section, configKey, kind)
+}
+
+func migrateRecord(store recordStore, configKey string, kind ConfigKind) ([]string, error) {
+	var (
+		ms      = migrationsFor(configKey, kind)
+		current = len(ms)
+		version = store.SchemaVersion(configKey)
+	)
+	switch {
+	case version > current:
+		return nil, &ErrSchemaVersionUnsupported{configKey, kind, version, current}
+	case version == current:
+		return nil, nil
+	}
+
+	record, err := store.RecordValue(configKey)
+	if err != nil {
+		if _, ok := err.(*config.ErrConfigRecordNotFound); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+	object, ok := record.(map[string]interface{})
+	if !ok {
+		return nil, &config.ErrKeyInvalid{Key: configKey, Value: record}
+	}
+
+	var applied []string
+	for v := version; v < current; v++ {
+		if err := ms[v].Migrate(object); err != nil {
+			return nil, fmt.Errorf(
+				"failed to migrate %v configuration for '%v' from version %v to version %v: %v",
+				kind, configKey, v, v+1, err)
+		}
+		applied = append(applied, ms[v].Description)
+	}
+
+	raw, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	store.SetConfigRecord(configKey, raw)
+	store.SetSchemaVersion(configKey, current)
+	return applied, nil
+}
@@ -0,0 +1,86 @@
+package loader
+
+import (
+	// Stdlib
+	"encoding/json"
+	"errors"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/config"
+)
+
+var _ = Describe("migrating configuration records", func() {
+
+	const configKey = "salsaflow.modules.issuetracking.migrationtest"
+
+	var section *config.ConfigurationsSection
+
+	RegisterMigrations(configKey, ConfigKindLocal,
+		&Migration{
+			Description: "rename labels to story_labels",
+			Migrate: func(record map[string]interface{}) error {
+				record["story_labels"] = record["labels"]
+				delete(record, "labels")
+				return nil
+			},
+		},
+		&Migration{
+			Description: "add skip_check_labels",
+			Migrate: func(record map[string]interface{}) error {
+				if _, ok := record["story_labels"]; !ok {
+					return errors.New("story_labels missing")
+				}
+				record["skip_check_labels"] = []interface{}{}
+				return nil
+			},
+		},
+	)
+
+	BeforeEach(func() {
+		section = &config.ConfigurationsSection{Records: make(map[string]*json.RawMessage)}
+		section.SetConfigRecord(configKey, []byte(`{"labels":["a"]}`))
+	})
+
+	It("returns the current schema version", func() {
+		Expect(SchemaVersion(configKey, ConfigKindLocal)).To(Equal(2))
+		Expect(SchemaVersion(configKey, ConfigKindGlobal)).To(Equal(0))
+	})
+
+	It("upgrades the record to the current schema version", func() {
+		applied, err := MigrateRecord(section, configKey, ConfigKindLocal)
+		Expect(err).To(BeNil())
+		Expect(applied).To(HaveLen(2))
+		Expect(section.SchemaVersion(configKey)).To(Equal(2))
+
+		record, err := section.RecordValue(configKey)
+		Expect(err).To(BeNil())
+		Expect(record).To(Equal(map[string]interface{}{
+			"story_labels":      []interface{}{"a"},
+			"skip_check_labels": []interface{}{},
+		}))
+	})
+
+	It("only applies the missing migrations", func() {
+		section.SetConfigRecord(configKey, []byte(`{"story_labels":["a"]}`))
+		section.SetSchemaVersion(configKey, 1)
+
+		applied, err := MigrateRecord(section, configKey, ConfigKindLocal)
+		Expect(err).To(BeNil())
+		Expect(applied).To(Equal([]string{"add skip_check_labels"}))
+	})
+
+	It("does nothing for records that are up to date", func() {
+		section.SetSchemaVersion(configKey, 2)
+
+		applied, err := MigrateRecord(section, configKey, ConfigKindLocal)
+		Expect(err).To(BeNil())
+		Expect(applied).To(BeEmpty())
+	})
+
+	It("refuses records using a newer schema version", func() {
+		section.SetSchemaVersion(configKey, 3)
+
+		_, err := MigrateRecord(section, configKey, ConfigKindLocal)
+		Expect(err).To(Equal(&ErrSchemaVersionUnsupported{configKey, ConfigKindLocal, 3, 2}))
+	})
+})
@@ -0,0 +1,196 @@
+package loader
+
+import (
+	// Stdlib
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/config"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/log"
+)
+
+// EnvVarPrefix is the prefix shared by all environment variables
+// that can be used to override configuration values.
+const EnvVarPrefix = "SALSAFLOW_"
+
+// Override represents a configuration value that is set outside of
+// the configuration files, i.e. using an environment variable or -set flag.
+type Override struct {
+	Key    *config.Key
+	Value  interface{}
+	Source string
+}
+
+var nonAlphanumeric = regexp.MustCompile("[^A-Za-z0-9]+")
+
+// EnvVarName returns the name of the environment variable that can be used
+// to override the value for the given key.
+//
+// The name consists of EnvVarPrefix followed by the configuration key without
+// the salsaflow.core or salsaflow.modules prefix and the path of the value,
+// all uppercased and joined by underscores. For example, the token for
+// salsaflow.modules.issuetracking.github can be overridden using
+// SALSAFLOW_ISSUETRACKING_GITHUB_TOKEN.
+func EnvVarName(key *config.Key) string {
+	configKey := strings.TrimPrefix(key.ConfigKey, "salsaflow.")
+	for _, group := range []string{"core.", "modules."} {
+		configKey = strings.TrimPrefix(configKey, group)
+	}
+	parts := append([]string{configKey}, key.Path...)
+	name := nonAlphanumeric.ReplaceAllString(strings.Join(parts, "_"), "_")
+	return EnvVarPrefix + strings.ToUpper(name)
+}
+
+// Overrides returns the overrides applicable to the given config spec.
+//
+// The environment variables are collected first, then the values passed
+// using -set flag, so in case the same key is set using both ways,
+// the value passed using the flag is applied last and takes precedence.
+func Overrides(spec ConfigSpec) ([]*Override, error) {
+	containers := []ConfigContainer{spec.GlobalConfig(), spec.LocalConfig()}
+	if err := checkOverrideKeys(spec.ConfigKey(), containers...); err != nil {
+		return nil, err
+	}
+
+	var overrides []*Override
+	for _, container := range containers {
+		containerOverrides, err := overridesForContainer(spec.ConfigKey(), container)
+		if err != nil {
+			return nil, err
+		}
+		overrides = append(overrides, containerOverrides...)
+	}
+	return overrides, nil
+}
+
+func overridesForContainer(configKey string, container ConfigContainer) ([]*Override, error) {
+	if container == nil {
+		return nil, nil
+	}
+
+	var overrides []*Override
+	fields := containerFields(container)
+
+	// Check the environment variables.
+	for _, field := range fields {
+		if !field.IsLeaf() {
+			continue
+		}
+		key := &config.Key{ConfigKey: configKey, Path: field.Path}
+		name := EnvVarName(key)
+		raw, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		value, err := parseOverride(field, raw)
+		if err != nil {
+			task := fmt.Sprintf("Parse environment variable %v", name)
+			return nil, errs.NewError(task, err)
+		}
+		overrides = append(overrides, &Override{key, value, "environment variable " + name})
+	}
+
+	// Check the values passed using -set flag.
+	for _, kv := range appflags.FlagSet.Values {
+		if !strings.HasPrefix(kv.Key, configKey+".") {
+			continue
+		}
+		path := strings.Split(strings.TrimPrefix(kv.Key, configKey+"."), ".")
+		field := findField(fields, path)
+		if field == nil {
+			continue
+		}
+		value, err := parseOverride(field, kv.Value)
+		if err != nil {
+			task := fmt.Sprintf("Parse the value passed using -set for key '%v'", kv.Key)
+			return nil, errs.NewError(task, err)
+		}
+		key := &config.Key{ConfigKey: configKey, Path: path}
+		overrides = append(overrides, &Override{key, value, "flag -set"})
+	}
+
+	return overrides, nil
+}
+
+// checkOverrideKeys makes sure that every key passed using -set flag
+// that belongs to the given configuration key matches a field
+// in one of the given containers, so that typos do not go unnoticed.
+func checkOverrideKeys(configKey string, containers ...ConfigContainer) error {
+	var fields []*configField
+	for _, container := range containers {
+		if container != nil {
+			fields = append(fields, containerFields(container)...)
+		}
+	}
+
+	for _, kv := range appflags.FlagSet.Values {
+		if !strings.HasPrefix(kv.Key, configKey+".") {
+			continue
+		}
+		path := strings.Split(strings.TrimPrefix(kv.Key, configKey+"."), ".")
+		if findField(fields, path) == nil {
+			task := fmt.Sprintf("Check the key passed using -set for '%v'", configKey)
+			return errs.NewError(task, fmt.Errorf("unknown configuration key '%v'", kv.Key))
+		}
+	}
+	return nil
+}
+
+func findField(fields []*configField, path []string) *configField {
+	for _, field := range fields {
+		if reflect.DeepEqual(field.Path, path) {
+			return field
+		}
+	}
+	return nil
+}
+
+// parseOverride returns the string as it is in case the field is a string,
+// otherwise the string is expected to contain the value encoded as JSON.
+func parseOverride(field *configField, raw string) (interface{}, error) {
+	if derefType(field.Type).Kind() == reflect.String {
+		return raw, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(raw)))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON value for field %v: %v",
+			strings.Join(field.Path, "."), err)
+	}
+	return v, nil
+}
+
+// applyOverrides returns a copy of the given config record with the overrides applied.
+// The record can be nil, in which case the record is assembled from scratch.
+func applyOverrides(
+	configKey string,
+	record *config.ConfigRecord,
+	overrides []*Override,
+) (*config.ConfigRecord, error) {
+
+	raw := []byte("{}")
+	if record != nil {
+		raw = record.RawConfig
+	}
+
+	section := newRecordSection(configKey, raw)
+
+	logger := log.V(log.Verbose)
+	for _, override := range overrides {
+		if err := section.SetValue(override.Key, override.Value); err != nil {
+			return nil, err
+		}
+		logger.Log(fmt.Sprintf("Using %v for '%v'", override.Source, override.Key))
+	}
+
+	return section.ConfigRecord(configKey)
+}
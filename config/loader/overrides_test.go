@@ -0,0 +1,116 @@
+package loader
+
+import (
+	// Stdlib
+	"encoding/json"
+	"os"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/config"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/flag"
+)
+
+type testOverridesConfig struct {
+	Token  string `json:"token"`
+	Limit  int    `json:"limit"`
+	Nested struct {
+		Labels []string `json:"labels"`
+	} `json:"nested"`
+}
+
+func (c *testOverridesConfig) PromptUserForConfig() error {
+	return nil
+}
+
+var _ = Describe("overriding configuration values", func() {
+
+	const configKey = "salsaflow.modules.issuetracking.test"
+
+	BeforeEach(func() {
+		appflags.FlagSet = flag.NewKeyValueFlag()
+	})
+
+	AfterEach(func() {
+		os.Unsetenv("SALSAFLOW_ISSUETRACKING_TEST_TOKEN")
+		os.Unsetenv("SALSAFLOW_ISSUETRACKING_TEST_LIMIT")
+	})
+
+	It("derives the environment variable names", func() {
+		Expect(EnvVarName(&config.Key{ConfigKey: "salsaflow.core.git", Path: []string{"trunk_branch"}})).
+			To(Equal("SALSAFLOW_GIT_TRUNK_BRANCH"))
+		Expect(EnvVarName(&config.Key{ConfigKey: configKey, Path: []string{"nested", "labels"}})).
+			To(Equal("SALSAFLOW_ISSUETRACKING_TEST_NESTED_LABELS"))
+	})
+
+	It("returns no overrides when nothing is set", func() {
+		overrides, err := overridesForContainer(configKey, &testOverridesConfig{})
+		Expect(err).To(BeNil())
+		Expect(overrides).To(BeEmpty())
+	})
+
+	It("applies environment variables and the flag values, the flag taking precedence", func() {
+		os.Setenv("SALSAFLOW_ISSUETRACKING_TEST_TOKEN", "env-token")
+		os.Setenv("SALSAFLOW_ISSUETRACKING_TEST_LIMIT", "10")
+		appflags.FlagSet.Set(configKey + `.nested.labels=["a","b"]`)
+		appflags.FlagSet.Set(configKey + ".token=flag-token")
+		appflags.FlagSet.Set("salsaflow.core.git.trunk_branch=master")
+
+		overrides, err := overridesForContainer(configKey, &testOverridesConfig{})
+		Expect(err).To(BeNil())
+		Expect(overrides).To(HaveLen(4))
+
+		record, err := applyOverrides(configKey, nil, overrides)
+		Expect(err).To(BeNil())
+
+		var c testOverridesConfig
+		Expect(json.Unmarshal(record.RawConfig, &c)).To(BeNil())
+		Expect(c.Token).To(Equal("flag-token"))
+		Expect(c.Limit).To(Equal(10))
+		Expect(c.Nested.Labels).To(Equal([]string{"a", "b"}))
+	})
+
+	It("keeps the values that are not overridden", func() {
+		os.Setenv("SALSAFLOW_ISSUETRACKING_TEST_TOKEN", "env-token")
+
+		overrides, err := overridesForContainer(configKey, &testOverridesConfig{})
+		Expect(err).To(BeNil())
+
+		section := &config.ConfigurationsSection{Records: make(map[string]*json.RawMessage)}
+		section.SetConfigRecord(configKey, []byte(`{"token":"file-token","limit":5}`))
+		original, err := section.ConfigRecord(configKey)
+		Expect(err).To(BeNil())
+
+		record, err := applyOverrides(configKey, original, overrides)
+		Expect(err).To(BeNil())
+
+		var c testOverridesConfig
+		Expect(json.Unmarshal(record.RawConfig, &c)).To(BeNil())
+		Expect(c.Token).To(Equal("env-token"))
+		Expect(c.Limit).To(Equal(5))
+	})
+
+	It("fails on the keys passed using -set that match no field", func() {
+		appflags.FlagSet.Set(configKey + ".tokne=flag-token")
+		appflags.FlagSet.Set("salsaflow.core.git.trunk_branch=master")
+
+		err := checkOverrideKeys(configKey, &testOverridesConfig{}, nil)
+		Expect(err).To(HaveOccurred())
+		Expect(errs.RootCause(err).Error()).To(ContainSubstring(configKey + ".tokne"))
+	})
+
+	It("accepts the keys passed using -set matching a field in any container", func() {
+		appflags.FlagSet.Set(configKey + ".nested.labels=[]")
+		appflags.FlagSet.Set(configKey + ".token=flag-token")
+
+		Expect(checkOverrideKeys(configKey, nil, &testOverridesConfig{})).To(BeNil())
+	})
+
+	It("fails on invalid JSON values", func() {
+		os.Setenv("SALSAFLOW_ISSUETRACKING_TEST_LIMIT", "ten")
+
+		_, err := overridesForContainer(configKey, &testOverridesConfig{})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,163 @@
+package loader
+
+import (
+	// Stdlib
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/config"
+)
+
+// SecretRefPrefix is the prefix of the values stored in the global configuration
+// file in place of the secrets that are kept in the secret store.
+// The rest of the value is the key the secret is stored under.
+const SecretRefPrefix = "secret:"
+
+// SecretStore keeps the values of the fields tagged as secret
+// outside of the global configuration file.
+type SecretStore interface {
+
+	// Enabled returns true when the secrets are to be kept in the store.
+	// Otherwise the secrets are written into the global configuration file.
+	Enabled() (bool, error)
+
+	// Get returns the secret stored under the given key.
+	Get(key string) (string, error)
+
+	// Set stores the secret under the given key.
+	Set(key, secret string) error
+}
+
+var secretStore SecretStore
+
+// RegisterSecretStore sets the store used to resolve the secrets.
+//
+// This function is exported for internal use by SalsaFlow packages,
+// it is not supposed to be used by modules.
+func RegisterSecretStore(store SecretStore) {
+	secretStore = store
+}
+
+// ErrSecretStoreNotAvailable is returned when a secret reference
+// is encountered, but there is no secret store registered.
+var ErrSecretStoreNotAvailable = errors.New("secret store not available")
+
+// IsSecretRef returns true when the given value references a secret.
+func IsSecretRef(value interface{}) bool {
+	s, ok := value.(string)
+	return ok && strings.HasPrefix(s, SecretRefPrefix)
+}
+
+// ResolveSecretRef returns the secret referenced by the given value.
+func ResolveSecretRef(ref string) (string, error) {
+	if secretStore == nil {
+		return "", ErrSecretStoreNotAvailable
+	}
+	return secretStore.Get(strings.TrimPrefix(ref, SecretRefPrefix))
+}
+
+// secretFields returns the fields of the given container tagged as secret.
+func secretFields(container ConfigContainer) []*configField {
+	var fields []*configField
+	for _, field := range containerFields(container) {
+		if field.Tag.Get("secret") != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// resolveSecrets returns a copy of the given record with the secret references
+// replaced by the secrets. The record is returned as it is in case
+// there are no references to resolve.
+func resolveSecrets(
+	configKey string,
+	record *config.ConfigRecord,
+	container ConfigContainer,
+) (*config.ConfigRecord, error) {
+
+	fields := secretFields(container)
+	if len(fields) == 0 {
+		return record, nil
+	}
+
+	section := newRecordSection(configKey, record.RawConfig)
+	var resolved bool
+	for _, field := range fields {
+		key := &config.Key{ConfigKey: configKey, Path: field.Path}
+		value, err := section.Value(key)
+		if err != nil || !IsSecretRef(value) {
+			continue
+		}
+
+		secret, err := ResolveSecretRef(value.(string))
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve secret for '%v': %v", key, err)
+		}
+		if err := section.SetValue(key, secret); err != nil {
+			return nil, err
+		}
+		resolved = true
+	}
+	if !resolved {
+		return record, nil
+	}
+	return section.ConfigRecord(configKey)
+}
+
+// storeSecrets moves the secrets contained in the given raw record
+// into the secret store, the secrets are replaced by the references.
+// The record is returned as it is in case the secret store is not enabled.
+func storeSecrets(configKey string, raw []byte, container ConfigContainer) ([]byte, error) {
+	if secretStore == nil {
+		return raw, nil
+	}
+	fields := secretFields(container)
+	if len(fields) == 0 {
+		return raw, nil
+	}
+	enabled, err := secretStore.Enabled()
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return raw, nil
+	}
+
+	section := newRecordSection(configKey, raw)
+	for _, field := range fields {
+		key := &config.Key{ConfigKey: configKey, Path: field.Path}
+		value, err := section.Value(key)
+		if err != nil {
+			continue
+		}
+		secret, ok := value.(string)
+		if !ok || secret == "" || IsSecretRef(secret) {
+			continue
+		}
+
+		if err := secretStore.Set(key.String(), secret); err != nil {
+			return nil, fmt.Errorf("failed to store secret for '%v': %v", key, err)
+		}
+		if err := section.SetValue(key, SecretRefPrefix+key.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	record, err := section.ConfigRecord(configKey)
+	if err != nil {
+		return nil, err
+	}
+	return record.RawConfig, nil
+}
+
+func newRecordSection(configKey string, raw []byte) *config.ConfigurationsSection {
+	section := &config.ConfigurationsSection{
+		Records: make(map[string]*json.RawMessage, 1),
+	}
+	section.SetConfigRecord(configKey, raw)
+	return section
+}
@@ -0,0 +1,97 @@
+package loader
+
+import (
+	// Stdlib
+	"encoding/json"
+)
+
+type testSecretStore struct {
+	enabled bool
+	secrets map[string]string
+}
+
+func (store *testSecretStore) Enabled() (bool, error) {
+	return store.enabled, nil
+}
+
+func (store *testSecretStore) Get(key string) (string, error) {
+	return store.secrets[key], nil
+}
+
+func (store *testSecretStore) Set(key, secret string) error {
+	store.secrets[key] = secret
+	return nil
+}
+
+type testSecretsConfig struct {
+	Token string `json:"token" secret:"true"`
+	Owner string `json:"owner"`
+}
+
+func (c *testSecretsConfig) PromptUserForConfig() error {
+	return nil
+}
+
+var _ = Describe("keeping secrets in the secret store", func() {
+
+	const configKey = "salsaflow.modules.issuetracking.test"
+
+	var store *testSecretStore
+
+	BeforeEach(func() {
+		store = &testSecretStore{true, make(map[string]string)}
+		RegisterSecretStore(store)
+	})
+
+	AfterEach(func() {
+		RegisterSecretStore(nil)
+	})
+
+	It("moves the secrets into the store when writing the record", func() {
+		raw, err := storeSecrets(configKey, []byte(`{"token":"s3cr3t","owner":"me"}`), &testSecretsConfig{})
+		Expect(err).To(BeNil())
+		Expect(store.secrets).To(Equal(map[string]string{configKey + ".token": "s3cr3t"}))
+
+		var c testSecretsConfig
+		Expect(json.Unmarshal(raw, &c)).To(BeNil())
+		Expect(c.Token).To(Equal(SecretRefPrefix + configKey + ".token"))
+		Expect(c.Owner).To(Equal("me"))
+	})
+
+	It("keeps the secrets in the record when the store is not enabled", func() {
+		store.enabled = false
+
+		raw := []byte(`{"token":"s3cr3t","owner":"me"}`)
+		stored, err := storeSecrets(configKey, raw, &testSecretsConfig{})
+		Expect(err).To(BeNil())
+		Expect(stored).To(Equal(raw))
+		Expect(store.secrets).To(BeEmpty())
+	})
+
+	It("resolves the secret references when loading the record", func() {
+		store.secrets[configKey+".token"] = "s3cr3t"
+
+		section := newRecordSection(configKey,
+			[]byte(`{"token":"`+SecretRefPrefix+configKey+`.token","owner":"me"}`))
+		record, err := section.ConfigRecord(configKey)
+		Expect(err).To(BeNil())
+
+		record, err = resolveSecrets(configKey, record, &testSecretsConfig{})
+		Expect(err).To(BeNil())
+
+		var c testSecretsConfig
+		Expect(json.Unmarshal(record.RawConfig, &c)).To(BeNil())
+		Expect(c.Token).To(Equal("s3cr3t"))
+	})
+
+	It("fails to resolve the references when there is no store", func() {
+		RegisterSecretStore(nil)
+
+		section := newRecordSection(configKey, []byte(`{"token":"`+SecretRefPrefix+`x"}`))
+		record, err := section.ConfigRecord(configKey)
+		Expect(err).To(BeNil())
+
+		_, err = resolveSecrets(configKey, record, &testSecretsConfig{})
+		Expect(err).To(HaveOccurred())
+	})
+})
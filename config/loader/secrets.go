@@ -0,0 +1,26 @@
+package loader
+
+import (
+	// Internal
+	"github.com/salsaflow/salsaflow/config"
+)
+
+// SecretKeys returns the keys of the fields tagged as secret
+// in both global and local configuration containers of the given spec.
+//
+// Fields are marked as secret using `secret:"true"` struct tag,
+// the same tag that makes the configuration dialog hide the input.
+func SecretKeys(spec ConfigSpec) []*config.Key {
+	var keys []*config.Key
+	for _, container := range []ConfigContainer{spec.GlobalConfig(), spec.LocalConfig()} {
+		if container == nil {
+			continue
+		}
+		for _, field := range containerFields(container) {
+			if field.Tag.Get("secret") != "" {
+				keys = append(keys, &config.Key{ConfigKey: spec.ConfigKey(), Path: field.Path})
+			}
+		}
+	}
+	return keys
+}
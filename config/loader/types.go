@@ -1,5 +1,10 @@
 package loader
 
+import (
+	// Stdlib
+	"reflect"
+)
+
 type ModuleKind string
 
 const (
@@ -68,3 +73,10 @@ type Unmarshaller interface {
 type Validator interface {
 	Validate(sectionPath string) error
 }
+
+// A ConfigContainer can implement FieldLister in case its fields are only known
+// at runtime. The fields returned are handled as if they were the fields
+// of the container struct, so that all the struct tags apply as usual.
+type FieldLister interface {
+	ConfigFields() []reflect.StructField
+}
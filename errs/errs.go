@@ -3,9 +3,12 @@ package errs
 import (
 	// Stdlib
 	"fmt"
+	"os"
 
 	// Internal
 	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/output"
+	"github.com/salsaflow/salsaflow/trace"
 )
 
 type Err interface {
@@ -125,10 +128,54 @@ func LogErrorWithHint(task string, err error, hint string) error {
 	return Log(NewErrorWithHint(task, err, hint))
 }
 
-// Fatal logs the error and exists the program with os.Exit(1).
+// Fatal logs the error and exists the program
+// with the exit code associated with the error.
 func Fatal(err error) {
 	Log(err)
-	log.Fatalln("\nFatal error: " + err.Error())
+	log.Println("\nFatal error: " + err.Error())
+	Terminate(err)
+}
+
+// Terminate exits the program with the exit code associated with the error.
+// In case the JSON output mode is active, the result is written before exiting.
+func Terminate(err error) {
+	code := ExitCode(err)
+	output.SetError(toOutputError(err, code))
+	if ex := output.Flush(); ex != nil {
+		log.Println("\nFailed to write the result: " + ex.Error())
+	}
+	trace.Close(code)
+	os.Exit(code)
+}
+
+func toOutputError(err error, exitCode int) *output.Error {
+	outErr := &output.Error{
+		Message:   err.Error(),
+		RootCause: RootCause(err).Error(),
+		ExitCode:  exitCode,
+	}
+	for {
+		ex, ok := err.(Err)
+		if !ok {
+			break
+		}
+		outErr.Chain = append(outErr.Chain, &output.ErrorFrame{
+			Task: ex.Task(),
+			Hint: ex.Hint(),
+		})
+		err = ex.Err()
+	}
+	if len(outErr.Chain) != 0 {
+		outErr.Task = outErr.Chain[0].Task
+	}
+	// Use the outermost hint available.
+	for _, frame := range outErr.Chain {
+		if frame.Hint != "" {
+			outErr.Hint = frame.Hint
+			break
+		}
+	}
+	return outErr
 }
 
 // RootCause returns the error deepest in the error chain.
@@ -0,0 +1,24 @@
+package errs
+
+import (
+	// Stdlib
+	"testing"
+
+	// Vendor - testing framework
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var (
+	Describe = ginkgo.Describe
+	It       = ginkgo.It
+
+	Equal   = gomega.Equal
+	Expect  = gomega.Expect
+	HaveLen = gomega.HaveLen
+)
+
+func TestErrs(t *testing.T) {
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "Errors")
+}
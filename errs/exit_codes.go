@@ -0,0 +1,80 @@
+package errs
+
+import (
+	// Stdlib
+	"errors"
+	"sync"
+)
+
+// Exit codes returned by SalsaFlow.
+//
+// Every category of failure that may be interesting for scripts
+// invoking SalsaFlow gets its own exit code, the rest is ExitCodeFailure.
+const (
+	ExitCodeSuccess         = 0
+	ExitCodeFailure         = 1
+	ExitCodeUsage           = 2
+	ExitCodeDirtyRepository = 3
+	ExitCodeRefNotInSync    = 4
+	ExitCodeNotStageable    = 5
+	ExitCodeNotClosable     = 6
+	ExitCodeCanceled        = 7
+)
+
+// ErrInvalidUsage is the root cause used when a command is invoked
+// with invalid arguments or flags. It is mapped to ExitCodeUsage.
+var ErrInvalidUsage = errors.New("invalid command usage")
+
+func init() {
+	RegisterExitCodeForError(ExitCodeUsage, ErrInvalidUsage)
+}
+
+// ExitCodeMatcher returns true when the given root cause error
+// belongs to the category associated with the matcher.
+type ExitCodeMatcher func(rootCause error) bool
+
+type exitCodeRecord struct {
+	code    int
+	matcher ExitCodeMatcher
+}
+
+var (
+	exitCodes     []*exitCodeRecord
+	exitCodesLock sync.Mutex
+)
+
+// RegisterExitCode associates the given exit code with the errors
+// matched by the given matcher. The packages defining the errors
+// are expected to register them in their init functions.
+func RegisterExitCode(code int, matcher ExitCodeMatcher) {
+	exitCodesLock.Lock()
+	exitCodes = append(exitCodes, &exitCodeRecord{code, matcher})
+	exitCodesLock.Unlock()
+}
+
+// RegisterExitCodeForError is a shortcut for registering a sentinel error value.
+func RegisterExitCodeForError(code int, target error) {
+	RegisterExitCode(code, func(err error) bool {
+		return err == target
+	})
+}
+
+// ExitCode returns the exit code associated with the root cause of the given error.
+// ExitCodeFailure is returned when no specific exit code is registered.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeSuccess
+	}
+
+	rootCause := RootCause(err)
+
+	exitCodesLock.Lock()
+	defer exitCodesLock.Unlock()
+
+	for _, record := range exitCodes {
+		if record.matcher(rootCause) {
+			return record.code
+		}
+	}
+	return ExitCodeFailure
+}
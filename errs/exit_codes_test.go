@@ -0,0 +1,63 @@
+package errs
+
+import (
+	// Stdlib
+	"errors"
+	"fmt"
+)
+
+type errTestRef struct {
+	ref string
+}
+
+func (err *errTestRef) Error() string {
+	return fmt.Sprintf("ref '%v' is not up to date", err.ref)
+}
+
+var errTestSentinel = errors.New("test sentinel")
+
+func init() {
+	RegisterExitCodeForError(ExitCodeNotClosable, errTestSentinel)
+	RegisterExitCode(ExitCodeRefNotInSync, func(err error) bool {
+		_, ok := err.(*errTestRef)
+		return ok
+	})
+}
+
+var _ = Describe("exit codes", func() {
+
+	It("should map no error to success", func() {
+		Expect(ExitCode(nil)).To(Equal(ExitCodeSuccess))
+	})
+
+	It("should map the root cause of the error chain", func() {
+		err := NewError("Close the release",
+			NewErrorWithHint("Check the reviews", errTestSentinel, "\nClose the reviews\n"))
+		Expect(ExitCode(err)).To(Equal(ExitCodeNotClosable))
+
+		err = NewError("Push the branch", &errTestRef{"develop"})
+		Expect(ExitCode(err)).To(Equal(ExitCodeRefNotInSync))
+	})
+
+	It("should map invalid usage to the usage exit code", func() {
+		Expect(ExitCode(ErrInvalidUsage)).To(Equal(ExitCodeUsage))
+	})
+
+	It("should fall back to the generic failure", func() {
+		err := NewError("Do something", errors.New("something went wrong"))
+		Expect(ExitCode(err)).To(Equal(ExitCodeFailure))
+	})
+
+	It("should turn the error chain into the JSON error", func() {
+		err := NewError("Close the release",
+			NewErrorWithHint("Check the reviews", errTestSentinel, "\nClose the reviews\n"))
+
+		outErr := toOutputError(err, ExitCode(err))
+		Expect(outErr.Task).To(Equal("Close the release"))
+		Expect(outErr.Hint).To(Equal("\nClose the reviews\n"))
+		Expect(outErr.RootCause).To(Equal("test sentinel"))
+		Expect(outErr.ExitCode).To(Equal(ExitCodeNotClosable))
+		Expect(outErr.Chain).To(HaveLen(2))
+		Expect(outErr.Chain[1].Task).To(Equal("Check the reviews"))
+	})
+})
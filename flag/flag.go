@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strings"
 )
 
 type RegexpSetFlag struct {
@@ -58,3 +59,29 @@ func (enum *StringEnumFlag) Set(value string) error {
 func (enum *StringEnumFlag) Value() string {
 	return enum.value
 }
+
+type KeyValue struct {
+	Key   string
+	Value string
+}
+
+type KeyValueFlag struct {
+	Values []KeyValue
+}
+
+func NewKeyValueFlag() *KeyValueFlag {
+	return &KeyValueFlag{make([]KeyValue, 0)}
+}
+
+func (kv *KeyValueFlag) String() string {
+	return fmt.Sprint(kv.Values)
+}
+
+func (kv *KeyValueFlag) Set(value string) error {
+	i := strings.Index(value, "=")
+	if i < 1 {
+		return errors.New("not in the key=value format")
+	}
+	kv.Values = append(kv.Values, KeyValue{value[:i], value[i+1:]})
+	return nil
+}
@@ -2,6 +2,9 @@ package git
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
 )
 
 const StoryIdUnassignedTagValue = "unassigned"
@@ -196,3 +199,65 @@ func FixCommitSources(commits []*Commit) error {
 
 	return nil
 }
+
+// CherryPickOnto applies the given commits on top of the base commit
+// and returns the SHA of the resulting commit.
+//
+// The commits that are already sitting on top of the base commit in the given
+// order are reused as they are. The rest is cherry-picked in a temporary
+// working tree, so the current working tree and branch are never touched.
+// That requires git 2.5 or newer, see repo.CheckGitVersion.
+func CherryPickOnto(base string, commits ...string) (hexsha string, err error) {
+	head, err := CommitHexsha(base)
+	if err != nil {
+		return "", err
+	}
+
+	// Resolve the revisions here since they would be resolved
+	// relative to the temporary working tree otherwise.
+	shas := make([]string, 0, len(commits))
+	for _, commit := range commits {
+		sha, err := CommitHexsha(commit)
+		if err != nil {
+			return "", err
+		}
+		shas = append(shas, sha)
+	}
+
+	// Reuse the commits that already form a chain on top of the base.
+	var i int
+	for ; i < len(shas); i++ {
+		parent, err := CommitHexsha(shas[i] + "^")
+		if err != nil || parent != head {
+			break
+		}
+		head = shas[i]
+	}
+	if i == len(shas) {
+		return head, nil
+	}
+
+	// Cherry-pick the rest in a temporary working tree.
+	dir, err := ioutil.TempDir("", "salsaflow-cherry-pick-")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		os.RemoveAll(dir)
+		Run("worktree", "prune")
+	}()
+
+	if _, err := Run("worktree", "add", "--detach", dir, head); err != nil {
+		return "", err
+	}
+	args := append([]string{"-C", dir, "cherry-pick", "--allow-empty"}, shas[i:]...)
+	if _, err := Run(args...); err != nil {
+		Run("-C", dir, "cherry-pick", "--abort")
+		return "", err
+	}
+	stdout, err := Run("-C", dir, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -1,12 +1,28 @@
 package git
 
 import (
+	// Stdlib
 	"errors"
 	"fmt"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/errs"
 )
 
 var ErrDirtyRepository = errors.New("the repository is dirty")
 
+func init() {
+	errs.RegisterExitCodeForError(errs.ExitCodeDirtyRepository, ErrDirtyRepository)
+	errs.RegisterExitCode(errs.ExitCodeDirtyRepository, func(err error) bool {
+		_, ok := err.(*ErrDirtyFile)
+		return ok
+	})
+	errs.RegisterExitCode(errs.ExitCodeRefNotInSync, func(err error) bool {
+		_, ok := err.(*ErrRefNotInSync)
+		return ok
+	})
+}
+
 type ErrDirtyFile struct {
 	RelativePath string
 }
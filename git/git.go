@@ -225,6 +225,16 @@ func Hexsha(ref string) (hexsha string, err error) {
 	return strings.Split(stdout.String(), " ")[0], nil
 }
 
+// CommitHexsha returns the full SHA of the commit the given revision resolves to.
+// ErrRefNotFound is returned in case the revision is not a valid commit.
+func CommitHexsha(revision string) (hexsha string, err error) {
+	stdout, _, err := shell.Run("git", "rev-parse", "--verify", "--quiet", revision+"^{commit}")
+	if err != nil {
+		return "", &ErrRefNotFound{revision}
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
 func BranchHexsha(branch string) (hexsha string, err error) {
 	return Hexsha("refs/heads/" + branch)
 }
@@ -5,12 +5,16 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"syscall"
+	"time"
 
 	// Internal
 	"github.com/salsaflow/salsaflow/errs"
 	"github.com/salsaflow/salsaflow/log"
 	"github.com/salsaflow/salsaflow/shell"
+	"github.com/salsaflow/salsaflow/trace"
 )
 
 func Run(args ...string) (stdout *bytes.Buffer, err error) {
@@ -20,7 +24,7 @@ func Run(args ...string) (stdout *bytes.Buffer, err error) {
 
 	task := fmt.Sprintf("Run git with args = %#v", args)
 	log.V(log.Debug).Log(task)
-	stdout, stderr, err := shell.Run("git", argsList...)
+	stdout, stderr, err := runGit(argsList)
 	if err != nil {
 		return nil, errs.NewErrorWithHint(task, err, stderr.String())
 	}
@@ -34,13 +38,46 @@ func RunCommand(command string, args ...string) (stdout *bytes.Buffer, err error
 
 	task := fmt.Sprintf("Run 'git %v' with args = %#v", command, args)
 	log.V(log.Debug).Log(task)
-	stdout, stderr, err := shell.Run("git", argsList...)
+	stdout, stderr, err := runGit(argsList)
 	if err != nil {
 		return nil, errs.NewErrorWithHint(task, err, stderr.String())
 	}
 	return stdout, nil
 }
 
+// runGit runs git with the given arguments and records the invocation in the trace log.
+func runGit(args []string) (stdout, stderr *bytes.Buffer, err error) {
+	start := time.Now()
+	stdout, stderr, err = shell.Run("git", args...)
+	duration := time.Since(start)
+
+	exitCode := 0
+	if err != nil {
+		exitCode = -1
+		if ex, ok := err.(*exec.ExitError); ok {
+			if status, ok := ex.Sys().(syscall.WaitStatus); ok {
+				exitCode = status.ExitStatus()
+			}
+		}
+	}
+	trace.Git(args, duration, exitCode)
+	return
+}
+
+// GitDirectoryAbsolutePath returns the absolute path of the .git directory.
+func GitDirectoryAbsolutePath() (path string, err error) {
+	task := "Get the .git directory absolute path"
+	stdout, err := Run("rev-parse", "--git-dir")
+	if err != nil {
+		return "", errs.NewError(task, err)
+	}
+	path, err = filepath.Abs(string(bytes.TrimSpace(stdout.Bytes())))
+	if err != nil {
+		return "", errs.NewError(task, err)
+	}
+	return path, nil
+}
+
 func RepositoryRootAbsolutePath() (path string, err error) {
 	task := "Get the repository root absolute path"
 	stdout, err := Run("rev-parse", "--show-toplevel")
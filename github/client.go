@@ -1,13 +1,42 @@
 package github
 
 import (
+	// Stdlib
+	"net/http"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/httputil"
+
+	// Vendor
 	"github.com/google/go-github/github"
 	"golang.org/x/oauth2"
 )
 
+// NewClient returns a new API client for the public github.com.
 func NewClient(token string) *github.Client {
-	httpClient := oauth2.NewClient(oauth2.NoContext, &tokenSource{token})
-	return github.NewClient(httpClient)
+	return NewClientForEndpoints(token, DefaultEndpoints())
+}
+
+// NewClientForEndpoints returns a new API client for the GitHub instance
+// located at the given endpoints, e.g. a GitHub Enterprise Server instance.
+//
+// The client is using the shared retrying transport, so the requests
+// failing because of the rate limit being exceeded are retried automatically.
+func NewClientForEndpoints(token string, endpoints *Endpoints) *github.Client {
+	httpClient := &http.Client{
+		Transport: &oauth2.Transport{
+			Source: oauth2.ReuseTokenSource(nil, &tokenSource{token}),
+			Base:   httputil.DefaultTransport,
+		},
+	}
+	client := github.NewClient(httpClient)
+	if endpoints != nil {
+		base := *endpoints.BaseURL
+		client.BaseURL = &base
+		upload := *endpoints.UploadURL
+		client.UploadURL = &upload
+	}
+	return client
 }
 
 type tokenSource struct {
@@ -0,0 +1,170 @@
+package github
+
+import (
+	// Stdlib
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	DefaultHost      = "github.com"
+	DefaultBaseURL   = "https://api.github.com/"
+	DefaultUploadURL = "https://uploads.github.com/"
+	DefaultWebURL    = "https://github.com/"
+)
+
+// The paths used by GitHub Enterprise Server for the API endpoints.
+const (
+	enterpriseAPIPath    = "/api/v3/"
+	enterpriseUploadPath = "/api/uploads/"
+)
+
+// enterpriseCloudDomain is the domain GitHub Enterprise Cloud
+// with data residency is running on, i.e. SUBDOMAIN.ghe.com.
+const enterpriseCloudDomain = ".ghe.com"
+
+// Endpoints specify where the GitHub instance being used is located.
+type Endpoints struct {
+	// BaseURL is the REST API base URL.
+	BaseURL *url.URL
+
+	// UploadURL is the base URL used for uploading files, e.g. release assets.
+	UploadURL *url.URL
+
+	// WebURL is the base URL of the web interface.
+	WebURL *url.URL
+}
+
+// DefaultEndpoints returns the endpoints for the public github.com.
+func DefaultEndpoints() *Endpoints {
+	endpoints, err := NewEndpoints(DefaultBaseURL, DefaultUploadURL, DefaultWebURL)
+	if err != nil {
+		panic(err)
+	}
+	return endpoints
+}
+
+// NewEndpoints parses the given URLs and returns the resulting Endpoints object.
+func NewEndpoints(baseURL, uploadURL, webURL string) (*Endpoints, error) {
+	base, err := parseBaseURL(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	upload, err := parseBaseURL(uploadURL)
+	if err != nil {
+		return nil, err
+	}
+	web, err := parseBaseURL(webURL)
+	if err != nil {
+		return nil, err
+	}
+	return &Endpoints{base, upload, web}, nil
+}
+
+// EndpointsForHost returns the endpoints for the GitHub instance running on the given host.
+//
+// github.com (or an empty host) resolves to the public GitHub,
+// SUBDOMAIN.ghe.com resolves to GitHub Enterprise Cloud and
+// any other host is expected to be a GitHub Enterprise Server instance.
+func EndpointsForHost(host string) (*Endpoints, error) {
+	switch {
+	case host == "" || host == DefaultHost || host == "www."+DefaultHost:
+		return DefaultEndpoints(), nil
+
+	case isEnterpriseCloudHost(host):
+		return NewEndpoints(
+			"https://api."+host+"/",
+			"https://uploads."+host+"/",
+			"https://"+host+"/")
+	}
+
+	web := "https://" + host + "/"
+	return NewEndpoints(
+		"https://"+host+enterpriseAPIPath,
+		"https://"+host+enterpriseUploadPath,
+		web)
+}
+
+// ResolveEndpoints returns the endpoints for the given upstream host
+// with the base URL and the upload URL overwritten by the given values, if set.
+//
+// The upstream host cannot be told apart from an SSH host alias,
+// so unless the base URL is set or the host is a known GitHub Enterprise host,
+// the github.com endpoints are returned.
+//
+// In case only the base URL is set and it looks like a GitHub Enterprise
+// API URL, the upload URL is derived from the base URL as well.
+func ResolveEndpoints(host, baseURL, uploadURL string) (*Endpoints, error) {
+	endpoints := DefaultEndpoints()
+
+	switch {
+	case baseURL != "":
+		base, err := parseBaseURL(baseURL)
+		if err != nil {
+			return nil, err
+		}
+		if base.String() == DefaultBaseURL {
+			break
+		}
+
+		endpoints, err = EndpointsForHost(host)
+		if err != nil {
+			return nil, err
+		}
+		endpoints.BaseURL = base
+
+		if strings.HasSuffix(base.Path, enterpriseAPIPath) {
+			derived := *base
+			derived.Path = strings.TrimSuffix(base.Path, enterpriseAPIPath) + enterpriseUploadPath
+			endpoints.UploadURL = &derived
+
+			// The web interface is running on the same host in that case.
+			if host == "" {
+				endpoints.WebURL = &url.URL{Scheme: base.Scheme, Host: base.Host, Path: "/"}
+			}
+		}
+
+	case isEnterpriseCloudHost(host):
+		var err error
+		endpoints, err = EndpointsForHost(host)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if uploadURL != "" {
+		upload, err := parseBaseURL(uploadURL)
+		if err != nil {
+			return nil, err
+		}
+		endpoints.UploadURL = upload
+	}
+
+	return endpoints, nil
+}
+
+func isEnterpriseCloudHost(host string) bool {
+	return strings.HasSuffix(host, enterpriseCloudDomain) && len(host) > len(enterpriseCloudDomain)
+}
+
+// IssueURL returns the web URL for the given issue.
+func (endpoints *Endpoints) IssueURL(owner, repo string, issueNum interface{}) string {
+	return fmt.Sprintf("%v%v/%v/issues/%v", endpoints.WebURL, owner, repo, issueNum)
+}
+
+// parseBaseURL parses the given URL and makes sure it ends with a slash,
+// which is necessary for the URL to be used as a base URL.
+func parseBaseURL(rawurl string) (*url.URL, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("not an absolute URL: %v", rawurl)
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	return u, nil
+}
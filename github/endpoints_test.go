@@ -0,0 +1,129 @@
+package github
+
+import (
+	"fmt"
+)
+
+type endpointsTestingData struct {
+	upstreamURL       string
+	baseURL           string
+	uploadURL         string
+	expectedBaseURL   string
+	expectedUploadURL string
+	expectedWebURL    string
+}
+
+var _ = Describe("resolving the GitHub API endpoints for an upstream URL", func() {
+
+	data := []endpointsTestingData{
+		// github.com, HTTPS
+		{
+			"https://github.com/owner/repo.git",
+			"",
+			"",
+			DefaultBaseURL,
+			DefaultUploadURL,
+			DefaultWebURL,
+		},
+		// github.com, custom SSH host alias
+		{
+			"git@github-work:owner/repo.git",
+			"",
+			"",
+			DefaultBaseURL,
+			DefaultUploadURL,
+			DefaultWebURL,
+		},
+		// github.com, custom SSH host alias, explicit base URL
+		{
+			"git@github-custom:owner/repo.git",
+			"https://api.github.com",
+			"",
+			DefaultBaseURL,
+			DefaultUploadURL,
+			DefaultWebURL,
+		},
+		// Unknown host, not configured as GitHub Enterprise
+		{
+			"https://github.company.com/owner/repo.git",
+			"",
+			"",
+			DefaultBaseURL,
+			DefaultUploadURL,
+			DefaultWebURL,
+		},
+		// GitHub Enterprise Cloud
+		{
+			"git@octocorp.ghe.com:owner/repo.git",
+			"",
+			"",
+			"https://api.octocorp.ghe.com/",
+			"https://uploads.octocorp.ghe.com/",
+			"https://octocorp.ghe.com/",
+		},
+		// GitHub Enterprise, SSH address, explicit base URL
+		{
+			"git@ghe.example.com:owner/repo.git",
+			"https://ghe.example.com/api/v3",
+			"",
+			"https://ghe.example.com/api/v3/",
+			"https://ghe.example.com/api/uploads/",
+			"https://ghe.example.com/",
+		},
+		// GitHub Enterprise, SSH scheme with a custom port, explicit base URL
+		{
+			"ssh://git@ghe.example.com:2222/owner/repo.git",
+			"https://ghe.example.com/api/v3/",
+			"",
+			"https://ghe.example.com/api/v3/",
+			"https://ghe.example.com/api/uploads/",
+			"https://ghe.example.com/",
+		},
+		// GitHub Enterprise, explicit base URL, upload URL derived
+		{
+			"https://ghe.example.com/owner/repo",
+			"https://api.example.com/api/v3",
+			"",
+			"https://api.example.com/api/v3/",
+			"https://api.example.com/api/uploads/",
+			"https://ghe.example.com/",
+		},
+		// GitHub Enterprise, explicit base URL and upload URL
+		{
+			"https://ghe.example.com/owner/repo",
+			"https://api.example.com/",
+			"https://uploads.example.com/",
+			"https://api.example.com/",
+			"https://uploads.example.com/",
+			"https://ghe.example.com/",
+		},
+	}
+
+	for _, td := range data {
+		func(d endpointsTestingData) {
+
+			Context(fmt.Sprintf("%+v", d), func() {
+
+				It("should return expected results", func() {
+
+					upstream, err := parseUpstream(d.upstreamURL)
+					Expect(err).To(BeNil())
+
+					endpoints, err := ResolveEndpoints(upstream.APIHost(), d.baseURL, d.uploadURL)
+					Expect(err).To(BeNil())
+
+					Expect(endpoints.BaseURL.String()).To(Equal(d.expectedBaseURL))
+					Expect(endpoints.UploadURL.String()).To(Equal(d.expectedUploadURL))
+					Expect(endpoints.WebURL.String()).To(Equal(d.expectedWebURL))
+				})
+			})
+		}(td)
+	}
+
+	It("should build the issue URL from the web URL", func() {
+		endpoints, err := EndpointsForHost("ghe.example.com")
+		Expect(err).To(BeNil())
+		Expect(endpoints.IssueURL("owner", "repo", 42)).To(
+			Equal("https://ghe.example.com/owner/repo/issues/42"))
+	})
+})
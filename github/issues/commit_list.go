@@ -1,10 +1,19 @@
 package issues
 
+import (
+	// Stdlib
+	"strings"
+)
+
 // CommitItem represents a line in the commit checklist.
 type CommitItem struct {
 	Reviewed    bool
 	CommitSHA   string
 	CommitTitle string
+
+	// ChangeId is the Change-Id tag of the commit. It is empty
+	// for the items created before the tag started to be recorded.
+	ChangeId string
 }
 
 // CommitList is a placeholder for multiple commit items.
@@ -22,9 +31,57 @@ func (list *CommitList) CommitItems() []*CommitItem {
 
 // AddCommit adds the commit to the list unless the commit is already there.
 func (list *CommitList) AddCommit(reviewed bool, commitSHA, commitTitle string) bool {
+	_, added := list.AddCommitWithChangeId(reviewed, commitSHA, "", commitTitle)
+	return added
+}
+
+// AddCommitWithChangeId adds the commit to the list unless the commit is already there.
+//
+// In case there is an item with the same Change-Id in the list, the commit
+// is treated as the rewritten version of the commit listed, e.g. after a rebase,
+// and the item is updated to point to the new commit. The reviewed state is kept.
+//
+// The added flag is set when the commit is new or replaces another commit,
+// the changed flag is set on top of that when the commit is already listed
+// and only its missing Change-Id is recorded.
+func (list *CommitList) AddCommitWithChangeId(
+	reviewed bool,
+	commitSHA string,
+	changeId string,
+	commitTitle string,
+) (changed, added bool) {
+
+	changed, added, _ = list.addCommit(reviewed, commitSHA, changeId, commitTitle)
+	return changed, added
+}
+
+// addCommit returns the SHA of the commit replaced, if any, in addition to the flags.
+func (list *CommitList) addCommit(
+	reviewed bool,
+	commitSHA string,
+	changeId string,
+	commitTitle string,
+) (changed, added bool, replacedSHA string) {
+
 	for _, item := range list.items {
 		if item.CommitSHA == commitSHA {
-			return false
+			// Record the Change-Id in case it is missing.
+			if item.ChangeId == "" && changeId != "" {
+				item.ChangeId = changeId
+				return true, false, ""
+			}
+			return false, false, ""
+		}
+	}
+
+	if changeId != "" {
+		for _, item := range list.items {
+			if item.ChangeId == changeId {
+				replacedSHA = item.CommitSHA
+				item.CommitSHA = commitSHA
+				item.CommitTitle = commitTitle
+				return true, true, replacedSHA
+			}
 		}
 	}
 
@@ -32,6 +89,20 @@ func (list *CommitList) AddCommit(reviewed bool, commitSHA, commitTitle string)
 		Reviewed:    reviewed,
 		CommitSHA:   commitSHA,
 		CommitTitle: commitTitle,
+		ChangeId:    changeId,
 	})
-	return true
+	return true, true, ""
+}
+
+// MarkCommitAsReviewed marks the given commit as reviewed.
+// The commit SHA can be abbreviated, the same as the SHAs in the list.
+// It returns false in case there is no such commit in the list.
+func (list *CommitList) MarkCommitAsReviewed(commitSHA string) bool {
+	for _, item := range list.items {
+		if strings.HasPrefix(item.CommitSHA, commitSHA) || strings.HasPrefix(commitSHA, item.CommitSHA) {
+			item.Reviewed = true
+			return true
+		}
+	}
+	return false
 }
@@ -2,8 +2,23 @@ package issues_test
 
 import (
 	. "github.com/salsaflow/salsaflow/github/issues"
+
+	// Stdlib
+	"strings"
+)
+
+var changeIdCommitList = NewCommitList(
+	[]*CommitItem{
+		{true, "fb8418b", "title A", ""},
+		{false, "e8d2c02", "title E", "I8e1bd2fc37f3f0e6a3a54bd1e1a86d5e1c4a8c3f"},
+	},
 )
 
+var changeIdCommitListString = string(
+	`The commits to be reviewed are following:
+- [x] fb8418b: title A
+- [ ] e8d2c02: title E <!-- Change-Id: I8e1bd2fc37f3f0e6a3a54bd1e1a86d5e1c4a8c3f -->`)
+
 var _ = Describe("CommitList", func() {
 	var (
 		reviewed           = false
@@ -35,5 +50,69 @@ var _ = Describe("CommitList", func() {
 			Expect(added).To(BeTrue())
 			Expect(len(list.CommitItems())).To(Equal(2))
 		})
+
+		It("should mark the commit as reviewed using the full commit SHA", func() {
+			list.AddCommit(reviewed, commitSHA, commitTitle)
+			list.AddCommit(reviewed, anotherCommitSHA, anotherCommitTitle)
+
+			Expect(list.MarkCommitAsReviewed(anotherCommitSHA + "789abc")).To(BeTrue())
+			Expect(list.CommitItems()[0].Reviewed).ToNot(BeTrue())
+			Expect(list.CommitItems()[1].Reviewed).To(BeTrue())
+
+			Expect(list.MarkCommitAsReviewed("abcdef")).ToNot(BeTrue())
+		})
+
+		It("should replace the commit with the same Change-Id", func() {
+			changeId := "I0123456789abcdef0123456789abcdef01234567"
+			list.AddCommitWithChangeId(true, commitSHA, changeId, commitTitle)
+
+			changed, added := list.AddCommitWithChangeId(false, anotherCommitSHA, changeId, anotherCommitTitle)
+			Expect(changed).To(BeTrue())
+			Expect(added).To(BeTrue())
+			Expect(len(list.CommitItems())).To(Equal(1))
+
+			item := list.CommitItems()[0]
+			Expect(item.CommitSHA).To(Equal(anotherCommitSHA))
+			Expect(item.CommitTitle).To(Equal(anotherCommitTitle))
+			Expect(item.Reviewed).To(BeTrue())
+		})
+
+		It("should only record the Change-Id for the commit listed already", func() {
+			changeId := "I0123456789abcdef0123456789abcdef01234567"
+			list.AddCommit(reviewed, commitSHA, commitTitle)
+
+			changed, added := list.AddCommitWithChangeId(false, commitSHA, changeId, commitTitle)
+			Expect(changed).To(BeTrue())
+			Expect(added).ToNot(BeTrue())
+			Expect(len(list.CommitItems())).To(Equal(1))
+			Expect(list.CommitItems()[0].ChangeId).To(Equal(changeId))
+
+			changed, added = list.AddCommitWithChangeId(false, commitSHA, changeId, commitTitle)
+			Expect(changed).ToNot(BeTrue())
+			Expect(added).ToNot(BeTrue())
+		})
+	})
+
+	Describe("Change-Id", func() {
+		issueBodyLines := []string{
+			storyLinkSection,
+			emptyLine,
+			storyMetadataSection,
+			emptyLine,
+			changeIdCommitListString,
+			emptyLine,
+		}
+
+		It("should be parsed from the commit list", func() {
+			githubIssue := newGitHubStoryReviewIssue(strings.Join(issueBodyLines, "\n"))
+			reviewIssue, err := ParseReviewIssue(githubIssue)
+			Expect(err).To(BeNil())
+			Expect(reviewIssue.(*StoryReviewIssue).CommitList).To(Equal(changeIdCommitList))
+		})
+
+		It("should be written into the commit list", func() {
+			issue := newStoryReviewIssue(changeIdCommitList, nil, "")
+			Expect(strings.Contains(issue.FormatBody(), changeIdCommitListString)).To(BeTrue())
+		})
 	})
 })
@@ -24,12 +24,34 @@ func newReviewIssueCommonBody() *ReviewIssueCommonBody {
 	}
 }
 
+// AddCommitWithChangeId is a part of ReviewIssue interface.
+//
+// It overrides CommitList.AddCommitWithChangeId so that the review blockers
+// opened for the commit being replaced are moved to the new commit as well.
+func (body *ReviewIssueCommonBody) AddCommitWithChangeId(
+	reviewed bool,
+	commitSHA string,
+	changeId string,
+	commitTitle string,
+) (changed, added bool) {
+
+	changed, added, replacedSHA := body.CommitList.addCommit(reviewed, commitSHA, changeId, commitTitle)
+	if replacedSHA != "" {
+		for _, item := range body.ReviewBlockerItems() {
+			if item.CommitSHA == replacedSHA {
+				item.CommitSHA = commitSHA
+			}
+		}
+	}
+	return changed, added
+}
+
 // Formatting ------------------------------------------------------------------
 
 const userContentSeparator = "----------"
 
 var reviewIssueCommonBodyTemplate = fmt.Sprintf(`The commits to be reviewed are following:
-{{range .CommitList.CommitItems}}- {{if .Reviewed}}[x]{{else}}[ ]{{end}} {{.CommitSHA}}: {{.CommitTitle}}
+{{range .CommitList.CommitItems}}- {{if .Reviewed}}[x]{{else}}[ ]{{end}} {{.CommitSHA}}: {{.CommitTitle}}{{with .ChangeId}} <!-- Change-Id: {{.}} -->{{end}}
 {{end}}
 {{with .ReviewBlockerList.ReviewBlockerItems}}The following review blockers were opened by the reviewer:{{range .}}
 - {{if .Fixed}}[x]{{else}}[ ]{{end}} [blocker {{.BlockerNumber}}]({{.CommentURL}}) (commit {{.CommitSHA}}): {{.BlockerSummary}}{{end}}
@@ -53,7 +75,7 @@ func (body *ReviewIssueCommonBody) execTemplate(w io.Writer) {
 // Parsing ---------------------------------------------------------------------
 
 var (
-	commonBodyCommitItemRegexp  = regexp.MustCompile(`^- \[([ xX])\] ([0-9a-f]+): (.+)$`)
+	commonBodyCommitItemRegexp  = regexp.MustCompile(`^- \[([ xX])\] ([0-9a-f]+): (.+?)(?: <!-- Change-Id: ([^ ]+) -->)?$`)
 	commonBodyBlockerItemRegexp = regexp.MustCompile(`^- \[([ xX])\] \[blocker ([0-9]+)\]\(([^)]+)\) \(commit ([0-9a-f]+)\): (.+)$`)
 )
 
@@ -145,8 +167,11 @@ func parseCommonBodyCommitList(err *error, scanner *bodyScanner) *CommitList {
 
 		// Add the commit to the commit list.
 		reviewed := match[1] != " "
-		commitSHA, commitTitle := match[2], match[3]
-		commitList.AddCommit(reviewed, commitSHA, commitTitle)
+		commitSHA, commitTitle, changeId := match[2], match[3], match[4]
+		if commitList.AddCommit(reviewed, commitSHA, commitTitle) {
+			items := commitList.CommitItems()
+			items[len(items)-1].ChangeId = changeId
+		}
 	}
 }
 
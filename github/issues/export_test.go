@@ -0,0 +1,4 @@
+package issues
+
+// CommitItemRegexp exports commitItemRegexp for testing.
+var CommitItemRegexp = commitItemRegexp
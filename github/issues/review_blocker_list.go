@@ -46,3 +46,19 @@ func (list *ReviewBlockerList) AddReviewBlocker(
 	})
 	return true
 }
+
+// FixReviewBlocker marks the blocker with the given number as fixed.
+// It returns false in case there is no such blocker in the list
+// or the blocker has been marked as fixed already.
+func (list *ReviewBlockerList) FixReviewBlocker(blockerNumber int) bool {
+	for _, item := range list.items {
+		if item.BlockerNumber == blockerNumber {
+			if item.Fixed {
+				return false
+			}
+			item.Fixed = true
+			return true
+		}
+	}
+	return false
+}
@@ -34,4 +34,16 @@ var _ = Describe("ReviewBlockerList", func() {
 		Expect(added).To(BeTrue())
 		Expect(len(list.ReviewBlockerItems())).To(Equal(2))
 	})
+
+	It("should mark the given blocker as fixed", func() {
+		list.AddReviewBlocker(fixed, commentURL, commitSHA, blockerSummary)
+		list.AddReviewBlocker(fixed, anotherCommentURL, commitSHA, blockerSummary)
+
+		Expect(list.FixReviewBlocker(2)).To(BeTrue())
+		Expect(list.ReviewBlockerItems()[0].Fixed).ToNot(BeTrue())
+		Expect(list.ReviewBlockerItems()[1].Fixed).To(BeTrue())
+
+		Expect(list.FixReviewBlocker(2)).ToNot(BeTrue())
+		Expect(list.FixReviewBlocker(3)).ToNot(BeTrue())
+	})
 })
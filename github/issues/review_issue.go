@@ -14,12 +14,23 @@ type ReviewIssue interface {
 	// AddCommit adds the commit to the commit checklist.
 	AddCommit(reviewed bool, commitSHA, commitTitle string) (added bool)
 
+	// AddCommitWithChangeId adds the commit to the commit checklist.
+	// The commit listed with the same Change-Id already is replaced.
+	// The added flag is only set for the commits that are new or replaced.
+	AddCommitWithChangeId(reviewed bool, commitSHA, changeId, commitTitle string) (changed, added bool)
+
+	// MarkCommitAsReviewed checks the given commit in the commit checklist.
+	MarkCommitAsReviewed(commitSHA string) (marked bool)
+
 	// CommitItems returns the list of commits contained in the commit checklist.
 	CommitItems() []*CommitItem
 
 	// AddReviewBlocker adds the review blocker to the blocker checkbox.
 	AddReviewBlocker(fixed bool, commentURL, commitSHA, blockerSummary string) (added bool)
 
+	// FixReviewBlocker marks the given review blocker as fixed.
+	FixReviewBlocker(blockerNumber int) (fixed bool)
+
 	// ReviewBlockerItems returns the list of blockers contained in the blocker checklist.
 	ReviewBlockerItems() []*ReviewBlockerItem
 
@@ -16,6 +16,7 @@ import (
 )
 
 var (
+	AfterEach      = ginkgo.AfterEach
 	BeforeEach     = ginkgo.BeforeEach
 	Context        = ginkgo.Context
 	Describe       = ginkgo.Describe
@@ -23,11 +24,13 @@ var (
 	JustBeforeEach = ginkgo.JustBeforeEach
 
 	BeEmpty = gomega.BeEmpty
+	BeFalse = gomega.BeFalse
 	BeNil   = gomega.BeNil
 	BeTrue  = gomega.BeTrue
 	BeZero  = gomega.BeZero
 	Equal   = gomega.Equal
 	Expect  = gomega.Expect
+	HaveLen = gomega.HaveLen
 )
 
 func TestReviewIssues(t *testing.T) {
@@ -39,11 +42,11 @@ func TestReviewIssues(t *testing.T) {
 
 var commitList = NewCommitList(
 	[]*CommitItem{
-		{true, "fb8418b", "title A"},
-		{true, "6336b11", "title B"},
-		{false, "f68e07a", "title C"},
-		{false, "d0cdb39", "title D"},
-		{false, "e8d2c02", "title E"},
+		{true, "fb8418b", "title A", ""},
+		{true, "6336b11", "title B", ""},
+		{false, "f68e07a", "title C", ""},
+		{false, "d0cdb39", "title D", ""},
+		{false, "e8d2c02", "title E", ""},
 	},
 )
 
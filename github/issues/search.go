@@ -4,7 +4,9 @@ import (
 	// Stdlib
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	// Vendor
 	"github.com/google/go-github/github"
@@ -38,8 +40,26 @@ func FindReviewIssueForStory(
 	return findReviewIssue(client, owner, repo, substring, "in:title", matchesTitle(substring))
 }
 
+// FindReviewIssueForStoryKey returns the story review issue
+// associated with the story of the given story key, i.e. the Story-Id tag.
+func FindReviewIssueForStoryKey(
+	client *github.Client,
+	owner string,
+	repo string,
+	storyKey string,
+) (*github.Issue, error) {
+
+	// Find the relevant review issue.
+	substring := fmt.Sprintf("%v: %v", TagStoryKey, storyKey)
+	re := regexp.MustCompile(fmt.Sprintf(`(?m)^%v\r?$`, regexp.QuoteMeta(substring)))
+	return findReviewIssue(client, owner, repo, substring, "in:body", matchesBodyRe(re))
+}
+
 // FindReviewIssueByCommitItem searches review issues for the one that
 // contains the given commit in its commit checklist.
+//
+// The commit SHA must be the full SHA since that is what the checklist
+// contains and GitHub search does not match a prefix of a word.
 func FindReviewIssueByCommitItem(
 	client *github.Client,
 	owner string,
@@ -47,14 +67,15 @@ func FindReviewIssueByCommitItem(
 	commitSHA string,
 ) (*github.Issue, error) {
 
-	// Use the first 7 chars from the commit hexsha.
-	shortSHA := commitSHA[:7]
-
-	// Commit item regexp.
-	re := regexp.MustCompile(fmt.Sprintf(`[-*] \[[xX ]\] %v[:]`, shortSHA))
-
 	// Perform the search.
-	return findReviewIssue(client, owner, repo, shortSHA, "in:body", matchesBodyRe(re))
+	re := commitItemRegexp(commitSHA)
+	return findReviewIssue(client, owner, repo, commitSHA, "in:body", matchesBodyRe(re))
+}
+
+// commitItemRegexp returns the regexp matching the commit checklist item
+// for the given commit.
+func commitItemRegexp(commitSHA string) *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf(`(?m)^[-*] \[[xX ]\] %v[0-9a-f]*:`, regexp.QuoteMeta(commitSHA)))
 }
 
 type matcherFunc func(*github.Issue) bool
@@ -114,3 +135,146 @@ func matchesBodyRe(re *regexp.Regexp) matcherFunc {
 		return re.MatchString(*issue.Body)
 	}
 }
+
+// searchResultsLimit is the maximum number of results
+// the GitHub Search API returns for a single query.
+const searchResultsLimit = 1000
+
+// ListReviewIssuesCreatedBetween returns the review issues,
+// both open and closed, created within the given time range.
+//
+// The Search API returns the first 1000 results only, so the time range
+// is split into smaller ranges until the results fit.
+func ListReviewIssuesCreatedBetween(
+	client *github.Client,
+	owner string,
+	repo string,
+	reviewLabel string,
+	since time.Time,
+	until time.Time,
+) ([]*github.Issue, error) {
+
+	const timeFormat = "2006-01-02T15:04:05Z"
+	query := fmt.Sprintf(
+		`repo:"%v/%v" label:"%v" type:issue created:%v..%v`,
+		owner, repo, reviewLabel,
+		since.UTC().Format(timeFormat), until.UTC().Format(timeFormat))
+
+	searchOpts := &github.SearchOptions{
+		Sort:  "created",
+		Order: "asc",
+	}
+	searchOpts.Page = 1
+	searchOpts.PerPage = 100
+
+	var reviewIssues []*github.Issue
+	for {
+		// Fetch another page.
+		result, _, err := client.Search.Issues(query, searchOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		// Split the time range in case there are too many results.
+		// The range boundaries are inclusive and the resolution is one second.
+		total := *result.Total
+		if searchOpts.Page == 1 && total > searchResultsLimit && until.Sub(since) > time.Second {
+			mid := since.Add(until.Sub(since) / 2).Truncate(time.Second)
+			if !mid.After(since) {
+				mid = since.Add(time.Second)
+			}
+			head, err := ListReviewIssuesCreatedBetween(
+				client, owner, repo, reviewLabel, since, mid.Add(-time.Second))
+			if err != nil {
+				return nil, err
+			}
+			tail, err := ListReviewIssuesCreatedBetween(
+				client, owner, repo, reviewLabel, mid, until)
+			if err != nil {
+				return nil, err
+			}
+			return append(head, tail...), nil
+		}
+
+		// Collect the issues.
+		for i := range result.Issues {
+			reviewIssues = append(reviewIssues, &result.Issues[i])
+		}
+
+		// Check whether we have reached the end or not.
+		// Asking for the results past the limit fails, so stop there.
+		fetched := len(reviewIssues)
+		if len(result.Issues) == 0 || fetched >= total || fetched >= searchResultsLimit {
+			return reviewIssues, nil
+		}
+
+		// Fetch the next page in the next iteration.
+		searchOpts.Page += 1
+	}
+}
+
+// ListOpenReviewIssues returns all open review issues, i.e. the open issues
+// labeled with the given review label.
+func ListOpenReviewIssues(
+	client *github.Client,
+	owner string,
+	repo string,
+	reviewLabel string,
+) ([]*github.Issue, error) {
+
+	return listOpenReviewIssues(client, owner, repo, &github.IssueListByRepoOptions{
+		State:  "open",
+		Labels: []string{reviewLabel},
+	})
+}
+
+// ListOpenReviewIssuesInMilestone returns the open review issues
+// that belong to the given milestone.
+func ListOpenReviewIssuesInMilestone(
+	client *github.Client,
+	owner string,
+	repo string,
+	reviewLabel string,
+	milestoneNumber int,
+) ([]*github.Issue, error) {
+
+	return listOpenReviewIssues(client, owner, repo, &github.IssueListByRepoOptions{
+		State:     "open",
+		Labels:    []string{reviewLabel},
+		Milestone: strconv.Itoa(milestoneNumber),
+	})
+}
+
+func listOpenReviewIssues(
+	client *github.Client,
+	owner string,
+	repo string,
+	listOpts *github.IssueListByRepoOptions,
+) ([]*github.Issue, error) {
+
+	listOpts.Page = 1
+	listOpts.PerPage = 50
+
+	var reviewIssues []*github.Issue
+	for {
+		// Fetch another page.
+		issues, resp, err := client.Issues.ListByRepo(owner, repo, listOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		// Collect the issues. Pull requests are listed as well, skip them.
+		for i := range issues {
+			if issues[i].PullRequestLinks != nil {
+				continue
+			}
+			reviewIssues = append(reviewIssues, &issues[i])
+		}
+
+		// Check whether we have reached the end or not.
+		if resp.NextPage == 0 {
+			return reviewIssues, nil
+		}
+		listOpts.Page = resp.NextPage
+	}
+}
@@ -0,0 +1,143 @@
+package issues_test
+
+import (
+	// issues package
+	. "github.com/salsaflow/salsaflow/github/issues"
+
+	// Stdlib
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+
+	// Vendor
+	"github.com/google/go-github/github"
+)
+
+var _ = Describe("searching for a review issue by commit", func() {
+
+	var (
+		fullSHA  = "fb8418b47c92962e03b8036b4a40e96c5ae8bc7b"
+		otherSHA = "6336b11d0a7ec9bdba1e5cc4d2a2df0dd4ce1e43"
+	)
+
+	formatBody := func(items ...*CommitItem) string {
+		return newStoryReviewIssue(NewCommitList(items), nil, "").FormatBody()
+	}
+
+	It("should match the commit item for the full commit SHA", func() {
+		body := formatBody(
+			&CommitItem{false, otherSHA, "title B", ""},
+			&CommitItem{true, fullSHA, "title A", "I8e1bd2fc37f3f0e6a3a54bd1e1a86d5e1c4a8c3f"})
+
+		Expect(CommitItemRegexp(fullSHA).MatchString(body)).To(BeTrue())
+	})
+
+	It("should not match the commit item for another commit", func() {
+		body := formatBody(&CommitItem{false, otherSHA, "title B", ""})
+
+		Expect(CommitItemRegexp(fullSHA).MatchString(body)).To(BeFalse())
+	})
+
+	It("should not match the commit only mentioned in the blocker list", func() {
+		issue := newStoryReviewIssue(
+			NewCommitList([]*CommitItem{{false, otherSHA, "title B", ""}}),
+			NewReviewBlockerList([]*ReviewBlockerItem{
+				{false, "https://github.com/someurlA", fullSHA, 1, "Bad!"},
+			}),
+			"")
+
+		Expect(CommitItemRegexp(fullSHA).MatchString(issue.FormatBody())).To(BeFalse())
+	})
+})
+
+var _ = Describe("listing review issues created in the given period", func() {
+
+	const timeFormat = "2006-01-02T15:04:05Z"
+
+	var (
+		server   *httptest.Server
+		client   *github.Client
+		since    = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		created  []time.Time
+		requests int
+	)
+
+	createdRegexp := regexp.MustCompile(`created:([^ ]+)\.\.([^ ]+)`)
+
+	BeforeEach(func() {
+		// One review issue every hour, i.e. more than the Search API returns.
+		created = nil
+		for i := 0; i < 1500; i++ {
+			created = append(created, since.Add(time.Duration(i)*time.Hour))
+		}
+		requests = 0
+
+		// The fake Search API, failing the same way GitHub does past the limit.
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			query := r.URL.Query()
+			page, _ := strconv.Atoi(query.Get("page"))
+			perPage, _ := strconv.Atoi(query.Get("per_page"))
+			if page*perPage > 1000 {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				w.Write([]byte(`{"message": "Only the first 1000 search results are available"}`))
+				return
+			}
+
+			match := createdRegexp.FindStringSubmatch(query.Get("q"))
+			from, _ := time.Parse(timeFormat, match[1])
+			to, _ := time.Parse(timeFormat, match[2])
+			var matching []github.Issue
+			for i, t := range created {
+				if !t.Before(from) && !t.After(to) {
+					createdAt := t
+					matching = append(matching, github.Issue{
+						Number:    github.Int(i + 1),
+						CreatedAt: &createdAt,
+					})
+				}
+			}
+
+			result := github.IssuesSearchResult{Total: github.Int(len(matching))}
+			if start := (page - 1) * perPage; start < len(matching) {
+				end := start + perPage
+				if end > len(matching) {
+					end = len(matching)
+				}
+				result.Issues = matching[start:end]
+			}
+			json.NewEncoder(w).Encode(&result)
+		}))
+
+		client = github.NewClient(nil)
+		client.BaseURL, _ = url.Parse(server.URL + "/")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should split the period in case there are more than 1000 results", func() {
+		until := since.Add(1500 * time.Hour)
+		issues, err := ListReviewIssuesCreatedBetween(
+			client, "owner", "repo", "review", since, until)
+		Expect(err).To(BeNil())
+		Expect(issues).To(HaveLen(1500))
+		for i, issue := range issues {
+			Expect(*issue.Number).To(Equal(i + 1))
+		}
+	})
+
+	It("should fetch the results in a single query when they fit", func() {
+		until := since.Add(150 * time.Hour)
+		issues, err := ListReviewIssuesCreatedBetween(
+			client, "owner", "repo", "review", since, until)
+		Expect(err).To(BeNil())
+		Expect(issues).To(HaveLen(151))
+		Expect(requests).To(Equal(2))
+	})
+})
@@ -18,6 +18,10 @@ type StoryReviewIssue struct {
 	TrackerName  string
 	StoryKey     string
 
+	// DependsOn is the review issue reference, e.g. #12, of the story
+	// this story is stacked on. It is empty for the stories not stacked.
+	DependsOn string
+
 	*ReviewIssueCommonBody
 }
 
@@ -48,14 +52,16 @@ func (issue *StoryReviewIssue) FormatTitle() string {
 const (
 	TagIssueTracker = "SF-Issue-Tracker"
 	TagStoryKey     = "SF-Story-Key"
+	TagDependsOn    = "SF-Depends-On"
 )
 
 var storyReviewIssueBodyTemplate = fmt.Sprintf(`Story being reviewed: [{{.StoryId}}]({{.StoryURL}})
 
 %v: {{.TrackerName}}
 %v: {{.StoryKey}}
-
-`, TagIssueTracker, TagStoryKey)
+{{with .DependsOn}}%v: {{.}}
+{{end}}
+`, TagIssueTracker, TagStoryKey, TagDependsOn)
 
 func (issue *StoryReviewIssue) FormatBody() string {
 	var buffer bytes.Buffer
@@ -71,6 +77,7 @@ var (
 	storyIssueIntroLineRegexp   = regexp.MustCompile(`^Story being reviewed: \[([^\]]+)\]\(([^ ]+)\)`)
 	storyIssueTrackerNameRegexp = regexp.MustCompile(fmt.Sprintf("^%v: (.+)$", TagIssueTracker))
 	storyIssueStoryKeyRegexp    = regexp.MustCompile(fmt.Sprintf("^%v: (.+)$", TagStoryKey))
+	storyIssueDependsOnRegexp   = regexp.MustCompile(fmt.Sprintf("^%v: (.+)$", TagDependsOn))
 )
 
 func parseStoryReviewIssue(issue *github.Issue) (*StoryReviewIssue, error) {
@@ -99,11 +106,8 @@ func parseStoryReviewIssue(issue *github.Issue) (*StoryReviewIssue, error) {
 	// An empty line follows.
 	readEmptyLine(&err, scanner)
 
-	// Parse the metadata.
-	issueTracker, storyKey := parseStoryReviewIssueMetadata(&err, scanner)
-
-	// An empty line follows.
-	readEmptyLine(&err, scanner)
+	// Parse the metadata. The empty line following is consumed as well.
+	issueTracker, storyKey, dependsOn := parseStoryReviewIssueMetadata(&err, scanner)
 
 	// Parse the common body.
 	commonBody := parseRemainingIssueBody(&err, scanner)
@@ -120,6 +124,7 @@ func parseStoryReviewIssue(issue *github.Issue) (*StoryReviewIssue, error) {
 		StorySummary:          storySummary,
 		TrackerName:           issueTracker,
 		StoryKey:              storyKey,
+		DependsOn:             dependsOn,
 		ReviewIssueCommonBody: commonBody,
 	}, nil
 }
@@ -172,23 +177,23 @@ func readEmptyLine(err *error, scanner *bodyScanner) {
 func parseStoryReviewIssueMetadata(
 	err *error,
 	scanner *bodyScanner,
-) (issueTracker, storyKey string) {
+) (issueTracker, storyKey, dependsOn string) {
 	if *err != nil {
-		return "", ""
+		return "", "", ""
 	}
 
 	// Read the tracker name line.
 	line, _, ex := scanner.ReadLine()
 	if ex != nil {
 		*err = ex
-		return "", ""
+		return "", "", ""
 	}
 
 	// Parse the tracker name tag.
 	match := storyIssueTrackerNameRegexp.FindStringSubmatch(line)
 	if len(match) != 2 {
 		*err = scanner.TagNotFound(TagIssueTracker)
-		return "", ""
+		return "", "", ""
 	}
 	issueTracker = match[1]
 
@@ -196,17 +201,35 @@ func parseStoryReviewIssueMetadata(
 	line, _, ex = scanner.ReadLine()
 	if ex != nil {
 		*err = ex
-		return "", ""
+		return "", "", ""
 	}
 
 	// Parse the story key tag.
 	match = storyIssueStoryKeyRegexp.FindStringSubmatch(line)
 	if len(match) != 2 {
 		*err = scanner.TagNotFound(TagStoryKey)
-		return "", ""
+		return "", "", ""
 	}
 	storyKey = match[1]
 
+	// Read the next line, which is either the optional depends-on tag or empty.
+	line, _, ex = scanner.ReadLine()
+	if ex != nil {
+		*err = ex
+		return "", "", ""
+	}
+
+	// Parse the depends-on tag, an empty line follows in that case.
+	if match := storyIssueDependsOnRegexp.FindStringSubmatch(line); len(match) == 2 {
+		dependsOn = match[1]
+		readEmptyLine(err, scanner)
+	} else if line != "" {
+		*err = scanner.CurrentLineInvalid()
+	}
+	if *err != nil {
+		return "", "", ""
+	}
+
 	// Return the results.
-	return issueTracker, storyKey
+	return issueTracker, storyKey, dependsOn
 }
@@ -172,6 +172,27 @@ var _ = Describe("parsing a story review issue", func() {
 		)
 	})
 
+	Context("depending on another review issue", func() {
+
+		issueBodyLines := []string{
+			storyLinkSection,
+			emptyLine,
+			storyMetadataSection,
+			"SF-Depends-On: #12",
+			emptyLine,
+			commitListString,
+			emptyLine,
+		}
+
+		githubIssue := newGitHubStoryReviewIssue(strings.Join(issueBodyLines, "\n"))
+
+		It("should yield the review issue reference", func() {
+			reviewIssue, err := ParseReviewIssue(githubIssue)
+			Expect(err).To(BeNil())
+			Expect(reviewIssue.(*StoryReviewIssue).DependsOn).To(Equal("#12"))
+		})
+	})
+
 	Context("missing the commit list", func() {
 
 		issueBodyLines := []string{
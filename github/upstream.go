@@ -12,13 +12,22 @@ import (
 	"github.com/salsaflow/salsaflow/git"
 )
 
-// ParseUpstreamURL parses the URL of the git upstream being used by SalsaFlow
-// and returns the given GitHub owner and repository.
-func ParseUpstreamURL() (owner, repo string, err error) {
+// Upstream represents the GitHub repository used as the SalsaFlow upstream.
+type Upstream struct {
+	// Host is the host as specified in the remote URL.
+	// It can also be an SSH host alias in case the remote is an SSH address.
+	Host  string
+	Owner string
+	Repo  string
+}
+
+// ParseUpstream parses the URL of the git upstream being used by SalsaFlow
+// and returns the given GitHub host, owner and repository.
+func ParseUpstream() (*Upstream, error) {
 	// Load the Git config.
 	gitConfig, err := git.LoadConfig()
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 	remoteName := gitConfig.RemoteName
 
@@ -26,73 +35,122 @@ func ParseUpstreamURL() (owner, repo string, err error) {
 	task := fmt.Sprintf("Get URL for git remote '%v'", remoteName)
 	remoteURL, err := git.GetConfigString(fmt.Sprintf("remote.%v.url", remoteName))
 	if err != nil {
-		return "", "", errs.NewError(task, err)
+		return nil, errs.NewError(task, err)
 	}
 
 	// Parse it and return the result.
-	return parseUpstreamURL(remoteURL)
+	return parseUpstream(remoteURL)
+}
+
+// ParseUpstreamURL parses the URL of the git upstream being used by SalsaFlow
+// and returns the given GitHub owner and repository.
+func ParseUpstreamURL() (owner, repo string, err error) {
+	upstream, err := ParseUpstream()
+	if err != nil {
+		return "", "", err
+	}
+	return upstream.Owner, upstream.Repo, nil
+}
+
+// UpstreamEndpoints returns the endpoints for the GitHub instance
+// hosting the SalsaFlow upstream, the base URL and the upload URL
+// being overwritten by the given values when set.
+func UpstreamEndpoints(baseURL, uploadURL string) (*Upstream, *Endpoints, error) {
+	upstream, err := ParseUpstream()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	task := "Get the GitHub API endpoints for the upstream repository"
+	endpoints, err := ResolveEndpoints(upstream.APIHost(), baseURL, uploadURL)
+	if err != nil {
+		return nil, nil, errs.NewError(task, err)
+	}
+	return upstream, endpoints, nil
+}
+
+// APIHost returns the host to be used to derive the GitHub endpoints.
+//
+// An empty string is returned for github.com, any other host is returned
+// as it is. The host alone selects GitHub Enterprise only when it is
+// a known GitHub Enterprise host, see ResolveEndpoints.
+func (upstream *Upstream) APIHost() string {
+	host := upstream.Host
+	if host == DefaultHost || host == "www."+DefaultHost {
+		return ""
+	}
+	return host
 }
 
 func parseUpstreamURL(remoteURL string) (owner, repo string, err error) {
+	upstream, err := parseUpstream(remoteURL)
+	if err != nil {
+		return "", "", err
+	}
+	return upstream.Owner, upstream.Repo, nil
+}
+
+func parseUpstream(remoteURL string) (upstream *Upstream, err error) {
 	// Parse the upstream URL to get the owner and repo name.
 	task := "Parse the upstream repository URL"
 
 	defer func() {
 		// Strip trailing .git if present.
-		if strings.HasSuffix(repo, ".git") {
-			repo = repo[:len(repo)-len(".git")]
+		if upstream != nil && strings.HasSuffix(upstream.Repo, ".git") {
+			upstream.Repo = upstream.Repo[:len(upstream.Repo)-len(".git")]
 		}
 	}()
 
 	// Try to parse the URL as an SSH URL first.
-	owner, repo, ok := tryParseUpstreamAsSSH(remoteURL)
+	upstream, ok := tryParseUpstreamAsSSH(remoteURL)
 	if ok {
-		return owner, repo, nil
+		return upstream, nil
 	}
 
 	// Try to parse the URL as a regular URL.
-	owner, repo, ok = tryParseUpstreamAsURL(remoteURL)
+	upstream, ok = tryParseUpstreamAsURL(remoteURL)
 	if ok {
-		return owner, repo, nil
+		return upstream, nil
 	}
 
 	// No success, return an error.
 	err = fmt.Errorf("failed to parse git remote URL: %v", remoteURL)
-	return "", "", errs.NewError(task, err)
+	return nil, errs.NewError(task, err)
 }
 
 // tryParseUpstreamAsSSH tries to parse the address as an SSH address,
 // e.g. git@github.com:owner/repo.git
-func tryParseUpstreamAsSSH(remoteURL string) (owner, repo string, ok bool) {
-	re := regexp.MustCompile("^git@[^:]+:([^/]+)/(.+)$")
+func tryParseUpstreamAsSSH(remoteURL string) (upstream *Upstream, ok bool) {
+	re := regexp.MustCompile("^(?:[^@/]+@)?([^:/]+):([^/]+)/(.+)$")
 	match := re.FindStringSubmatch(remoteURL)
 	if len(match) != 0 {
-		owner, repo := match[1], match[2]
-		return owner, repo, true
+		return &Upstream{match[1], match[2], match[3]}, true
 	}
 
-	return "", "", false
+	return nil, false
 }
 
 // tryParseUpstreamAsURL tries to parse the address as a regular URL,
 // e.g. https://github.com/owner/repo
-func tryParseUpstreamAsURL(remoteURL string) (owner, repo string, ok bool) {
+func tryParseUpstreamAsURL(remoteURL string) (upstream *Upstream, ok bool) {
 	u, err := url.Parse(remoteURL)
 	if err != nil {
-		return "", "", false
+		return nil, false
 	}
 
 	switch u.Scheme {
-	case "ssh":
-		fallthrough
-	case "https":
+	case "ssh", "git", "http", "https":
 		re := regexp.MustCompile("^/([^/]+)/(.+)$")
 		match := re.FindStringSubmatch(u.Path)
 		if len(match) != 0 {
-			owner, repo := match[1], match[2]
-			return owner, repo, true
+			// Drop the port, it is not relevant for the API endpoints.
+			host := u.Host
+			if i := strings.LastIndex(host, ":"); i != -1 {
+				host = host[:i]
+			}
+			return &Upstream{host, match[1], match[2]}, true
 		}
 	}
 
-	return "", "", false
+	return nil, false
 }
@@ -0,0 +1,373 @@
+package webhooks
+
+import (
+	// Stdlib
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/git"
+	ghissues "github.com/salsaflow/salsaflow/github/issues"
+	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/modules/common"
+
+	// Vendor
+	"github.com/google/go-github/github"
+)
+
+var (
+	// BlockerCommentPattern matches the first line of the review issue comments
+	// to be recorded as review blockers, e.g. "BLOCKER 1a2b3c4: Fix the typo".
+	// The commit SHA is optional, the last commit in the checklist is used when missing.
+	BlockerCommentPattern = regexp.MustCompile(`^(?i)[ \t]*BLOCKER(?:[ \t]+([0-9a-f]{7,40}))?:[ \t]*(.+)$`)
+
+	// FixesBlockerTagPattern matches the commit message tag marking the review blockers
+	// fixed by the commit, e.g. "Fixes-Blocker: 1, 2".
+	FixesBlockerTagPattern = regexp.MustCompile(`^(?i)[ \t]*Fixes-Blocker:[ \t]+([0-9, \t]+)$`)
+)
+
+// Issues ----------------------------------------------------------------------
+
+// processIssuesEvent marks the story as reviewed once the review issue is closed.
+func (handler *Handler) processIssuesEvent(payload []byte) error {
+	var event github.IssueActivityEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return errs.NewError("Parse the issues event payload", err)
+	}
+	if event.Action == nil || *event.Action != "closed" || !handler.isReviewIssue(event.Issue) {
+		return nil
+	}
+
+	issue := event.Issue
+	task := fmt.Sprintf("Parse review issue #%v", *issue.Number)
+	reviewIssue, err := ghissues.ParseReviewIssue(issue)
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+
+	// Only the story review issues are interesting.
+	storyIssue, ok := reviewIssue.(*ghissues.StoryReviewIssue)
+	if !ok {
+		return nil
+	}
+
+	for _, item := range reviewIssue.CommitItems() {
+		if !item.Reviewed {
+			log.Warn(fmt.Sprintf(
+				"Review issue #%v closed with commits not marked as reviewed", *issue.Number))
+			break
+		}
+	}
+
+	_, err = common.MarkStoryAsReviewed(handler.opts.Tracker, storyIssue.StoryKey)
+	return err
+}
+
+// Issue comments --------------------------------------------------------------
+
+// processIssueCommentEvent records the blocker comments in the review issue blocker list.
+func (handler *Handler) processIssueCommentEvent(payload []byte) error {
+	var event github.IssueCommentEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return errs.NewError("Parse the issue_comment event payload", err)
+	}
+	if event.Action == nil || *event.Action != "created" || !handler.isReviewIssue(event.Issue) {
+		return nil
+	}
+
+	comment := event.Comment
+	if comment == nil || comment.Body == nil || comment.HTMLURL == nil {
+		return nil
+	}
+	firstLine := strings.SplitN(*comment.Body, "\n", 2)[0]
+	match := BlockerCommentPattern.FindStringSubmatch(strings.TrimSpace(firstLine))
+	if match == nil {
+		return nil
+	}
+
+	// Fetch the issue again, the payload can be outdated already.
+	issue, reviewIssue, err := handler.getReviewIssue(*event.Issue.Number)
+	if err != nil {
+		return err
+	}
+
+	// Get the commit, use the last commit in the checklist when not specified.
+	commitSHA := match[1]
+	if commitSHA == "" {
+		items := reviewIssue.CommitItems()
+		if len(items) == 0 {
+			log.Warn(fmt.Sprintf(
+				"Review issue #%v contains no commits, blocker not recorded", *issue.Number))
+			return nil
+		}
+		commitSHA = items[len(items)-1].CommitSHA
+	}
+
+	if !reviewIssue.AddReviewBlocker(false, *comment.HTMLURL, commitSHA, match[2]) {
+		// The blocker is already there.
+		return nil
+	}
+	return handler.updateReviewIssue(issue, reviewIssue, false)
+}
+
+// Push ------------------------------------------------------------------------
+
+// maxPushEventCommits is the number of commits GitHub includes
+// in the push event payload at most.
+const maxPushEventCommits = 20
+
+type pushEvent struct {
+	Ref     string        `json:"ref"`
+	Before  string        `json:"before"`
+	After   string        `json:"after"`
+	Commits []*pushCommit `json:"commits"`
+}
+
+type pushCommit struct {
+	Id       string `json:"id"`
+	Message  string `json:"message"`
+	Distinct bool   `json:"distinct"`
+}
+
+// processPushEvent adds the commits pushed into the trunk branch into the review issues
+// for the relevant stories and marks the review blockers fixed by the commits as fixed.
+func (handler *Handler) processPushEvent(payload []byte) error {
+	var event pushEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return errs.NewError("Parse the push event payload", err)
+	}
+	if event.Ref != "refs/heads/"+handler.opts.TrunkBranch {
+		return nil
+	}
+
+	// The payload commit list is truncated for larger pushes,
+	// get the commits using the compare API in that case.
+	commits := event.Commits
+	if len(commits) >= maxPushEventCommits {
+		if cs, err := handler.listPushedCommits(&event); err != nil {
+			errs.Log(err)
+			log.Warn(fmt.Sprintf(
+				"Only the first %v commits pushed are added into the review issues", len(commits)))
+		} else {
+			commits = cs
+		}
+	}
+
+	// Group the commits by the Story-Id tag.
+	var (
+		storyTags        []string
+		commitsByStoryId = make(map[string][]*pushCommit)
+	)
+	for _, commit := range commits {
+		if !commit.Distinct {
+			continue
+		}
+		tag := storyIdTag(commit.Message)
+		if tag == "" || tag == git.StoryIdUnassignedTagValue {
+			continue
+		}
+		if _, ok := commitsByStoryId[tag]; !ok {
+			storyTags = append(storyTags, tag)
+		}
+		commitsByStoryId[tag] = append(commitsByStoryId[tag], commit)
+	}
+
+	// Update the review issues.
+	var failed bool
+	for _, tag := range storyTags {
+		if err := handler.syncReviewIssue(tag, commitsByStoryId[tag]); err != nil {
+			errs.Log(err)
+			failed = true
+		}
+	}
+	if failed {
+		return errs.NewError("Process the push event", ErrEventNotProcessed)
+	}
+	return nil
+}
+
+// listPushedCommits returns the commits pushed as returned by the compare API.
+func (handler *Handler) listPushedCommits(event *pushEvent) ([]*pushCommit, error) {
+	opts := handler.opts
+
+	task := fmt.Sprintf("Fetch the commits pushed into %v", event.Ref)
+	if strings.Trim(event.Before, "0") == "" {
+		return nil, errs.NewError(task, errors.New("the branch has just been created"))
+	}
+
+	comparison, _, err := opts.Client.Repositories.CompareCommits(
+		opts.Owner, opts.Repository, event.Before, event.After)
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+	if total := comparison.TotalCommits; total != nil && *total > len(comparison.Commits) {
+		log.Warn(fmt.Sprintf(
+			"Only the first %v of %v commits pushed are added into the review issues",
+			len(comparison.Commits), *total))
+	}
+
+	commits := make([]*pushCommit, 0, len(comparison.Commits))
+	for _, commit := range comparison.Commits {
+		if commit.SHA == nil || commit.Commit == nil || commit.Commit.Message == nil {
+			continue
+		}
+		commits = append(commits, &pushCommit{
+			Id:       *commit.SHA,
+			Message:  *commit.Commit.Message,
+			Distinct: true,
+		})
+	}
+	return commits, nil
+}
+
+func (handler *Handler) syncReviewIssue(storyTag string, commits []*pushCommit) error {
+	var (
+		opts   = handler.opts
+		client = opts.Client
+	)
+
+	// Find the review issue for the story.
+	storyId, err := opts.Tracker.StoryTagToReadableStoryId(storyTag)
+	if err != nil {
+		return err
+	}
+
+	task := fmt.Sprintf("Search for the review issue for story %v", storyId)
+	found, err := ghissues.FindReviewIssueForStory(client, opts.Owner, opts.Repository, storyId)
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+	if found == nil {
+		log.Log(fmt.Sprintf("No review issue found for story %v", storyId))
+		return nil
+	}
+
+	// Fetch the issue again, the search index can be lagging behind.
+	issue, reviewIssue, err := handler.getReviewIssue(*found.Number)
+	if err != nil {
+		return err
+	}
+
+	// Update the checklists.
+	var changed, added bool
+	for _, commit := range commits {
+		title := strings.SplitN(commit.Message, "\n", 2)[0]
+		commitChanged, commitAdded := reviewIssue.AddCommitWithChangeId(
+			false, commit.Id, changeIdTag(commit.Message), title)
+		if commitChanged {
+			changed = true
+		}
+		if commitAdded {
+			added = true
+		}
+		for _, blockerNumber := range fixedBlockers(commit.Message) {
+			if reviewIssue.FixReviewBlocker(blockerNumber) {
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return nil
+	}
+	// The review issue is reopened in case there are new commits to be reviewed.
+	return handler.updateReviewIssue(issue, reviewIssue, added)
+}
+
+// storyIdTag returns the Story-Id tag contained in the commit message.
+func storyIdTag(message string) string {
+	for _, line := range strings.Split(message, "\n") {
+		if match := git.StoryIdTagPattern.FindStringSubmatch(line); match != nil {
+			return match[1]
+		}
+	}
+	return ""
+}
+
+// changeIdTag returns the Change-Id tag contained in the commit message.
+func changeIdTag(message string) string {
+	for _, line := range strings.Split(message, "\n") {
+		if match := git.ChangeIdTagPattern.FindStringSubmatch(line); match != nil {
+			return match[1]
+		}
+	}
+	return ""
+}
+
+// fixedBlockers returns the blocker numbers listed in the Fixes-Blocker tags.
+func fixedBlockers(message string) []int {
+	var numbers []int
+	for _, line := range strings.Split(message, "\n") {
+		match := FixesBlockerTagPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		for _, field := range strings.FieldsFunc(match[1], func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		}) {
+			if n, err := strconv.Atoi(field); err == nil {
+				numbers = append(numbers, n)
+			}
+		}
+	}
+	return numbers
+}
+
+// Helpers ---------------------------------------------------------------------
+
+func (handler *Handler) isReviewIssue(issue *github.Issue) bool {
+	if issue == nil || issue.Number == nil || issue.PullRequestLinks != nil {
+		return false
+	}
+	for _, label := range issue.Labels {
+		if label.Name != nil && *label.Name == handler.opts.ReviewLabel {
+			return true
+		}
+	}
+	return false
+}
+
+func (handler *Handler) getReviewIssue(issueNum int) (*github.Issue, ghissues.ReviewIssue, error) {
+	opts := handler.opts
+
+	task := fmt.Sprintf("Fetch GitHub issue #%v", issueNum)
+	issue, _, err := opts.Client.Issues.Get(opts.Owner, opts.Repository, issueNum)
+	if err != nil {
+		return nil, nil, errs.NewError(task, err)
+	}
+
+	task = fmt.Sprintf("Parse review issue #%v", issueNum)
+	reviewIssue, err := ghissues.ParseReviewIssue(issue)
+	if err != nil {
+		return nil, nil, errs.NewError(task, err)
+	}
+	return issue, reviewIssue, nil
+}
+
+func (handler *Handler) updateReviewIssue(
+	issue *github.Issue,
+	reviewIssue ghissues.ReviewIssue,
+	reopen bool,
+) error {
+
+	opts := handler.opts
+
+	request := &github.IssueRequest{
+		Body: github.String(reviewIssue.FormatBody()),
+	}
+	if reopen {
+		request.State = github.String("open")
+	}
+
+	task := fmt.Sprintf("Update GitHub issue #%v", *issue.Number)
+	log.Run(task)
+	_, _, err := opts.Client.Issues.Edit(opts.Owner, opts.Repository, *issue.Number, request)
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+	return nil
+}
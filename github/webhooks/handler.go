@@ -0,0 +1,176 @@
+package webhooks
+
+import (
+	// Stdlib
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/modules/common"
+
+	// Vendor
+	"github.com/google/go-github/github"
+)
+
+// The GitHub webhook events processed by Handler.
+const (
+	EventPing         = "ping"
+	EventIssues       = "issues"
+	EventIssueComment = "issue_comment"
+	EventPush         = "push"
+)
+
+// maxPayloadSize limits the size of the webhook payloads accepted, GitHub caps them at 25 MB.
+const maxPayloadSize = 25 << 20
+
+var (
+	ErrInvalidSignature  = errors.New("invalid webhook signature")
+	ErrEventNotProcessed = errors.New("failed to process the event completely")
+)
+
+// Options configure Handler.
+type Options struct {
+	// Secret is the secret configured for the webhook on GitHub.
+	Secret string
+
+	// Client is the GitHub API client used to update the review issues.
+	Client *github.Client
+
+	// Owner and Repository identify the repository the review issues live in.
+	Owner      string
+	Repository string
+
+	// ReviewLabel is the label the review issues are marked with.
+	ReviewLabel string
+
+	// TrunkBranch is the branch the commits are synchronised from.
+	TrunkBranch string
+
+	// Tracker is the issue tracker the story state is updated in.
+	Tracker common.IssueTracker
+}
+
+// Handler processes the GitHub webhooks to keep the review issues
+// and the issue tracker in sync with what is happening on GitHub.
+type Handler struct {
+	opts *Options
+
+	// Events are processed one by one so that the review issue updates
+	// do not overwrite each other.
+	mu sync.Mutex
+
+	// wg tracks the events being processed in the background.
+	wg sync.WaitGroup
+}
+
+func NewHandler(opts *Options) *Handler {
+	return &Handler{opts: opts}
+}
+
+// ServeHTTP is a part of http.Handler interface.
+//
+// The payload signature is verified, then the event is processed
+// in the background so that GitHub does not time out waiting for the response.
+func (handler *Handler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	payload, err := ioutil.ReadAll(http.MaxBytesReader(rw, r.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(rw, "failed to read the payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := handler.verifySignature(r.Header, payload); err != nil {
+		log.Warn(fmt.Sprintf("Webhook rejected: %v", err))
+		http.Error(rw, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	event := r.Header.Get("X-GitHub-Event")
+	switch event {
+	case EventPing:
+		rw.WriteHeader(http.StatusOK)
+		return
+	case EventIssues, EventIssueComment, EventPush:
+	default:
+		// Not interested.
+		rw.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	delivery := r.Header.Get("X-GitHub-Delivery")
+	handler.wg.Add(1)
+	go func() {
+		defer handler.wg.Done()
+		if err := handler.Process(event, payload); err != nil {
+			errs.LogError(fmt.Sprintf("Process %v webhook (delivery %v)", event, delivery), err)
+		}
+	}()
+
+	rw.WriteHeader(http.StatusAccepted)
+}
+
+// Wait blocks until all the events accepted by ServeHTTP are processed.
+func (handler *Handler) Wait() {
+	handler.wg.Wait()
+}
+
+// Process processes the webhook payload for the given event.
+func (handler *Handler) Process(event string, payload []byte) error {
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+
+	switch event {
+	case EventIssues:
+		return handler.processIssuesEvent(payload)
+	case EventIssueComment:
+		return handler.processIssueCommentEvent(payload)
+	case EventPush:
+		return handler.processPushEvent(payload)
+	default:
+		return nil
+	}
+}
+
+// verifySignature checks the HMAC signature sent by GitHub.
+// X-Hub-Signature-256 is preferred, X-Hub-Signature is used
+// by older GitHub Enterprise instances.
+func (handler *Handler) verifySignature(header http.Header, payload []byte) error {
+	var (
+		signature string
+		hashFunc  func() hash.Hash
+	)
+	if v := header.Get("X-Hub-Signature-256"); v != "" {
+		signature, hashFunc = strings.TrimPrefix(v, "sha256="), sha256.New
+	} else if v := header.Get("X-Hub-Signature"); v != "" {
+		signature, hashFunc = strings.TrimPrefix(v, "sha1="), sha1.New
+	} else {
+		return ErrInvalidSignature
+	}
+
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(hashFunc, []byte(handler.opts.Secret))
+	mac.Write(payload)
+	if !hmac.Equal(mac.Sum(nil), expected) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package webhooks
+
+import (
+	// Stdlib
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/action"
+	"github.com/salsaflow/salsaflow/modules/common"
+
+	// Vendor
+	"github.com/google/go-github/github"
+)
+
+const (
+	testSecret   = "s3cr3t"
+	testIssueURL = "/repos/acme/widgets/issues/7"
+)
+
+// fakeStory implements the parts of common.Story used by the handler.
+type fakeStory struct {
+	common.Story
+	state  common.StoryState
+	marked bool
+}
+
+func (story *fakeStory) ReadableId() string       { return "#42" }
+func (story *fakeStory) State() common.StoryState { return story.state }
+func (story *fakeStory) MarkAsReviewed() (action.Action, error) {
+	story.marked = true
+	return nil, nil
+}
+
+// fakeTracker implements the parts of common.IssueTracker used by the handler.
+type fakeTracker struct {
+	common.IssueTracker
+	story *fakeStory
+}
+
+func (tracker *fakeTracker) ServiceName() string { return "GitHub Issues" }
+
+func (tracker *fakeTracker) ListStoriesByTag(tags []string) ([]common.Story, error) {
+	stories := make([]common.Story, len(tags))
+	for i, tag := range tags {
+		if tag == "acme/widgets#42" {
+			stories[i] = tracker.story
+		}
+	}
+	return stories, nil
+}
+
+func (tracker *fakeTracker) StoryTagToReadableStoryId(tag string) (string, error) {
+	return tag[strings.Index(tag, "#"):], nil
+}
+
+// loadPayload reads the recorded webhook payload from testdata.
+func loadPayload(name string) []byte {
+	payload, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	Expect(err).ToNot(HaveOccurred())
+	return payload
+}
+
+func sign(payload []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+var _ = Describe("Handler", func() {
+
+	var (
+		server  *httptest.Server
+		story   *fakeStory
+		handler *Handler
+
+		lock  sync.Mutex
+		issue map[string]interface{}
+		edits []map[string]interface{}
+	)
+
+	BeforeEach(func() {
+		// Start with the review issue as contained in the recorded payload.
+		var event struct {
+			Issue map[string]interface{} `json:"issue"`
+		}
+		Expect(json.Unmarshal(loadPayload("issue_comment_created.json"), &event)).To(Succeed())
+		issue = event.Issue
+		edits = nil
+
+		// Fake the relevant parts of the GitHub API.
+		mux := http.NewServeMux()
+		mux.HandleFunc(testIssueURL, func(w http.ResponseWriter, r *http.Request) {
+			lock.Lock()
+			defer lock.Unlock()
+			if r.Method == "PATCH" {
+				var edit map[string]interface{}
+				Expect(json.NewDecoder(r.Body).Decode(&edit)).To(Succeed())
+				edits = append(edits, edit)
+				for k, v := range edit {
+					issue[k] = v
+				}
+			}
+			json.NewEncoder(w).Encode(issue)
+		})
+		mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+			lock.Lock()
+			defer lock.Unlock()
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"total_count": 1,
+				"items":       []interface{}{issue},
+			})
+		})
+		mux.HandleFunc("/repos/acme/widgets/compare/", func(w http.ResponseWriter, r *http.Request) {
+			commits := make([]interface{}, 0, maxPushEventCommits+1)
+			for i := 0; i < maxPushEventCommits; i++ {
+				commits = append(commits, map[string]interface{}{
+					"sha":    fmt.Sprintf("%040x", i),
+					"commit": map[string]interface{}{"message": "Refactor the widget"},
+				})
+			}
+			commits = append(commits, map[string]interface{}{
+				"sha": "9abcdef0123456789abcdef0123456789abcdef0",
+				"commit": map[string]interface{}{
+					"message": "Make the widget navy blue\n\nStory-Id: acme/widgets#42",
+				},
+			})
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"total_commits": len(commits),
+				"commits":       commits,
+			})
+		})
+		server = httptest.NewServer(mux)
+
+		client := github.NewClient(nil)
+		client.BaseURL, _ = url.Parse(server.URL + "/")
+
+		story = &fakeStory{state: common.StoryStateImplemented}
+		handler = NewHandler(&Options{
+			Secret:      testSecret,
+			Client:      client,
+			Owner:       "acme",
+			Repository:  "widgets",
+			ReviewLabel: "review",
+			TrunkBranch: "develop",
+			Tracker:     &fakeTracker{story: story},
+		})
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("ServeHTTP", func() {
+
+		post := func(event string, payload []byte, signature string) int {
+			r := httptest.NewRequest("POST", "/", bytes.NewReader(payload))
+			r.Header.Set("X-GitHub-Event", event)
+			if signature != "" {
+				r.Header.Set("X-Hub-Signature-256", signature)
+			}
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, r)
+			return rw.Code
+		}
+
+		It("should accept a correctly signed payload", func() {
+			payload := []byte(`{"zen": "Keep it logically awesome."}`)
+			Expect(post(EventPing, payload, sign(payload, testSecret))).To(Equal(http.StatusOK))
+		})
+
+		It("should reject a payload signed using another secret", func() {
+			payload := loadPayload("issues_closed.json")
+			code := post(EventIssues, payload, sign(payload, "another secret"))
+			Expect(code).To(Equal(http.StatusUnauthorized))
+			Expect(story.marked).ToNot(BeTrue())
+		})
+
+		It("should process the accepted events in the background", func() {
+			payload := loadPayload("issues_closed.json")
+			code := post(EventIssues, payload, sign(payload, testSecret))
+			Expect(code).To(Equal(http.StatusAccepted))
+			handler.Wait()
+			Expect(story.marked).To(BeTrue())
+		})
+
+		It("should reject an unsigned payload", func() {
+			payload := loadPayload("issues_closed.json")
+			Expect(post(EventIssues, payload, "")).To(Equal(http.StatusUnauthorized))
+		})
+	})
+
+	Describe("Process", func() {
+
+		Context("when a review issue is closed", func() {
+
+			It("should mark the story as reviewed", func() {
+				err := handler.Process(EventIssues, loadPayload("issues_closed.json"))
+				Expect(err).To(BeNil())
+				Expect(story.marked).To(BeTrue())
+			})
+
+			It("should leave the story alone when the review is being skipped", func() {
+				story.state = common.StoryStateReviewed
+				err := handler.Process(EventIssues, loadPayload("issues_closed.json"))
+				Expect(err).To(BeNil())
+				Expect(story.marked).ToNot(BeTrue())
+			})
+		})
+
+		Context("when a blocker comment is created", func() {
+
+			It("should add the blocker into the review issue", func() {
+				err := handler.Process(EventIssueComment, loadPayload("issue_comment_created.json"))
+				Expect(err).To(BeNil())
+
+				Expect(edits).To(HaveLen(1))
+				Expect(edits[0]["body"]).To(ContainSubstring(
+					"- [ ] [blocker 1](https://github.com/acme/widgets/issues/7#issuecomment-131234)" +
+						" (commit 5d6e7f8): The widget must be navy blue"))
+				Expect(edits[0]["body"]).To(ContainSubstring("Please check the colour of the widget."))
+			})
+
+			It("should not add the same blocker twice", func() {
+				payload := loadPayload("issue_comment_created.json")
+				Expect(handler.Process(EventIssueComment, payload)).To(BeNil())
+				Expect(handler.Process(EventIssueComment, payload)).To(BeNil())
+				Expect(edits).To(HaveLen(1))
+			})
+		})
+
+		Context("when commits are pushed into the trunk branch", func() {
+
+			BeforeEach(func() {
+				err := handler.Process(EventIssueComment, loadPayload("issue_comment_created.json"))
+				Expect(err).To(BeNil())
+				edits = nil
+			})
+
+			It("should add the commits and fix the blockers", func() {
+				err := handler.Process(EventPush, loadPayload("push.json"))
+				Expect(err).To(BeNil())
+
+				Expect(edits).To(HaveLen(1))
+				body := edits[0]["body"]
+				Expect(body).To(ContainSubstring(
+					"- [ ] 9abcdef0123456789abcdef0123456789abcdef0: Make the widget navy blue"))
+				Expect(body).To(ContainSubstring("- [x] [blocker 1]("))
+				Expect(body).ToNot(ContainSubstring("Update the changelog"))
+				Expect(strings.Count(body.(string), "1a2b3c4d5e6f")).To(Equal(1))
+				Expect(edits[0]["state"]).To(Equal("open"))
+			})
+
+			It("should not touch the review issue when the commits are listed already", func() {
+				payload := loadPayload("push.json")
+				Expect(handler.Process(EventPush, payload)).To(BeNil())
+				Expect(handler.Process(EventPush, payload)).To(BeNil())
+				Expect(edits).To(HaveLen(1))
+			})
+
+			It("should fetch the commits when the payload is truncated", func() {
+				commits := make([]*pushCommit, 0, maxPushEventCommits)
+				for i := 0; i < maxPushEventCommits; i++ {
+					commits = append(commits, &pushCommit{
+						Id:       fmt.Sprintf("%040x", i),
+						Message:  "Refactor the widget",
+						Distinct: true,
+					})
+				}
+				payload, err := json.Marshal(&pushEvent{
+					Ref:     "refs/heads/develop",
+					Before:  "5d6e7f8a0b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e",
+					After:   "9abcdef0123456789abcdef0123456789abcdef0",
+					Commits: commits,
+				})
+				Expect(err).To(BeNil())
+
+				Expect(handler.Process(EventPush, payload)).To(BeNil())
+				Expect(edits).To(HaveLen(1))
+				Expect(edits[0]["body"]).To(ContainSubstring(
+					"- [ ] 9abcdef0123456789abcdef0123456789abcdef0: Make the widget navy blue"))
+			})
+
+			It("should ignore the other branches", func() {
+				payload := bytes.Replace(loadPayload("push.json"),
+					[]byte(`"refs/heads/develop"`), []byte(`"refs/heads/story/widget"`), 1)
+				Expect(handler.Process(EventPush, payload)).To(BeNil())
+				Expect(edits).To(BeEmpty())
+			})
+		})
+	})
+})
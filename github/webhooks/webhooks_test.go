@@ -0,0 +1,33 @@
+package webhooks
+
+import (
+	// Stdlib
+	"testing"
+
+	// Vendor - testing framework
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var (
+	AfterEach  = ginkgo.AfterEach
+	BeforeEach = ginkgo.BeforeEach
+	Context    = ginkgo.Context
+	Describe   = ginkgo.Describe
+	It         = ginkgo.It
+
+	BeEmpty          = gomega.BeEmpty
+	BeNil            = gomega.BeNil
+	BeTrue           = gomega.BeTrue
+	ContainSubstring = gomega.ContainSubstring
+	Equal            = gomega.Equal
+	Expect           = gomega.Expect
+	HaveLen          = gomega.HaveLen
+	HaveOccurred     = gomega.HaveOccurred
+	Succeed          = gomega.Succeed
+)
+
+func TestWebhooks(t *testing.T) {
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "GitHub webhooks")
+}
@@ -54,18 +54,18 @@ func CheckAndUpsert(hookType HookType, force bool) error {
 	var confirmed bool
 
 	// Ping the git hook with our secret argument.
-	repoRoot, err := gitutil.RepositoryRootAbsolutePath()
+	hookDestPath, err := hookPath(hookType)
 	if err != nil {
 		return err
 	}
 
-	hookDestPath := filepath.Join(repoRoot, ".git", "hooks", string(hookType))
-
 	// Try to get the hook version.
-	stdout, _, _ := shell.Run(hookDestPath, "-"+versionFlag)
+	installedVersion, err := InstalledVersion(hookType)
+	if err != nil {
+		return err
+	}
 
 	// In case the versions match, we are done here (unless force).
-	installedVersion, err := version.Parse(strings.TrimSpace(stdout.String()))
 	if !force && installedVersion != nil && installedVersion.String() == metadata.Version {
 		return nil
 	}
@@ -120,6 +120,31 @@ runs as your `+string(hookType)+` hook and run me again!
 	return copyHook(hookType, hookExecutable, hookDestPath)
 }
 
+// InstalledVersion returns the version of the SalsaFlow git hook
+// of the given type installed in the current repository.
+//
+// In case there is no hook installed or the hook is not a SalsaFlow hook,
+// nil is returned.
+func InstalledVersion(hookType HookType) (*version.Version, error) {
+	hookDestPath, err := hookPath(hookType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ping the git hook with our secret argument.
+	stdout, _, _ := shell.Run(hookDestPath, "-"+versionFlag)
+	installedVersion, _ := version.Parse(strings.TrimSpace(stdout.String()))
+	return installedVersion, nil
+}
+
+func hookPath(hookType HookType) (string, error) {
+	repoRoot, err := gitutil.RepositoryRootAbsolutePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(repoRoot, ".git", "hooks", string(hookType)), nil
+}
+
 // copyHook installs the SalsaFlow git hook by copying the hook executable
 // from the expected absolute path to the git config hook directory.
 func copyHook(hookType HookType, hookExecutable, hookDestPath string) error {
@@ -0,0 +1,29 @@
+package httputil
+
+import (
+	// Stdlib
+	"testing"
+
+	// Vendor - testing framework
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var (
+	AfterEach  = ginkgo.AfterEach
+	BeforeEach = ginkgo.BeforeEach
+	Context    = ginkgo.Context
+	Describe   = ginkgo.Describe
+	It         = ginkgo.It
+
+	BeEmpty       = gomega.BeEmpty
+	BeNil         = gomega.BeNil
+	BeNumerically = gomega.BeNumerically
+	Equal         = gomega.Equal
+	Expect        = gomega.Expect
+)
+
+func TestHTTPUtilities(t *testing.T) {
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "HTTP utilities")
+}
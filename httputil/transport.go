@@ -0,0 +1,266 @@
+package httputil
+
+import (
+	// Stdlib
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/log"
+)
+
+const (
+	// DefaultMaxRetries is the number of times a request is retried by default.
+	DefaultMaxRetries = 5
+
+	// DefaultMinBackoff is the delay before the first retry.
+	DefaultMinBackoff = 500 * time.Millisecond
+
+	// DefaultMaxBackoff caps the delay between two consecutive retries.
+	DefaultMaxBackoff = 30 * time.Second
+
+	// DefaultMaxRateLimitWait is the longest time we are willing to wait
+	// for a rate limit to be reset. The response is returned as it is
+	// when the rate limit is to be reset later than that.
+	DefaultMaxRateLimitWait = 5 * time.Minute
+
+	// DefaultMaxConcurrentRequests caps the number of requests
+	// being sent over the default transport at the same time.
+	DefaultMaxConcurrentRequests = 10
+)
+
+// DefaultTransport is the transport shared by all the service modules.
+//
+// It is important that the modules share the same transport instance,
+// otherwise the concurrency cap would not apply across the modules.
+var DefaultTransport = NewTransport(nil, DefaultMaxConcurrentRequests)
+
+// DefaultClient is an http.Client using DefaultTransport.
+var DefaultClient = &http.Client{Transport: DefaultTransport}
+
+var installOnce sync.Once
+
+// InstallDefaultTransport makes http.DefaultClient use DefaultTransport.
+//
+// This is necessary for the API client libraries that always use
+// http.DefaultClient and cannot be configured to use another client.
+func InstallDefaultTransport() {
+	installOnce.Do(func() {
+		http.DefaultClient.Transport = DefaultTransport
+	})
+}
+
+// Transport is an http.RoundTripper that retries failed requests
+// using exponential backoff while respecting the service rate limits.
+//
+// Requests are retried when the rate limit was exceeded (403 with
+// X-RateLimit-Remaining set to 0 or with Retry-After, or 429). Idempotent requests are also
+// retried on server errors (5xx) and on network errors.
+//
+// In case the response contains X-RateLimit-Reset or Retry-After header,
+// the header is used to decide how long to wait before retrying.
+type Transport struct {
+	// Base is the underlying transport. http.DefaultTransport is used when nil.
+	Base http.RoundTripper
+
+	MaxRetries       int
+	MinBackoff       time.Duration
+	MaxBackoff       time.Duration
+	MaxRateLimitWait time.Duration
+
+	// sem limits the number of concurrent requests, it is nil when unlimited.
+	sem chan struct{}
+
+	// sleep is here so that the tests don't need to wait.
+	sleep func(time.Duration)
+	// now is here so that the tests can fake the current time.
+	now func() time.Time
+}
+
+// NewTransport returns a new Transport wrapping the given base transport.
+// maxConcurrentRequests being zero means that there is no limit.
+func NewTransport(base http.RoundTripper, maxConcurrentRequests int) *Transport {
+	t := &Transport{
+		Base:             base,
+		MaxRetries:       DefaultMaxRetries,
+		MinBackoff:       DefaultMinBackoff,
+		MaxBackoff:       DefaultMaxBackoff,
+		MaxRateLimitWait: DefaultMaxRateLimitWait,
+		sleep:            time.Sleep,
+		now:              time.Now,
+	}
+	if maxConcurrentRequests > 0 {
+		t.sem = make(chan struct{}, maxConcurrentRequests)
+	}
+	return t
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The request body must be replayable for the request to be retried.
+	canReplay := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+
+	backoff := t.MinBackoff
+	for attempt := 0; ; attempt++ {
+		r, err := t.prepareRequest(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := t.roundTrip(r)
+
+		// Decide whether to retry or not.
+		if attempt >= t.MaxRetries || !canReplay {
+			return resp, err
+		}
+		wait, retry := t.shouldRetry(req, resp, err)
+		if !retry {
+			return resp, err
+		}
+		if wait == 0 {
+			wait = backoff
+			backoff *= 2
+			if backoff > t.MaxBackoff {
+				backoff = t.MaxBackoff
+			}
+		}
+		if wait > t.MaxRateLimitWait {
+			return resp, err
+		}
+
+		// Drop the response and wait.
+		reason := describeFailure(resp, err)
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		log.V(log.Verbose).Warn(fmt.Sprintf("%v %v: %v, retrying in %v",
+			req.Method, req.URL.Host, reason, wait))
+		t.sleep(wait)
+	}
+}
+
+func (t *Transport) prepareRequest(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 || req.GetBody == nil {
+		return req, nil
+	}
+
+	// Never reuse the request body, get a fresh copy instead.
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	r := new(http.Request)
+	*r = *req
+	r.Body = body
+	return r, nil
+}
+
+func (t *Transport) roundTrip(req *http.Request) (*http.Response, error) {
+	if t.sem != nil {
+		t.sem <- struct{}{}
+		defer func() { <-t.sem }()
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
+// shouldRetry returns true in case the request is to be retried.
+// In case the response says how long to wait, the duration is returned as well.
+func (t *Transport) shouldRetry(req *http.Request, resp *http.Response, err error) (time.Duration, bool) {
+	// Network errors.
+	if err != nil {
+		return 0, isIdempotent(req.Method)
+	}
+
+	// Rate limiting. The request was not processed, so it is safe
+	// to retry it no matter what the request method is.
+	if isRateLimited(resp) {
+		return t.rateLimitWait(resp), true
+	}
+
+	// Server errors.
+	if resp.StatusCode >= 500 && isIdempotent(req.Method) {
+		return t.retryAfter(resp), true
+	}
+
+	return 0, false
+}
+
+func (t *Transport) rateLimitWait(resp *http.Response) time.Duration {
+	if wait := t.retryAfter(resp); wait != 0 {
+		return wait
+	}
+
+	if reset := resp.Header.Get("X-RateLimit-Reset"); reset != "" {
+		if sec, err := strconv.ParseInt(reset, 10, 64); err == nil {
+			// Add a second as a safety margin, the resolution is just seconds.
+			wait := time.Unix(sec, 0).Sub(t.now()) + time.Second
+			if wait > 0 {
+				return wait
+			}
+		}
+	}
+
+	return 0
+}
+
+func (t *Transport) retryAfter(resp *http.Response) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	// Retry-After can be either a number of seconds or an HTTP date.
+	if sec, err := strconv.Atoi(value); err == nil && sec > 0 {
+		return time.Duration(sec) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := date.Sub(t.now()); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
+func isRateLimited(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		// GitHub secondary rate limits keep X-RateLimit-Remaining non-zero,
+		// but the response contains Retry-After.
+		return resp.Header.Get("X-RateLimit-Remaining") == "0" ||
+			resp.Header.Get("Retry-After") != ""
+	default:
+		return false
+	}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "TRACE", "PUT", "DELETE":
+		return true
+	default:
+		return false
+	}
+}
+
+func describeFailure(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	if isRateLimited(resp) {
+		return "rate limit exceeded"
+	}
+	return resp.Status
+}
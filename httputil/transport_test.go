@@ -0,0 +1,233 @@
+package httputil
+
+import (
+	// Stdlib
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var _ = Describe("the retrying transport", func() {
+
+	var (
+		server    *httptest.Server
+		handler   func(attempt int, w http.ResponseWriter, r *http.Request)
+		attempts  int32
+		bodies    []string
+		sleeps    []time.Duration
+		transport *Transport
+		client    *http.Client
+		now       = time.Unix(1500000000, 0)
+		lock      sync.Mutex
+	)
+
+	BeforeEach(func() {
+		attempts = 0
+		bodies = nil
+		sleeps = nil
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempt := int(atomic.AddInt32(&attempts, 1))
+			body, _ := ioutil.ReadAll(r.Body)
+			lock.Lock()
+			bodies = append(bodies, string(body))
+			lock.Unlock()
+			handler(attempt, w, r)
+		}))
+
+		transport = NewTransport(nil, 0)
+		transport.sleep = func(d time.Duration) {
+			lock.Lock()
+			sleeps = append(sleeps, d)
+			lock.Unlock()
+		}
+		transport.now = func() time.Time {
+			return now
+		}
+		client = &http.Client{Transport: transport}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	// failFirst returns a handler that writes the given status
+	// for the first n attempts and 200 OK afterwards.
+	failFirst := func(n, status int, header http.Header) func(int, http.ResponseWriter, *http.Request) {
+		return func(attempt int, w http.ResponseWriter, r *http.Request) {
+			if attempt <= n {
+				for k, vs := range header {
+					w.Header()[k] = vs
+				}
+				w.WriteHeader(status)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}
+	}
+
+	post := func() (*http.Response, error) {
+		return client.Post(server.URL, "text/plain", bytes.NewBufferString("payload"))
+	}
+
+	Context("when the server keeps returning 5xx", func() {
+
+		BeforeEach(func() {
+			handler = failFirst(2, http.StatusBadGateway, nil)
+		})
+
+		It("should retry GET requests using exponential backoff", func() {
+			resp, err := client.Get(server.URL)
+			Expect(err).To(BeNil())
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(attempts).To(Equal(int32(3)))
+			Expect(sleeps).To(Equal([]time.Duration{DefaultMinBackoff, 2 * DefaultMinBackoff}))
+		})
+
+		It("should not retry POST requests", func() {
+			resp, err := post()
+			Expect(err).To(BeNil())
+			Expect(resp.StatusCode).To(Equal(http.StatusBadGateway))
+			Expect(attempts).To(Equal(int32(1)))
+		})
+
+		It("should give up after MaxRetries retries", func() {
+			transport.MaxRetries = 1
+			resp, err := client.Get(server.URL)
+			Expect(err).To(BeNil())
+			Expect(resp.StatusCode).To(Equal(http.StatusBadGateway))
+			Expect(attempts).To(Equal(int32(2)))
+		})
+	})
+
+	Context("when the rate limit is exceeded", func() {
+
+		It("should wait until X-RateLimit-Reset and retry even POST requests", func() {
+			reset := now.Add(42 * time.Second).Unix()
+			handler = failFirst(1, http.StatusForbidden, http.Header{
+				"X-Ratelimit-Remaining": {"0"},
+				"X-Ratelimit-Reset":     {strconv.FormatInt(reset, 10)},
+			})
+
+			resp, err := post()
+			Expect(err).To(BeNil())
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(sleeps).To(Equal([]time.Duration{43 * time.Second}))
+			Expect(bodies).To(Equal([]string{"payload", "payload"}))
+		})
+
+		It("should honour Retry-After", func() {
+			handler = failFirst(1, http.StatusTooManyRequests, http.Header{
+				"Retry-After": {"7"},
+			})
+
+			resp, err := client.Get(server.URL)
+			Expect(err).To(BeNil())
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(sleeps).To(Equal([]time.Duration{7 * time.Second}))
+		})
+
+		It("should honour Retry-After for the secondary rate limit", func() {
+			handler = failFirst(1, http.StatusForbidden, http.Header{
+				"X-Ratelimit-Remaining": {"4999"},
+				"Retry-After":           {"60"},
+			})
+
+			resp, err := post()
+			Expect(err).To(BeNil())
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(sleeps).To(Equal([]time.Duration{60 * time.Second}))
+			Expect(bodies).To(Equal([]string{"payload", "payload"}))
+		})
+
+		It("should not wait longer than MaxRateLimitWait", func() {
+			handler = failFirst(1, http.StatusTooManyRequests, http.Header{
+				"Retry-After": {"3600"},
+			})
+
+			resp, err := client.Get(server.URL)
+			Expect(err).To(BeNil())
+			Expect(resp.StatusCode).To(Equal(http.StatusTooManyRequests))
+			Expect(sleeps).To(BeEmpty())
+		})
+
+		It("should not treat a regular 403 as rate limiting", func() {
+			handler = failFirst(1, http.StatusForbidden, nil)
+
+			resp, err := client.Get(server.URL)
+			Expect(err).To(BeNil())
+			Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+			Expect(attempts).To(Equal(int32(1)))
+		})
+	})
+
+	Context("when the connection is dropped", func() {
+
+		BeforeEach(func() {
+			handler = func(attempt int, w http.ResponseWriter, r *http.Request) {
+				if attempt == 1 {
+					conn, _, _ := w.(http.Hijacker).Hijack()
+					conn.Close()
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}
+		})
+
+		It("should retry GET requests", func() {
+			resp, err := client.Get(server.URL)
+			Expect(err).To(BeNil())
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(attempts).To(Equal(int32(2)))
+		})
+
+		It("should not retry POST requests", func() {
+			_, err := post()
+			Expect(err).NotTo(BeNil())
+			Expect(attempts).To(Equal(int32(1)))
+		})
+	})
+
+	Context("when the number of concurrent requests is limited", func() {
+
+		It("should never send more requests at once", func() {
+			var inFlight, maxInFlight int32
+			handler = func(attempt int, w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&inFlight, 1)
+				defer atomic.AddInt32(&inFlight, -1)
+				for {
+					max := atomic.LoadInt32(&maxInFlight)
+					if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+				w.WriteHeader(http.StatusOK)
+			}
+
+			limited := NewTransport(nil, 2)
+			client := &http.Client{Transport: limited}
+
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					resp, err := client.Get(server.URL)
+					if err == nil {
+						resp.Body.Close()
+					}
+				}()
+			}
+			wg.Wait()
+
+			Expect(attempts).To(Equal(int32(10)))
+			Expect(maxInFlight).To(BeNumerically("<=", 2))
+		})
+	})
+})
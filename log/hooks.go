@@ -0,0 +1,43 @@
+package log
+
+import (
+	// Stdlib
+	"sync"
+)
+
+// Tag identifies the kind of a task message being logged.
+type Tag string
+
+const (
+	TagRun      Tag = "RUN"
+	TagRollback Tag = "ROLLBACK"
+)
+
+// Hook is a function that is called every time a task message is logged.
+//
+// Hooks are called regardless of the current verbosity level,
+// so they can be used to record what is happening even when
+// the log output itself is not being printed.
+type Hook func(tag Tag, msg string)
+
+var (
+	hooks     []Hook
+	hooksLock sync.Mutex
+)
+
+// AddHook registers a new hook to be called on task messages.
+func AddHook(hook Hook) {
+	hooksLock.Lock()
+	hooks = append(hooks, hook)
+	hooksLock.Unlock()
+}
+
+func runHooks(tag Tag, msg string) {
+	hooksLock.Lock()
+	hs := hooks
+	hooksLock.Unlock()
+
+	for _, hook := range hs {
+		hook(tag, msg)
+	}
+}
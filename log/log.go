@@ -168,10 +168,12 @@ func (l Logger) UnsafeFatalln(v ...interface{}) {
 }
 
 func (l Logger) Run(msg string) {
+	runHooks(TagRun, msg)
 	l.logf("[RUN]      %v\n", msg)
 }
 
 func (l Logger) UnsafeRun(msg string) {
+	runHooks(TagRun, msg)
 	l.unsafeLogf("[RUN]      %v\n", msg)
 }
 
@@ -224,10 +226,12 @@ func (l Logger) UnsafeFail(msg string) {
 }
 
 func (l Logger) Rollback(msg string) {
+	runHooks(TagRollback, msg)
 	l.logf("[ROLLBACK] %v\n", msg)
 }
 
 func (l Logger) UnsafeRollback(msg string) {
+	runHooks(TagRollback, msg)
 	l.unsafeLogf("[ROLLBACK] %v\n", msg)
 }
 
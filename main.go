@@ -7,15 +7,23 @@ import (
 	"os/signal"
 
 	// Internal
+	"github.com/salsaflow/salsaflow/app"
 	"github.com/salsaflow/salsaflow/app/appflags"
 	"github.com/salsaflow/salsaflow/app/metadata"
 	"github.com/salsaflow/salsaflow/commands/cherrypick"
+	"github.com/salsaflow/salsaflow/commands/config"
+	"github.com/salsaflow/salsaflow/commands/logs"
 	"github.com/salsaflow/salsaflow/commands/pkg"
 	"github.com/salsaflow/salsaflow/commands/release"
 	"github.com/salsaflow/salsaflow/commands/repo"
 	"github.com/salsaflow/salsaflow/commands/review"
+	"github.com/salsaflow/salsaflow/commands/serve"
 	"github.com/salsaflow/salsaflow/commands/story"
 	"github.com/salsaflow/salsaflow/commands/version"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/modules"
+	"github.com/salsaflow/salsaflow/output"
+	"github.com/salsaflow/salsaflow/trace"
 
 	// Other
 	"gopkg.in/tchap/gocli.v2"
@@ -36,8 +44,7 @@ func main() {
 	trunk.Flags.BoolVar(&flagVersion, "version", flagVersion, "print SalsaFlow version and exit")
 	trunk.Action = func(cmd *gocli.Command, args []string) {
 		if len(args) != 0 || !flagVersion {
-			cmd.Usage()
-			os.Exit(2)
+			app.ExitWithUsage(cmd)
 		}
 		fmt.Println(metadata.Version)
 	}
@@ -47,10 +54,13 @@ func main() {
 
 	// Register subcommands.
 	trunk.MustRegisterSubcommand(cherrypickCmd.Command)
+	trunk.MustRegisterSubcommand(configCmd.Command)
+	trunk.MustRegisterSubcommand(logsCmd.Command)
 	trunk.MustRegisterSubcommand(pkgCmd.Command)
 	trunk.MustRegisterSubcommand(releaseCmd.Command)
 	trunk.MustRegisterSubcommand(repoCmd.Command)
 	trunk.MustRegisterSubcommand(reviewCmd.Command)
+	trunk.MustRegisterSubcommand(serveCmd.Command)
 	trunk.MustRegisterSubcommand(storyCmd.Command)
 	trunk.MustRegisterSubcommand(versionCmd.Command)
 
@@ -61,6 +71,18 @@ func main() {
 
 	// Run the application.
 	trunk.Run(os.Args[1:])
+
+	// Stop the plugins started, if any.
+	modules.ClosePlugins()
+
+	// Write the result in case the JSON output mode is active.
+	// Failures never get here, errs.Fatal writes the result and exits.
+	if err := output.Flush(); err != nil {
+		errs.Fatal(err)
+	}
+
+	// Close the trace log, if enabled.
+	trace.Close(errs.ExitCodeSuccess)
 }
 
 func catchSignals(ch chan os.Signal) {
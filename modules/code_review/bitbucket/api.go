@@ -0,0 +1,160 @@
+package bitbucket
+
+import (
+	// Stdlib
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/httputil"
+)
+
+// Pull request states, the same for Bitbucket Cloud and Bitbucket Server.
+const (
+	pullRequestStateOpen     = "OPEN"
+	pullRequestStateMerged   = "MERGED"
+	pullRequestStateDeclined = "DECLINED"
+)
+
+// pullRequest represents a Bitbucket pull request,
+// independently on the Bitbucket flavour being used.
+type pullRequest struct {
+	Id           int
+	Title        string
+	Description  string
+	State        string
+	SourceBranch string
+	TargetBranch string
+	URL          string
+	Reviewers    []string
+	CreatedAt    time.Time
+
+	// version is required by Bitbucket Server to update the pull request.
+	version int
+}
+
+// pullRequestFilter narrows down the pull requests being listed.
+// The fields left empty are not used for filtering.
+type pullRequestFilter struct {
+	// SourceBranch is the exact name of the source branch.
+	SourceBranch string
+
+	// TargetBranchPrefix is the prefix the target branch name starts with.
+	TargetBranchPrefix string
+}
+
+// match returns true when the given pull request matches the filter.
+// It is used for the filtering not supported by the Bitbucket API.
+func (filter *pullRequestFilter) match(pr *pullRequest) bool {
+	if filter.SourceBranch != "" && pr.SourceBranch != filter.SourceBranch {
+		return false
+	}
+	return strings.HasPrefix(pr.TargetBranch, filter.TargetBranchPrefix)
+}
+
+// client is implemented for both Bitbucket Cloud and Bitbucket Server.
+type client interface {
+	// ListOpenPullRequests returns the open pull requests matching the filter.
+	// The filtering is done by Bitbucket as far as the API allows for that.
+	ListOpenPullRequests(filter *pullRequestFilter) ([]*pullRequest, error)
+
+	// GetPullRequest returns the pull request with the given ID.
+	GetPullRequest(id int) (*pullRequest, error)
+
+	// CreatePullRequest creates a new pull request. The reviewers are optional.
+	CreatePullRequest(pr *pullRequest) (*pullRequest, error)
+
+	// UpdatePullRequest updates the title and the description of the given pull request.
+	UpdatePullRequest(pr *pullRequest) (*pullRequest, error)
+}
+
+// newClient returns the client for the Bitbucket flavour being configured.
+func newClient(config *moduleConfig) client {
+	api := &apiClient{
+		username: config.Username,
+		password: config.Password,
+		http:     httputil.DefaultClient,
+	}
+	if config.ServerURL != "" {
+		api.baseURL = config.ServerURL + "/rest/api/1.0"
+		return &serverClient{api, config.Project, config.Repository}
+	}
+	api.baseURL = cloudAPIBaseURL
+	return &cloudClient{api, config.Project, config.Repository}
+}
+
+// ErrorResponse is returned in case the Bitbucket API returns an error.
+type ErrorResponse struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (err *ErrorResponse) Error() string {
+	return fmt.Sprintf("%v %v: %v %v", err.Method, err.URL, err.StatusCode, err.Body)
+}
+
+// apiClient sends the requests to the Bitbucket REST API.
+type apiClient struct {
+	baseURL  string
+	username string
+	password string
+	http     *http.Client
+}
+
+// do sends the request and decodes the response body into v unless it is nil.
+// The path can be also an absolute URL, which is handy for following pagination links.
+func (api *apiClient) do(method, path string, body, v interface{}) error {
+	url := path
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		url = api.baseURL + path
+	}
+
+	var bodyReader *bytes.Reader
+	if body != nil {
+		content, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		bodyReader = bytes.NewReader(content)
+	} else {
+		bodyReader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, url, bodyReader)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(api.username, api.password)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := api.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		content, _ := ioutil.ReadAll(resp.Body)
+		return &ErrorResponse{
+			Method:     method,
+			URL:        url,
+			StatusCode: resp.StatusCode,
+			Body:       strings.TrimSpace(string(content)),
+		}
+	}
+
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
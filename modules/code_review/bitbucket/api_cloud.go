@@ -0,0 +1,169 @@
+package bitbucket
+
+import (
+	// Stdlib
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const cloudAPIBaseURL = "https://api.bitbucket.org/2.0"
+
+// cloudClient implements client for Bitbucket Cloud, API version 2.0.
+type cloudClient struct {
+	api       *apiClient
+	workspace string
+	repo      string
+}
+
+type cloudBranchRef struct {
+	Branch struct {
+		Name string `json:"name"`
+	} `json:"branch"`
+}
+
+type cloudUser struct {
+	AccountId string `json:"account_id,omitempty"`
+}
+
+type cloudPullRequest struct {
+	Id          int             `json:"id,omitempty"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	State       string          `json:"state,omitempty"`
+	Source      *cloudBranchRef `json:"source,omitempty"`
+	Destination *cloudBranchRef `json:"destination,omitempty"`
+	Reviewers   []*cloudUser    `json:"reviewers,omitempty"`
+	CreatedOn   *time.Time      `json:"created_on,omitempty"`
+	Links       *cloudPullLinks `json:"links,omitempty"`
+}
+
+type cloudPullLinks struct {
+	HTML struct {
+		Href string `json:"href"`
+	} `json:"html"`
+}
+
+type cloudPullRequestPage struct {
+	Values []*cloudPullRequest `json:"values"`
+	Next   string              `json:"next"`
+}
+
+func (c *cloudClient) pullRequestsPath() string {
+	return fmt.Sprintf("/repositories/%v/%v/pullrequests",
+		url.PathEscape(c.workspace), url.PathEscape(c.repo))
+}
+
+// ListOpenPullRequests is a part of client interface.
+//
+// The pull requests are filtered using the Bitbucket query language.
+// The target branch prefix can only be matched as a substring there,
+// so the prefix is checked once more for the pull requests returned.
+func (c *cloudClient) ListOpenPullRequests(filter *pullRequestFilter) ([]*pullRequest, error) {
+	conditions := []string{fmt.Sprintf("state = %v", strconv.Quote(pullRequestStateOpen))}
+	if filter.SourceBranch != "" {
+		conditions = append(conditions,
+			fmt.Sprintf("source.branch.name = %v", strconv.Quote(filter.SourceBranch)))
+	}
+	if filter.TargetBranchPrefix != "" {
+		conditions = append(conditions,
+			fmt.Sprintf("destination.branch.name ~ %v", strconv.Quote(filter.TargetBranchPrefix)))
+	}
+	query := url.Values{
+		"q":       []string{strings.Join(conditions, " AND ")},
+		"pagelen": []string{"50"},
+	}
+
+	var prs []*pullRequest
+	next := c.pullRequestsPath() + "?" + query.Encode()
+	for next != "" {
+		var page cloudPullRequestPage
+		if err := c.api.do("GET", next, nil, &page); err != nil {
+			return nil, err
+		}
+		for _, pr := range page.Values {
+			if pr := pr.toPullRequest(); filter.match(pr) {
+				prs = append(prs, pr)
+			}
+		}
+		next = page.Next
+	}
+	return prs, nil
+}
+
+// GetPullRequest is a part of client interface.
+func (c *cloudClient) GetPullRequest(id int) (*pullRequest, error) {
+	var pr cloudPullRequest
+	path := fmt.Sprintf("%v/%v", c.pullRequestsPath(), id)
+	if err := c.api.do("GET", path, nil, &pr); err != nil {
+		return nil, err
+	}
+	return pr.toPullRequest(), nil
+}
+
+// CreatePullRequest is a part of client interface.
+func (c *cloudClient) CreatePullRequest(pr *pullRequest) (*pullRequest, error) {
+	body := &cloudPullRequest{
+		Title:       pr.Title,
+		Description: pr.Description,
+		Source:      newCloudBranchRef(pr.SourceBranch),
+		Destination: newCloudBranchRef(pr.TargetBranch),
+	}
+	for _, reviewer := range pr.Reviewers {
+		body.Reviewers = append(body.Reviewers, &cloudUser{reviewer})
+	}
+
+	var created cloudPullRequest
+	if err := c.api.do("POST", c.pullRequestsPath(), body, &created); err != nil {
+		return nil, err
+	}
+	return created.toPullRequest(), nil
+}
+
+// UpdatePullRequest is a part of client interface.
+func (c *cloudClient) UpdatePullRequest(pr *pullRequest) (*pullRequest, error) {
+	body := &cloudPullRequest{
+		Title:       pr.Title,
+		Description: pr.Description,
+	}
+
+	var updated cloudPullRequest
+	path := fmt.Sprintf("%v/%v", c.pullRequestsPath(), pr.Id)
+	if err := c.api.do("PUT", path, body, &updated); err != nil {
+		return nil, err
+	}
+	return updated.toPullRequest(), nil
+}
+
+func newCloudBranchRef(branch string) *cloudBranchRef {
+	var ref cloudBranchRef
+	ref.Branch.Name = branch
+	return &ref
+}
+
+func (pr *cloudPullRequest) toPullRequest() *pullRequest {
+	result := &pullRequest{
+		Id:          pr.Id,
+		Title:       pr.Title,
+		Description: pr.Description,
+		State:       pr.State,
+	}
+	if pr.Source != nil {
+		result.SourceBranch = pr.Source.Branch.Name
+	}
+	if pr.Destination != nil {
+		result.TargetBranch = pr.Destination.Branch.Name
+	}
+	if pr.Links != nil {
+		result.URL = pr.Links.HTML.Href
+	}
+	if pr.CreatedOn != nil {
+		result.CreatedAt = *pr.CreatedOn
+	}
+	for _, reviewer := range pr.Reviewers {
+		result.Reviewers = append(result.Reviewers, reviewer.AccountId)
+	}
+	return result
+}
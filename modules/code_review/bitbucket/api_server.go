@@ -0,0 +1,183 @@
+package bitbucket
+
+import (
+	// Stdlib
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// serverClient implements client for Bitbucket Server, REST API version 1.0.
+type serverClient struct {
+	api     *apiClient
+	project string
+	repo    string
+}
+
+type serverRepository struct {
+	Slug    string `json:"slug"`
+	Project struct {
+		Key string `json:"key"`
+	} `json:"project"`
+}
+
+type serverRef struct {
+	Id         string            `json:"id"`
+	DisplayId  string            `json:"displayId,omitempty"`
+	Repository *serverRepository `json:"repository,omitempty"`
+}
+
+type serverParticipant struct {
+	User struct {
+		Name string `json:"name"`
+	} `json:"user"`
+}
+
+type serverPullRequest struct {
+	Id          int                  `json:"id,omitempty"`
+	Version     int                  `json:"version"`
+	Title       string               `json:"title"`
+	Description string               `json:"description"`
+	State       string               `json:"state,omitempty"`
+	FromRef     *serverRef           `json:"fromRef,omitempty"`
+	ToRef       *serverRef           `json:"toRef,omitempty"`
+	Reviewers   []*serverParticipant `json:"reviewers,omitempty"`
+	CreatedDate int64                `json:"createdDate,omitempty"`
+	Links       *serverPullLinks     `json:"links,omitempty"`
+}
+
+type serverPullLinks struct {
+	Self []struct {
+		Href string `json:"href"`
+	} `json:"self"`
+}
+
+type serverPullRequestPage struct {
+	Values        []*serverPullRequest `json:"values"`
+	IsLastPage    bool                 `json:"isLastPage"`
+	NextPageStart int                  `json:"nextPageStart"`
+}
+
+func (c *serverClient) pullRequestsPath() string {
+	return fmt.Sprintf("/projects/%v/repos/%v/pull-requests",
+		url.PathEscape(c.project), url.PathEscape(c.repo))
+}
+
+// ListOpenPullRequests is a part of client interface.
+//
+// The source branch is matched by Bitbucket Server, which cannot
+// filter by a branch prefix, so the target branch is checked locally.
+func (c *serverClient) ListOpenPullRequests(filter *pullRequestFilter) ([]*pullRequest, error) {
+	var (
+		prs   []*pullRequest
+		start int
+	)
+	for {
+		query := url.Values{
+			"state": []string{pullRequestStateOpen},
+			"limit": []string{"50"},
+			"start": []string{strconv.Itoa(start)},
+		}
+		if filter.SourceBranch != "" {
+			query.Set("at", "refs/heads/"+filter.SourceBranch)
+			query.Set("direction", "OUTGOING")
+		}
+
+		var page serverPullRequestPage
+		if err := c.api.do("GET", c.pullRequestsPath()+"?"+query.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+		for _, pr := range page.Values {
+			if pr := pr.toPullRequest(); filter.match(pr) {
+				prs = append(prs, pr)
+			}
+		}
+		if page.IsLastPage {
+			return prs, nil
+		}
+		start = page.NextPageStart
+	}
+}
+
+// GetPullRequest is a part of client interface.
+func (c *serverClient) GetPullRequest(id int) (*pullRequest, error) {
+	var pr serverPullRequest
+	path := fmt.Sprintf("%v/%v", c.pullRequestsPath(), id)
+	if err := c.api.do("GET", path, nil, &pr); err != nil {
+		return nil, err
+	}
+	return pr.toPullRequest(), nil
+}
+
+// CreatePullRequest is a part of client interface.
+func (c *serverClient) CreatePullRequest(pr *pullRequest) (*pullRequest, error) {
+	body := &serverPullRequest{
+		Title:       pr.Title,
+		Description: pr.Description,
+		FromRef:     c.newRef(pr.SourceBranch),
+		ToRef:       c.newRef(pr.TargetBranch),
+	}
+	for _, reviewer := range pr.Reviewers {
+		var participant serverParticipant
+		participant.User.Name = reviewer
+		body.Reviewers = append(body.Reviewers, &participant)
+	}
+
+	var created serverPullRequest
+	if err := c.api.do("POST", c.pullRequestsPath(), body, &created); err != nil {
+		return nil, err
+	}
+	return created.toPullRequest(), nil
+}
+
+// UpdatePullRequest is a part of client interface.
+func (c *serverClient) UpdatePullRequest(pr *pullRequest) (*pullRequest, error) {
+	body := &serverPullRequest{
+		Version:     pr.version,
+		Title:       pr.Title,
+		Description: pr.Description,
+	}
+
+	var updated serverPullRequest
+	path := fmt.Sprintf("%v/%v", c.pullRequestsPath(), pr.Id)
+	if err := c.api.do("PUT", path, body, &updated); err != nil {
+		return nil, err
+	}
+	return updated.toPullRequest(), nil
+}
+
+func (c *serverClient) newRef(branch string) *serverRef {
+	repo := &serverRepository{Slug: c.repo}
+	repo.Project.Key = c.project
+	return &serverRef{
+		Id:         "refs/heads/" + branch,
+		Repository: repo,
+	}
+}
+
+func (pr *serverPullRequest) toPullRequest() *pullRequest {
+	result := &pullRequest{
+		Id:          pr.Id,
+		Title:       pr.Title,
+		Description: pr.Description,
+		State:       pr.State,
+		version:     pr.Version,
+	}
+	if pr.FromRef != nil {
+		result.SourceBranch = pr.FromRef.DisplayId
+	}
+	if pr.ToRef != nil {
+		result.TargetBranch = pr.ToRef.DisplayId
+	}
+	if pr.Links != nil && len(pr.Links.Self) != 0 {
+		result.URL = pr.Links.Self[0].Href
+	}
+	if pr.CreatedDate != 0 {
+		result.CreatedAt = time.Unix(0, pr.CreatedDate*int64(time.Millisecond))
+	}
+	for _, reviewer := range pr.Reviewers {
+		result.Reviewers = append(result.Reviewers, reviewer.User.Name)
+	}
+	return result
+}
@@ -0,0 +1,444 @@
+package bitbucket
+
+import (
+	// Stdlib
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	testProject    = "acme"
+	testRepository = "widgets"
+	testUsername   = "alice"
+	testPassword   = "s3cr3t"
+)
+
+var testCreatedAt = time.Date(2016, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// fakeBitbucket is an in-memory fake of the pull request endpoints
+// of both the Bitbucket Cloud and the Bitbucket Server REST API.
+type fakeBitbucket struct {
+	server   *httptest.Server
+	pageSize int
+
+	lock sync.Mutex
+	prs  []*pullRequest
+}
+
+func newFakeBitbucket() *fakeBitbucket {
+	fake := &fakeBitbucket{pageSize: 2}
+
+	cloudPath := fmt.Sprintf("/2.0/repositories/%v/%v/pullrequests", testProject, testRepository)
+	serverPath := fmt.Sprintf("/rest/api/1.0/projects/%v/repos/%v/pull-requests", testProject, testRepository)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(cloudPath, fake.authenticated(fake.serveCloudPullRequests))
+	mux.HandleFunc(cloudPath+"/", fake.authenticated(fake.serveCloudPullRequest))
+	mux.HandleFunc(serverPath, fake.authenticated(fake.serveServerPullRequests))
+	mux.HandleFunc(serverPath+"/", fake.authenticated(fake.serveServerPullRequest))
+	fake.server = httptest.NewServer(mux)
+	return fake
+}
+
+func (fake *fakeBitbucket) Close() {
+	fake.server.Close()
+}
+
+// addPullRequest stores the given pull request, assigning it the next ID.
+func (fake *fakeBitbucket) addPullRequest(pr *pullRequest) *pullRequest {
+	pr.Id = len(fake.prs) + 1
+	pr.URL = fmt.Sprintf("%v/pull-requests/%v", fake.server.URL, pr.Id)
+	pr.CreatedAt = testCreatedAt
+	if pr.State == "" {
+		pr.State = pullRequestStateOpen
+	}
+	fake.prs = append(fake.prs, pr)
+	return pr
+}
+
+func (fake *fakeBitbucket) authenticated(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != testUsername || password != testPassword {
+			http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+		fake.lock.Lock()
+		defer fake.lock.Unlock()
+		handler(w, r)
+	}
+}
+
+func (fake *fakeBitbucket) lookup(w http.ResponseWriter, r *http.Request) *pullRequest {
+	id, err := strconv.Atoi(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+	if err != nil || id < 1 || id > len(fake.prs) {
+		http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+		return nil
+	}
+	return fake.prs[id-1]
+}
+
+// filter returns the pull requests accepted by the given function.
+func (fake *fakeBitbucket) filter(accept func(pr *pullRequest) bool) []*pullRequest {
+	var prs []*pullRequest
+	for _, pr := range fake.prs {
+		if accept(pr) {
+			prs = append(prs, pr)
+		}
+	}
+	return prs
+}
+
+// page returns the pull requests starting at the given index.
+func (fake *fakeBitbucket) page(prs []*pullRequest, start int) (page []*pullRequest, last bool) {
+	end := start + fake.pageSize
+	if end >= len(prs) {
+		return prs[start:], true
+	}
+	return prs[start:end], false
+}
+
+// Bitbucket Cloud -------------------------------------------------------------
+
+// cloudConditionRegexp matches a single condition of a Bitbucket Cloud query.
+var cloudConditionRegexp = regexp.MustCompile(`^([a-z.]+) ([=~]) ("(?:[^"\\]|\\.)*")$`)
+
+func (fake *fakeBitbucket) serveCloudPullRequests(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		// Only the subset of the query language used by the client is supported.
+		var conditions [][]string
+		for _, cond := range strings.Split(r.URL.Query().Get("q"), " AND ") {
+			match := cloudConditionRegexp.FindStringSubmatch(cond)
+			if match == nil {
+				http.Error(w, `{"error": "invalid query"}`, http.StatusBadRequest)
+				return
+			}
+			value, err := strconv.Unquote(match[3])
+			if err != nil {
+				http.Error(w, `{"error": "invalid query"}`, http.StatusBadRequest)
+				return
+			}
+			conditions = append(conditions, []string{match[1], match[2], value})
+		}
+		if len(conditions) == 0 || conditions[0][0] != "state" {
+			http.Error(w, `{"error": "state filter expected"}`, http.StatusBadRequest)
+			return
+		}
+
+		start, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		prs, last := fake.page(fake.filter(func(pr *pullRequest) bool {
+			for _, cond := range conditions {
+				var field string
+				switch cond[0] {
+				case "state":
+					field = pr.State
+				case "source.branch.name":
+					field = pr.SourceBranch
+				case "destination.branch.name":
+					field = pr.TargetBranch
+				}
+				if cond[1] == "=" && field != cond[2] {
+					return false
+				}
+				if cond[1] == "~" && !strings.Contains(field, cond[2]) {
+					return false
+				}
+			}
+			return true
+		}), start)
+
+		var page struct {
+			Values []*cloudPullRequest `json:"values"`
+			Next   string              `json:"next,omitempty"`
+		}
+		for _, pr := range prs {
+			page.Values = append(page.Values, toCloudPullRequest(pr))
+		}
+		if !last {
+			query := r.URL.Query()
+			query.Set("page", strconv.Itoa(start+fake.pageSize))
+			page.Next = fmt.Sprintf("%v%v?%v", fake.server.URL, r.URL.Path, query.Encode())
+		}
+		json.NewEncoder(w).Encode(&page)
+
+	case "POST":
+		var body cloudPullRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, `{"error": "bad request"}`, http.StatusBadRequest)
+			return
+		}
+		pr := fake.addPullRequest(body.toPullRequest())
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(toCloudPullRequest(pr))
+	}
+}
+
+func (fake *fakeBitbucket) serveCloudPullRequest(w http.ResponseWriter, r *http.Request) {
+	pr := fake.lookup(w, r)
+	if pr == nil {
+		return
+	}
+	if r.Method == "PUT" {
+		var body cloudPullRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, `{"error": "bad request"}`, http.StatusBadRequest)
+			return
+		}
+		pr.Title = body.Title
+		pr.Description = body.Description
+	}
+	json.NewEncoder(w).Encode(toCloudPullRequest(pr))
+}
+
+func toCloudPullRequest(pr *pullRequest) *cloudPullRequest {
+	body := &cloudPullRequest{
+		Id:          pr.Id,
+		Title:       pr.Title,
+		Description: pr.Description,
+		State:       pr.State,
+		Source:      newCloudBranchRef(pr.SourceBranch),
+		Destination: newCloudBranchRef(pr.TargetBranch),
+		CreatedOn:   &pr.CreatedAt,
+		Links:       &cloudPullLinks{},
+	}
+	body.Links.HTML.Href = pr.URL
+	for _, reviewer := range pr.Reviewers {
+		body.Reviewers = append(body.Reviewers, &cloudUser{reviewer})
+	}
+	return body
+}
+
+// Bitbucket Server ------------------------------------------------------------
+
+func (fake *fakeBitbucket) serveServerPullRequests(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		query := r.URL.Query()
+		if query.Get("state") != pullRequestStateOpen {
+			http.Error(w, `{"error": "state filter expected"}`, http.StatusBadRequest)
+			return
+		}
+		at := strings.TrimPrefix(query.Get("at"), "refs/heads/")
+		if at != "" && query.Get("direction") != "OUTGOING" {
+			http.Error(w, `{"error": "outgoing direction expected"}`, http.StatusBadRequest)
+			return
+		}
+
+		start, _ := strconv.Atoi(query.Get("start"))
+		prs, last := fake.page(fake.filter(func(pr *pullRequest) bool {
+			return pr.State == pullRequestStateOpen && (at == "" || pr.SourceBranch == at)
+		}), start)
+
+		var page serverPullRequestPage
+		for _, pr := range prs {
+			page.Values = append(page.Values, toServerPullRequest(pr))
+		}
+		page.IsLastPage = last
+		if !last {
+			page.NextPageStart = start + fake.pageSize
+		}
+		json.NewEncoder(w).Encode(&page)
+
+	case "POST":
+		var body serverPullRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, `{"error": "bad request"}`, http.StatusBadRequest)
+			return
+		}
+		pr := body.toPullRequest()
+		pr.SourceBranch = strings.TrimPrefix(body.FromRef.Id, "refs/heads/")
+		pr.TargetBranch = strings.TrimPrefix(body.ToRef.Id, "refs/heads/")
+		pr = fake.addPullRequest(pr)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(toServerPullRequest(pr))
+	}
+}
+
+func (fake *fakeBitbucket) serveServerPullRequest(w http.ResponseWriter, r *http.Request) {
+	pr := fake.lookup(w, r)
+	if pr == nil {
+		return
+	}
+	if r.Method == "PUT" {
+		var body serverPullRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, `{"error": "bad request"}`, http.StatusBadRequest)
+			return
+		}
+		// Bitbucket Server rejects updates of outdated pull requests.
+		if body.Version != pr.version {
+			http.Error(w, `{"error": "version mismatch"}`, http.StatusConflict)
+			return
+		}
+		pr.Title = body.Title
+		pr.Description = body.Description
+		pr.version++
+	}
+	json.NewEncoder(w).Encode(toServerPullRequest(pr))
+}
+
+func toServerPullRequest(pr *pullRequest) *serverPullRequest {
+	body := &serverPullRequest{
+		Id:          pr.Id,
+		Version:     pr.version,
+		Title:       pr.Title,
+		Description: pr.Description,
+		State:       pr.State,
+		FromRef:     &serverRef{Id: "refs/heads/" + pr.SourceBranch, DisplayId: pr.SourceBranch},
+		ToRef:       &serverRef{Id: "refs/heads/" + pr.TargetBranch, DisplayId: pr.TargetBranch},
+		CreatedDate: pr.CreatedAt.UnixNano() / int64(time.Millisecond),
+		Links:       &serverPullLinks{},
+	}
+	body.Links.Self = append(body.Links.Self, struct {
+		Href string `json:"href"`
+	}{pr.URL})
+	for _, reviewer := range pr.Reviewers {
+		var participant serverParticipant
+		participant.User.Name = reviewer
+		body.Reviewers = append(body.Reviewers, &participant)
+	}
+	return body
+}
+
+// newTestClient returns the client for the given flavour talking to the fake.
+func newTestClient(fake *fakeBitbucket, server bool) client {
+	config := &moduleConfig{
+		Project:    testProject,
+		Repository: testRepository,
+		Username:   testUsername,
+		Password:   testPassword,
+	}
+	if server {
+		config.ServerURL = fake.server.URL
+		return newClient(config)
+	}
+
+	c := newClient(config).(*cloudClient)
+	c.api.baseURL = fake.server.URL + "/2.0"
+	return c
+}
+
+var _ = Describe("Bitbucket API client", func() {
+
+	for _, flavour := range []string{"Bitbucket Cloud", "Bitbucket Server"} {
+		server := flavour == "Bitbucket Server"
+
+		Context("using "+flavour, func() {
+
+			var (
+				fake *fakeBitbucket
+				c    client
+			)
+
+			BeforeEach(func() {
+				fake = newFakeBitbucket()
+				c = newTestClient(fake, server)
+			})
+
+			AfterEach(func() {
+				fake.Close()
+			})
+
+			It("should list the open pull requests across pages", func() {
+				for i, state := range []string{
+					pullRequestStateOpen,
+					pullRequestStateMerged,
+					pullRequestStateOpen,
+					pullRequestStateDeclined,
+					pullRequestStateOpen,
+					pullRequestStateOpen,
+				} {
+					fake.addPullRequest(&pullRequest{
+						Title:        fmt.Sprintf("Pull request %v", i+1),
+						State:        state,
+						SourceBranch: fmt.Sprintf("review/1.2.0/story-%v", i+1),
+						TargetBranch: fmt.Sprintf("review-base/1.2.0/story-%v", i+1),
+					})
+				}
+
+				prs, err := c.ListOpenPullRequests(&pullRequestFilter{})
+				Expect(err).To(BeNil())
+				Expect(prs).To(HaveLen(4))
+				Expect(prs[3].Id).To(Equal(6))
+				Expect(prs[1].State).To(Equal(pullRequestStateOpen))
+				Expect(prs[1].SourceBranch).To(Equal("review/1.2.0/story-3"))
+				Expect(prs[1].TargetBranch).To(Equal("review-base/1.2.0/story-3"))
+				Expect(prs[0].CreatedAt.Equal(testCreatedAt)).To(BeTrue())
+			})
+
+			It("should filter the open pull requests by the branches", func() {
+				for _, pr := range []*pullRequest{
+					{SourceBranch: "review/1.2.0/story-1", TargetBranch: "review-base/1.2.0/story-1"},
+					{SourceBranch: "review/1.2.0/story-2", TargetBranch: "review-base/1.2.0/story-2"},
+					{SourceBranch: "review/1.3.0/story-3", TargetBranch: "review-base/1.3.0/story-3"},
+					{SourceBranch: "review/1.3.0/story-1.2.0", TargetBranch: "review-base/1.3.0/review-base/1.2.0"},
+				} {
+					fake.addPullRequest(pr)
+				}
+
+				prs, err := c.ListOpenPullRequests(&pullRequestFilter{SourceBranch: "review/1.2.0/story-2"})
+				Expect(err).To(BeNil())
+				Expect(prs).To(HaveLen(1))
+				Expect(prs[0].Id).To(Equal(2))
+
+				prs, err = c.ListOpenPullRequests(&pullRequestFilter{TargetBranchPrefix: "review-base/1.2.0/"})
+				Expect(err).To(BeNil())
+				Expect(prs).To(HaveLen(2))
+				Expect(prs[0].Id).To(Equal(1))
+				Expect(prs[1].Id).To(Equal(2))
+			})
+
+			It("should create a pull request", func() {
+				pr, err := c.CreatePullRequest(&pullRequest{
+					Title:        "Review story #42: Paint the widget",
+					Description:  "Story: [#42](https://example.com/42)",
+					SourceBranch: "review/1.2.0/story-42",
+					TargetBranch: "review-base/1.2.0/story-42",
+					Reviewers:    []string{"bob"},
+				})
+				Expect(err).To(BeNil())
+				Expect(pr.Id).To(Equal(1))
+				Expect(pr.URL).To(Equal(fake.server.URL + "/pull-requests/1"))
+				Expect(pr.State).To(Equal(pullRequestStateOpen))
+
+				stored := fake.prs[0]
+				Expect(stored.Title).To(Equal("Review story #42: Paint the widget"))
+				Expect(stored.SourceBranch).To(Equal("review/1.2.0/story-42"))
+				Expect(stored.TargetBranch).To(Equal("review-base/1.2.0/story-42"))
+				Expect(stored.Reviewers).To(Equal([]string{"bob"}))
+			})
+
+			It("should update the pull request description", func() {
+				fake.addPullRequest(&pullRequest{Title: "Review commit 1a2b3c4: Fix the widget"})
+
+				pr, err := c.GetPullRequest(1)
+				Expect(err).To(BeNil())
+
+				pr.Description = "Commit: 1a2b3c4"
+				pr, err = c.UpdatePullRequest(pr)
+				Expect(err).To(BeNil())
+				Expect(pr.Description).To(Equal("Commit: 1a2b3c4"))
+
+				// The version returned is used for the next update.
+				pr.Description = "Commit: 5d6e7f8"
+				_, err = c.UpdatePullRequest(pr)
+				Expect(err).To(BeNil())
+				Expect(fake.prs[0].Description).To(Equal("Commit: 5d6e7f8"))
+			})
+
+			It("should return the error response for a missing pull request", func() {
+				_, err := c.GetPullRequest(7)
+				Expect(err).To(HaveOccurred())
+				Expect(err.(*ErrorResponse).StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+	}
+})
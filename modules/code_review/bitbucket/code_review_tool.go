@@ -0,0 +1,457 @@
+package bitbucket
+
+import (
+	// Stdlib
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/git"
+	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/output"
+	"github.com/salsaflow/salsaflow/repo"
+	"github.com/salsaflow/salsaflow/version"
+
+	// Vendor
+	"github.com/toqueteos/webbrowser"
+)
+
+var errPostReviewRequest = errors.New("failed to post a review request")
+
+func newCodeReviewTool() (common.CodeReviewTool, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return &codeReviewTool{config, newClient(config)}, nil
+}
+
+type codeReviewTool struct {
+	config *moduleConfig
+	client client
+}
+
+func (tool *codeReviewTool) NewRelease(v *version.Version) common.Release {
+	return newRelease(tool, v)
+}
+
+func (tool *codeReviewTool) PostReviewRequests(
+	ctxs []*common.ReviewContext,
+	opts map[string]interface{},
+) (err error) {
+
+	// The review branches are built using git worktree,
+	// so make sure it is available before anything is pushed.
+	if _, err := repo.CheckGitVersion(); err != nil {
+		return err
+	}
+
+	// Group commits by story ID.
+	//
+	// In case the commit is associated with a story, we add it to the relevant story group.
+	// Otherwise the commit is marked as unassigned and added to the relevant list.
+	var (
+		ctxsByStoryId     = make(map[string][]*common.ReviewContext, 1)
+		unassignedCommits = make([]*git.Commit, 0, 1)
+	)
+	for _, ctx := range ctxs {
+		story := ctx.Story
+		if story != nil {
+			sid := story.ReadableId()
+			ctxsByStoryId[sid] = append(ctxsByStoryId[sid], ctx)
+		} else {
+			unassignedCommits = append(unassignedCommits, ctx.Commit)
+		}
+	}
+
+	// Post the assigned commits.
+	_, open := opts["open"]
+	for _, ctxs := range ctxsByStoryId {
+		var (
+			story   = ctxs[0].Story
+			commits = make([]*git.Commit, 0, len(ctxs))
+		)
+		for _, ctx := range ctxs {
+			commits = append(commits, ctx.Commit)
+		}
+
+		// Create/update the pull request.
+		pr, ex := tool.postReviewBranch(
+			storyBranchKey(story.ReadableId()),
+			fmt.Sprintf("Review story %v: %v", story.ReadableId(), story.Title()),
+			fmt.Sprintf("Story: [%v](%v)", story.ReadableId(), story.URL()),
+			commits, opts)
+		if ex != nil {
+			errs.Log(ex)
+			err = errPostReviewRequest
+			continue
+		}
+		recordPullRequest(pr, open)
+	}
+
+	// Get the value of -fixes.
+	var fixes int
+	if v, ok := opts["fixes"].(uint); ok && v != 0 {
+		fixes = int(v)
+	}
+
+	// Post the unassigned commits.
+	for _, commit := range unassignedCommits {
+		var (
+			pr *pullRequest
+			ex error
+		)
+		if fixes != 0 {
+			// Extend the specified pull request.
+			pr, ex = tool.extendPullRequestById(fixes, []*git.Commit{commit})
+		} else {
+			// Create/update the pull request.
+			pr, ex = tool.postReviewBranch(
+				"commit-"+commit.SHA,
+				fmt.Sprintf("Review commit %v: %v", commit.SHA, commit.MessageTitle),
+				fmt.Sprintf("Commit: %v", commit.SHA),
+				[]*git.Commit{commit}, opts)
+		}
+		if ex != nil {
+			errs.Log(ex)
+			err = errPostReviewRequest
+			continue
+		}
+		recordPullRequest(pr, open)
+	}
+
+	return
+}
+
+func (tool *codeReviewTool) PostReviewFollowupMessage() string {
+	return `
+Bitbucket pull requests successfully created.
+
+Please visit the pull requests that have been created and make sure
+a reviewer is assigned. Annotate and explain the changes to make
+the reviewer's job easier.
+
+In case there are any issues raised for a story pull request,
+just keep posting commits for review. The pull request is updated
+automatically when the same Story-Id tag is used.
+
+In case there are any issues raised for an unassigned commit
+pull request, use
+
+    $ salsaflow review post -fixes=PULL_REQUEST_ID
+
+to add the fixing commits into the pull request PULL_REQUEST_ID.
+`
+}
+
+// Review branches -------------------------------------------------------------
+
+// Every review request is a pull request from the review branch
+// into the review base branch. The base branch points to the parent
+// of the first commit posted, the review branch contains the commits posted
+// on top of the base branch.
+// The release the commits belong to is encoded into the branch names
+// since there are no milestones in Bitbucket.
+const (
+	reviewBranchPrefix     = "review/"
+	reviewBaseBranchPrefix = "review-base/"
+)
+
+func reviewBranch(v *version.Version, key string) string {
+	return reviewBranchPrefix + v.BaseString() + "/" + key
+}
+
+func reviewBaseBranch(v *version.Version, key string) string {
+	return releaseBaseBranchPrefix(v) + key
+}
+
+// releaseBaseBranchPrefix returns the prefix shared by the target branches
+// of all pull requests associated with the given release.
+func releaseBaseBranchPrefix(v *version.Version) string {
+	return reviewBaseBranchPrefix + v.BaseString() + "/"
+}
+
+var branchKeyRegexp = regexp.MustCompile("[^A-Za-z0-9._-]+")
+
+// storyBranchKey turns the readable story ID into a branch name component.
+func storyBranchKey(storyId string) string {
+	return "story-" + strings.Trim(branchKeyRegexp.ReplaceAllString(storyId, "-"), "-")
+}
+
+// postReviewBranch pushes the commits into the review branch for the given key
+// and creates the pull request for the review branch unless there is one open already.
+func (tool *codeReviewTool) postReviewBranch(
+	key string,
+	title string,
+	header string,
+	commits []*git.Commit,
+	opts map[string]interface{},
+) (*pullRequest, error) {
+
+	var (
+		first = commits[0]
+		last  = commits[len(commits)-1]
+	)
+
+	// Get the release associated with the commits.
+	task := fmt.Sprintf("Get the release associated with commit %v", last.SHA)
+	v, err := version.GetByBranch(last.SHA)
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+
+	var (
+		source = reviewBranch(v, key)
+		target = reviewBaseBranch(v, key)
+	)
+
+	// Search for an existing pull request for the review branch.
+	task = fmt.Sprintf("Search for an open pull request for branch '%v'", source)
+	log.Run(task)
+	prs, err := tool.client.ListOpenPullRequests(&pullRequestFilter{SourceBranch: source})
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+	if len(prs) != 0 {
+		// An open pull request found, extend it.
+		return tool.extendPullRequest(prs[0], commits)
+	}
+
+	// Get the remote to push into.
+	gitConfig, err := git.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	remoteName := gitConfig.RemoteName
+
+	// Push the base branch unless it exists already, which is the case
+	// when the previous pull request for the review branch is closed.
+	task = fmt.Sprintf("Push review base branch '%v'", target)
+	base, err := remoteBranchHexsha(remoteName, target)
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+	if base == "" {
+		log.Run(task)
+		base, err = git.CommitHexsha(first.SHA + "^")
+		if err != nil {
+			return nil, errs.NewError(task, fmt.Errorf(
+				"commit %v has no parent to be used as the review base", first.SHA))
+		}
+		if err := git.Push(remoteName, base+":refs/heads/"+target); err != nil {
+			return nil, errs.NewError(task, err)
+		}
+	}
+
+	// Push the review branch.
+	if err := pushReviewBranch(remoteName, source, base, commits); err != nil {
+		return nil, err
+	}
+
+	// Create the pull request.
+	task = fmt.Sprintf("Create pull request for branch '%v'", source)
+	log.Run(task)
+	pr := &pullRequest{
+		Title:        title,
+		Description:  formatDescription(header, nil, commits),
+		SourceBranch: source,
+		TargetBranch: target,
+	}
+	if reviewer, ok := opts["reviewer"].(string); ok && reviewer != "" {
+		pr.Reviewers = []string{reviewer}
+	}
+	pr, err = tool.client.CreatePullRequest(pr)
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+	return pr, nil
+}
+
+// extendPullRequestById adds the commits into the given pull request.
+func (tool *codeReviewTool) extendPullRequestById(id int, commits []*git.Commit) (*pullRequest, error) {
+	task := fmt.Sprintf("Fetch pull request #%v", id)
+	log.Run(task)
+	pr, err := tool.client.GetPullRequest(id)
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+	if pr.State != pullRequestStateOpen {
+		return nil, errs.NewError(task, fmt.Errorf("pull request #%v is not open", id))
+	}
+	return tool.extendPullRequest(pr, commits)
+}
+
+// extendPullRequest pushes the commits into the review branch
+// and lists the new commits in the pull request description.
+func (tool *codeReviewTool) extendPullRequest(pr *pullRequest, commits []*git.Commit) (*pullRequest, error) {
+	gitConfig, err := git.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	// Push the commits that are not in the review branch yet.
+	header, listed := parseDescription(pr.Description)
+	if newCommits := unlistedCommits(listed, commits); len(newCommits) != 0 {
+		remoteName := gitConfig.RemoteName
+		task := fmt.Sprintf("Get review branch '%v'", pr.SourceBranch)
+		tip, err := remoteBranchHexsha(remoteName, pr.SourceBranch)
+		if err != nil {
+			return nil, errs.NewError(task, err)
+		}
+		if tip == "" {
+			return nil, errs.NewError(task, fmt.Errorf(
+				"branch '%v' not found in remote '%v'", pr.SourceBranch, remoteName))
+		}
+		if err := pushReviewBranch(remoteName, pr.SourceBranch, tip, newCommits); err != nil {
+			return nil, err
+		}
+	}
+
+	// Update the pull request description.
+	description := formatDescription(header, listed, commits)
+	if description == pr.Description {
+		return pr, nil
+	}
+
+	task := fmt.Sprintf("Update pull request #%v", pr.Id)
+	log.Run(task)
+	pr.Description = description
+	updated, err := tool.client.UpdatePullRequest(pr)
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+	return updated, nil
+}
+
+// pushReviewBranch pushes the given commits on top of the given base
+// into the review branch.
+//
+// The commits are cherry-picked onto the base unless they are sitting
+// on top of it already. That way the review branch contains exactly
+// the commits posted, not the commits of other stories in between.
+func pushReviewBranch(remoteName, branch, base string, commits []*git.Commit) error {
+	task := fmt.Sprintf("Push review branch '%v'", branch)
+	log.Run(task)
+	shas := make([]string, 0, len(commits))
+	for _, commit := range commits {
+		shas = append(shas, commit.SHA)
+	}
+	head, err := git.CherryPickOnto(base, shas...)
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+	if err := git.PushForce(remoteName, head+":refs/heads/"+branch); err != nil {
+		return errs.NewError(task, err)
+	}
+	return nil
+}
+
+// remoteBranchHexsha returns the SHA of the given remote branch,
+// an empty string in case the branch does not exist.
+// The branch is fetched unless the commit is available locally.
+func remoteBranchHexsha(remoteName, branch string) (string, error) {
+	ref := "refs/heads/" + branch
+	stdout, err := git.Run("ls-remote", "--heads", remoteName, ref)
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(stdout.String())
+	if len(fields) == 0 {
+		return "", nil
+	}
+	sha := fields[0]
+	if _, err := git.CommitHexsha(sha); err != nil {
+		if _, err := git.Run("fetch", remoteName, ref); err != nil {
+			return "", err
+		}
+	}
+	return sha, nil
+}
+
+func recordPullRequest(pr *pullRequest, open bool) {
+	// Record the pull request for the JSON output.
+	output.AddURL("pull_request", pr.URL)
+
+	// Open the pull request in the browser if requested.
+	if open {
+		if err := webbrowser.Open(pr.URL); err != nil {
+			log.Warn("Failed to open the pull request in the browser")
+		}
+	}
+}
+
+// Pull request description ----------------------------------------------------
+
+// reviewCommit represents a commit listed in the pull request description.
+type reviewCommit struct {
+	SHA   string
+	Title string
+}
+
+var descriptionCommitRegexp = regexp.MustCompile(`^- ([0-9a-f]+): (.+)$`)
+
+const descriptionCommitsHeading = "Commits:"
+
+// formatDescription formats the pull request description,
+// listing the commits already listed and the new commits.
+func formatDescription(header string, listed []*reviewCommit, commits []*git.Commit) string {
+	seen := make(map[string]struct{}, len(listed)+len(commits))
+	for _, commit := range listed {
+		seen[commit.SHA] = struct{}{}
+	}
+	for _, commit := range commits {
+		if _, ok := seen[commit.SHA]; ok {
+			continue
+		}
+		seen[commit.SHA] = struct{}{}
+		listed = append(listed, &reviewCommit{commit.SHA, commit.MessageTitle})
+	}
+
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "%v\n\n%v\n\n", header, descriptionCommitsHeading)
+	for _, commit := range listed {
+		fmt.Fprintf(&buffer, "- %v: %v\n", commit.SHA, commit.Title)
+	}
+	return buffer.String()
+}
+
+// unlistedCommits returns the commits that are not listed yet.
+func unlistedCommits(listed []*reviewCommit, commits []*git.Commit) []*git.Commit {
+	seen := make(map[string]struct{}, len(listed))
+	for _, commit := range listed {
+		seen[commit.SHA] = struct{}{}
+	}
+	var unlisted []*git.Commit
+	for _, commit := range commits {
+		if _, ok := seen[commit.SHA]; !ok {
+			unlisted = append(unlisted, commit)
+		}
+	}
+	return unlisted
+}
+
+// parseDescription is the inverse of formatDescription.
+func parseDescription(description string) (header string, listed []*reviewCommit) {
+	var headerLines []string
+	inCommits := false
+	for _, line := range strings.Split(description, "\n") {
+		line = strings.TrimRight(line, "\r")
+		switch {
+		case line == descriptionCommitsHeading:
+			inCommits = true
+		case inCommits:
+			if match := descriptionCommitRegexp.FindStringSubmatch(line); match != nil {
+				listed = append(listed, &reviewCommit{match[1], match[2]})
+			}
+		default:
+			headerLines = append(headerLines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(headerLines, "\n")), listed
+}
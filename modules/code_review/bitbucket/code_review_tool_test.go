@@ -0,0 +1,66 @@
+package bitbucket
+
+import (
+	// Internal
+	"github.com/salsaflow/salsaflow/git"
+)
+
+var _ = Describe("code review tool", func() {
+
+	Describe("parseRemoteURL", func() {
+
+		It("should parse the Bitbucket Cloud and the Bitbucket Server URLs", func() {
+			for _, remoteURL := range []string{
+				"git@bitbucket.org:acme/widgets.git",
+				"https://alice@bitbucket.org/acme/widgets.git",
+				"ssh://git@bitbucket.example.com:7999/acme/widgets.git",
+				"https://bitbucket.example.com/scm/acme/widgets",
+			} {
+				project, repo, err := parseRemoteURL(remoteURL)
+				Expect(err).To(BeNil())
+				Expect(project).To(Equal("acme"))
+				Expect(repo).To(Equal("widgets"))
+			}
+		})
+	})
+
+	Describe("storyBranchKey", func() {
+
+		It("should turn the story ID into a branch name component", func() {
+			Expect(storyBranchKey("#42")).To(Equal("story-42"))
+			Expect(storyBranchKey("SF-42")).To(Equal("story-SF-42"))
+		})
+	})
+
+	Describe("pull request description", func() {
+
+		It("should list the new commits after the commits listed already", func() {
+			header := "Story: [#42](https://example.com/42)"
+			description := formatDescription(header, nil, []*git.Commit{
+				{SHA: "1a2b3c4", MessageTitle: "Build the widget"},
+			})
+
+			parsedHeader, listed := parseDescription(description)
+			Expect(parsedHeader).To(Equal(header))
+			Expect(listed).To(HaveLen(1))
+
+			description = formatDescription(parsedHeader, listed, []*git.Commit{
+				{SHA: "1a2b3c4", MessageTitle: "Build the widget"},
+				{SHA: "5d6e7f8", MessageTitle: "Paint the widget"},
+			})
+			Expect(description).To(Equal(header + "\n\nCommits:\n\n" +
+				"- 1a2b3c4: Build the widget\n" +
+				"- 5d6e7f8: Paint the widget\n"))
+		})
+
+		It("should return the commits that are not listed yet", func() {
+			listed := []*reviewCommit{{"1a2b3c4", "Build the widget"}}
+			unlisted := unlistedCommits(listed, []*git.Commit{
+				{SHA: "1a2b3c4", MessageTitle: "Build the widget"},
+				{SHA: "5d6e7f8", MessageTitle: "Paint the widget"},
+			})
+			Expect(unlisted).To(HaveLen(1))
+			Expect(unlisted[0].SHA).To(Equal("5d6e7f8"))
+		})
+	})
+})
@@ -0,0 +1,168 @@
+package bitbucket
+
+import (
+	// Stdlib
+	"fmt"
+	"regexp"
+	"strings"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/config/loader"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/git"
+	"github.com/salsaflow/salsaflow/prompt"
+)
+
+// Configuration ===============================================================
+
+type moduleConfig struct {
+	// Bitbucket Server base URL, empty for Bitbucket Cloud.
+	ServerURL string
+
+	// Bitbucket repository, the project is the workspace for Bitbucket Cloud.
+	Project    string
+	Repository string
+
+	// Bitbucket API authentication.
+	Username string
+	Password string
+}
+
+func loadConfig() (*moduleConfig, error) {
+	task := fmt.Sprintf("Load config for module '%v'", ModuleId)
+
+	// Load the config.
+	spec := newConfigSpec()
+	if err := loader.LoadConfig(spec); err != nil {
+		return nil, errs.NewError(task, err)
+	}
+
+	// Get the Bitbucket project and repository from the upstream URL.
+	project, repo, err := parseUpstreamURL()
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+
+	return &moduleConfig{
+		ServerURL:  strings.TrimSuffix(spec.local.ServerURL, "/"),
+		Project:    project,
+		Repository: repo,
+		Username:   spec.global.Username,
+		Password:   spec.global.Password,
+	}, nil
+}
+
+// parseUpstreamURL returns the Bitbucket project and repository
+// the git remote used by SalsaFlow points to.
+func parseUpstreamURL() (project, repo string, err error) {
+	gitConfig, err := git.LoadConfig()
+	if err != nil {
+		return "", "", err
+	}
+	remoteName := gitConfig.RemoteName
+
+	task := fmt.Sprintf("Get URL for git remote '%v'", remoteName)
+	remoteURL, err := git.GetConfigString(fmt.Sprintf("remote.%v.url", remoteName))
+	if err != nil {
+		return "", "", errs.NewError(task, err)
+	}
+
+	return parseRemoteURL(remoteURL)
+}
+
+var remoteURLRegexp = regexp.MustCompile(`[:/]([^:/]+)/([^:/]+?)(?:\.git)?/?$`)
+
+// parseRemoteURL returns the last two path components of the given remote URL,
+// which works for Bitbucket Cloud as well as for Bitbucket Server, e.g.
+//
+//	git@bitbucket.org:workspace/repo.git
+//	ssh://git@bitbucket.example.com:7999/project/repo.git
+//	https://bitbucket.example.com/scm/project/repo.git
+func parseRemoteURL(remoteURL string) (project, repo string, err error) {
+	match := remoteURLRegexp.FindStringSubmatch(remoteURL)
+	if match == nil {
+		task := "Parse the upstream repository URL"
+		return "", "", errs.NewError(task, fmt.Errorf("failed to parse git remote URL: %v", remoteURL))
+	}
+	return match[1], match[2], nil
+}
+
+// Configuration spec ----------------------------------------------------------
+
+type configSpec struct {
+	global *GlobalConfig
+	local  *LocalConfig
+}
+
+func newConfigSpec() *configSpec {
+	return &configSpec{}
+}
+
+// ConfigKey is a part of loader.ConfigSpec
+func (spec *configSpec) ConfigKey() string {
+	return ModuleId
+}
+
+// ModuleKind is a part of loader.ModuleConfigSpec
+func (spec *configSpec) ModuleKind() loader.ModuleKind {
+	return ModuleKind
+}
+
+// GlobalConfig is a part of loader.ConfigSpec
+func (spec *configSpec) GlobalConfig() loader.ConfigContainer {
+	spec.global = &GlobalConfig{}
+	return spec.global
+}
+
+// LocalConfig is a part of loader.ConfigSpec
+func (spec *configSpec) LocalConfig() loader.ConfigContainer {
+	spec.local = &LocalConfig{}
+	return spec.local
+}
+
+// Local configuration ---------------------------------------------------------
+
+type LocalConfig struct {
+	// Bitbucket Server base URL, Bitbucket Cloud is used when not set.
+	ServerURL string `json:"server_url,omitempty" optional:"true"`
+}
+
+// PromptUserForConfig is a part of loader.ConfigContainer interface.
+func (local *LocalConfig) PromptUserForConfig() error {
+	var c LocalConfig
+
+	cloud, err := prompt.Confirm("Is the repository hosted on Bitbucket Cloud?", true)
+	if err != nil {
+		return err
+	}
+	if !cloud {
+		c.ServerURL, err = prompt.Prompt("Insert the Bitbucket Server URL: ")
+		if err != nil {
+			if err == prompt.ErrCanceled {
+				prompt.PanicCancel()
+			}
+			return err
+		}
+	}
+
+	*local = c
+	return nil
+}
+
+// Global configuration --------------------------------------------------------
+
+type GlobalConfig struct {
+	Username string `prompt:"Bitbucket username" json:"username"`
+	Password string `prompt:"Bitbucket app password or access token" secret:"true" json:"password"`
+}
+
+// PromptUserForConfig is a part of loader.ConfigContainer interface.
+func (global *GlobalConfig) PromptUserForConfig() error {
+	var c GlobalConfig
+	if err := prompt.Dialog(&c, "Insert your"); err != nil {
+		return err
+	}
+
+	*global = c
+	return nil
+}
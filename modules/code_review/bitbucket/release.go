@@ -0,0 +1,155 @@
+package bitbucket
+
+import (
+	// Stdlib
+	"bytes"
+	"fmt"
+	"strings"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/action"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/git"
+	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/version"
+)
+
+type release struct {
+	tool *codeReviewTool
+	v    *version.Version
+
+	closable bool
+}
+
+func newRelease(tool *codeReviewTool, v *version.Version) *release {
+	return &release{
+		tool: tool,
+		v:    v,
+	}
+}
+
+// Initialise is a part of common.Release interface.
+//
+// There is nothing to be done since the review branches
+// for the release are created when the commits are posted for review.
+func (r *release) Initialise() (action.Action, error) {
+	return action.Noop, nil
+}
+
+// EnsureClosable is a part of common.Release interface.
+//
+// The release can be closed when all the pull requests
+// associated with the release are either merged or declined.
+func (r *release) EnsureClosable() error {
+	// Get the pull requests associated with the release.
+	releaseString := r.v.BaseString()
+	task := fmt.Sprintf("Get Bitbucket pull requests for release %v", releaseString)
+	log.Run(task)
+	prs, err := r.tool.client.ListOpenPullRequests(&pullRequestFilter{
+		TargetBranchPrefix: releaseBaseBranchPrefix(r.v),
+	})
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+
+	// Make sure none of them is still open.
+	task = fmt.Sprintf("Make sure the pull requests for release %v are closed", releaseString)
+	if len(prs) != 0 {
+		var hint bytes.Buffer
+		fmt.Fprintf(&hint, "\nThe following pull requests for release %v are still open:\n\n", releaseString)
+		for _, pr := range prs {
+			fmt.Fprintf(&hint, "  #%v %v\n", pr.Id, pr.Title)
+		}
+		fmt.Fprintln(&hint)
+		return errs.NewErrorWithHint(task, common.ErrNotClosable, hint.String())
+	}
+
+	r.closable = true
+	return nil
+}
+
+// Close is a part of common.Release interface.
+//
+// Close makes sure the release can be closed, then it deletes
+// the review branches and the review base branches for the release.
+// The branches are pushed back on rollback.
+func (r *release) Close() (action.Action, error) {
+	if !r.closable {
+		if err := r.EnsureClosable(); err != nil {
+			return nil, err
+		}
+	}
+
+	gitConfig, err := git.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	remoteName := gitConfig.RemoteName
+
+	// Get the review branches for the release.
+	releaseString := r.v.BaseString()
+	task := fmt.Sprintf("Get the review branches for release %v", releaseString)
+	log.Run(task)
+	stdout, err := git.Run("ls-remote", "--heads", remoteName,
+		"refs/heads/"+reviewBranchPrefix+releaseString+"/*",
+		"refs/heads/"+releaseBaseBranchPrefix(r.v)+"*")
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+	branches := parseRemoteHeads(stdout.String())
+	if len(branches) == 0 {
+		return action.Noop, nil
+	}
+
+	// Fetch the branches so that they can be pushed back on rollback.
+	refs := make([]string, 0, len(branches))
+	for _, branch := range branches {
+		refs = append(refs, "refs/heads/"+branch.name)
+	}
+	if _, err := git.Run(append([]string{"fetch", remoteName}, refs...)...); err != nil {
+		return nil, errs.NewError(task, err)
+	}
+
+	// Delete the branches.
+	deleteTask := fmt.Sprintf("Delete the review branches for release %v", releaseString)
+	log.Run(deleteTask)
+	if _, err := git.Run(append([]string{"push", remoteName, "--delete"}, refs...)...); err != nil {
+		return nil, errs.NewError(deleteTask, err)
+	}
+
+	// Push the branches back on rollback.
+	return action.ActionFunc(func() error {
+		log.Rollback(deleteTask)
+		task := fmt.Sprintf("Push the review branches for release %v", releaseString)
+		refspecs := make([]string, 0, len(branches))
+		for _, branch := range branches {
+			refspecs = append(refspecs, branch.hexsha+":refs/heads/"+branch.name)
+		}
+		if _, err := git.Run(append([]string{"push", remoteName}, refspecs...)...); err != nil {
+			return errs.NewError(task, err)
+		}
+		return nil
+	}), nil
+}
+
+type remoteHead struct {
+	name   string
+	hexsha string
+}
+
+// parseRemoteHeads parses the output of git ls-remote --heads.
+func parseRemoteHeads(stdout string) []*remoteHead {
+	var heads []*remoteHead
+	for _, line := range strings.Split(stdout, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || !strings.HasPrefix(fields[1], "refs/heads/") {
+			continue
+		}
+		heads = append(heads, &remoteHead{
+			name:   strings.TrimPrefix(fields[1], "refs/heads/"),
+			hexsha: fields[0],
+		})
+	}
+	return heads
+}
@@ -0,0 +1,88 @@
+package bitbucket
+
+import (
+	// Internal
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/version"
+)
+
+var _ = Describe("release", func() {
+
+	var (
+		fake *fakeBitbucket
+		r    *release
+	)
+
+	BeforeEach(func() {
+		fake = newFakeBitbucket()
+
+		v, err := version.Parse("1.2.0")
+		Expect(err).To(BeNil())
+		r = newRelease(&codeReviewTool{client: newTestClient(fake, false)}, v)
+
+		fake.addPullRequest(&pullRequest{
+			Title:        "Review story #1: Build the widget",
+			State:        pullRequestStateMerged,
+			SourceBranch: "review/1.2.0/story-1",
+			TargetBranch: "review-base/1.2.0/story-1",
+		})
+		fake.addPullRequest(&pullRequest{
+			Title:        "Review story #2: Break the widget",
+			State:        pullRequestStateDeclined,
+			SourceBranch: "review/1.2.0/story-2",
+			TargetBranch: "review-base/1.2.0/story-2",
+		})
+		fake.addPullRequest(&pullRequest{
+			Title:        "Review story #3: Rebuild the widget",
+			SourceBranch: "review/1.3.0/story-3",
+			TargetBranch: "review-base/1.3.0/story-3",
+		})
+	})
+
+	AfterEach(func() {
+		fake.Close()
+	})
+
+	Describe("EnsureClosable", func() {
+
+		It("should succeed when all the pull requests are merged or declined", func() {
+			Expect(r.EnsureClosable()).To(BeNil())
+		})
+
+		It("should fail when there is a pull request still open", func() {
+			fake.addPullRequest(&pullRequest{
+				Title:        "Review commit 1a2b3c4: Polish the widget",
+				SourceBranch: "review/1.2.0/commit-1a2b3c4",
+				TargetBranch: "review-base/1.2.0/commit-1a2b3c4",
+			})
+
+			err := r.EnsureClosable()
+			Expect(err).To(HaveOccurred())
+			Expect(errs.RootCause(err)).To(Equal(common.ErrNotClosable))
+			Expect(err.(errs.Err).Hint()).To(ContainSubstring("#4 Review commit 1a2b3c4"))
+		})
+	})
+
+	Describe("Close", func() {
+
+		It("should make sure the release can be closed", func() {
+			fake.prs[1].State = pullRequestStateOpen
+			_, err := r.Close()
+			Expect(errs.RootCause(err)).To(Equal(common.ErrNotClosable))
+		})
+	})
+
+	Describe("parseRemoteHeads", func() {
+
+		It("should return the branch names and the commit SHAs", func() {
+			heads := parseRemoteHeads(
+				"1a2b3c4d5e6f1a2b3c4d5e6f1a2b3c4d5e6f1a2b\trefs/heads/review/1.2.0/story-1\n" +
+					"5e6f1a2b3c4d5e6f1a2b3c4d5e6f1a2b3c4d5e6f\trefs/heads/review-base/1.2.0/story-1\n")
+			Expect(heads).To(HaveLen(2))
+			Expect(heads[0].name).To(Equal("review/1.2.0/story-1"))
+			Expect(heads[0].hexsha).To(Equal("1a2b3c4d5e6f1a2b3c4d5e6f1a2b3c4d5e6f1a2b"))
+			Expect(heads[1].name).To(Equal("review-base/1.2.0/story-1"))
+		})
+	})
+})
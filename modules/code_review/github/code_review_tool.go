@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"strconv"
 
 	// Internal
 	"github.com/salsaflow/salsaflow/action"
@@ -14,6 +15,7 @@ import (
 	ghissues "github.com/salsaflow/salsaflow/github/issues"
 	"github.com/salsaflow/salsaflow/log"
 	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/output"
 	"github.com/salsaflow/salsaflow/version"
 
 	// Vendor
@@ -97,6 +99,9 @@ func (tool *codeReviewTool) PostReviewRequests(
 		// Add comments to the commits posted for review.
 		linkCommitsToReviewIssue(tool.config, owner, repo, *issue.Number, postedCommits)
 
+		// Record the review issue for the JSON output.
+		output.AddURL("review_issue", *issue.HTMLURL)
+
 		// Open the review issue in the browser if requested.
 		if open {
 			openIssue(issue)
@@ -137,6 +142,9 @@ func (tool *codeReviewTool) PostReviewRequests(
 		// Add comments to the commits posted for review.
 		linkCommitsToReviewIssue(tool.config, owner, repo, *issue.Number, postedCommits)
 
+		// Record the review issue for the JSON output.
+		output.AddURL("review_issue", *issue.HTMLURL)
+
 		// Open the review issue in the browser if requested.
 		if open {
 			openIssue(issue)
@@ -146,11 +154,65 @@ func (tool *codeReviewTool) PostReviewRequests(
 	return
 }
 
+// PostStackedReviewRequest is a part of common.StackedReviewPoster interface.
+//
+// The dependency is recorded in the review issue body using SF-Depends-On tag.
+func (tool *codeReviewTool) PostStackedReviewRequest(
+	ctxs []*common.ReviewContext,
+	dependsOn string,
+	opts map[string]interface{},
+) (string, error) {
+
+	story := ctxs[0].Story
+	commits := make([]*git.Commit, 0, len(ctxs))
+	for _, ctx := range ctxs {
+		if ctx.Story == nil || ctx.Story.Id() != story.Id() {
+			panic("PostStackedReviewRequest: the commits must be associated with the same story")
+		}
+		commits = append(commits, ctx.Commit)
+	}
+
+	// Get the GitHub owner and repository from the upstream URL.
+	owner, repo, err := ghutil.ParseUpstreamURL()
+	if err != nil {
+		return "", err
+	}
+
+	// Create/update the review issue.
+	stackOpts := make(map[string]interface{}, len(opts)+1)
+	for k, v := range opts {
+		stackOpts[k] = v
+	}
+	if dependsOn != "" {
+		stackOpts["depends_on"] = "#" + dependsOn
+	}
+	issue, postedCommits, err := postAssignedReviewRequest(
+		tool.config, owner, repo, story, commits, stackOpts)
+	if err != nil {
+		return "", err
+	}
+
+	// Add comments to the commits posted for review.
+	linkCommitsToReviewIssue(tool.config, owner, repo, *issue.Number, postedCommits)
+
+	// Record the review issue for the JSON output.
+	output.AddURL("review_issue", *issue.HTMLURL)
+
+	// Open the review issue in the browser if requested.
+	if _, open := opts["open"]; open {
+		openIssue(issue)
+	}
+
+	return strconv.Itoa(*issue.Number), nil
+}
+
 func (tool *codeReviewTool) PostReviewFollowupMessage() string {
 	return `
 GitHub review issues successfully created.
 
-Please visit the issues that have been created and assign a reviewer.
+Please visit the issues that have been created and make sure
+a reviewer is assigned. The reviewer is only selected automatically
+when the reviewer selection is configured for the repository.
 Annotate and explain the changes to make the reviewer's job easier.
 
 In case there are any review issues raised for a story review issue,
@@ -181,7 +243,7 @@ func postAssignedReviewRequest(
 	task := fmt.Sprintf("Search for an existing review issue for story %v", story.ReadableId())
 	log.Run(task)
 
-	client := ghutil.NewClient(config.Token)
+	client := ghutil.NewClientForEndpoints(config.Token, config.GitHubEndpoints)
 	issue, err := ghissues.FindReviewIssueForStory(client, owner, repo, story.ReadableId())
 	if err != nil {
 		return nil, nil, errs.NewError(task, err)
@@ -222,8 +284,9 @@ func createAssignedReviewRequest(
 		story.IssueTracker().ServiceName(),
 		story.Tag())
 
+	reviewIssue.DependsOn = optValueString(opts["depends_on"])
 	for _, commit := range commits {
-		reviewIssue.AddCommit(false, commit.SHA, commit.MessageTitle)
+		reviewIssue.AddCommitWithChangeId(false, commit.SHA, commit.ChangeIdTag, commit.MessageTitle)
 	}
 
 	// Get the right review milestone to add the issue into.
@@ -233,12 +296,14 @@ func createAssignedReviewRequest(
 		return nil, errs.NewError(task, err)
 	}
 
-	// Create a new review issue.
+	// Create a new review issue. The reviewer is only selected here,
+	// the existing review issues keep the reviewer already assigned.
 	var implemented bool
 	implementedOpt, ok := opts["implemented"]
 	if ok {
 		implemented = implementedOpt.(bool)
 	}
+	opts = withReviewer(config, owner, repo, commits, opts)
 
 	issue, err := createIssue(
 		task, config, owner, repo,
@@ -264,7 +329,7 @@ func postUnassignedReviewRequest(
 	task := fmt.Sprintf("Search for an existing review issue for commit %v", commit.SHA)
 	log.Run(task)
 
-	client := ghutil.NewClient(config.Token)
+	client := ghutil.NewClientForEndpoints(config.Token, config.GitHubEndpoints)
 	issue, err := ghissues.FindReviewIssueForCommit(client, owner, repo, commit.SHA)
 	if err != nil {
 		return nil, nil, errs.NewError(task, err)
@@ -306,7 +371,9 @@ func createUnassignedReviewRequest(
 		return nil, errs.NewError(task, err)
 	}
 
-	// Create a new review issue.
+	// Create a new review issue. The reviewer is only selected here,
+	// the existing review issues keep the reviewer already assigned.
+	opts = withReviewer(config, owner, repo, []*git.Commit{commit}, opts)
 	issue, err := createIssue(
 		task, config, owner, repo,
 		reviewIssue.FormatTitle(), reviewIssue.FormatBody(),
@@ -331,7 +398,7 @@ func extendUnassignedReviewRequest(
 	// Fetch the issue.
 	task := fmt.Sprintf("Fetch GitHub issue #%v", issueNum)
 	log.Run(task)
-	client := ghutil.NewClient(config.Token)
+	client := ghutil.NewClientForEndpoints(config.Token, config.GitHubEndpoints)
 	issue, _, err := client.Issues.Get(owner, repo, issueNum)
 	if err != nil {
 		return nil, nil, errs.NewError(task, err)
@@ -361,14 +428,34 @@ func extendReviewRequest(
 		return nil, nil, errs.NewError(task, err)
 	}
 
-	// Add the commits.
-	newCommits := make([]*git.Commit, 0, len(commits))
+	// Add the commits. The commits rewritten since the last time,
+	// e.g. by a rebase, replace the original commits in the checklist.
+	// The commits that are listed already only get their Change-Id recorded.
+	var (
+		newCommits     = make([]*git.Commit, 0, len(commits))
+		changeIdsAdded bool
+	)
 	for _, commit := range commits {
-		if reviewIssue.AddCommit(false, commit.SHA, commit.MessageTitle) {
+		changed, added := reviewIssue.AddCommitWithChangeId(
+			false, commit.SHA, commit.ChangeIdTag, commit.MessageTitle)
+		switch {
+		case added:
 			newCommits = append(newCommits, commit)
+		case changed:
+			changeIdsAdded = true
 		}
 	}
-	if len(newCommits) == 0 {
+
+	// Update the review issue the story depends on, if requested.
+	var dependsOnChanged bool
+	if dependsOn := optValueString(opts["depends_on"]); dependsOn != "" {
+		if storyIssue, ok := reviewIssue.(*ghissues.StoryReviewIssue); ok && storyIssue.DependsOn != dependsOn {
+			storyIssue.DependsOn = dependsOn
+			dependsOnChanged = true
+		}
+	}
+
+	if len(newCommits) == 0 && !changeIdsAdded && !dependsOnChanged {
 		log.Log(fmt.Sprintf("All commits already listed in issue #%v", issueNum))
 		return issue, nil, nil
 	}
@@ -405,24 +492,33 @@ func extendReviewRequest(
 	task = fmt.Sprintf("Update GitHub issue #%v", issueNum)
 	log.Run(task)
 
-	client := ghutil.NewClient(config.Token)
-	updatedIssue, _, err := client.Issues.Edit(owner, repo, issueNum, &github.IssueRequest{
+	client := ghutil.NewClientForEndpoints(config.Token, config.GitHubEndpoints)
+	request := &github.IssueRequest{
 		Body:   github.String(reviewIssue.FormatBody()),
-		State:  github.String("open"),
 		Labels: labelsPtr,
-	})
+	}
+	if len(newCommits) != 0 {
+		request.State = github.String("open")
+	}
+	updatedIssue, _, err := client.Issues.Edit(owner, repo, issueNum, request)
 	if err != nil {
 		return nil, nil, errs.NewError(task, err)
 	}
 
 	// Add the review comment.
-	if err := addReviewComment(config, owner, repo, issueNum, newCommits); err != nil {
-		return nil, nil, err
+	if len(newCommits) != 0 {
+		if err := addReviewComment(config, owner, repo, issueNum, newCommits); err != nil {
+			return nil, nil, err
+		}
 	}
 
 	return updatedIssue, newCommits, nil
 }
 
+// reviewCommentHeader starts the comment listing the commits added
+// to an existing review issue, it is used to count the review rounds.
+const reviewCommentHeader = "The following commits were added to this issue:"
+
 func addReviewComment(
 	config *moduleConfig,
 	owner string,
@@ -432,14 +528,14 @@ func addReviewComment(
 ) error {
 
 	// Generate the comment body.
-	buffer := bytes.NewBufferString("The following commits were added to this issue:")
+	buffer := bytes.NewBufferString(reviewCommentHeader)
 	for _, commit := range commits {
 		fmt.Fprintf(buffer, "\n* %v: %v", commit.SHA, commit.MessageTitle)
 	}
 
 	// Call GitHub API.
 	task := fmt.Sprintf("Add review comment for issue #%v", issueNum)
-	client := ghutil.NewClient(config.Token)
+	client := ghutil.NewClientForEndpoints(config.Token, config.GitHubEndpoints)
 	_, _, err := client.Issues.CreateComment(owner, repo, issueNum, &github.IssueComment{
 		Body: github.String(buffer.String()),
 	})
@@ -457,7 +553,7 @@ func linkCommitsToReviewIssue(
 	commits []*git.Commit,
 ) {
 	// Instantiate an API client.
-	client := ghutil.NewClient(config.Token)
+	client := ghutil.NewClientForEndpoints(config.Token, config.GitHubEndpoints)
 
 	// Loop over the commits and post a commit comment for each of them.
 	for _, commit := range commits {
@@ -497,7 +593,7 @@ func createIssue(
 ) (issue *github.Issue, err error) {
 
 	log.Run(task)
-	client := ghutil.NewClient(config.Token)
+	client := ghutil.NewClientForEndpoints(config.Token, config.GitHubEndpoints)
 
 	var labels []string
 	if implemented {
@@ -535,7 +631,7 @@ func createMilestone(
 
 	// Create the review milestone for the given version.
 	var (
-		client = ghutil.NewClient(config.Token)
+		client = ghutil.NewClientForEndpoints(config.Token, config.GitHubEndpoints)
 		title  = milestoneTitle(v)
 	)
 	return ghissues.CreateMilestone(client, owner, repo, title)
@@ -550,7 +646,7 @@ func milestoneForVersion(
 
 	// Find the milestone matching the version.
 	var (
-		client = ghutil.NewClient(config.Token)
+		client = ghutil.NewClientForEndpoints(config.Token, config.GitHubEndpoints)
 		title  = milestoneTitle(v)
 	)
 	return ghissues.FindMilestoneByTitle(client, owner, repo, title)
@@ -571,7 +667,7 @@ func getOrCreateMilestoneForCommit(
 
 	// Get or create the milestone for the given title.
 	var (
-		client = ghutil.NewClient(config.Token)
+		client = ghutil.NewClientForEndpoints(config.Token, config.GitHubEndpoints)
 		title  = milestoneTitle(v)
 	)
 	milestone, _, err := ghissues.GetOrCreateMilestoneForTitle(client, owner, repo, title)
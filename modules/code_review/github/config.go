@@ -1,8 +1,14 @@
 package github
 
 import (
+	// Stdlib
+	"errors"
+	"fmt"
+
 	// Internal
 	"github.com/salsaflow/salsaflow/config/loader"
+	"github.com/salsaflow/salsaflow/errs"
+	ghutil "github.com/salsaflow/salsaflow/github"
 	"github.com/salsaflow/salsaflow/prompt"
 )
 
@@ -12,6 +18,8 @@ type moduleConfig struct {
 	Token                 string
 	ReviewLabel           string
 	StoryImplementedLabel string
+	GitHubEndpoints       *ghutil.Endpoints
+	Reviewers             *ReviewersConfig
 }
 
 func loadConfig() (*moduleConfig, error) {
@@ -19,10 +27,25 @@ func loadConfig() (*moduleConfig, error) {
 	if err := loader.LoadConfig(spec); err != nil {
 		return nil, err
 	}
+
+	// Get the API endpoints for the GitHub instance the upstream is hosted on.
+	_, endpoints, err := ghutil.UpstreamEndpoints(
+		spec.local.GitHubAPIBaseURL, spec.local.GitHubUploadURL)
+	if err != nil {
+		return nil, errs.NewError("Get the GitHub API endpoints", err)
+	}
+
+	// Make sure the reviewer selection is configured correctly.
+	if err := spec.local.Reviewers.validate(); err != nil {
+		return nil, errs.NewError("Check the reviewer selection configuration", err)
+	}
+
 	return &moduleConfig{
 		Token:                 spec.global.Token,
 		ReviewLabel:           spec.local.ReviewLabel,
 		StoryImplementedLabel: spec.local.StoryImplementedLabel,
+		GitHubEndpoints:       endpoints,
+		Reviewers:             spec.local.Reviewers,
 	}, nil
 }
 
@@ -60,6 +83,69 @@ func (spec *configSpec) LocalConfig() loader.ConfigContainer {
 type LocalConfig struct {
 	ReviewLabel           string `prompt:"with"           default:"review"      json:"review_issue_label"`
 	StoryImplementedLabel string `prompt:"as implemented" default:"implemented" json:"story_implemented_label"`
+
+	// GitHub Enterprise endpoints, derived from the upstream URL when not set.
+	GitHubAPIBaseURL string `json:"github_api_base_url,omitempty" optional:"true"`
+	GitHubUploadURL  string `json:"github_upload_url,omitempty"   optional:"true"`
+
+	// Automatic reviewer selection, disabled when not set.
+	Reviewers *ReviewersConfig `json:"reviewers,omitempty" optional:"true"`
+}
+
+// The reviewer selection policies available.
+const (
+	ReviewerPolicyNone        = ""
+	ReviewerPolicyRoundRobin  = "round_robin"
+	ReviewerPolicyLeastLoaded = "least_loaded"
+)
+
+// ReviewersConfig configures how the reviewer is selected
+// when no reviewer is specified using review post -reviewer.
+type ReviewersConfig struct {
+	// Policy is used to choose among the candidate reviewers.
+	// When not set, the first candidate is chosen.
+	Policy string `json:"policy,omitempty"`
+
+	// Team lists the GitHub logins of the candidate reviewers
+	// used when no rule matches the files touched by the commits.
+	Team []string `json:"team,omitempty"`
+
+	// Rules assign the candidate reviewers based on the files touched.
+	Rules []*ReviewerRule `json:"rules,omitempty"`
+
+	// Unavailable lists the GitHub logins that are never selected.
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// ReviewerRule assigns the reviewers to the files matching the path patterns.
+// The patterns use the CODEOWNERS syntax, the last matching rule wins.
+type ReviewerRule struct {
+	Paths     []string `json:"paths"`
+	Reviewers []string `json:"reviewers"`
+}
+
+func (rc *ReviewersConfig) validate() error {
+	if rc == nil {
+		return nil
+	}
+
+	switch rc.Policy {
+	case ReviewerPolicyNone, ReviewerPolicyRoundRobin, ReviewerPolicyLeastLoaded:
+	default:
+		return fmt.Errorf("unknown reviewer policy: %v", rc.Policy)
+	}
+
+	for _, rule := range rc.Rules {
+		if len(rule.Paths) == 0 || len(rule.Reviewers) == 0 {
+			return errors.New("reviewer rule must specify both paths and reviewers")
+		}
+		for _, pattern := range rule.Paths {
+			if _, err := compilePathPattern(pattern); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
 // PromptUserForConfig is a part of loader.ConfigContainer interface.
@@ -0,0 +1,29 @@
+package github
+
+import (
+	// Stdlib
+	"testing"
+
+	// Vendor - testing framework
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var (
+	BeforeEach = ginkgo.BeforeEach
+	Context    = ginkgo.Context
+	Describe   = ginkgo.Describe
+	It         = ginkgo.It
+
+	BeEmpty = gomega.BeEmpty
+	BeNil   = gomega.BeNil
+	BeTrue  = gomega.BeTrue
+	Equal   = gomega.Equal
+	Expect  = gomega.Expect
+	HaveLen = gomega.HaveLen
+)
+
+func TestGitHubCodeReview(t *testing.T) {
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "GitHub Code Review")
+}
@@ -134,7 +134,7 @@ func (r *release) Close() (action.Action, error) {
 
 func (r *release) prepareForApiCalls() (client *github.Client, owner, repo string, err error) {
 	if r.client == nil {
-		r.client = ghutil.NewClient(r.tool.config.Token)
+		r.client = ghutil.NewClientForEndpoints(r.tool.config.Token, r.tool.config.GitHubEndpoints)
 	}
 
 	if r.owner == "" || r.repo == "" {
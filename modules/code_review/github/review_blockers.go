@@ -0,0 +1,357 @@
+package github
+
+import (
+	// Stdlib
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/git"
+	ghutil "github.com/salsaflow/salsaflow/github"
+	ghissues "github.com/salsaflow/salsaflow/github/issues"
+	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/modules/common"
+
+	// Vendor
+	"github.com/google/go-github/github"
+)
+
+// AddReviewBlocker is a part of common.ReviewBlockerManager interface.
+//
+// A commit comment is created for the blocker, then the blocker
+// is added into the blocker checklist of the associated review issue.
+func (tool *codeReviewTool) AddReviewBlocker(
+	commitSHA string,
+	summary string,
+) (*common.ReviewRequest, *common.ReviewBlocker, error) {
+
+	client, owner, repo, err := tool.prepareForApiCalls()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Find the review issue the commit is being reviewed in.
+	issue, reviewIssue, err := findReviewIssueForCommit(client, owner, repo, commitSHA)
+	if err != nil {
+		return nil, nil, err
+	}
+	issueNum := *issue.Number
+
+	// Create the blocker comment.
+	blockerNumber := len(reviewIssue.ReviewBlockerItems()) + 1
+	commentTask := fmt.Sprintf("Create review blocker comment for commit %v", commitSHA)
+	log.Run(commentTask)
+	body := fmt.Sprintf(
+		"**Review blocker %v** (review issue #%v)\n\n%v", blockerNumber, issueNum, summary)
+	comment, _, err := client.Repositories.CreateComment(owner, repo, commitSHA,
+		&github.RepositoryComment{
+			Body: github.String(body),
+		})
+	if err != nil {
+		return nil, nil, errs.NewError(commentTask, err)
+	}
+
+	// Add the blocker to the checklist and update the issue.
+	// The issue is reopened in case it has been closed already.
+	reviewIssue.AddReviewBlocker(false, *comment.HTMLURL, commitSHA, summary)
+
+	task := fmt.Sprintf("Update GitHub issue #%v", issueNum)
+	log.Run(task)
+	updatedIssue, _, err := client.Issues.Edit(owner, repo, issueNum, &github.IssueRequest{
+		Body:  github.String(reviewIssue.FormatBody()),
+		State: github.String("open"),
+	})
+	if err != nil {
+		// Delete the comment so that it is not left dangling.
+		log.Rollback(commentTask)
+		deleteTask := fmt.Sprintf("Delete review blocker comment for commit %v", commitSHA)
+		if _, ex := client.Repositories.DeleteComment(owner, repo, *comment.ID); ex != nil {
+			errs.LogError(deleteTask, ex)
+		}
+		return nil, nil, errs.NewError(task, err)
+	}
+
+	rr := newReviewRequest(updatedIssue, reviewIssue)
+	return rr, rr.Blockers[len(rr.Blockers)-1], nil
+}
+
+// ListReviewBlockers is a part of common.ReviewBlockerManager interface.
+func (tool *codeReviewTool) ListReviewBlockers(storyId string) ([]*common.ReviewRequest, error) {
+	return tool.ReviewStatus(storyId, "")
+}
+
+// FixReviewBlocker is a part of common.ReviewBlockerManager interface.
+//
+// The blocker is checked in the blocker checklist and a comment is added
+// to the review issue saying what commit the blocker was fixed by.
+func (tool *codeReviewTool) FixReviewBlocker(
+	rrid string,
+	blockerNumber int,
+	fixedBy string,
+) (*common.ReviewRequest, *common.ReviewBlocker, error) {
+
+	client, owner, repo, err := tool.prepareForApiCalls()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Get the review issue.
+	var (
+		issue       *github.Issue
+		reviewIssue ghissues.ReviewIssue
+	)
+	switch {
+	case rrid != "":
+		issue, reviewIssue, err = getReviewIssue(client, owner, repo, rrid)
+	case fixedBy != "":
+		issue, reviewIssue, err = findReviewIssueForFixingCommit(client, owner, repo, fixedBy)
+	default:
+		panic("FixReviewBlocker: either rrid or fixedBy must be set")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	issueNum := *issue.Number
+
+	// Mark the blocker as fixed.
+	task := fmt.Sprintf("Mark review blocker %v in issue #%v as fixed", blockerNumber, issueNum)
+	var blocker *ghissues.ReviewBlockerItem
+	for _, item := range reviewIssue.ReviewBlockerItems() {
+		if item.BlockerNumber == blockerNumber {
+			blocker = item
+			break
+		}
+	}
+	if blocker == nil {
+		return nil, nil, errs.NewError(task, fmt.Errorf(
+			"review blocker %v not found in issue #%v", blockerNumber, issueNum))
+	}
+	if blocker.Fixed {
+		log.Log(fmt.Sprintf(
+			"Review blocker %v in issue #%v already marked as fixed", blockerNumber, issueNum))
+		rr := newReviewRequest(issue, reviewIssue)
+		return rr, rr.Blockers[blockerIndex(rr, blockerNumber)], nil
+	}
+	reviewIssue.FixReviewBlocker(blockerNumber)
+
+	log.Run(task)
+	updatedIssue, _, err := client.Issues.Edit(owner, repo, issueNum, &github.IssueRequest{
+		Body: github.String(reviewIssue.FormatBody()),
+	})
+	if err != nil {
+		return nil, nil, errs.NewError(task, err)
+	}
+
+	// Add the review comment.
+	task = fmt.Sprintf("Add review comment for issue #%v", issueNum)
+	body := fmt.Sprintf("Review blocker [%v](%v) was fixed", blockerNumber, blocker.CommentURL)
+	if fixedBy != "" {
+		body += " by commit " + fixedBy
+	}
+	body += "."
+	_, _, err = client.Issues.CreateComment(owner, repo, issueNum, &github.IssueComment{
+		Body: github.String(body),
+	})
+	if err != nil {
+		// The blocker is already marked as fixed, no need to fail here.
+		errs.LogError(task, err)
+	}
+
+	rr := newReviewRequest(updatedIssue, reviewIssue)
+	return rr, rr.Blockers[blockerIndex(rr, blockerNumber)], nil
+}
+
+// Helpers ---------------------------------------------------------------------
+
+func (tool *codeReviewTool) prepareForApiCalls() (client *github.Client, owner, repo string, err error) {
+	owner, repo, err = ghutil.ParseUpstreamURL()
+	if err != nil {
+		return nil, "", "", err
+	}
+	client = ghutil.NewClientForEndpoints(tool.config.Token, tool.config.GitHubEndpoints)
+	return client, owner, repo, nil
+}
+
+// findReviewIssueForCommit returns the review issue containing the given commit
+// in its commit checklist.
+func findReviewIssueForCommit(
+	client *github.Client,
+	owner string,
+	repo string,
+	commitSHA string,
+) (*github.Issue, ghissues.ReviewIssue, error) {
+
+	task := fmt.Sprintf("Search for the review issue for commit %v", commitSHA)
+
+	// The commit checklist contains full SHAs, make sure we have one.
+	commitSHA, err := git.CommitHexsha(commitSHA)
+	if err != nil {
+		return nil, nil, errs.NewError(task, err)
+	}
+
+	log.Run(task)
+	issue, err := ghissues.FindReviewIssueByCommitItem(client, owner, repo, commitSHA)
+	if err != nil {
+		return nil, nil, errs.NewError(task, err)
+	}
+	if issue == nil {
+		return nil, nil, errs.NewError(task, &common.ErrReviewRequestNotFound{What: "commit " + commitSHA})
+	}
+
+	return parseReviewIssue(issue)
+}
+
+// findReviewIssueForFixingCommit returns the review issue the given commit
+// is fixing a review blocker in.
+//
+// The fixing commit is usually not posted for review yet, so the review issue
+// is looked up using the Story-Id tag of the commit. The commit checklist
+// is searched in case the commit is not associated with any story.
+func findReviewIssueForFixingCommit(
+	client *github.Client,
+	owner string,
+	repo string,
+	commitSHA string,
+) (*github.Issue, ghissues.ReviewIssue, error) {
+
+	task := fmt.Sprintf("Get the Story-Id tag of commit %v", commitSHA)
+	commits, err := git.ShowCommits(commitSHA)
+	if err != nil {
+		return nil, nil, errs.NewError(task, err)
+	}
+	storyKey := commits[0].StoryIdTag
+	if storyKey == "" {
+		return findReviewIssueForCommit(client, owner, repo, commitSHA)
+	}
+
+	task = fmt.Sprintf("Search for the review issue for story %v", storyKey)
+	log.Run(task)
+	issue, err := ghissues.FindReviewIssueForStoryKey(client, owner, repo, storyKey)
+	if err != nil {
+		return nil, nil, errs.NewError(task, err)
+	}
+	if issue == nil {
+		return nil, nil, errs.NewError(task, &common.ErrReviewRequestNotFound{What: "story " + storyKey})
+	}
+
+	return parseReviewIssue(issue)
+}
+
+func parseReviewIssue(issue *github.Issue) (*github.Issue, ghissues.ReviewIssue, error) {
+	task := fmt.Sprintf("Parse review issue #%v", *issue.Number)
+	reviewIssue, err := ghissues.ParseReviewIssue(issue)
+	if err != nil {
+		return nil, nil, errs.NewError(task, err)
+	}
+	return issue, reviewIssue, nil
+}
+
+// getReviewIssue fetches the review issue of the given number.
+func getReviewIssue(
+	client *github.Client,
+	owner string,
+	repo string,
+	rrid string,
+) (*github.Issue, ghissues.ReviewIssue, error) {
+
+	task := fmt.Sprintf("Fetch GitHub issue #%v", rrid)
+	log.Run(task)
+	issueNum, err := strconv.Atoi(rrid)
+	if err != nil {
+		return nil, nil, errs.NewError(task, fmt.Errorf("not a valid issue number: %v", rrid))
+	}
+	issue, _, err := client.Issues.Get(owner, repo, issueNum)
+	if err != nil {
+		if ex, ok := err.(*github.ErrorResponse); ok && ex.Response.StatusCode == http.StatusNotFound {
+			err = &common.ErrReviewRequestNotFound{What: "ID " + rrid}
+		}
+		return nil, nil, errs.NewError(task, err)
+	}
+
+	return parseReviewIssue(issue)
+}
+
+func newReviewRequest(issue *github.Issue, reviewIssue ghissues.ReviewIssue) *common.ReviewRequest {
+	commitItems := reviewIssue.CommitItems()
+	commits := make([]*common.ReviewCommit, 0, len(commitItems))
+	for _, item := range commitItems {
+		commits = append(commits, &common.ReviewCommit{
+			SHA:      item.CommitSHA,
+			Title:    item.CommitTitle,
+			Reviewed: item.Reviewed,
+		})
+	}
+
+	blockerItems := reviewIssue.ReviewBlockerItems()
+	blockers := make([]*common.ReviewBlocker, 0, len(blockerItems))
+	for _, item := range blockerItems {
+		blockers = append(blockers, &common.ReviewBlocker{
+			Number:     item.BlockerNumber,
+			Fixed:      item.Fixed,
+			CommitSHA:  item.CommitSHA,
+			Summary:    item.BlockerSummary,
+			CommentURL: item.CommentURL,
+		})
+	}
+
+	var storyTag string
+	if storyIssue, ok := reviewIssue.(*ghissues.StoryReviewIssue); ok {
+		storyTag = storyIssue.StoryKey
+	}
+
+	var assignee string
+	if issue.Assignee != nil && issue.Assignee.Login != nil {
+		assignee = *issue.Assignee.Login
+	}
+
+	var createdAt time.Time
+	if issue.CreatedAt != nil {
+		createdAt = *issue.CreatedAt
+	}
+
+	return &common.ReviewRequest{
+		Id:        strconv.Itoa(*issue.Number),
+		Title:     *issue.Title,
+		URL:       *issue.HTMLURL,
+		StoryTag:  storyTag,
+		Closed:    issue.State != nil && *issue.State == "closed",
+		Assignee:  assignee,
+		CreatedAt: createdAt,
+		Commits:   commits,
+		Blockers:  blockers,
+	}
+}
+
+// forEachReviewIssue parses the given review issues and calls fn for each of them.
+//
+// The issues that cannot be parsed are logged and skipped.
+func forEachReviewIssue(
+	issues []*github.Issue,
+	fn func(issue *github.Issue, reviewIssue ghissues.ReviewIssue) error,
+) error {
+
+	for _, issue := range issues {
+		reviewIssue, err := ghissues.ParseReviewIssue(issue)
+		if err != nil {
+			// Do not fail because of a single issue someone has messed up.
+			errs.LogError(fmt.Sprintf("Parse review issue #%v", *issue.Number), err)
+			continue
+		}
+		if err := fn(issue, reviewIssue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func blockerIndex(rr *common.ReviewRequest, blockerNumber int) int {
+	for i, blocker := range rr.Blockers {
+		if blocker.Number == blockerNumber {
+			return i
+		}
+	}
+	panic(fmt.Errorf("review blocker %v not found", blockerNumber))
+}
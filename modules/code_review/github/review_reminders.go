@@ -0,0 +1,154 @@
+package github
+
+import (
+	// Stdlib
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/errs"
+	ghissues "github.com/salsaflow/salsaflow/github/issues"
+	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/modules/common"
+
+	// Vendor
+	"github.com/google/go-github/github"
+)
+
+// ListStaleReviewRequests is a part of common.StaleReviewReporter interface.
+//
+// The review issues in all open review milestones are checked.
+// The oldest review requests go first.
+func (tool *codeReviewTool) ListStaleReviewRequests(
+	createdBefore time.Time,
+) ([]*common.ReviewRequest, error) {
+
+	client, owner, repo, err := tool.prepareForApiCalls()
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the open review milestones.
+	task := "Fetch the open review milestones"
+	log.Run(task)
+	milestones, err := listOpenMilestones(client, owner, repo)
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+
+	// Collect the review requests.
+	var rrs []*common.ReviewRequest
+	for _, milestone := range milestones {
+		if !strings.HasSuffix(*milestone.Title, "-review") {
+			continue
+		}
+
+		task := fmt.Sprintf("Fetch the open review issues in milestone '%v'", *milestone.Title)
+		log.Run(task)
+		issues, err := ghissues.ListOpenReviewIssuesInMilestone(
+			client, owner, repo, tool.config.ReviewLabel, *milestone.Number)
+		if err != nil {
+			return nil, errs.NewError(task, err)
+		}
+
+		var stale []*github.Issue
+		for _, issue := range issues {
+			if issue.CreatedAt != nil && issue.CreatedAt.Before(createdBefore) {
+				stale = append(stale, issue)
+			}
+		}
+		forEachReviewIssue(stale, func(issue *github.Issue, reviewIssue ghissues.ReviewIssue) error {
+			rrs = append(rrs, newReviewRequest(issue, reviewIssue))
+			return nil
+		})
+	}
+
+	sort.SliceStable(rrs, func(i, j int) bool {
+		return rrs[i].CreatedAt.Before(rrs[j].CreatedAt)
+	})
+	return rrs, nil
+}
+
+// listOpenMilestones returns all open milestones, going through all the pages.
+//
+// MilestoneListOptions does not support paging, so the request is built here.
+func listOpenMilestones(
+	client *github.Client,
+	owner string,
+	repo string,
+) ([]github.Milestone, error) {
+
+	page := 1
+
+	var milestones []github.Milestone
+	for {
+		u := fmt.Sprintf("repos/%v/%v/milestones?state=open&per_page=100&page=%v", owner, repo, page)
+		req, err := client.NewRequest("GET", u, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var ms []github.Milestone
+		resp, err := client.Do(req, &ms)
+		if err != nil {
+			return nil, err
+		}
+		milestones = append(milestones, ms...)
+
+		if resp.NextPage == 0 {
+			return milestones, nil
+		}
+		page = resp.NextPage
+	}
+}
+
+const reminderCommentFormat = "@%v, this review request has been waiting since %v."
+
+// reminderCommentRegexp matches the comments posted by RemindReviewer.
+var reminderCommentRegexp = regexp.MustCompile(
+	`^@[^,\s]+, this review request has been waiting since `)
+
+// RemindReviewer is a part of common.StaleReviewReporter interface.
+//
+// A comment mentioning the assignee is added to the review issue.
+func (tool *codeReviewTool) RemindReviewer(rr *common.ReviewRequest) error {
+	if rr.Assignee == "" {
+		panic("RemindReviewer: the review request is not assigned")
+	}
+
+	client, owner, repo, err := tool.prepareForApiCalls()
+	if err != nil {
+		return err
+	}
+
+	task := fmt.Sprintf("Add review reminder comment for issue #%v", rr.Id)
+	issueNum, err := strconv.Atoi(rr.Id)
+	if err != nil {
+		return errs.NewError(task, fmt.Errorf("not a valid issue number: %v", rr.Id))
+	}
+
+	body := fmt.Sprintf(reminderCommentFormat, rr.Assignee, rr.CreatedAt.Format("Jan 2, 2006"))
+	var left []string
+	if n := rr.UnreviewedCommits(); n != 0 {
+		left = append(left, common.Plural(n, "commit")+" to be reviewed")
+	}
+	if n := rr.OpenBlockers(); n != 0 {
+		left = append(left, common.Plural(n, "review blocker")+" to be fixed")
+	}
+	if len(left) != 0 {
+		body += fmt.Sprintf(" Remaining: %v.", strings.Join(left, ", "))
+	}
+
+	log.Run(task)
+	_, _, err = client.Issues.CreateComment(owner, repo, issueNum, &github.IssueComment{
+		Body: github.String(body),
+	})
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package github
+
+import (
+	// Stdlib
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	// Vendor
+	"github.com/google/go-github/github"
+)
+
+var _ = Describe("stale review requests", func() {
+
+	Describe("listOpenMilestones", func() {
+
+		It("should go through all the pages", func() {
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Path).To(Equal("/repos/owner/repo/milestones"))
+				Expect(r.URL.Query().Get("state")).To(Equal("open"))
+				if r.URL.Query().Get("page") == "1" {
+					w.Header().Set("Link", fmt.Sprintf(
+						`<%v/repos/owner/repo/milestones?state=open&per_page=100&page=2>; rel="next"`,
+						server.URL))
+					fmt.Fprint(w, `[{"number": 1, "title": "1.0.0-review"}]`)
+					return
+				}
+				fmt.Fprint(w, `[{"number": 2, "title": "1.1.0-review"}]`)
+			}))
+			defer server.Close()
+
+			client := github.NewClient(nil)
+			client.BaseURL, _ = url.Parse(server.URL + "/")
+
+			milestones, err := listOpenMilestones(client, "owner", "repo")
+			Expect(err).To(BeNil())
+			Expect(milestones).To(HaveLen(2))
+			Expect(*milestones[1].Title).To(Equal("1.1.0-review"))
+		})
+	})
+})
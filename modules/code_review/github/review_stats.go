@@ -0,0 +1,140 @@
+package github
+
+import (
+	// Stdlib
+	"fmt"
+	"strings"
+	"time"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/errs"
+	ghissues "github.com/salsaflow/salsaflow/github/issues"
+	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/modules/common"
+
+	// Vendor
+	"github.com/google/go-github/github"
+)
+
+// ListReviewActivity is a part of common.ReviewStatsReporter interface.
+//
+// The review issues created within the given time range are searched for,
+// then the comments are fetched for every issue to get the review timeline.
+func (tool *codeReviewTool) ListReviewActivity(
+	since time.Time,
+	until time.Time,
+) ([]*common.ReviewActivity, error) {
+
+	client, owner, repo, err := tool.prepareForApiCalls()
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the review issues.
+	task := "Fetch the review issues created in the given period"
+	log.Run(task)
+	issues, err := ghissues.ListReviewIssuesCreatedBetween(
+		client, owner, repo, tool.config.ReviewLabel, since, until)
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+
+	// Collect the review activity.
+	activity := make([]*common.ReviewActivity, 0, len(issues))
+	err = forEachReviewIssue(issues, func(issue *github.Issue, reviewIssue ghissues.ReviewIssue) error {
+		task := fmt.Sprintf("Fetch the comments for review issue #%v", *issue.Number)
+		log.Go(task)
+		comments, err := listIssueComments(client, owner, repo, *issue.Number)
+		if err != nil {
+			return errs.NewError(task, err)
+		}
+
+		activity = append(activity, newReviewActivity(issue, reviewIssue, comments))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+func listIssueComments(
+	client *github.Client,
+	owner string,
+	repo string,
+	issueNum int,
+) ([]github.IssueComment, error) {
+
+	listOpts := &github.IssueListCommentsOptions{}
+	listOpts.Page = 1
+	listOpts.PerPage = 100
+
+	var comments []github.IssueComment
+	for {
+		page, resp, err := client.Issues.ListComments(owner, repo, issueNum, listOpts)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, page...)
+
+		if resp.NextPage == 0 {
+			return comments, nil
+		}
+		listOpts.Page = resp.NextPage
+	}
+}
+
+// newReviewActivity assembles the review activity from the issue and its comments.
+//
+// The first response is the first comment posted by somebody else than
+// the issue author, not counting the comments generated by SalsaFlow itself.
+// Closing the issue counts as a response as well. Every comment listing
+// the commits added to the issue starts another review round.
+func newReviewActivity(
+	issue *github.Issue,
+	reviewIssue ghissues.ReviewIssue,
+	comments []github.IssueComment,
+) *common.ReviewActivity {
+
+	activity := &common.ReviewActivity{
+		ReviewRequest: newReviewRequest(issue, reviewIssue),
+		Rounds:        1,
+	}
+	if issue.ClosedAt != nil {
+		activity.ClosedAt = *issue.ClosedAt
+		activity.FirstResponseAt = *issue.ClosedAt
+	}
+
+	var author string
+	if issue.User != nil && issue.User.Login != nil {
+		author = *issue.User.Login
+	}
+
+	for _, comment := range comments {
+		var body string
+		if comment.Body != nil {
+			body = *comment.Body
+		}
+
+		if strings.HasPrefix(body, reviewCommentHeader) {
+			activity.Rounds++
+			continue
+		}
+		if reminderCommentRegexp.MatchString(body) {
+			continue
+		}
+
+		if comment.User == nil || comment.User.Login == nil || *comment.User.Login == author {
+			continue
+		}
+		if comment.CreatedAt == nil {
+			continue
+		}
+		at := *comment.CreatedAt
+		if activity.FirstResponseAt.IsZero() || at.Before(activity.FirstResponseAt) {
+			activity.FirstResponseAt = at
+		}
+	}
+
+	return activity
+}
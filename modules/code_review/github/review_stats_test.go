@@ -0,0 +1,74 @@
+package github
+
+import (
+	// Stdlib
+	"time"
+
+	// Internal
+	ghissues "github.com/salsaflow/salsaflow/github/issues"
+
+	// Vendor
+	"github.com/google/go-github/github"
+)
+
+var _ = Describe("review activity", func() {
+
+	var (
+		createdAt   = time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+		issue       *github.Issue
+		reviewIssue ghissues.ReviewIssue
+	)
+
+	comment := func(login string, after time.Duration, body string) github.IssueComment {
+		return github.IssueComment{
+			User:      &github.User{Login: github.String(login)},
+			CreatedAt: timePtr(createdAt.Add(after)),
+			Body:      github.String(body),
+		}
+	}
+
+	BeforeEach(func() {
+		reviewIssue = ghissues.NewCommitReviewIssue("1a2b3c4d5e", "Build the widget")
+		issue = &github.Issue{
+			Number:    github.Int(12),
+			Title:     github.String(reviewIssue.FormatTitle()),
+			HTMLURL:   github.String("https://github.com/acme/widgets/issues/12"),
+			User:      &github.User{Login: github.String("alice")},
+			CreatedAt: timePtr(createdAt),
+		}
+	})
+
+	It("should skip the author and the generated comments when looking for the first response", func() {
+		activity := newReviewActivity(issue, reviewIssue, []github.IssueComment{
+			comment("alice", time.Hour, "Ping."),
+			comment("bob", 2*time.Hour, "@carol, this review request has been waiting since Mar 2, 2026."),
+			comment("carol", 5*time.Hour, "Looking into it."),
+			comment("bob", 6*time.Hour, "Me too."),
+		})
+		Expect(activity.FirstResponseAt).To(Equal(createdAt.Add(5 * time.Hour)))
+		Expect(activity.ClosedAt.IsZero()).To(BeTrue())
+		Expect(activity.Rounds).To(Equal(1))
+	})
+
+	It("should count the follow-ups as review rounds", func() {
+		activity := newReviewActivity(issue, reviewIssue, []github.IssueComment{
+			comment("carol", time.Hour, reviewCommentHeader+"\n* 5d6e7f8: Fix the widget"),
+			comment("alice", 2*time.Hour, reviewCommentHeader+"\n* 9abcdef: Fix the widget again"),
+		})
+		Expect(activity.Rounds).To(Equal(3))
+		Expect(activity.FirstResponseAt.IsZero()).To(BeTrue())
+	})
+
+	It("should treat closing the issue as a response", func() {
+		issue.ClosedAt = timePtr(createdAt.Add(3 * time.Hour))
+		activity := newReviewActivity(issue, reviewIssue, []github.IssueComment{
+			comment("carol", 4*time.Hour, "LGTM."),
+		})
+		Expect(activity.FirstResponseAt).To(Equal(*issue.ClosedAt))
+		Expect(activity.ClosedAt).To(Equal(*issue.ClosedAt))
+	})
+})
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
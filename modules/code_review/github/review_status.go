@@ -0,0 +1,150 @@
+package github
+
+import (
+	// Stdlib
+	"fmt"
+	"strconv"
+	"strings"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/errs"
+	ghissues "github.com/salsaflow/salsaflow/github/issues"
+	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/modules/common"
+
+	// Vendor
+	"github.com/google/go-github/github"
+)
+
+// ReviewStatus is a part of common.ReviewApprover interface.
+func (tool *codeReviewTool) ReviewStatus(storyId, commitSHA string) ([]*common.ReviewRequest, error) {
+	client, owner, repo, err := tool.prepareForApiCalls()
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the review issues.
+	var issues []*github.Issue
+	switch {
+	case commitSHA != "":
+		issue, reviewIssue, err := findReviewIssueForCommit(client, owner, repo, commitSHA)
+		if err != nil {
+			return nil, err
+		}
+		return []*common.ReviewRequest{newReviewRequest(issue, reviewIssue)}, nil
+
+	case storyId != "":
+		task := fmt.Sprintf("Search for the review issue for story %v", storyId)
+		log.Run(task)
+		issue, err := ghissues.FindReviewIssueForStory(client, owner, repo, storyId)
+		if err != nil {
+			return nil, errs.NewError(task, err)
+		}
+		if issue == nil {
+			return nil, errs.NewError(task, &common.ErrReviewRequestNotFound{What: "story " + storyId})
+		}
+		issues = []*github.Issue{issue}
+
+	default:
+		task := "Fetch the open review issues"
+		log.Run(task)
+		issues, err = ghissues.ListOpenReviewIssues(client, owner, repo, tool.config.ReviewLabel)
+		if err != nil {
+			return nil, errs.NewError(task, err)
+		}
+	}
+
+	// Parse the review issues.
+	rrs := make([]*common.ReviewRequest, 0, len(issues))
+	forEachReviewIssue(issues, func(issue *github.Issue, reviewIssue ghissues.ReviewIssue) error {
+		rrs = append(rrs, newReviewRequest(issue, reviewIssue))
+		return nil
+	})
+	return rrs, nil
+}
+
+// GetReviewRequest is a part of common.ReviewRequestGetter interface.
+//
+// The review request ID is the review issue number, optionally prefixed with #.
+func (tool *codeReviewTool) GetReviewRequest(rrid string) (*common.ReviewRequest, error) {
+	client, owner, repo, err := tool.prepareForApiCalls()
+	if err != nil {
+		return nil, err
+	}
+
+	rrid = strings.TrimPrefix(rrid, "#")
+	if _, err := strconv.Atoi(rrid); err != nil {
+		task := "Get the review request"
+		return nil, errs.NewError(task, &common.ErrReviewRequestNotFound{What: "ID " + rrid})
+	}
+
+	issue, reviewIssue, err := getReviewIssue(client, owner, repo, rrid)
+	if err != nil {
+		return nil, err
+	}
+	return newReviewRequest(issue, reviewIssue), nil
+}
+
+// ApproveCommits is a part of common.ReviewApprover interface.
+//
+// The commits are checked in the commit checklist of the review issue.
+// The issue is closed once the review request is approved.
+func (tool *codeReviewTool) ApproveCommits(
+	rrid string,
+	commitSHAs []string,
+) (*common.ReviewRequest, error) {
+
+	client, owner, repo, err := tool.prepareForApiCalls()
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the review issue.
+	var (
+		issue       *github.Issue
+		reviewIssue ghissues.ReviewIssue
+	)
+	switch {
+	case rrid != "":
+		issue, reviewIssue, err = getReviewIssue(client, owner, repo, rrid)
+	case len(commitSHAs) != 0:
+		issue, reviewIssue, err = findReviewIssueForCommit(client, owner, repo, commitSHAs[0])
+	default:
+		panic("ApproveCommits: either rrid or commitSHAs must be set")
+	}
+	if err != nil {
+		return nil, err
+	}
+	issueNum := *issue.Number
+
+	// Check the commits.
+	task := fmt.Sprintf("Mark the commits in issue #%v as reviewed", issueNum)
+	if len(commitSHAs) == 0 {
+		for _, item := range reviewIssue.CommitItems() {
+			item.Reviewed = true
+		}
+	}
+	for _, sha := range commitSHAs {
+		if !reviewIssue.MarkCommitAsReviewed(sha) {
+			return nil, errs.NewError(task, fmt.Errorf(
+				"commit %v not found in issue #%v", sha, issueNum))
+		}
+	}
+
+	// Update the issue, closing it in case the review is finished.
+	state := "open"
+	if newReviewRequest(issue, reviewIssue).Approved() {
+		state = "closed"
+	}
+
+	log.Run(task)
+	updatedIssue, _, err := client.Issues.Edit(owner, repo, issueNum, &github.IssueRequest{
+		Body:  github.String(reviewIssue.FormatBody()),
+		State: github.String(state),
+	})
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+
+	return newReviewRequest(updatedIssue, reviewIssue), nil
+}
@@ -0,0 +1,345 @@
+package github
+
+import (
+	// Stdlib
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/git"
+	ghutil "github.com/salsaflow/salsaflow/github"
+	ghissues "github.com/salsaflow/salsaflow/github/issues"
+	"github.com/salsaflow/salsaflow/log"
+
+	// Vendor
+	"github.com/google/go-github/github"
+)
+
+// selectReviewer returns the reviewer for the given commits
+// according to the reviewer selection configuration.
+//
+// An empty string is returned in case the automatic selection is not enabled
+// or there is no reviewer available.
+func selectReviewer(
+	config *moduleConfig,
+	owner string,
+	repo string,
+	commits []*git.Commit,
+) (string, error) {
+
+	rc := config.Reviewers
+	if rc == nil {
+		return "", nil
+	}
+
+	task := "Select the reviewer"
+	log.Run(task)
+
+	// Get the candidates, the file owners go first.
+	files, err := changedFiles(commits)
+	if err != nil {
+		return "", errs.NewError(task, err)
+	}
+	candidates := fileOwners(rc.Rules, files)
+	if len(candidates) == 0 {
+		candidates = rc.Team
+	}
+
+	// Drop the commit authors and the reviewers not available.
+	client := ghutil.NewClientForEndpoints(config.Token, config.GitHubEndpoints)
+	excluded := append(commitAuthors(client, owner, repo, commits), rc.Unavailable...)
+	candidates = filterCandidates(candidates, excluded)
+	if len(candidates) == 0 {
+		log.Warn("No reviewer available, the review issue is not going to be assigned")
+		return "", nil
+	}
+
+	// Apply the policy.
+	var reviewer string
+	switch rc.Policy {
+	case ReviewerPolicyRoundRobin:
+		last, err := lastAssignee(client, owner, repo, config.ReviewLabel, candidates)
+		if err != nil {
+			return "", errs.NewError(task, err)
+		}
+		reviewer = nextReviewer(candidates, last)
+
+	case ReviewerPolicyLeastLoaded:
+		issues, err := ghissues.ListOpenReviewIssues(client, owner, repo, config.ReviewLabel)
+		if err != nil {
+			return "", errs.NewError(task, err)
+		}
+		load := make(map[string]int, len(candidates))
+		for _, issue := range issues {
+			if issue.Assignee != nil && issue.Assignee.Login != nil {
+				load[strings.ToLower(*issue.Assignee.Login)]++
+			}
+		}
+		reviewer = leastLoadedReviewer(candidates, load)
+
+	default:
+		reviewer = candidates[0]
+	}
+
+	log.Log(fmt.Sprintf("Reviewer selected: %v", reviewer))
+	return reviewer, nil
+}
+
+// withReviewer returns the post options with the reviewer set
+// in case it is not set explicitly and the reviewer can be selected automatically.
+// The options passed in are not modified. It is only to be used when a new
+// review issue is being created, the reviewer is not changed for the existing ones.
+func withReviewer(
+	config *moduleConfig,
+	owner string,
+	repo string,
+	commits []*git.Commit,
+	opts map[string]interface{},
+) map[string]interface{} {
+
+	if _, ok := opts["reviewer"]; ok || config.Reviewers == nil {
+		return opts
+	}
+
+	reviewer, err := selectReviewer(config, owner, repo, commits)
+	if err != nil {
+		// Just print the error, the review request can be assigned manually.
+		errs.Log(err)
+		return opts
+	}
+	if reviewer == "" {
+		return opts
+	}
+
+	newOpts := make(map[string]interface{}, len(opts)+1)
+	for k, v := range opts {
+		newOpts[k] = v
+	}
+	newOpts["reviewer"] = reviewer
+	return newOpts
+}
+
+// changedFiles returns the files touched by the given commits.
+func changedFiles(commits []*git.Commit) ([]string, error) {
+	var (
+		files   []string
+		fileSet = make(map[string]struct{})
+	)
+	for _, commit := range commits {
+		stdout, err := git.Run(
+			"diff-tree", "--no-commit-id", "--name-only", "-r", "--root", commit.SHA)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range strings.Split(stdout.String(), "\n") {
+			if file == "" {
+				continue
+			}
+			if _, ok := fileSet[file]; ok {
+				continue
+			}
+			fileSet[file] = struct{}{}
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// commitAuthors returns the GitHub logins of the commit authors
+// together with the login of the current user. The authors that cannot be
+// mapped to GitHub accounts are skipped.
+func commitAuthors(client *github.Client, owner, repo string, commits []*git.Commit) []string {
+	var logins []string
+	if me, _, err := client.Users.Get(""); err == nil && me.Login != nil {
+		logins = append(logins, *me.Login)
+	}
+	for _, commit := range commits {
+		c, _, err := client.Repositories.GetCommit(owner, repo, commit.SHA)
+		if err != nil || c.Author == nil || c.Author.Login == nil {
+			continue
+		}
+		logins = append(logins, *c.Author.Login)
+	}
+	return logins
+}
+
+// lastAssignee returns the candidate that was assigned the most recent review issue.
+// An empty string is returned when there is no such candidate.
+func lastAssignee(
+	client *github.Client,
+	owner string,
+	repo string,
+	reviewLabel string,
+	candidates []string,
+) (string, error) {
+
+	issues, _, err := client.Issues.ListByRepo(owner, repo, &github.IssueListByRepoOptions{
+		State:       "all",
+		Labels:      []string{reviewLabel},
+		Sort:        "created",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{PerPage: 50},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for _, issue := range issues {
+		if issue.Assignee == nil || issue.Assignee.Login == nil {
+			continue
+		}
+		login := *issue.Assignee.Login
+		for _, candidate := range candidates {
+			if strings.EqualFold(candidate, login) {
+				return candidate, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// nextReviewer returns the candidate following the last one.
+func nextReviewer(candidates []string, last string) string {
+	for i, candidate := range candidates {
+		if strings.EqualFold(candidate, last) {
+			return candidates[(i+1)%len(candidates)]
+		}
+	}
+	return candidates[0]
+}
+
+// leastLoadedReviewer returns the candidate with the least review issues assigned.
+// The load map is keyed by lowercase logins. The earlier candidates win the ties.
+func leastLoadedReviewer(candidates []string, load map[string]int) string {
+	reviewer := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if load[strings.ToLower(candidate)] < load[strings.ToLower(reviewer)] {
+			reviewer = candidate
+		}
+	}
+	return reviewer
+}
+
+// filterCandidates drops the excluded logins from the candidate list.
+func filterCandidates(candidates []string, excluded []string) []string {
+	excludedSet := make(map[string]struct{}, len(excluded))
+	for _, login := range excluded {
+		excludedSet[strings.ToLower(login)] = struct{}{}
+	}
+
+	filtered := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		if _, ok := excludedSet[strings.ToLower(candidate)]; !ok {
+			filtered = append(filtered, candidate)
+		}
+	}
+	return filtered
+}
+
+// fileOwners returns the reviewers owning the given files according to the rules.
+// The last rule matching the file wins. The reviewers owning more files go first.
+func fileOwners(rules []*ReviewerRule, files []string) []string {
+	type compiledRule struct {
+		patterns  []*regexp.Regexp
+		reviewers []string
+	}
+
+	compiledRules := make([]*compiledRule, 0, len(rules))
+	for _, rule := range rules {
+		cr := &compiledRule{reviewers: rule.Reviewers}
+		for _, pattern := range rule.Paths {
+			// The patterns are checked when the config is loaded.
+			if re, err := compilePathPattern(pattern); err == nil {
+				cr.patterns = append(cr.patterns, re)
+			}
+		}
+		compiledRules = append(compiledRules, cr)
+	}
+
+	var (
+		owners []string
+		counts = make(map[string]int)
+	)
+	for _, file := range files {
+		var matched []string
+		for _, rule := range compiledRules {
+			for _, re := range rule.patterns {
+				if re.MatchString(file) {
+					matched = rule.reviewers
+					break
+				}
+			}
+		}
+		for _, owner := range matched {
+			if _, ok := counts[owner]; !ok {
+				owners = append(owners, owner)
+			}
+			counts[owner]++
+		}
+	}
+
+	sort.SliceStable(owners, func(i, j int) bool {
+		return counts[owners[i]] > counts[owners[j]]
+	})
+	return owners
+}
+
+// compilePathPattern turns the CODEOWNERS-style path pattern into a regexp.
+//
+// The pattern is anchored to the repository root in case it starts with /
+// or contains / in the middle, otherwise it matches at any level.
+// A pattern ending with / matches directories only. * matches anything
+// except /, ** matches anything, ? matches a single character except /.
+func compilePathPattern(pattern string) (*regexp.Regexp, error) {
+	p := strings.TrimSpace(pattern)
+	if p == "" || p == "/" {
+		return nil, fmt.Errorf("invalid path pattern: '%v'", pattern)
+	}
+
+	dirOnly := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+
+	var buf bytes.Buffer
+	buf.WriteString("^")
+	if !anchored {
+		buf.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; c {
+		case '*':
+			switch {
+			case strings.HasPrefix(p[i:], "**/"):
+				// Zero or more directories.
+				buf.WriteString("(?:.*/)?")
+				i += 2
+			case strings.HasPrefix(p[i:], "**"):
+				buf.WriteString(".*")
+				i++
+			default:
+				buf.WriteString("[^/]*")
+			}
+		case '?':
+			buf.WriteString("[^/]")
+		default:
+			buf.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		}
+	}
+	if dirOnly {
+		buf.WriteString("/.*")
+	} else {
+		buf.WriteString("(?:/.*)?")
+	}
+	buf.WriteString("$")
+
+	re, err := regexp.Compile(buf.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid path pattern: '%v'", pattern)
+	}
+	return re, nil
+}
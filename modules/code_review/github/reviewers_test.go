@@ -0,0 +1,73 @@
+package github
+
+var _ = Describe("reviewer selection", func() {
+
+	Describe("path patterns", func() {
+		matches := func(pattern, path string) bool {
+			re, err := compilePathPattern(pattern)
+			Expect(err).To(BeNil())
+			return re.MatchString(path)
+		}
+
+		It("should match file name patterns at any level", func() {
+			Expect(matches("*.go", "main.go")).To(BeTrue())
+			Expect(matches("*.go", "git/git.go")).To(BeTrue())
+			Expect(matches("*.go", "README.md")).ToNot(BeTrue())
+		})
+
+		It("should anchor the patterns containing a slash", func() {
+			Expect(matches("/docs", "docs/index.md")).To(BeTrue())
+			Expect(matches("/docs", "web/docs/index.md")).ToNot(BeTrue())
+			Expect(matches("modules/*.go", "modules/loader.go")).To(BeTrue())
+			Expect(matches("modules/*.go", "modules/common/errors.go")).ToNot(BeTrue())
+			Expect(matches("modules/**/*.go", "modules/common/errors.go")).To(BeTrue())
+		})
+
+		It("should match the directory contents for the patterns ending with a slash", func() {
+			Expect(matches("docs/", "web/docs/index.md")).To(BeTrue())
+			Expect(matches("docs/", "docs")).ToNot(BeTrue())
+		})
+	})
+
+	Describe("file owners", func() {
+		rules := []*ReviewerRule{
+			{Paths: []string{"*"}, Reviewers: []string{"alice"}},
+			{Paths: []string{"*.go"}, Reviewers: []string{"bob"}},
+			{Paths: []string{"/docs/"}, Reviewers: []string{"carol", "dave"}},
+		}
+
+		It("should let the last matching rule win", func() {
+			Expect(fileOwners(rules, []string{"main.go"})).To(Equal([]string{"bob"}))
+			Expect(fileOwners(rules, []string{"Makefile"})).To(Equal([]string{"alice"}))
+		})
+
+		It("should put the reviewers owning more files first", func() {
+			files := []string{"Makefile", "docs/a.md", "docs/b.md"}
+			Expect(fileOwners(rules, files)).To(Equal([]string{"carol", "dave", "alice"}))
+		})
+
+		It("should return no owners in case no rule matches", func() {
+			Expect(fileOwners(rules[2:], []string{"main.go"})).To(BeEmpty())
+		})
+	})
+
+	Describe("policies", func() {
+		candidates := []string{"alice", "Bob", "carol"}
+
+		It("should exclude the authors and unavailable reviewers", func() {
+			Expect(filterCandidates(candidates, []string{"bob", "carol"})).To(
+				Equal([]string{"alice"}))
+		})
+
+		It("should rotate the reviewers", func() {
+			Expect(nextReviewer(candidates, "")).To(Equal("alice"))
+			Expect(nextReviewer(candidates, "bob")).To(Equal("carol"))
+			Expect(nextReviewer(candidates, "carol")).To(Equal("alice"))
+		})
+
+		It("should choose the least loaded reviewer", func() {
+			load := map[string]int{"alice": 2, "bob": 1, "carol": 1}
+			Expect(leastLoadedReviewer(candidates, load)).To(Equal("Bob"))
+		})
+	})
+})
@@ -0,0 +1,40 @@
+package github
+
+import (
+	// Internal
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/git"
+	"github.com/salsaflow/salsaflow/github/webhooks"
+	"github.com/salsaflow/salsaflow/modules/common"
+)
+
+// WebhookHandler is a part of common.WebhookReceiver interface.
+//
+// The handler updates the review issues and the stories according to
+// the issues, issue_comment and push events sent by GitHub.
+func (tool *codeReviewTool) WebhookHandler(
+	secret string,
+	tracker common.IssueTracker,
+) (common.WebhookHandler, error) {
+
+	task := "Set up the GitHub webhook handler"
+	client, owner, repo, err := tool.prepareForApiCalls()
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+
+	gitConfig, err := git.LoadConfig()
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+
+	return webhooks.NewHandler(&webhooks.Options{
+		Secret:      secret,
+		Client:      client,
+		Owner:       owner,
+		Repository:  repo,
+		ReviewLabel: tool.config.ReviewLabel,
+		TrunkBranch: gitConfig.TrunkBranchName,
+		Tracker:     tracker,
+	}), nil
+}
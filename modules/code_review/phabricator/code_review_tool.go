@@ -0,0 +1,392 @@
+package phabricator
+
+import (
+	// Stdlib
+	"errors"
+	"fmt"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/action"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/git"
+	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/output"
+	"github.com/salsaflow/salsaflow/repo"
+	"github.com/salsaflow/salsaflow/version"
+
+	// Vendor
+	"github.com/toqueteos/webbrowser"
+)
+
+var errPostReviewRequest = errors.New("failed to post a review request")
+
+func newCodeReviewTool() (common.CodeReviewTool, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return &codeReviewTool{config, newConduitClient(config)}, nil
+}
+
+type codeReviewTool struct {
+	config *moduleConfig
+	client *conduitClient
+}
+
+func (tool *codeReviewTool) NewRelease(v *version.Version) common.Release {
+	return newRelease(tool, v)
+}
+
+func (tool *codeReviewTool) PostReviewRequests(
+	ctxs []*common.ReviewContext,
+	opts map[string]interface{},
+) (err error) {
+
+	// The diffs are built using git worktree,
+	// so make sure it is available before any project is touched.
+	if _, err := repo.CheckGitVersion(); err != nil {
+		return err
+	}
+
+	// Group commits by story ID.
+	//
+	// In case the commit is associated with a story, we add it to the relevant story group.
+	// Otherwise the commit is marked as unassigned and added to the relevant list.
+	var (
+		ctxsByStoryId     = make(map[string][]*common.ReviewContext, 1)
+		unassignedCommits = make([]*git.Commit, 0, 1)
+	)
+	for _, ctx := range ctxs {
+		story := ctx.Story
+		if story != nil {
+			sid := story.ReadableId()
+			ctxsByStoryId[sid] = append(ctxsByStoryId[sid], ctx)
+		} else {
+			unassignedCommits = append(unassignedCommits, ctx.Commit)
+		}
+	}
+
+	// Post the assigned commits.
+	_, open := opts["open"]
+	for _, ctxs := range ctxsByStoryId {
+		var (
+			story   = ctxs[0].Story
+			commits = make([]*git.Commit, 0, len(ctxs))
+		)
+		for _, ctx := range ctxs {
+			commits = append(commits, ctx.Commit)
+		}
+
+		// Create/update the revision.
+		rev, ex := tool.postRevision(&revisionSummary{
+			Header:   fmt.Sprintf("Story: [[%v | %v]]", story.URL(), story.ReadableId()),
+			StoryKey: story.Tag(),
+		}, fmt.Sprintf("Review story %v: %v", story.ReadableId(), story.Title()), 0, commits, opts)
+		if ex != nil {
+			errs.Log(ex)
+			err = errPostReviewRequest
+			continue
+		}
+		recordRevision(rev, open)
+	}
+
+	// Get the value of -fixes.
+	var fixes int
+	if v, ok := opts["fixes"].(uint); ok && v != 0 {
+		fixes = int(v)
+	}
+
+	// Post the unassigned commits.
+	for _, commit := range unassignedCommits {
+		rev, ex := tool.postRevision(&revisionSummary{
+			Header: fmt.Sprintf("Commit: %v", commit.SHA),
+		}, fmt.Sprintf("Review commit %v: %v", commit.SHA, commit.MessageTitle),
+			fixes, []*git.Commit{commit}, opts)
+		if ex != nil {
+			errs.Log(ex)
+			err = errPostReviewRequest
+			continue
+		}
+		recordRevision(rev, open)
+	}
+
+	return
+}
+
+func (tool *codeReviewTool) PostReviewFollowupMessage() string {
+	return `
+Phabricator Differential revisions successfully created.
+
+Please visit the revisions that have been created and make sure
+a reviewer is assigned. Annotate and explain the changes to make
+the reviewer's job easier.
+
+In case there are any issues raised for a story revision,
+just keep posting commits for review. The revision is updated
+automatically when the same Story-Id tag is used. The same happens
+when the commits are rewritten as long as the Change-Id tag is kept.
+
+In case there are any issues raised for an unassigned commit
+revision, use
+
+    $ salsaflow review post -fixes=REVISION_ID
+
+to update revision REVISION_ID (without the D prefix) with the fixing commits.
+`
+}
+
+// postRevision creates or updates the revision for the given commits.
+//
+// The revision with the given ID is updated in case the ID is not zero.
+// Otherwise the open revision for the release is searched for, first using
+// the Change-Id tags of the commits, then using the story key in the summary.
+// A new revision is created with the given summary in case nothing is found.
+func (tool *codeReviewTool) postRevision(
+	newSummary *revisionSummary,
+	title string,
+	revisionId int,
+	commits []*git.Commit,
+	opts map[string]interface{},
+) (rev *revision, err error) {
+
+	last := commits[len(commits)-1]
+
+	// Get the project for the release associated with the commits.
+	task := fmt.Sprintf("Get the release associated with commit %v", last.SHA)
+	v, err := version.GetByBranch(last.SHA)
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+	proj, act, err := tool.getOrCreateReleaseProject(v)
+	if err != nil {
+		return nil, err
+	}
+	// Archive the project again in case it has just been created
+	// and the revision cannot be posted.
+	defer action.RollbackOnError(&err, act)
+
+	// Find the revision to be updated.
+	existing, err := tool.findRevision(proj, newSummary.StoryKey, revisionId, commits)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update the summary.
+	summary := newSummary
+	if existing != nil {
+		summary = parseSummary(existing.Summary)
+	}
+	summary.addCommits(commits)
+
+	// Get the diff base, which is the parent of the first commit listed.
+	first := summary.Commits[0]
+	task = fmt.Sprintf("Get the parent of commit %v", first.SHA)
+	base, err := git.CommitHexsha(first.SHA + "^")
+	if err != nil {
+		return nil, errs.NewError(task, fmt.Errorf(
+			"commit %v has no parent to be used as the diff base", first.SHA))
+	}
+	summary.BaseCommit = base
+
+	// Apply the commits listed onto the base so that the diff contains
+	// exactly these commits and not the other commits in between.
+	task = fmt.Sprintf("Apply the commits listed in the summary onto %v", shortSHA(base))
+	listed := make([]string, 0, len(summary.Commits))
+	for _, commit := range summary.Commits {
+		listed = append(listed, commit.SHA)
+	}
+	head, err := git.CherryPickOnto(base, listed...)
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+
+	// Upload the diff.
+	task = fmt.Sprintf("Upload the diff for %v..%v", shortSHA(base), last.SHA)
+	log.Run(task)
+	stdout, err := git.Run("diff", "-M", "--no-ext-diff", "--no-textconv",
+		"--src-prefix=a/", "--dst-prefix=b/", "-U32767", base, head)
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+	diffPHID, err := tool.client.createRawDiff(stdout.String())
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+
+	// Create/update the revision.
+	txs := []*transaction{
+		{"update", diffPHID},
+		{"title", title},
+		{"summary", summary.String()},
+	}
+	var revisionPHID string
+	if existing != nil {
+		revisionPHID = existing.PHID
+		task = fmt.Sprintf("Update revision %v", existing.ReadableId())
+	} else {
+		txs = append(txs, &transaction{"projects.add", []string{proj.PHID}})
+		if reviewer, ok := opts["reviewer"].(string); ok && reviewer != "" {
+			userPHID, err := tool.client.findUserPHID(reviewer)
+			if err != nil {
+				return nil, errs.NewError("Get the reviewer", err)
+			}
+			txs = append(txs, &transaction{"reviewers.add", []string{userPHID}})
+		}
+		task = "Create a new revision"
+	}
+	log.Run(task)
+	revisionPHID, err = tool.client.editRevision(revisionPHID, txs)
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+
+	// Fetch the revision to get the ID and the URL.
+	revisions, err := tool.client.searchRevisions(map[string]interface{}{
+		"phids": []string{revisionPHID},
+	})
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+	if len(revisions) == 0 {
+		return nil, errs.NewError(task, fmt.Errorf("revision not found: %v", revisionPHID))
+	}
+	return revisions[0], nil
+}
+
+// findRevision returns the revision to be updated, nil in case there is no such revision.
+func (tool *codeReviewTool) findRevision(
+	proj *project,
+	storyKey string,
+	revisionId int,
+	commits []*git.Commit,
+) (*revision, error) {
+
+	// Get the revision by ID when set.
+	if revisionId != 0 {
+		task := fmt.Sprintf("Fetch revision D%v", revisionId)
+		log.Run(task)
+		rev, err := tool.client.getRevision(revisionId)
+		if err != nil {
+			return nil, errs.NewError(task, err)
+		}
+		if rev == nil {
+			return nil, errs.NewError(task, &common.ErrReviewRequestNotFound{
+				What: fmt.Sprintf("revision D%v", revisionId),
+			})
+		}
+		if rev.Closed {
+			return nil, errs.NewError(task, fmt.Errorf("revision D%v is closed", revisionId))
+		}
+		return rev, nil
+	}
+
+	// Search the open revisions for the release.
+	task := fmt.Sprintf("Search for an existing revision in project '%v'", proj.Name)
+	log.Run(task)
+	revisions, err := tool.client.searchRevisions(map[string]interface{}{
+		"projects": []string{proj.PHID},
+	})
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+
+	// The Change-Id is the stable key, try that first.
+	for _, rev := range revisions {
+		if !rev.Closed && parseSummary(rev.Summary).hasChangeId(commits) {
+			return rev, nil
+		}
+	}
+
+	// Try the story key then.
+	if storyKey == "" {
+		return nil, nil
+	}
+	for _, rev := range revisions {
+		if !rev.Closed && parseSummary(rev.Summary).StoryKey == storyKey {
+			return rev, nil
+		}
+	}
+	return nil, nil
+}
+
+// Release projects ------------------------------------------------------------
+
+// releaseProjectName returns the name of the project
+// used to tag the revisions associated with the given release.
+func (tool *codeReviewTool) releaseProjectName(v *version.Version) string {
+	return fmt.Sprintf("%v-%v-review", tool.config.Repository, v.BaseString())
+}
+
+// getOrCreateReleaseProject returns the project for the given release,
+// creating the project in case it does not exist yet. An archived project
+// is activated again, which happens when the project was archived
+// by a rollback before.
+//
+// Projects cannot be deleted using Conduit, so the project
+// is archived in case the returned action is rolled back.
+func (tool *codeReviewTool) getOrCreateReleaseProject(v *version.Version) (*project, action.Action, error) {
+	name := tool.releaseProjectName(v)
+
+	task := fmt.Sprintf("Get Phabricator project '%v'", name)
+	proj, err := tool.client.findProject(name)
+	if err != nil {
+		return nil, nil, errs.NewError(task, err)
+	}
+	if proj != nil {
+		if !proj.Archived {
+			return proj, action.Noop, nil
+		}
+
+		task := fmt.Sprintf("Activate Phabricator project '%v'", name)
+		log.Run(task)
+		if err := tool.client.setProjectStatus(proj.PHID, projectStatusActive); err != nil {
+			return nil, nil, errs.NewError(task, err)
+		}
+		proj.Archived = false
+
+		return proj, tool.archiveProjectAction(task, proj), nil
+	}
+
+	task = fmt.Sprintf("Create Phabricator project '%v'", name)
+	log.Run(task)
+	phid, err := tool.client.createProject(name)
+	if err != nil {
+		return nil, nil, errs.NewError(task, err)
+	}
+	proj = &project{PHID: phid, Name: name}
+
+	return proj, tool.archiveProjectAction(task, proj), nil
+}
+
+// archiveProjectAction returns the rollback action for the given task,
+// which archives the given project.
+func (tool *codeReviewTool) archiveProjectAction(task string, proj *project) action.Action {
+	return action.ActionFunc(func() error {
+		log.Rollback(task)
+		task := fmt.Sprintf("Archive Phabricator project '%v'", proj.Name)
+		return errs.Wrap(task, tool.client.setProjectStatus(proj.PHID, projectStatusArchived))
+	})
+}
+
+// Utilities -------------------------------------------------------------------
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+func recordRevision(rev *revision, open bool) {
+	// Record the revision for the JSON output.
+	output.AddURL("revision", rev.URL)
+
+	// Open the revision in the browser if requested.
+	if open {
+		if err := webbrowser.Open(rev.URL); err != nil {
+			log.Warn("Failed to open the revision in the browser")
+		}
+	}
+}
@@ -0,0 +1,341 @@
+package phabricator
+
+import (
+	// Stdlib
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/httputil"
+)
+
+// conduitClient calls the Phabricator Conduit API.
+type conduitClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func newConduitClient(config *moduleConfig) *conduitClient {
+	return &conduitClient{
+		baseURL: config.URL,
+		token:   config.Token,
+		http:    httputil.DefaultClient,
+	}
+}
+
+// ConduitError is returned in case the Conduit API call fails.
+type ConduitError struct {
+	Method string
+	Code   string
+	Info   string
+}
+
+func (err *ConduitError) Error() string {
+	return fmt.Sprintf("%v: %v: %v", err.Method, err.Code, err.Info)
+}
+
+// call calls the given Conduit method and decodes the result into v unless it is nil.
+func (c *conduitClient) call(method string, params map[string]interface{}, v interface{}) error {
+	// Add the token to the parameters.
+	withToken := make(map[string]interface{}, len(params)+1)
+	for k, v := range params {
+		withToken[k] = v
+	}
+	withToken["__conduit__"] = map[string]string{"token": c.token}
+
+	content, err := json.Marshal(withToken)
+	if err != nil {
+		return err
+	}
+	form := url.Values{
+		"params":      []string{string(content)},
+		"output":      []string{"json"},
+		"__conduit__": []string{"1"},
+	}
+
+	resp, err := c.http.PostForm(c.baseURL+"/api/"+method, form)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &ConduitError{method, "HTTP", resp.Status}
+	}
+
+	var body struct {
+		Result    json.RawMessage `json:"result"`
+		ErrorCode *string         `json:"error_code"`
+		ErrorInfo *string         `json:"error_info"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return err
+	}
+	if body.ErrorCode != nil {
+		var info string
+		if body.ErrorInfo != nil {
+			info = *body.ErrorInfo
+		}
+		return &ConduitError{method, *body.ErrorCode, info}
+	}
+
+	if v == nil {
+		return nil
+	}
+	return json.Unmarshal(body.Result, v)
+}
+
+// transaction is an edit transaction as accepted by the *.edit methods.
+type transaction struct {
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// edit calls the given *.edit method, creating a new object
+// in case the object identifier is empty. The object PHID is returned.
+func (c *conduitClient) edit(method, objectIdentifier string, txs []*transaction) (string, error) {
+	params := map[string]interface{}{
+		"transactions": txs,
+	}
+	if objectIdentifier != "" {
+		params["objectIdentifier"] = objectIdentifier
+	}
+
+	var result struct {
+		Object struct {
+			Id   int    `json:"id"`
+			PHID string `json:"phid"`
+		} `json:"object"`
+	}
+	if err := c.call(method, params, &result); err != nil {
+		return "", err
+	}
+	return result.Object.PHID, nil
+}
+
+// search calls the given *.search method, following the cursor
+// and calling handle for every page of results.
+func (c *conduitClient) search(
+	method string,
+	constraints map[string]interface{},
+	handle func(data json.RawMessage) error,
+) error {
+	var after string
+	for {
+		params := map[string]interface{}{
+			"constraints": constraints,
+		}
+		if after != "" {
+			params["after"] = after
+		}
+
+		var result struct {
+			Data   json.RawMessage `json:"data"`
+			Cursor struct {
+				After *string `json:"after"`
+			} `json:"cursor"`
+		}
+		if err := c.call(method, params, &result); err != nil {
+			return err
+		}
+		if err := handle(result.Data); err != nil {
+			return err
+		}
+
+		if result.Cursor.After == nil || *result.Cursor.After == "" {
+			return nil
+		}
+		after = *result.Cursor.After
+	}
+}
+
+// Differential ----------------------------------------------------------------
+
+// revision represents a Differential revision.
+type revision struct {
+	Id        int
+	PHID      string
+	Title     string
+	Summary   string
+	URL       string
+	Closed    bool
+	CreatedAt time.Time
+}
+
+// ReadableId returns the revision ID as used in Phabricator, e.g. D42.
+func (r *revision) ReadableId() string {
+	return fmt.Sprintf("D%v", r.Id)
+}
+
+type revisionData struct {
+	Id     int    `json:"id"`
+	PHID   string `json:"phid"`
+	Fields struct {
+		Title   string `json:"title"`
+		Summary string `json:"summary"`
+		URI     string `json:"uri"`
+		Status  struct {
+			Value  string `json:"value"`
+			Closed bool   `json:"closed"`
+		} `json:"status"`
+		DateCreated int64 `json:"dateCreated"`
+	} `json:"fields"`
+}
+
+func (data *revisionData) toRevision() *revision {
+	return &revision{
+		Id:        data.Id,
+		PHID:      data.PHID,
+		Title:     data.Fields.Title,
+		Summary:   data.Fields.Summary,
+		URL:       data.Fields.URI,
+		Closed:    data.Fields.Status.Closed,
+		CreatedAt: time.Unix(data.Fields.DateCreated, 0),
+	}
+}
+
+// searchRevisions returns the revisions matching the given constraints.
+func (c *conduitClient) searchRevisions(constraints map[string]interface{}) ([]*revision, error) {
+	var revisions []*revision
+	err := c.search("differential.revision.search", constraints, func(data json.RawMessage) error {
+		var page []*revisionData
+		if err := json.Unmarshal(data, &page); err != nil {
+			return err
+		}
+		for _, rd := range page {
+			revisions = append(revisions, rd.toRevision())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// getRevision returns the revision with the given ID, nil in case there is no such revision.
+func (c *conduitClient) getRevision(id int) (*revision, error) {
+	revisions, err := c.searchRevisions(map[string]interface{}{
+		"ids": []int{id},
+	})
+	if err != nil || len(revisions) == 0 {
+		return nil, err
+	}
+	return revisions[0], nil
+}
+
+// createRawDiff uploads the given unified diff and returns the diff PHID.
+func (c *conduitClient) createRawDiff(diff string) (string, error) {
+	var result struct {
+		PHID string `json:"phid"`
+	}
+	if err := c.call("differential.createrawdiff", map[string]interface{}{
+		"diff": diff,
+	}, &result); err != nil {
+		return "", err
+	}
+	return result.PHID, nil
+}
+
+// editRevision creates or updates a revision. The revision is created
+// in case the revision PHID is empty. The revision PHID is returned.
+func (c *conduitClient) editRevision(revisionPHID string, txs []*transaction) (string, error) {
+	return c.edit("differential.revision.edit", revisionPHID, txs)
+}
+
+// Projects --------------------------------------------------------------------
+
+// Project statuses as used by the status transaction.
+const (
+	projectStatusActive   = "active"
+	projectStatusArchived = "archived"
+)
+
+// project represents a Phabricator project.
+type project struct {
+	PHID     string
+	Name     string
+	Archived bool
+}
+
+// findProject returns the project with the given name, nil in case there is no such project.
+func (c *conduitClient) findProject(name string) (*project, error) {
+	var found *project
+	err := c.search("project.search", map[string]interface{}{
+		"name": name,
+	}, func(data json.RawMessage) error {
+		var page []struct {
+			PHID   string `json:"phid"`
+			Fields struct {
+				Name   string `json:"name"`
+				Status string `json:"status"`
+			} `json:"fields"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return err
+		}
+		// The name constraint matches substrings, so check the name.
+		for _, p := range page {
+			if found == nil && strings.EqualFold(p.Fields.Name, name) {
+				found = &project{
+					PHID:     p.PHID,
+					Name:     p.Fields.Name,
+					Archived: p.Fields.Status == projectStatusArchived,
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+// createProject creates a new project with the given name and returns its PHID.
+func (c *conduitClient) createProject(name string) (string, error) {
+	return c.edit("project.edit", "", []*transaction{
+		{"name", name},
+	})
+}
+
+// setProjectStatus archives or activates the given project.
+func (c *conduitClient) setProjectStatus(projectPHID, status string) error {
+	_, err := c.edit("project.edit", projectPHID, []*transaction{
+		{"status", status},
+	})
+	return err
+}
+
+// Users -----------------------------------------------------------------------
+
+// findUserPHID returns the PHID of the user with the given username.
+func (c *conduitClient) findUserPHID(username string) (string, error) {
+	var phid string
+	err := c.search("user.search", map[string]interface{}{
+		"usernames": []string{username},
+	}, func(data json.RawMessage) error {
+		var page []struct {
+			PHID string `json:"phid"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return err
+		}
+		if len(page) != 0 && phid == "" {
+			phid = page[0].PHID
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if phid == "" {
+		return "", fmt.Errorf("Phabricator user not found: %v", username)
+	}
+	return phid, nil
+}
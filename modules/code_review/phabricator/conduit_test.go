@@ -0,0 +1,276 @@
+package phabricator
+
+import (
+	// Stdlib
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const testToken = "api-s3cr3t"
+
+type fakeProject struct {
+	PHID   string `json:"phid"`
+	Fields struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+	} `json:"fields"`
+}
+
+type fakeRevision struct {
+	revisionData
+	projects []string
+}
+
+// fakeConduit is an in-memory fake of the Conduit methods used by the module.
+type fakeConduit struct {
+	server   *httptest.Server
+	pageSize int
+
+	lock      sync.Mutex
+	projects  []*fakeProject
+	revisions []*fakeRevision
+	diffs     []string
+}
+
+func newFakeConduit() *fakeConduit {
+	fake := &fakeConduit{pageSize: 2}
+	fake.server = httptest.NewServer(http.HandlerFunc(fake.serveHTTP))
+	return fake
+}
+
+func (fake *fakeConduit) Close() {
+	fake.server.Close()
+}
+
+func (fake *fakeConduit) addProject(name, status string) *fakeProject {
+	p := &fakeProject{PHID: fmt.Sprintf("PHID-PROJ-%v", len(fake.projects)+1)}
+	p.Fields.Name = name
+	p.Fields.Status = status
+	fake.projects = append(fake.projects, p)
+	return p
+}
+
+func (fake *fakeConduit) addRevision(title, summary string, closed bool, projects ...string) *fakeRevision {
+	id := len(fake.revisions) + 1
+	rev := &fakeRevision{projects: projects}
+	rev.Id = id
+	rev.PHID = fmt.Sprintf("PHID-DREV-%v", id)
+	rev.Fields.Title = title
+	rev.Fields.Summary = summary
+	rev.Fields.URI = fmt.Sprintf("%v/D%v", fake.server.URL, id)
+	rev.Fields.Status.Value = "needs-review"
+	if closed {
+		rev.Fields.Status.Value = "published"
+		rev.Fields.Status.Closed = true
+	}
+	fake.revisions = append(fake.revisions, rev)
+	return rev
+}
+
+func (fake *fakeConduit) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	fake.lock.Lock()
+	defer fake.lock.Unlock()
+
+	var params struct {
+		Conduit struct {
+			Token string `json:"token"`
+		} `json:"__conduit__"`
+		Constraints      map[string]json.RawMessage `json:"constraints"`
+		After            string                     `json:"after"`
+		ObjectIdentifier string                     `json:"objectIdentifier"`
+		Transactions     []*transaction             `json:"transactions"`
+		Diff             string                     `json:"diff"`
+	}
+	if err := json.Unmarshal([]byte(r.FormValue("params")), &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	reply := func(result interface{}) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"result":     result,
+			"error_code": nil,
+			"error_info": nil,
+		})
+	}
+	fail := func(code, info string) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"result":     nil,
+			"error_code": code,
+			"error_info": info,
+		})
+	}
+
+	if params.Conduit.Token != testToken {
+		fail("ERR-INVALID-AUTH", "API token is invalid.")
+		return
+	}
+
+	switch method := strings.TrimPrefix(r.URL.Path, "/api/"); method {
+	case "project.search":
+		var name string
+		json.Unmarshal(params.Constraints["name"], &name)
+		data := []*fakeProject{}
+		for _, p := range fake.projects {
+			if strings.Contains(strings.ToLower(p.Fields.Name), strings.ToLower(name)) {
+				data = append(data, p)
+			}
+		}
+		reply(map[string]interface{}{"data": data, "cursor": map[string]interface{}{"after": nil}})
+
+	case "project.edit":
+		var p *fakeProject
+		if params.ObjectIdentifier == "" {
+			p = fake.addProject("", projectStatusActive)
+		} else {
+			for _, candidate := range fake.projects {
+				if candidate.PHID == params.ObjectIdentifier {
+					p = candidate
+				}
+			}
+		}
+		if p == nil {
+			fail("ERR-CONDUIT-CORE", "No such object.")
+			return
+		}
+		for _, tx := range params.Transactions {
+			switch tx.Type {
+			case "name":
+				p.Fields.Name = tx.Value.(string)
+			case "status":
+				p.Fields.Status = tx.Value.(string)
+			}
+		}
+		reply(map[string]interface{}{"object": map[string]interface{}{"phid": p.PHID}})
+
+	case "differential.revision.search":
+		var (
+			ids      []int
+			phids    []string
+			projects []string
+		)
+		json.Unmarshal(params.Constraints["ids"], &ids)
+		json.Unmarshal(params.Constraints["phids"], &phids)
+		json.Unmarshal(params.Constraints["projects"], &projects)
+
+		var matching []*revisionData
+		for _, rev := range fake.revisions {
+			if matchesInt(ids, rev.Id) && matchesString(phids, rev.PHID) && matchesAny(projects, rev.projects) {
+				matching = append(matching, &rev.revisionData)
+			}
+		}
+
+		// Return a page of the results, the cursor being the index.
+		start, _ := strconv.Atoi(params.After)
+		end := start + fake.pageSize
+		var after interface{}
+		if end < len(matching) {
+			after = strconv.Itoa(end)
+		} else {
+			end = len(matching)
+		}
+		reply(map[string]interface{}{
+			"data":   matching[start:end],
+			"cursor": map[string]interface{}{"after": after},
+		})
+
+	case "differential.createrawdiff":
+		fake.diffs = append(fake.diffs, params.Diff)
+		reply(map[string]interface{}{"phid": fmt.Sprintf("PHID-DIFF-%v", len(fake.diffs))})
+
+	default:
+		fail("ERR-CONDUIT-CALL", fmt.Sprintf("Conduit method %v does not exist.", method))
+	}
+}
+
+func matchesInt(constraint []int, value int) bool {
+	for _, v := range constraint {
+		if v == value {
+			return true
+		}
+	}
+	return constraint == nil
+}
+
+func matchesString(constraint []string, value string) bool {
+	return matchesAny(constraint, []string{value})
+}
+
+func matchesAny(constraint []string, values []string) bool {
+	for _, c := range constraint {
+		for _, v := range values {
+			if c == v {
+				return true
+			}
+		}
+	}
+	return constraint == nil
+}
+
+// newTestClient returns the client talking to the fake.
+func newTestClient(fake *fakeConduit) *conduitClient {
+	return newConduitClient(&moduleConfig{
+		URL:        fake.server.URL,
+		Repository: "widgets",
+		Token:      testToken,
+	})
+}
+
+var _ = Describe("Conduit client", func() {
+
+	var (
+		fake   *fakeConduit
+		client *conduitClient
+	)
+
+	BeforeEach(func() {
+		fake = newFakeConduit()
+		client = newTestClient(fake)
+	})
+
+	AfterEach(func() {
+		fake.Close()
+	})
+
+	It("should follow the cursor when searching", func() {
+		for i := 1; i <= 5; i++ {
+			fake.addRevision(fmt.Sprintf("Revision %v", i), "", i%2 == 0, "PHID-PROJ-1")
+		}
+		fake.addRevision("Another project", "", false, "PHID-PROJ-2")
+
+		revisions, err := client.searchRevisions(map[string]interface{}{
+			"projects": []string{"PHID-PROJ-1"},
+		})
+		Expect(err).To(BeNil())
+		Expect(revisions).To(HaveLen(5))
+		Expect(revisions[4].ReadableId()).To(Equal("D5"))
+		Expect(revisions[1].Closed).To(BeTrue())
+		Expect(revisions[2].Closed).ToNot(BeTrue())
+	})
+
+	It("should find the project by the exact name", func() {
+		fake.addProject("widgets-1.2.0-review-old", projectStatusActive)
+		fake.addProject("widgets-1.2.0-review", projectStatusArchived)
+
+		proj, err := client.findProject("widgets-1.2.0-review")
+		Expect(err).To(BeNil())
+		Expect(proj.PHID).To(Equal("PHID-PROJ-2"))
+		Expect(proj.Archived).To(BeTrue())
+
+		proj, err = client.findProject("widgets-1.3.0-review")
+		Expect(err).To(BeNil())
+		Expect(proj).To(BeNil())
+	})
+
+	It("should return the Conduit error", func() {
+		client.token = "invalid"
+		_, err := client.findProject("widgets-1.2.0-review")
+		Expect(err).To(HaveOccurred())
+		Expect(err.(*ConduitError).Code).To(Equal("ERR-INVALID-AUTH"))
+	})
+})
@@ -0,0 +1,109 @@
+package phabricator
+
+import (
+	// Stdlib
+	"fmt"
+	"strings"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/config/loader"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/prompt"
+)
+
+// Configuration ===============================================================
+
+type moduleConfig struct {
+	// Phabricator instance base URL.
+	URL string
+
+	// Phabricator repository short name, used to name the release projects.
+	Repository string
+
+	// Conduit API authentication.
+	Token string
+}
+
+func loadConfig() (*moduleConfig, error) {
+	task := fmt.Sprintf("Load config for module '%v'", ModuleId)
+
+	// Load the config.
+	spec := newConfigSpec()
+	if err := loader.LoadConfig(spec); err != nil {
+		return nil, errs.NewError(task, err)
+	}
+
+	return &moduleConfig{
+		URL:        strings.TrimSuffix(spec.local.URL, "/"),
+		Repository: spec.local.Repository,
+		Token:      spec.global.Token,
+	}, nil
+}
+
+// Configuration spec ----------------------------------------------------------
+
+type configSpec struct {
+	global *GlobalConfig
+	local  *LocalConfig
+}
+
+func newConfigSpec() *configSpec {
+	return &configSpec{}
+}
+
+// ConfigKey is a part of loader.ConfigSpec
+func (spec *configSpec) ConfigKey() string {
+	return ModuleId
+}
+
+// ModuleKind is a part of loader.ModuleConfigSpec
+func (spec *configSpec) ModuleKind() loader.ModuleKind {
+	return ModuleKind
+}
+
+// GlobalConfig is a part of loader.ConfigSpec
+func (spec *configSpec) GlobalConfig() loader.ConfigContainer {
+	spec.global = &GlobalConfig{}
+	return spec.global
+}
+
+// LocalConfig is a part of loader.ConfigSpec
+func (spec *configSpec) LocalConfig() loader.ConfigContainer {
+	spec.local = &LocalConfig{}
+	return spec.local
+}
+
+// Local configuration ---------------------------------------------------------
+
+type LocalConfig struct {
+	URL        string `prompt:"Phabricator URL"                    json:"url"`
+	Repository string `prompt:"Phabricator repository short name" json:"repository"`
+}
+
+// PromptUserForConfig is a part of loader.ConfigContainer interface.
+func (local *LocalConfig) PromptUserForConfig() error {
+	var c LocalConfig
+	if err := prompt.Dialog(&c, "Insert the"); err != nil {
+		return err
+	}
+
+	*local = c
+	return nil
+}
+
+// Global configuration --------------------------------------------------------
+
+type GlobalConfig struct {
+	Token string `prompt:"Conduit API token" secret:"true" json:"token"`
+}
+
+// PromptUserForConfig is a part of loader.ConfigContainer interface.
+func (global *GlobalConfig) PromptUserForConfig() error {
+	var c GlobalConfig
+	if err := prompt.Dialog(&c, "Insert your"); err != nil {
+		return err
+	}
+
+	*global = c
+	return nil
+}
@@ -0,0 +1,31 @@
+package phabricator
+
+import (
+	// Stdlib
+	"testing"
+
+	// Vendor - testing framework
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var (
+	AfterEach  = ginkgo.AfterEach
+	BeforeEach = ginkgo.BeforeEach
+	Context    = ginkgo.Context
+	Describe   = ginkgo.Describe
+	It         = ginkgo.It
+
+	BeNil            = gomega.BeNil
+	BeTrue           = gomega.BeTrue
+	ContainSubstring = gomega.ContainSubstring
+	Equal            = gomega.Equal
+	Expect           = gomega.Expect
+	HaveLen          = gomega.HaveLen
+	HaveOccurred     = gomega.HaveOccurred
+)
+
+func TestPhabricatorCodeReview(t *testing.T) {
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "Phabricator Code Review")
+}
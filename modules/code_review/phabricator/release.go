@@ -0,0 +1,123 @@
+package phabricator
+
+import (
+	// Stdlib
+	"bytes"
+	"errors"
+	"fmt"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/action"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/version"
+)
+
+type release struct {
+	tool *codeReviewTool
+	v    *version.Version
+
+	closingProject *project
+}
+
+func newRelease(tool *codeReviewTool, v *version.Version) *release {
+	return &release{
+		tool: tool,
+		v:    v,
+	}
+}
+
+// Initialise is a part of common.Release interface.
+//
+// It makes sure the project used to tag the revisions for the release exists.
+func (r *release) Initialise() (action.Action, error) {
+	task := fmt.Sprintf(
+		"Check whether Phabricator release project exists for release %v", r.v.BaseString())
+	log.Run(task)
+	_, act, err := r.tool.getOrCreateReleaseProject(r.v)
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+	return act, nil
+}
+
+// EnsureClosable is a part of common.Release interface.
+//
+// The release can be closed when all the revisions
+// tagged with the release project are closed.
+func (r *release) EnsureClosable() error {
+	// Get the release project.
+	releaseString := r.v.BaseString()
+	name := r.tool.releaseProjectName(r.v)
+	task := fmt.Sprintf("Get Phabricator project for release %v", releaseString)
+	log.Run(task)
+	proj, err := r.tool.client.findProject(name)
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+	if proj == nil {
+		return errs.NewErrorWithHint(task, errors.New("project not found"),
+			fmt.Sprintf("\nMake sure project '%v' exists\n\n", name))
+	}
+
+	// Get the revisions tagged with the project.
+	task = fmt.Sprintf("Get Phabricator revisions for release %v", releaseString)
+	revisions, err := r.tool.client.searchRevisions(map[string]interface{}{
+		"projects": []string{proj.PHID},
+	})
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+
+	// Make sure none of them is still open.
+	task = fmt.Sprintf("Make sure the revisions for release %v are closed", releaseString)
+	var (
+		hint bytes.Buffer
+		open int
+	)
+	fmt.Fprintf(&hint, "\nThe following revisions for release %v are still open:\n\n", releaseString)
+	for _, rev := range revisions {
+		if !rev.Closed {
+			fmt.Fprintf(&hint, "  %v %v\n", rev.ReadableId(), rev.Title)
+			open++
+		}
+	}
+	fmt.Fprintln(&hint)
+	if open != 0 {
+		return errs.NewErrorWithHint(task, common.ErrNotClosable, hint.String())
+	}
+
+	r.closingProject = proj
+	return nil
+}
+
+// Close is a part of common.Release interface.
+//
+// It archives the release project, the project is activated again on rollback.
+func (r *release) Close() (action.Action, error) {
+	// Make sure EnsureClosable has been called.
+	if r.closingProject == nil {
+		if err := r.EnsureClosable(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Archive the project.
+	var (
+		releaseString = r.v.BaseString()
+		phid          = r.closingProject.PHID
+	)
+	archiveTask := fmt.Sprintf("Archive Phabricator project for release %v", releaseString)
+	log.Run(archiveTask)
+	if err := r.tool.client.setProjectStatus(phid, projectStatusArchived); err != nil {
+		return nil, errs.NewError(archiveTask, err)
+	}
+
+	// Return a rollback function.
+	return action.ActionFunc(func() error {
+		log.Rollback(archiveTask)
+		task := fmt.Sprintf("Activate Phabricator project for release %v", releaseString)
+		return errs.Wrap(task, r.tool.client.setProjectStatus(phid, projectStatusActive))
+	}), nil
+}
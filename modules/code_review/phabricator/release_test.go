@@ -0,0 +1,108 @@
+package phabricator
+
+import (
+	// Internal
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/version"
+)
+
+var _ = Describe("release", func() {
+
+	var (
+		fake *fakeConduit
+		r    *release
+	)
+
+	BeforeEach(func() {
+		fake = newFakeConduit()
+
+		v, err := version.Parse("1.2.0")
+		Expect(err).To(BeNil())
+		tool := &codeReviewTool{
+			config: &moduleConfig{Repository: "widgets"},
+			client: newTestClient(fake),
+		}
+		r = newRelease(tool, v)
+	})
+
+	AfterEach(func() {
+		fake.Close()
+	})
+
+	Describe("Initialise", func() {
+
+		It("should create the release project and archive it on rollback", func() {
+			act, err := r.Initialise()
+			Expect(err).To(BeNil())
+			Expect(fake.projects).To(HaveLen(1))
+			Expect(fake.projects[0].Fields.Name).To(Equal("widgets-1.2.0-review"))
+			Expect(fake.projects[0].Fields.Status).To(Equal(projectStatusActive))
+
+			Expect(act.Rollback()).To(BeNil())
+			Expect(fake.projects[0].Fields.Status).To(Equal(projectStatusArchived))
+		})
+
+		It("should keep the existing release project", func() {
+			fake.addProject("widgets-1.2.0-review", projectStatusActive)
+
+			act, err := r.Initialise()
+			Expect(err).To(BeNil())
+			Expect(act.Rollback()).To(BeNil())
+			Expect(fake.projects).To(HaveLen(1))
+			Expect(fake.projects[0].Fields.Status).To(Equal(projectStatusActive))
+		})
+
+		It("should activate the archived release project and archive it on rollback", func() {
+			fake.addProject("widgets-1.2.0-review", projectStatusArchived)
+
+			act, err := r.Initialise()
+			Expect(err).To(BeNil())
+			Expect(fake.projects).To(HaveLen(1))
+			Expect(fake.projects[0].Fields.Status).To(Equal(projectStatusActive))
+
+			Expect(act.Rollback()).To(BeNil())
+			Expect(fake.projects[0].Fields.Status).To(Equal(projectStatusArchived))
+		})
+	})
+
+	Context("when the revisions are tagged with the release project", func() {
+
+		BeforeEach(func() {
+			fake.addProject("widgets-1.2.0-review", projectStatusActive)
+			fake.addProject("widgets-1.3.0-review", projectStatusActive)
+			fake.addRevision("Review story #1: Build the widget", "", true, "PHID-PROJ-1")
+			fake.addRevision("Review story #2: Paint the widget", "", true, "PHID-PROJ-1")
+			fake.addRevision("Review story #3: Rebuild the widget", "", false, "PHID-PROJ-2")
+		})
+
+		Describe("EnsureClosable", func() {
+
+			It("should succeed when all the revisions are closed", func() {
+				Expect(r.EnsureClosable()).To(BeNil())
+			})
+
+			It("should fail when there is a revision still open", func() {
+				fake.revisions[1].Fields.Status.Closed = false
+
+				err := r.EnsureClosable()
+				Expect(err).To(HaveOccurred())
+				Expect(errs.RootCause(err)).To(Equal(common.ErrNotClosable))
+				Expect(err.(errs.Err).Hint()).To(ContainSubstring("D2 Review story #2"))
+			})
+		})
+
+		Describe("Close", func() {
+
+			It("should archive the release project and activate it on rollback", func() {
+				act, err := r.Close()
+				Expect(err).To(BeNil())
+				Expect(fake.projects[0].Fields.Status).To(Equal(projectStatusArchived))
+				Expect(fake.projects[1].Fields.Status).To(Equal(projectStatusActive))
+
+				Expect(act.Rollback()).To(BeNil())
+				Expect(fake.projects[0].Fields.Status).To(Equal(projectStatusActive))
+			})
+		})
+	})
+})
@@ -0,0 +1,142 @@
+package phabricator
+
+import (
+	// Stdlib
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/git"
+)
+
+// revisionCommit represents a commit listed in the revision summary.
+type revisionCommit struct {
+	SHA      string
+	Title    string
+	ChangeId string
+}
+
+// revisionSummary represents the summary of a revision created by SalsaFlow.
+//
+// Apart from the human-readable header, the summary lists the commits
+// being reviewed and the metadata used to find the revision again.
+type revisionSummary struct {
+	Header     string
+	Commits    []*revisionCommit
+	StoryKey   string
+	BaseCommit string
+}
+
+const (
+	summaryCommitsHeading = "Commits:"
+
+	TagStoryKey   = "SF-Story-Key"
+	TagBaseCommit = "SF-Base-Commit"
+)
+
+var (
+	summaryCommitRegexp = regexp.MustCompile(`^- ([0-9a-f]+): (.+?)(?: \(Change-Id: ([^ )]+)\))?$`)
+	summaryTagRegexp    = regexp.MustCompile(`^(SF-[A-Za-z-]+): (.+)$`)
+)
+
+// parseSummary parses the revision summary as formatted by revisionSummary.String.
+func parseSummary(summary string) *revisionSummary {
+	var (
+		s           revisionSummary
+		headerLines []string
+		inCommits   bool
+	)
+	for _, line := range strings.Split(summary, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if match := summaryTagRegexp.FindStringSubmatch(line); match != nil {
+			switch match[1] {
+			case TagStoryKey:
+				s.StoryKey = match[2]
+			case TagBaseCommit:
+				s.BaseCommit = match[2]
+			}
+			continue
+		}
+
+		switch {
+		case line == summaryCommitsHeading:
+			inCommits = true
+		case inCommits:
+			if match := summaryCommitRegexp.FindStringSubmatch(line); match != nil {
+				s.Commits = append(s.Commits, &revisionCommit{match[1], match[2], match[3]})
+			}
+		default:
+			headerLines = append(headerLines, line)
+		}
+	}
+	s.Header = strings.TrimSpace(strings.Join(headerLines, "\n"))
+	return &s
+}
+
+// String formats the revision summary.
+func (s *revisionSummary) String() string {
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "%v\n\n%v\n\n", s.Header, summaryCommitsHeading)
+	for _, commit := range s.Commits {
+		fmt.Fprintf(&buffer, "- %v: %v", commit.SHA, commit.Title)
+		if commit.ChangeId != "" {
+			fmt.Fprintf(&buffer, " (Change-Id: %v)", commit.ChangeId)
+		}
+		fmt.Fprintln(&buffer)
+	}
+	fmt.Fprintln(&buffer)
+	if s.StoryKey != "" {
+		fmt.Fprintf(&buffer, "%v: %v\n", TagStoryKey, s.StoryKey)
+	}
+	fmt.Fprintf(&buffer, "%v: %v\n", TagBaseCommit, s.BaseCommit)
+	return buffer.String()
+}
+
+// addCommits adds the given commits into the summary.
+//
+// A commit with the same Change-Id as a commit listed already
+// replaces that commit, e.g. when the commits were rebased.
+// It returns true in case any commit was replaced that way.
+func (s *revisionSummary) addCommits(commits []*git.Commit) (rewritten bool) {
+CommitLoop:
+	for _, commit := range commits {
+		for _, listed := range s.Commits {
+			sameChange := commit.ChangeIdTag != "" && listed.ChangeId == commit.ChangeIdTag
+			if sameChange || listed.SHA == commit.SHA {
+				if listed.SHA != commit.SHA {
+					rewritten = true
+				}
+				listed.SHA = commit.SHA
+				listed.Title = commit.MessageTitle
+				if commit.ChangeIdTag != "" {
+					listed.ChangeId = commit.ChangeIdTag
+				}
+				continue CommitLoop
+			}
+		}
+		s.Commits = append(s.Commits, &revisionCommit{
+			SHA:      commit.SHA,
+			Title:    commit.MessageTitle,
+			ChangeId: commit.ChangeIdTag,
+		})
+	}
+	return
+}
+
+// hasChangeId returns true when any of the given commits
+// shares the Change-Id with a commit listed in the summary.
+func (s *revisionSummary) hasChangeId(commits []*git.Commit) bool {
+	for _, commit := range commits {
+		if commit.ChangeIdTag == "" {
+			continue
+		}
+		for _, listed := range s.Commits {
+			if listed.ChangeId == commit.ChangeIdTag {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package phabricator
+
+import (
+	// Internal
+	"github.com/salsaflow/salsaflow/git"
+)
+
+var _ = Describe("revision summary", func() {
+
+	summary := `Story: [[https://example.com/42 | #42]]
+
+Commits:
+
+- 1a2b3c4: Build the widget (Change-Id: I1a2b3c4d)
+- 5d6e7f8: Paint the widget
+
+SF-Story-Key: acme/widgets#42
+SF-Base-Commit: 0123456789abcdef0123456789abcdef01234567
+`
+
+	It("should be parsed and formatted again", func() {
+		s := parseSummary(summary)
+		Expect(s.Header).To(Equal("Story: [[https://example.com/42 | #42]]"))
+		Expect(s.Commits).To(HaveLen(2))
+		Expect(*s.Commits[0]).To(Equal(revisionCommit{"1a2b3c4", "Build the widget", "I1a2b3c4d"}))
+		Expect(*s.Commits[1]).To(Equal(revisionCommit{"5d6e7f8", "Paint the widget", ""}))
+		Expect(s.StoryKey).To(Equal("acme/widgets#42"))
+		Expect(s.BaseCommit).To(Equal("0123456789abcdef0123456789abcdef01234567"))
+		Expect(s.String()).To(Equal(summary))
+	})
+
+	It("should replace the commit with the same Change-Id", func() {
+		s := parseSummary(summary)
+		rewritten := s.addCommits([]*git.Commit{
+			{SHA: "9abcdef", MessageTitle: "Build the blue widget", ChangeIdTag: "I1a2b3c4d"},
+			{SHA: "5d6e7f8", MessageTitle: "Paint the widget"},
+			{SHA: "0fedcba", MessageTitle: "Sell the widget", ChangeIdTag: "I0fedcba9"},
+		})
+		Expect(rewritten).To(BeTrue())
+		Expect(s.Commits).To(HaveLen(3))
+		Expect(*s.Commits[0]).To(Equal(revisionCommit{"9abcdef", "Build the blue widget", "I1a2b3c4d"}))
+		Expect(s.Commits[2].SHA).To(Equal("0fedcba"))
+	})
+
+	It("should match the revision by the Change-Id", func() {
+		s := parseSummary(summary)
+		Expect(s.hasChangeId([]*git.Commit{{SHA: "9abcdef", ChangeIdTag: "I1a2b3c4d"}})).To(BeTrue())
+		Expect(s.hasChangeId([]*git.Commit{{SHA: "5d6e7f8"}})).ToNot(BeTrue())
+	})
+})
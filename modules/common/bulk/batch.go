@@ -0,0 +1,25 @@
+package bulk
+
+// Batches splits the given items into batches of the given size.
+// In case size is not positive, all items are put into a single batch.
+//
+// This is useful for the services that support fetching multiple items
+// using a single request, the batches can be then processed using Do.
+func Batches(items []interface{}, size int) []interface{} {
+	if len(items) == 0 {
+		return nil
+	}
+	if size <= 0 {
+		size = len(items)
+	}
+
+	batches := make([]interface{}, 0, (len(items)+size-1)/size)
+	for start := 0; start < len(items); start += size {
+		end := start + size
+		if end > len(items) {
+			end = len(items)
+		}
+		batches = append(batches, items[start:end])
+	}
+	return batches
+}
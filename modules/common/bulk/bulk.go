@@ -0,0 +1,249 @@
+// Package bulk implements operations that are to be applied to many items at once,
+// e.g. updating all issues or stories associated with a release.
+//
+// The items are processed concurrently, the results are collected per item
+// and the items that were changed successfully can be rolled back on error.
+package bulk
+
+import (
+	// Stdlib
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/action"
+	"github.com/salsaflow/salsaflow/errs"
+)
+
+// DefaultMaxConcurrency is used when Options.MaxConcurrency is not set.
+const DefaultMaxConcurrency = 1
+
+// ErrSkipped can be returned by a Func to signal that the item was left untouched.
+// Such items are returned as they are and they are not rolled back.
+var ErrSkipped = errors.New("item skipped")
+
+// Func is applied to a single item. It returns the item as it looks
+// after the operation was performed, or an error.
+type Func func(item interface{}) (interface{}, error)
+
+// Options can be used to modify the way items are processed.
+type Options struct {
+	// MaxConcurrency limits the number of items being processed at the same time.
+	MaxConcurrency int
+
+	// Describe returns a human-readable description of the given item.
+	// It is used when the error hint is being generated.
+	Describe func(item interface{}) string
+
+	// Progress is the message printed along with the progress indicator.
+	// The progress indicator is disabled when Progress is empty.
+	Progress string
+}
+
+// Result represents the outcome of processing a single item.
+type Result struct {
+	// Index is the index of the item in the slice being processed.
+	Index int
+
+	// Item is the item as passed into the function.
+	Item interface{}
+
+	// Value is the item as returned by the function.
+	// It is the same as Item when the item was skipped.
+	Value interface{}
+
+	// Err is the error returned by the function, if any.
+	Err error
+
+	// Skipped is true when the function returned ErrSkipped.
+	Skipped bool
+}
+
+// Results are ordered the same way as the items being processed.
+type Results []*Result
+
+// Succeeded returns the results for the items that were processed successfully,
+// including the items that were skipped.
+func (results Results) Succeeded() Results {
+	return results.filter(func(r *Result) bool { return r.Err == nil })
+}
+
+// Changed returns the results for the items that were processed successfully,
+// excluding the items that were skipped.
+func (results Results) Changed() Results {
+	return results.filter(func(r *Result) bool { return r.Err == nil && !r.Skipped })
+}
+
+// Failed returns the results for the items that failed to be processed.
+func (results Results) Failed() Results {
+	return results.filter(func(r *Result) bool { return r.Err != nil })
+}
+
+// Values returns the values as returned by the function, in the original order.
+func (results Results) Values() []interface{} {
+	vs := make([]interface{}, 0, len(results))
+	for _, r := range results {
+		vs = append(vs, r.Value)
+	}
+	return vs
+}
+
+func (results Results) filter(pred func(*Result) bool) Results {
+	var rs Results
+	for _, r := range results {
+		if pred(r) {
+			rs = append(rs, r)
+		}
+	}
+	return rs
+}
+
+// Error is returned by Update in case some of the items failed to be processed.
+type Error struct {
+	// Results contains the results for all the items being processed.
+	Results Results
+
+	// RollbackResults contains the results for the items being rolled back.
+	RollbackResults Results
+}
+
+func (err *Error) Error() string {
+	return fmt.Sprintf("failed to process %v out of %v items",
+		len(err.Results.Failed()), len(err.Results))
+}
+
+// RollbackFailed returns true when some of the items failed to be rolled back.
+func (err *Error) RollbackFailed() bool {
+	return len(err.RollbackResults.Failed()) != 0
+}
+
+// Do applies the given function to all the items and returns the results.
+// The items are processed concurrently as specified by the options.
+func Do(items []interface{}, f Func, opts *Options) Results {
+	if opts == nil {
+		opts = &Options{}
+	}
+	concurrency := opts.MaxConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultMaxConcurrency
+	}
+
+	var (
+		results  = make(Results, len(items))
+		progress = newProgress(opts.Progress, len(items))
+		sem      = make(chan struct{}, concurrency)
+		wg       sync.WaitGroup
+	)
+	for i, item := range items {
+		wg.Add(1)
+		go func(i int, item interface{}) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			result := &Result{Index: i, Item: item}
+			value, err := f(item)
+			switch {
+			case err == ErrSkipped:
+				result.Value = item
+				result.Skipped = true
+			case err != nil:
+				result.Value = item
+				result.Err = err
+			default:
+				result.Value = value
+			}
+			results[i] = result
+			progress.Inc()
+		}(i, item)
+	}
+	wg.Wait()
+	progress.Done()
+
+	return results
+}
+
+// Update applies updateFunc to all the items.
+//
+// In case all the items are updated successfully, the results are returned
+// together with an action that applies rollbackFunc to the items that were changed.
+//
+// In case any of the items fails to be updated, rollbackFunc is applied to
+// the items that were changed successfully and an error is returned.
+// The root cause of the error is *Error, the hint lists the errors per item.
+//
+// rollbackFunc can be nil, in which case nothing is rolled back.
+func Update(
+	task string,
+	items []interface{},
+	updateFunc Func,
+	rollbackFunc Func,
+	opts *Options,
+) (Results, action.Action, error) {
+
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	// Apply the update function.
+	results := Do(items, updateFunc, opts)
+	changed := results.Changed()
+
+	// Roll back the items that were changed in case there is an error.
+	if len(results.Failed()) != 0 {
+		err := &Error{Results: results}
+		if rollbackFunc != nil && len(changed) != 0 {
+			rollbackOpts := *opts
+			rollbackOpts.Progress = ""
+			err.RollbackResults = Do(changed.Values(), rollbackFunc, &rollbackOpts)
+		}
+		return results, nil, errs.NewErrorWithHint(task, err, formatHint(err, opts))
+	}
+
+	// Return the rollback action on success.
+	act := action.ActionFunc(func() error {
+		if rollbackFunc == nil || len(changed) == 0 {
+			return nil
+		}
+
+		rollbackOpts := *opts
+		rollbackOpts.Progress = ""
+		rollbackResults := Do(changed.Values(), rollbackFunc, &rollbackOpts)
+		if len(rollbackResults.Failed()) != 0 {
+			err := &Error{Results: rollbackResults}
+			task := fmt.Sprintf("Revert changes made by task '%v'", task)
+			var hint bytes.Buffer
+			writeErrors(&hint, "Rollback Errors", rollbackResults.Failed(), opts)
+			return errs.NewErrorWithHint(task, err, hint.String())
+		}
+		return nil
+	})
+	return results, act, nil
+}
+
+func formatHint(err *Error, opts *Options) string {
+	var hint bytes.Buffer
+	writeErrors(&hint, "Update Errors", err.Results.Failed(), opts)
+	if err.RollbackFailed() {
+		writeErrors(&hint, "Rollback Errors", err.RollbackResults.Failed(), opts)
+	}
+	return hint.String()
+}
+
+func writeErrors(buf *bytes.Buffer, title string, failed Results, opts *Options) {
+	fmt.Fprintf(buf, "\n%v\n%v\n", title, underline(title))
+	for _, r := range failed {
+		if opts.Describe != nil {
+			fmt.Fprintf(buf, "%v: %v\n", opts.Describe(r.Item), r.Err)
+		} else {
+			fmt.Fprintln(buf, r.Err)
+		}
+	}
+	fmt.Fprintln(buf)
+}
+
+func underline(title string) string {
+	return string(bytes.Repeat([]byte("-"), len(title)))
+}
@@ -0,0 +1,181 @@
+package bulk
+
+import (
+	// Stdlib
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/errs"
+
+	// Vendor - testing framework
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var (
+	BeforeEach = ginkgo.BeforeEach
+	Context    = ginkgo.Context
+	Describe   = ginkgo.Describe
+	It         = ginkgo.It
+
+	BeEmpty       = gomega.BeEmpty
+	BeNil         = gomega.BeNil
+	BeNumerically = gomega.BeNumerically
+	BeTrue        = gomega.BeTrue
+	ConsistOf     = gomega.ConsistOf
+	Equal         = gomega.Equal
+	Expect        = gomega.Expect
+	HaveLen       = gomega.HaveLen
+)
+
+func TestBulk(t *testing.T) {
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "Bulk operations")
+}
+
+var _ = Describe("bulk operations", func() {
+
+	var (
+		items      []interface{}
+		lock       sync.Mutex
+		updated    []int
+		rolledBack []int
+	)
+
+	// update increments the item, failing for the given items.
+	update := func(failing ...int) Func {
+		return func(item interface{}) (interface{}, error) {
+			n := item.(int)
+			for _, f := range failing {
+				if n == f {
+					return nil, fmt.Errorf("item %v failed", n)
+				}
+			}
+			if n%10 == 0 {
+				return nil, ErrSkipped
+			}
+			lock.Lock()
+			updated = append(updated, n)
+			lock.Unlock()
+			return n + 100, nil
+		}
+	}
+
+	rollback := func(item interface{}) (interface{}, error) {
+		lock.Lock()
+		rolledBack = append(rolledBack, item.(int))
+		lock.Unlock()
+		return item.(int) - 100, nil
+	}
+
+	BeforeEach(func() {
+		items = []interface{}{1, 2, 3, 10}
+		updated = nil
+		rolledBack = nil
+	})
+
+	Context("when all items are updated successfully", func() {
+
+		It("should return the results in the original order", func() {
+			results, _, err := Update("Update items", items, update(), rollback, nil)
+			Expect(err).To(BeNil())
+			Expect(results.Values()).To(Equal([]interface{}{101, 102, 103, 10}))
+			Expect(results[3].Skipped).To(BeTrue())
+		})
+
+		It("should roll back just the items that were changed", func() {
+			_, act, err := Update("Update items", items, update(), rollback, nil)
+			Expect(err).To(BeNil())
+			Expect(act.Rollback()).To(BeNil())
+			Expect(rolledBack).To(ConsistOf(101, 102, 103))
+		})
+	})
+
+	Context("when some items fail to be updated", func() {
+
+		It("should roll back the items that succeeded", func() {
+			results, act, err := Update("Update items", items, update(2), rollback, nil)
+			Expect(act).To(BeNil())
+			Expect(err).NotTo(BeNil())
+			Expect(rolledBack).To(ConsistOf(101, 103))
+			Expect(results.Failed()).To(HaveLen(1))
+			Expect(results.Failed()[0].Item).To(Equal(2))
+		})
+
+		It("should return the structured results as the root cause", func() {
+			_, _, err := Update("Update items", items, update(1, 3), rollback, &Options{
+				Describe: func(item interface{}) string { return fmt.Sprintf("#%v", item) },
+			})
+			bulkErr, ok := errs.RootCause(err).(*Error)
+			Expect(ok).To(BeTrue())
+			Expect(bulkErr.Results.Failed()).To(HaveLen(2))
+			Expect(bulkErr.RollbackFailed()).To(Equal(false))
+
+			hint := err.(errs.Err).Hint()
+			Expect(strings.Contains(hint, "#1: item 1 failed")).To(BeTrue())
+			Expect(strings.Contains(hint, "#3: item 3 failed")).To(BeTrue())
+		})
+
+		It("should report the rollback errors as well", func() {
+			failingRollback := func(item interface{}) (interface{}, error) {
+				return nil, errors.New("rollback failed")
+			}
+			_, _, err := Update("Update items", items, update(2), failingRollback, nil)
+			bulkErr := errs.RootCause(err).(*Error)
+			Expect(bulkErr.RollbackFailed()).To(BeTrue())
+			Expect(strings.Contains(err.(errs.Err).Hint(), "Rollback Errors")).To(BeTrue())
+		})
+	})
+
+	Context("when the concurrency is limited", func() {
+
+		It("should never process more items at once", func() {
+			var (
+				inFlight    int
+				maxInFlight int
+			)
+			items := make([]interface{}, 20)
+			for i := range items {
+				items[i] = i
+			}
+			Do(items, func(item interface{}) (interface{}, error) {
+				lock.Lock()
+				inFlight++
+				if inFlight > maxInFlight {
+					maxInFlight = inFlight
+				}
+				lock.Unlock()
+
+				time.Sleep(time.Millisecond)
+
+				lock.Lock()
+				inFlight--
+				lock.Unlock()
+				return item, nil
+			}, &Options{MaxConcurrency: 3})
+
+			Expect(maxInFlight).To(BeNumerically("<=", 3))
+		})
+	})
+
+	Context("when splitting items into batches", func() {
+
+		It("should keep the order and the last batch can be shorter", func() {
+			batches := Batches([]interface{}{1, 2, 3, 4, 5}, 2)
+			Expect(batches).To(Equal([]interface{}{
+				[]interface{}{1, 2},
+				[]interface{}{3, 4},
+				[]interface{}{5},
+			}))
+		})
+
+		It("should return no batches for no items", func() {
+			Expect(Batches(nil, 2)).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,52 @@
+package bulk
+
+import (
+	// Stdlib
+	"sync"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/log"
+)
+
+// ProgressThreshold is the number of items that must be processed
+// for the progress indicator to be shown.
+const ProgressThreshold = 10
+
+type progress struct {
+	msg   string
+	total int
+	done  int
+	lock  sync.Mutex
+}
+
+// newProgress returns a progress indicator, or nil in case
+// the progress indicator is not supposed to be shown.
+func newProgress(msg string, total int) *progress {
+	if msg == "" || total < ProgressThreshold {
+		return nil
+	}
+	p := &progress{msg: msg, total: total}
+	p.print()
+	return p
+}
+
+func (p *progress) Inc() {
+	if p == nil {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.done++
+	p.print()
+}
+
+func (p *progress) Done() {
+	if p == nil {
+		return
+	}
+	log.Print("\n")
+}
+
+func (p *progress) print() {
+	log.Printf("\r[PROGRESS] %v: %v/%v", p.msg, p.done, p.total)
+}
@@ -1,6 +1,11 @@
 package common
 
 import (
+	// Stdlib
+	"fmt"
+	"net/http"
+	"time"
+
 	// Internal
 	"github.com/salsaflow/salsaflow/action"
 	"github.com/salsaflow/salsaflow/config/loader"
@@ -28,6 +33,19 @@ type CodeReviewTool interface {
 	PostReviewFollowupMessage() string
 }
 
+// StackedReviewPoster can be optionally implemented by code review tools
+// supporting review requests that depend on each other. It is used by `review post -stack`.
+type StackedReviewPoster interface {
+	// PostStackedReviewRequest posts the given commits, all associated with the same story,
+	// the same way PostReviewRequests does. The review request is marked as depending
+	// on the review request dependsOn unless it is empty. The review request ID is returned.
+	PostStackedReviewRequest(
+		ctxs []*ReviewContext,
+		dependsOn string,
+		opts map[string]interface{},
+	) (rrid string, err error)
+}
+
 type Release interface {
 	// Initialise is called during `release start`
 	// when a new version string is committed into trunk.
@@ -43,3 +61,186 @@ type Release interface {
 	// Close closes the given release in the code review tool.
 	Close() (rollback action.Action, err error)
 }
+
+// Review requests -------------------------------------------------------------
+
+// ReviewRequest represents a review request in the code review tool,
+// e.g. a GitHub review issue.
+type ReviewRequest struct {
+	// Id is the review request ID as understood by the code review tool,
+	// i.e. the RRID that can be passed to `review post -fixes`.
+	Id    string
+	Title string
+	URL   string
+
+	// StoryTag is the Story-Id tag of the story being reviewed.
+	// It is empty in case the review request is not associated with any story.
+	StoryTag string
+
+	// Closed is set in case the review is finished.
+	Closed bool
+
+	// Assignee is the reviewer the review request is assigned to.
+	// It is empty in case the review request is not assigned.
+	Assignee string
+
+	// CreatedAt is the time the review request was created.
+	CreatedAt time.Time
+
+	Commits  []*ReviewCommit
+	Blockers []*ReviewBlocker
+}
+
+// Approved returns true when all the commits are reviewed
+// and all the review blockers are fixed.
+func (rr *ReviewRequest) Approved() bool {
+	return rr.UnreviewedCommits() == 0 && rr.OpenBlockers() == 0
+}
+
+// UnreviewedCommits returns the number of the commits not reviewed yet.
+func (rr *ReviewRequest) UnreviewedCommits() int {
+	var n int
+	for _, commit := range rr.Commits {
+		if !commit.Reviewed {
+			n++
+		}
+	}
+	return n
+}
+
+// OpenBlockers returns the number of the review blockers not fixed yet.
+func (rr *ReviewRequest) OpenBlockers() int {
+	var n int
+	for _, blocker := range rr.Blockers {
+		if !blocker.Fixed {
+			n++
+		}
+	}
+	return n
+}
+
+// Plural formats n followed by the noun, adding an s unless n is 1,
+// e.g. the counts returned by UnreviewedCommits and OpenBlockers.
+func Plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%v %vs", n, noun)
+}
+
+// ReviewCommit represents a commit being reviewed in a review request.
+type ReviewCommit struct {
+	SHA      string
+	Title    string
+	Reviewed bool
+}
+
+// ReviewBlocker represents an issue raised by the reviewer
+// that must be fixed before the review request can be closed.
+type ReviewBlocker struct {
+	// Number is the blocker number, unique within the review request.
+	Number     int
+	Fixed      bool
+	CommitSHA  string
+	Summary    string
+	CommentURL string
+}
+
+// ReviewBlockerManager can be optionally implemented by code review tools
+// supporting review blockers. It is used by `review blocker` subcommands.
+type ReviewBlockerManager interface {
+	// AddReviewBlocker opens a new review blocker for the given commit
+	// in the review request the commit is being reviewed in.
+	AddReviewBlocker(commitSHA, summary string) (*ReviewRequest, *ReviewBlocker, error)
+
+	// ListReviewBlockers returns the review request for the given story
+	// or all open review requests in case the story ID is empty.
+	ListReviewBlockers(storyId string) ([]*ReviewRequest, error)
+
+	// FixReviewBlocker marks the given review blocker as fixed.
+	//
+	// In case rrid is empty, the review request is the one for the story
+	// the fixing commit is associated with. fixedBy is optional, though.
+	FixReviewBlocker(rrid string, blockerNumber int, fixedBy string) (*ReviewRequest, *ReviewBlocker, error)
+}
+
+// ReviewApprover can be optionally implemented by code review tools
+// tracking the review progress. It is used by `review status` and `review approve`.
+type ReviewApprover interface {
+	// ReviewStatus returns the review request for the given story or commit.
+	// All open review requests are returned in case both are empty.
+	ReviewStatus(storyId, commitSHA string) ([]*ReviewRequest, error)
+
+	// ApproveCommits marks the given commits as reviewed. All the commits
+	// in the review request are marked in case commitSHAs is empty.
+	//
+	// In case rrid is empty, the review request is the one the first commit
+	// is being reviewed in. Once all the commits are reviewed and all
+	// the blockers are fixed, the review request is closed.
+	ApproveCommits(rrid string, commitSHAs []string) (*ReviewRequest, error)
+}
+
+// ReviewRequestGetter can be optionally implemented by code review tools
+// to fetch review requests by ID. It is used by `review show`.
+type ReviewRequestGetter interface {
+	// GetReviewRequest returns the review request with the given ID.
+	// ErrReviewRequestNotFound is returned in case there is no such review request.
+	GetReviewRequest(rrid string) (*ReviewRequest, error)
+}
+
+// StaleReviewReporter can be optionally implemented by code review tools
+// to report the review requests nobody has taken care of. It is used by `review stale`.
+type StaleReviewReporter interface {
+	// ListStaleReviewRequests returns the open review requests
+	// in the current review cycle created before the given time.
+	ListStaleReviewRequests(createdBefore time.Time) ([]*ReviewRequest, error)
+
+	// RemindReviewer posts a reminder into the review request
+	// mentioning the reviewer the review request is assigned to.
+	RemindReviewer(rr *ReviewRequest) error
+}
+
+// ReviewActivity describes the history of a review request.
+type ReviewActivity struct {
+	ReviewRequest *ReviewRequest
+
+	// FirstResponseAt is the time somebody else than the author
+	// first reacted to the review request. It is zero when nobody has yet.
+	FirstResponseAt time.Time
+
+	// ClosedAt is the time the review request was closed.
+	// It is zero when the review request is still open.
+	ClosedAt time.Time
+
+	// Rounds is the number of times commits were posted into the review request,
+	// i.e. 1 plus the number of follow-ups posted using `review post -fixes`.
+	Rounds int
+}
+
+// ReviewStatsReporter can be optionally implemented by code review tools
+// to report the review activity. It is used by `review stats`.
+type ReviewStatsReporter interface {
+	// ListReviewActivity returns the activity for the review requests
+	// created within the given time range.
+	ListReviewActivity(since, until time.Time) ([]*ReviewActivity, error)
+}
+
+// WebhookReceiver can be optionally implemented by code review tools
+// able to process the webhooks sent by the code review service.
+// It is used by `serve`.
+type WebhookReceiver interface {
+	// WebhookHandler returns the handler processing the webhooks.
+	// The payloads are verified using the given secret and the stories
+	// are updated in the given issue tracker.
+	WebhookHandler(secret string, tracker IssueTracker) (WebhookHandler, error)
+}
+
+// WebhookHandler processes the webhooks sent by the code review service.
+type WebhookHandler interface {
+	http.Handler
+
+	// Wait blocks until the webhooks accepted are processed.
+	// The webhooks can be processed in the background, so Wait
+	// is to be called before exiting once the HTTP server is stopped.
+	Wait()
+}
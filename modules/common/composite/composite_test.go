@@ -0,0 +1,216 @@
+package composite
+
+import (
+	// Stdlib
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/action"
+	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/version"
+
+	// Vendor - testing framework
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var (
+	BeforeEach = ginkgo.BeforeEach
+	Context    = ginkgo.Context
+	Describe   = ginkgo.Describe
+	It         = ginkgo.It
+
+	BeNil        = gomega.BeNil
+	BeTrue       = gomega.BeTrue
+	BeFalse      = gomega.BeFalse
+	Equal        = gomega.Equal
+	Expect       = gomega.Expect
+	HaveOccurred = gomega.HaveOccurred
+)
+
+func TestComposite(t *testing.T) {
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "Composite issue tracker")
+}
+
+// Fake issue tracker ----------------------------------------------------------
+
+type fakeTracker struct {
+	common.IssueTracker
+
+	name      string
+	tagRegexp *regexp.Regexp
+	log       *[]string
+	failStage bool
+}
+
+func (tracker *fakeTracker) ServiceName() string {
+	return tracker.name
+}
+
+func (tracker *fakeTracker) StoryTagToReadableStoryId(tag string) (string, error) {
+	if !tracker.tagRegexp.MatchString(tag) {
+		return "", fmt.Errorf("invalid %v tag: %v", tracker.name, tag)
+	}
+	return tag[strings.LastIndexAny(tag, "/#"):], nil
+}
+
+func (tracker *fakeTracker) ListStoriesByTag(tags []string) ([]common.Story, error) {
+	*tracker.log = append(*tracker.log, fmt.Sprintf("%v: list %v", tracker.name, tags))
+	stories := make([]common.Story, len(tags))
+	for i, tag := range tags {
+		stories[i] = &fakeStory{tag: tag}
+	}
+	return stories, nil
+}
+
+func (tracker *fakeTracker) RunningRelease(v *version.Version) common.RunningRelease {
+	return &fakeRelease{tracker}
+}
+
+type fakeStory struct {
+	common.Story
+
+	tag string
+}
+
+func (story *fakeStory) Tag() string {
+	return story.tag
+}
+
+type fakeRelease struct {
+	tracker *fakeTracker
+}
+
+func (release *fakeRelease) Version() *version.Version        { return nil }
+func (release *fakeRelease) Stories() ([]common.Story, error) { return nil, nil }
+func (release *fakeRelease) EnsureStageable() error           { return nil }
+func (release *fakeRelease) EnsureClosable() error            { return nil }
+func (release *fakeRelease) Close() (action.Action, error)    { return action.Noop, nil }
+
+func (release *fakeRelease) Stage() (action.Action, error) {
+	tracker := release.tracker
+	if tracker.failStage {
+		return nil, errors.New("stage failed")
+	}
+	*tracker.log = append(*tracker.log, tracker.name+": stage")
+	return action.ActionFunc(func() error {
+		*tracker.log = append(*tracker.log, tracker.name+": unstage")
+		return nil
+	}), nil
+}
+
+// Tests -----------------------------------------------------------------------
+
+var _ = Describe("composite issue tracker", func() {
+
+	var (
+		log     []string
+		pivotal *fakeTracker
+		github  *fakeTracker
+		jira    *fakeTracker
+		tracker common.IssueTracker
+	)
+
+	BeforeEach(func() {
+		log = nil
+		pivotal = &fakeTracker{
+			name:      "pivotal",
+			tagRegexp: regexp.MustCompile(`^[0-9]+/stories/[0-9]+$`),
+			log:       &log,
+		}
+		github = &fakeTracker{
+			name:      "github",
+			tagRegexp: regexp.MustCompile(`^[^/]+/[^#]+#[0-9]+$`),
+			log:       &log,
+		}
+		jira = &fakeTracker{
+			name:      "jira",
+			tagRegexp: regexp.MustCompile(`^[A-Z]+-[0-9]+$`),
+			log:       &log,
+		}
+		tracker = NewIssueTracker(pivotal,
+			&Backend{Tracker: github},
+			&Backend{Tracker: jira, StoryTagPattern: regexp.MustCompile(`^CORE-`)})
+	})
+
+	Describe("StoryTagToReadableStoryId", func() {
+
+		It("routes the tags claimed by additional backends", func() {
+			id, err := tracker.StoryTagToReadableStoryId("owner/repo#12")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(id).To(Equal("#12"))
+		})
+
+		It("routes the tags not claimed by any backend to the primary tracker", func() {
+			id, err := tracker.StoryTagToReadableStoryId("123/stories/456")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(id).To(Equal("/456"))
+
+			// Not matching the pattern for jira, so the primary tracker fails.
+			_, err = tracker.StoryTagToReadableStoryId("WEB-1")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("invalid pivotal tag: WEB-1"))
+		})
+	})
+
+	Describe("ListStoriesByTag", func() {
+
+		It("groups the tags by backend and keeps the order of the stories", func() {
+			tags := []string{"1/stories/1", "o/r#1", "CORE-1", "2/stories/2", "o/r#2"}
+			stories, err := tracker.ListStoriesByTag(tags)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(log).To(Equal([]string{
+				"pivotal: list [1/stories/1 2/stories/2]",
+				"github: list [o/r#1 o/r#2]",
+				"jira: list [CORE-1]",
+			}))
+			for i, story := range stories {
+				Expect(story.Tag()).To(Equal(tags[i]))
+			}
+		})
+	})
+
+	Describe("RunningRelease", func() {
+
+		It("stages the release in every issue tracker", func() {
+			act, err := tracker.RunningRelease(nil).Stage()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(log).To(Equal([]string{"pivotal: stage", "github: stage", "jira: stage"}))
+
+			Expect(act.Rollback()).To(BeNil())
+			Expect(log[3:]).To(Equal([]string{"jira: unstage", "github: unstage", "pivotal: unstage"}))
+		})
+
+		Context("staging fails for one of the issue trackers", func() {
+
+			It("rolls back the issue trackers staged already", func() {
+				jira.failStage = true
+				_, err := tracker.RunningRelease(nil).Stage()
+				Expect(err).To(HaveOccurred())
+				Expect(log).To(Equal([]string{
+					"pivotal: stage", "github: stage", "github: unstage", "pivotal: unstage",
+				}))
+			})
+		})
+	})
+
+	Describe("Backend", func() {
+
+		It("requires both the prefix and the pattern to match when both are set", func() {
+			backend := &Backend{
+				Tracker:         jira,
+				StoryTagPrefix:  "CORE-",
+				StoryTagPattern: regexp.MustCompile(`[0-9]$`),
+			}
+			Expect(backend.Claims("CORE-1")).To(BeTrue())
+			Expect(backend.Claims("CORE-X")).To(BeFalse())
+			Expect(backend.Claims("WEB-1")).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,239 @@
+package composite
+
+import (
+	// Stdlib
+	"fmt"
+	"regexp"
+	"strings"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/version"
+)
+
+// Backend represents an issue tracker that is a part of the composite issue tracker,
+// together with the rule specifying what Story-Id tags the backend claims.
+type Backend struct {
+	Tracker common.IssueTracker
+
+	// StoryTagPrefix, when not empty, makes the backend claim
+	// the Story-Id tags starting with the given prefix.
+	StoryTagPrefix string
+
+	// StoryTagPattern, when not nil, makes the backend claim
+	// the Story-Id tags matching the given regular expression.
+	StoryTagPattern *regexp.Regexp
+}
+
+// Claims returns true in case the given Story-Id tag belongs to the backend.
+//
+// In case no rule is set for the backend, the tag is claimed
+// when the underlying issue tracker can parse it.
+func (backend *Backend) Claims(tag string) bool {
+	switch {
+	case backend.StoryTagPrefix != "" && backend.StoryTagPattern != nil:
+		return strings.HasPrefix(tag, backend.StoryTagPrefix) &&
+			backend.StoryTagPattern.MatchString(tag)
+	case backend.StoryTagPrefix != "":
+		return strings.HasPrefix(tag, backend.StoryTagPrefix)
+	case backend.StoryTagPattern != nil:
+		return backend.StoryTagPattern.MatchString(tag)
+	default:
+		_, err := backend.Tracker.StoryTagToReadableStoryId(tag)
+		return err == nil
+	}
+}
+
+type issueTracker struct {
+	primary    common.IssueTracker
+	additional []*Backend
+}
+
+// NewIssueTracker returns an issue tracker that combines the given issue trackers.
+//
+// Story-Id tags are routed to the first additional backend claiming the tag,
+// the primary issue tracker handles the tags not claimed by any additional backend.
+// The methods not related to any particular story are called for every issue tracker
+// and the results are combined.
+func NewIssueTracker(primary common.IssueTracker, additional ...*Backend) common.IssueTracker {
+	return &issueTracker{primary, additional}
+}
+
+// ServiceName is a part of common.IssueTracker interface.
+func (tracker *issueTracker) ServiceName() string {
+	names := make([]string, 0, len(tracker.additional)+1)
+	for _, t := range tracker.trackers() {
+		names = append(names, t.ServiceName())
+	}
+	return strings.Join(names, ", ")
+}
+
+// CurrentUser is a part of common.IssueTracker interface.
+//
+// The user record returned is the one from the primary issue tracker.
+// Use the issue tracker associated with the story in question,
+// i.e. story.IssueTracker(), to get the right user record for the given story.
+func (tracker *issueTracker) CurrentUser() (common.User, error) {
+	return tracker.primary.CurrentUser()
+}
+
+// StartableStories is a part of common.IssueTracker interface.
+func (tracker *issueTracker) StartableStories() ([]common.Story, error) {
+	return tracker.collectStories(func(t common.IssueTracker) ([]common.Story, error) {
+		return t.StartableStories()
+	})
+}
+
+// ReviewableStories is a part of common.IssueTracker interface.
+func (tracker *issueTracker) ReviewableStories() ([]common.Story, error) {
+	return tracker.collectStories(func(t common.IssueTracker) ([]common.Story, error) {
+		return t.ReviewableStories()
+	})
+}
+
+// ReviewedStories is a part of common.IssueTracker interface.
+func (tracker *issueTracker) ReviewedStories() ([]common.Story, error) {
+	return tracker.collectStories(func(t common.IssueTracker) ([]common.Story, error) {
+		return t.ReviewedStories()
+	})
+}
+
+// ListStoriesByTag is a part of common.IssueTracker interface.
+//
+// The tags are grouped by the issue tracker claiming them,
+// but the stories are returned in the same order as the tags.
+func (tracker *issueTracker) ListStoriesByTag(tags []string) ([]common.Story, error) {
+	// Group the tags by the issue tracker.
+	type group struct {
+		tags    []string
+		indexes []int
+	}
+	var (
+		trackers = tracker.trackers()
+		groups   = make([]group, len(trackers))
+	)
+	for i, tag := range tags {
+		g := &groups[tracker.route(tag)]
+		g.tags = append(g.tags, tag)
+		g.indexes = append(g.indexes, i)
+	}
+
+	// Fetch the stories and put them into the right place.
+	stories := make([]common.Story, len(tags))
+	for i, g := range groups {
+		if len(g.tags) == 0 {
+			continue
+		}
+		ss, err := trackers[i].ListStoriesByTag(g.tags)
+		if err != nil {
+			return nil, err
+		}
+		for j, story := range ss {
+			stories[g.indexes[j]] = story
+		}
+	}
+	return stories, nil
+}
+
+// ListStoriesByRelease is a part of common.IssueTracker interface.
+func (tracker *issueTracker) ListStoriesByRelease(v *version.Version) ([]common.Story, error) {
+	return tracker.collectStories(func(t common.IssueTracker) ([]common.Story, error) {
+		return t.ListStoriesByRelease(v)
+	})
+}
+
+// NextRelease is a part of common.IssueTracker interface.
+func (tracker *issueTracker) NextRelease(
+	trunkVersion *version.Version,
+	nextTrunkVersion *version.Version,
+) common.NextRelease {
+
+	releases := make([]common.NextRelease, 0, len(tracker.additional)+1)
+	for _, t := range tracker.trackers() {
+		releases = append(releases, t.NextRelease(trunkVersion, nextTrunkVersion))
+	}
+	return &nextRelease{releases}
+}
+
+// RunningRelease is a part of common.IssueTracker interface.
+func (tracker *issueTracker) RunningRelease(v *version.Version) common.RunningRelease {
+	releases := make([]common.RunningRelease, 0, len(tracker.additional)+1)
+	for _, t := range tracker.trackers() {
+		releases = append(releases, t.RunningRelease(v))
+	}
+	return &runningRelease{v, releases}
+}
+
+// OpenStory is a part of common.IssueTracker interface.
+//
+// Apart from the readable story ID, which is passed to the primary issue tracker,
+// the whole Story-Id tag can be used to open a story in any of the issue trackers.
+func (tracker *issueTracker) OpenStory(storyId string) error {
+	for _, backend := range tracker.additional {
+		if backend.Claims(storyId) {
+			readableId, err := backend.Tracker.StoryTagToReadableStoryId(storyId)
+			if err != nil {
+				return err
+			}
+			return backend.Tracker.OpenStory(readableId)
+		}
+	}
+
+	if readableId, err := tracker.primary.StoryTagToReadableStoryId(storyId); err == nil {
+		storyId = readableId
+	}
+	return tracker.primary.OpenStory(storyId)
+}
+
+// StoryTagToReadableStoryId is a part of common.IssueTracker interface.
+func (tracker *issueTracker) StoryTagToReadableStoryId(tag string) (storyId string, err error) {
+	return tracker.trackers()[tracker.route(tag)].StoryTagToReadableStoryId(tag)
+}
+
+// CheckHealth implements common.HealthChecker.
+// All the issue trackers combined are checked.
+func (tracker *issueTracker) CheckHealth() error {
+	for _, t := range tracker.trackers() {
+		if err := common.CheckIssueTrackerHealth(t); err != nil {
+			return errs.NewError(fmt.Sprintf("Check %v", t.ServiceName()), err)
+		}
+	}
+	return nil
+}
+
+// route returns the index of the issue tracker responsible for the given Story-Id tag,
+// the index pointing into the slice as returned by trackers().
+func (tracker *issueTracker) route(tag string) int {
+	for i, backend := range tracker.additional {
+		if backend.Claims(tag) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// trackers returns all issue trackers, the primary one being the first.
+func (tracker *issueTracker) trackers() []common.IssueTracker {
+	ts := make([]common.IssueTracker, 0, len(tracker.additional)+1)
+	ts = append(ts, tracker.primary)
+	for _, backend := range tracker.additional {
+		ts = append(ts, backend.Tracker)
+	}
+	return ts
+}
+
+func (tracker *issueTracker) collectStories(
+	list func(common.IssueTracker) ([]common.Story, error),
+) ([]common.Story, error) {
+
+	var stories []common.Story
+	for _, t := range tracker.trackers() {
+		ss, err := list(t)
+		if err != nil {
+			return nil, err
+		}
+		stories = append(stories, ss...)
+	}
+	return stories, nil
+}
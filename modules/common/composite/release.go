@@ -0,0 +1,116 @@
+package composite
+
+import (
+	// Internal
+	"github.com/salsaflow/salsaflow/action"
+	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/version"
+)
+
+// nextRelease ----------------------------------------------------------------
+
+type nextRelease struct {
+	releases []common.NextRelease
+}
+
+// PromptUserToConfirmStart is a part of common.NextRelease interface.
+//
+// The release is only started when confirmed for every issue tracker.
+func (release *nextRelease) PromptUserToConfirmStart() (bool, error) {
+	for _, r := range release.releases {
+		confirmed, err := r.PromptUserToConfirmStart()
+		if err != nil || !confirmed {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// Start is a part of common.NextRelease interface.
+func (release *nextRelease) Start() (action.Action, error) {
+	var starts []func() (action.Action, error)
+	for _, r := range release.releases {
+		starts = append(starts, r.Start)
+	}
+	return runAll(starts)
+}
+
+// runningRelease -------------------------------------------------------------
+
+type runningRelease struct {
+	version  *version.Version
+	releases []common.RunningRelease
+}
+
+// Version is a part of common.RunningRelease interface.
+func (release *runningRelease) Version() *version.Version {
+	return release.version
+}
+
+// Stories is a part of common.RunningRelease interface.
+func (release *runningRelease) Stories() ([]common.Story, error) {
+	var stories []common.Story
+	for _, r := range release.releases {
+		ss, err := r.Stories()
+		if err != nil {
+			return nil, err
+		}
+		stories = append(stories, ss...)
+	}
+	return stories, nil
+}
+
+// EnsureStageable is a part of common.RunningRelease interface.
+func (release *runningRelease) EnsureStageable() error {
+	for _, r := range release.releases {
+		if err := r.EnsureStageable(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stage is a part of common.RunningRelease interface.
+func (release *runningRelease) Stage() (action.Action, error) {
+	var stages []func() (action.Action, error)
+	for _, r := range release.releases {
+		stages = append(stages, r.Stage)
+	}
+	return runAll(stages)
+}
+
+// EnsureClosable is a part of common.RunningRelease interface.
+func (release *runningRelease) EnsureClosable() error {
+	for _, r := range release.releases {
+		if err := r.EnsureClosable(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close is a part of common.RunningRelease interface.
+func (release *runningRelease) Close() (action.Action, error) {
+	var closes []func() (action.Action, error)
+	for _, r := range release.releases {
+		closes = append(closes, r.Close)
+	}
+	return runAll(closes)
+}
+
+// runAll runs the given functions one by one. In case any of them fails,
+// the functions that have already been run successfully are rolled back.
+// Otherwise an action rolling back all the functions is returned.
+func runAll(funcs []func() (action.Action, error)) (_ action.Action, err error) {
+	chain := action.NewActionChain()
+	defer chain.RollbackOnError(&err)
+
+	for _, f := range funcs {
+		act, ex := f()
+		if ex != nil {
+			return nil, ex
+		}
+		chain.Push(act)
+	}
+	return chain, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	// Internal
+	"github.com/salsaflow/salsaflow/errs"
 	"github.com/salsaflow/salsaflow/version"
 )
 
@@ -17,6 +18,11 @@ var ErrNotStageable = errors.New("release cannot be staged")
 // when the given release cannot be released yet.
 var ErrNotClosable = errors.New("release cannot be closed")
 
+func init() {
+	errs.RegisterExitCodeForError(errs.ExitCodeNotStageable, ErrNotStageable)
+	errs.RegisterExitCodeForError(errs.ExitCodeNotClosable, ErrNotClosable)
+}
+
 // ErrReleaseNotFound shall be returned from GenerateReleaseNotes
 // or perhaps any other function when the given release was not found.
 type ErrReleaseNotFound struct {
@@ -26,3 +32,13 @@ type ErrReleaseNotFound struct {
 func (err *ErrReleaseNotFound) Error() string {
 	return fmt.Sprintf("release %v not found", err.Version.BaseString())
 }
+
+// ErrReviewRequestNotFound shall be returned by the code review tools
+// when the review request that was requested does not exist.
+type ErrReviewRequestNotFound struct {
+	What string
+}
+
+func (err *ErrReviewRequestNotFound) Error() string {
+	return fmt.Sprintf("review request not found for %v", err.What)
+}
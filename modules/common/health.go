@@ -0,0 +1,23 @@
+package common
+
+// HealthChecker can be optionally implemented by issue trackers,
+// code review tools and release notes managers.
+//
+// CheckHealth is used by `repo doctor` to make sure the service is reachable,
+// the credentials are valid and the resources configured, e.g. the project,
+// are accessible. An error is returned in case that is not the case.
+type HealthChecker interface {
+	CheckHealth() error
+}
+
+// CheckIssueTrackerHealth checks the given issue tracker.
+//
+// In case the issue tracker does not implement HealthChecker,
+// the current user is fetched to at least check the credentials.
+func CheckIssueTrackerHealth(tracker IssueTracker) error {
+	if checker, ok := tracker.(HealthChecker); ok {
+		return checker.CheckHealth()
+	}
+	_, err := tracker.CurrentUser()
+	return err
+}
@@ -135,6 +135,13 @@ type Story interface {
 	IssueTracker() IssueTracker
 }
 
+// ReviewedMarker can be optionally implemented by stories.
+// It is used by `review approve` and `serve` to mark the story as reviewed
+// once the associated review request is closed.
+type ReviewedMarker interface {
+	MarkAsReviewed() (action.Action, error)
+}
+
 // Stories implement sort.Interface
 type Stories []Story
 
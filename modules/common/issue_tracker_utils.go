@@ -1,5 +1,14 @@
 package common
 
+import (
+	// Stdlib
+	"fmt"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/log"
+)
+
 // FilterStories is the filter function implemented for []common.Story type.
 func FilterStories(stories []Story, filter func(Story) bool) []Story {
 	ss := make([]Story, 0, len(stories))
@@ -10,3 +19,72 @@ func FilterStories(stories []Story, filter func(Story) bool) []Story {
 	}
 	return ss
 }
+
+// CurrentUserAssignee returns a function that can be used to check
+// whether the current user is assigned to the given story.
+//
+// The current user record is fetched from the issue tracker the story
+// is associated with, which matters when multiple issue trackers are active.
+// The records are cached so that every issue tracker is asked only once.
+func CurrentUserAssignee() func(Story) (bool, error) {
+	users := make(map[string]User)
+	return func(story Story) (bool, error) {
+		tracker := story.IssueTracker()
+		user, ok := users[tracker.ServiceName()]
+		if !ok {
+			var err error
+			user, err = tracker.CurrentUser()
+			if err != nil {
+				return false, err
+			}
+			users[tracker.ServiceName()] = user
+		}
+
+		for _, assignee := range story.Assignees() {
+			if assignee.Id() == user.Id() {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+// MarkStoryAsReviewed marks the story with the given Story-Id tag as reviewed
+// and returns the story, nil in case the story is left alone.
+//
+// The story is only marked as reviewed while it is being implemented
+// or implemented. Otherwise the review phase is already over for the story,
+// e.g. because the review is being skipped or the story is tested already.
+func MarkStoryAsReviewed(tracker IssueTracker, storyTag string) (Story, error) {
+	task := fmt.Sprintf("Fetch the story for Story-Id tag %v", storyTag)
+	stories, err := tracker.ListStoriesByTag([]string{storyTag})
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+	if len(stories) == 0 || stories[0] == nil {
+		log.Warn(fmt.Sprintf("Story not found for Story-Id tag %v", storyTag))
+		return nil, nil
+	}
+	story := stories[0]
+
+	switch state := story.State(); state {
+	case StoryStateBeingImplemented, StoryStateImplemented:
+	default:
+		log.Log(fmt.Sprintf("Story %v is %v, not marking it as reviewed", story.ReadableId(), state))
+		return nil, nil
+	}
+
+	marker, ok := story.(ReviewedMarker)
+	if !ok {
+		log.Warn(fmt.Sprintf(
+			"%v does not support marking stories as reviewed", tracker.ServiceName()))
+		return nil, nil
+	}
+
+	task = fmt.Sprintf("Mark story %v as reviewed", story.ReadableId())
+	log.Run(task)
+	if _, err := marker.MarkAsReviewed(); err != nil {
+		return nil, errs.NewError(task, err)
+	}
+	return story, nil
+}
@@ -2,14 +2,12 @@ package github
 
 import (
 	// Stdlib
-	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
 
 	// Internal
 	"github.com/salsaflow/salsaflow/action"
-	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/modules/common/bulk"
 
 	// Vendor
 	"github.com/google/go-github/github"
@@ -17,16 +15,12 @@ import (
 
 type issueUpdateFunc func(client *github.Client, owner, repo string, issue *github.Issue) (*github.Issue, error)
 
-type issueUpdateResult struct {
-	issue *github.Issue
-	err   error
-}
-
 // updateIssues can be used to update multiple issues at once concurrently.
 // It basically calls the given update function on all given issues and
-// collects the results. In case there is any error, updateIssues tries
-// to revert partial changes. The error returned contains the complete list
-// of API call errors as the error hint.
+// collects the results. In case there is any error, updateIssues reverts
+// the changes for the issues that were updated successfully. The error
+// returned contains the complete list of API call errors as the error hint,
+// the root cause being *bulk.Error containing the results per issue.
 func updateIssues(
 	client *github.Client,
 	owner string,
@@ -34,90 +28,48 @@ func updateIssues(
 	issues []*github.Issue,
 	updateFunc issueUpdateFunc,
 	rollbackFunc issueUpdateFunc,
+	opts *bulk.Options,
 ) ([]*github.Issue, action.Action, error) {
 
-	// Prepare a function that can be used to apply the given updateFunc.
-	// It is later used to both update issues and revert changes.
-	update := func(
-		issues []*github.Issue,
-		updateFunc issueUpdateFunc,
-	) (newIssues []*github.Issue, errHint string, err error) {
-
-		// Send the requests concurrently.
-		retCh := make(chan *issueUpdateResult, len(issues))
-		for _, issue := range issues {
-			go func(issue *github.Issue) {
-				var (
-					updatedIssue *github.Issue
-					err          error
-				)
-				withRequestAllocated(func() {
-					updatedIssue, err = updateFunc(client, owner, repo, issue)
-				})
-				if err == nil {
-					// On success, return the updated story.
-					retCh <- &issueUpdateResult{updatedIssue, nil}
-				} else {
-					// On error, keep the original story, add the error.
-					retCh <- &issueUpdateResult{nil, err}
-				}
-			}(issue)
+	// Turn the issue update function into a bulk function.
+	bulkFunc := func(updateFunc issueUpdateFunc) bulk.Func {
+		if updateFunc == nil {
+			return nil
 		}
-
-		// Wait for the requests to complete.
-		var (
-			updatedIssues = make([]*github.Issue, 0, len(issues))
-			errFailed     = errors.New("failed to update GitHub issues")
-			stderr        bytes.Buffer
-		)
-		for range issues {
-			if ret := <-retCh; ret.err != nil {
-				fmt.Fprintln(&stderr, ret.err)
-				err = errFailed
-			} else {
-				updatedIssues = append(updatedIssues, ret.issue)
-			}
+		return func(item interface{}) (updated interface{}, err error) {
+			withRequestAllocated(func() {
+				updated, err = updateFunc(client, owner, repo, item.(*github.Issue))
+			})
+			return
 		}
+	}
 
-		return updatedIssues, stderr.String(), err
+	items := make([]interface{}, 0, len(issues))
+	for _, issue := range issues {
+		items = append(items, issue)
 	}
 
-	// Apply the update function.
-	updatedIssues, errHint, err := update(issues, updateFunc)
+	// Update the issues.
+	results, act, err := bulk.Update(
+		"Update GitHub issues", items, bulkFunc(updateFunc), bulkFunc(rollbackFunc), opts)
 	if err != nil {
-		// In case there is an error, generate the error hint.
-		var errHintAcc bytes.Buffer
-		errHintAcc.WriteString("\nUpdate Errors\n-------------\n")
-		errHintAcc.WriteString(errHint)
-		errHintAcc.WriteString("\n")
-
-		// Revert the changes.
-		_, errHint, ex := update(updatedIssues, rollbackFunc)
-		if ex != nil {
-			// In case there is an error during rollback, extend the error hint.
-			errHintAcc.WriteString("Rollback Errors\n---------------\n")
-			errHintAcc.WriteString(errHint)
-			errHintAcc.WriteString("\n")
-		}
-
-		return nil, nil, errs.NewErrorWithHint("Update GitHub issues", err, errHintAcc.String())
+		return nil, nil, err
 	}
 
-	// On success, return the updated issues and a rollback function.
-	act := action.ActionFunc(func() error {
-		_, errHint, err := update(updatedIssues, rollbackFunc)
-		if err != nil {
-			var errHintAcc bytes.Buffer
-			errHintAcc.WriteString("\nRollback Errors\n---------------\n")
-			errHintAcc.WriteString(errHint)
-			errHintAcc.WriteString("\n")
-			return errs.NewErrorWithHint("Revert GitHub issue updates", err, errHintAcc.String())
-		}
-		return nil
-	})
+	// On success, return the updated issues and the rollback action.
+	updatedIssues := make([]*github.Issue, 0, len(results))
+	for _, v := range results.Values() {
+		updatedIssues = append(updatedIssues, v.(*github.Issue))
+	}
 	return updatedIssues, act, nil
 }
 
+// describeIssue can be used as bulk.Options.Describe for GitHub issues.
+func describeIssue(item interface{}) string {
+	issue := item.(*github.Issue)
+	return fmt.Sprintf("issue #%v", *issue.Number)
+}
+
 // setMilestone returns an update function that can be passed into
 // updateIssues to set the milestone to the given value.
 func setMilestone(milestone *github.Milestone) issueUpdateFunc {
@@ -19,9 +19,15 @@ type moduleConfig struct {
 	GitHubOwner      string
 	GitHubRepository string
 
+	// GitHub instance, useful for GitHub Enterprise.
+	GitHubEndpoints *github.Endpoints
+
 	// GitHub API authentication.
 	UserToken string
 
+	// The number of GitHub API requests that can be sent concurrently.
+	MaxConcurrentRequests int
+
 	// Story labels.
 	StoryLabels []string
 
@@ -48,21 +54,31 @@ func loadConfig() (*moduleConfig, error) {
 		return nil, errs.NewError(task, err)
 	}
 
-	// Parse the main repo upstream URL.
-	owner, repo, err := github.ParseUpstreamURL()
+	var (
+		local  = spec.local
+		global = spec.global
+	)
+
+	// Parse the main repo upstream URL and get the API endpoints.
+	upstream, endpoints, err := github.UpstreamEndpoints(
+		local.GitHubAPIBaseURL, local.GitHubUploadURL)
 	if err != nil {
 		return nil, errs.NewError(task, err)
 	}
 
+	// Use the default values where necessary.
+	maxConcurrentRequests := local.MaxConcurrentRequests
+	if maxConcurrentRequests == 0 {
+		maxConcurrentRequests = DefaultMaxConcurrentRequests
+	}
+
 	// Assemble the config object.
-	var (
-		local  = spec.local
-		global = spec.global
-	)
 	return &moduleConfig{
-		GitHubOwner:           owner,
-		GitHubRepository:      repo,
+		GitHubOwner:           upstream.Owner,
+		GitHubRepository:      upstream.Repo,
+		GitHubEndpoints:       endpoints,
 		UserToken:             global.UserToken,
+		MaxConcurrentRequests: maxConcurrentRequests,
 		StoryLabels:           local.StoryLabels,
 		ApprovedLabel:         local.StateLabels.ApprovedLabel,
 		BeingImplementedLabel: local.StateLabels.BeingImplementedLabel,
@@ -168,6 +184,13 @@ type LocalConfig struct {
 	} `json:"state_labels"`
 
 	SkipCheckLabels []string `json:"skip_release_check_labels"`
+
+	// GitHub Enterprise endpoints, derived from the upstream URL when not set.
+	GitHubAPIBaseURL string `json:"github_api_base_url,omitempty" optional:"true"`
+	GitHubUploadURL  string `json:"github_upload_url,omitempty"   optional:"true"`
+
+	// The number of GitHub API requests that can be sent concurrently.
+	MaxConcurrentRequests int `json:"max_concurrent_requests,omitempty" optional:"true"`
 }
 
 // PromptUserForConfig is a part of loader.ConfigContainer interface.
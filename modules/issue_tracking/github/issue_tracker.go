@@ -13,6 +13,7 @@ import (
 	ghissues "github.com/salsaflow/salsaflow/github/issues"
 	"github.com/salsaflow/salsaflow/log"
 	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/modules/common/bulk"
 	"github.com/salsaflow/salsaflow/version"
 
 	// Other
@@ -32,6 +33,7 @@ func newIssueTracker() (common.IssueTracker, error) {
 		return nil, err
 	}
 
+	setMaxConcurrentRequests(config.MaxConcurrentRequests)
 	return &issueTracker{config}, nil
 }
 
@@ -59,6 +61,26 @@ func (tracker *issueTracker) CurrentUser() (common.User, error) {
 	return &user{me}, nil
 }
 
+// CheckHealth implements common.HealthChecker.
+// It makes sure the token is valid and the repository is accessible.
+func (tracker *issueTracker) CheckHealth() error {
+	var (
+		config = tracker.config
+		owner  = config.GitHubOwner
+		repo   = config.GitHubRepository
+		client = tracker.newClient()
+		err    error
+	)
+	task := fmt.Sprintf("Get GitHub repository %v/%v", owner, repo)
+	withRequestAllocated(func() {
+		_, _, err = client.Repositories.Get(owner, repo)
+	})
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+	return nil
+}
+
 // StartableStories is a part of common.IssueTracker interface.
 func (tracker *issueTracker) StartableStories() ([]common.Story, error) {
 	return tracker.searchIssuesAndWrap(`state:open label:"%v"`, tracker.config.ApprovedLabel)
@@ -150,8 +172,8 @@ func (tracker *issueTracker) RunningRelease(
 
 // OpenStory is a part of common.IssueTracker interface.
 func (tracker *issueTracker) OpenStory(storyId string) error {
-	u := fmt.Sprintf("https://github.com/%v/%v/issues/%v",
-		tracker.config.GitHubOwner, tracker.config.GitHubRepository, storyId)
+	config := tracker.config
+	u := config.GitHubEndpoints.IssueURL(config.GitHubOwner, config.GitHubRepository, storyId)
 
 	return webbrowser.Open(u)
 }
@@ -170,7 +192,7 @@ func (tracker *issueTracker) StoryTagToReadableStoryId(tag string) (storyId stri
 // Utility methods used internally ---------------------------------------------
 
 func (tracker *issueTracker) newClient() *github.Client {
-	return ghutil.NewClient(tracker.config.UserToken)
+	return ghutil.NewClientForEndpoints(tracker.config.UserToken, tracker.config.GitHubEndpoints)
 }
 
 type searchResult struct {
@@ -384,8 +406,13 @@ func (tracker *issueTracker) updateIssues(
 		client = tracker.newClient()
 		owner  = tracker.config.GitHubOwner
 		repo   = tracker.config.GitHubRepository
+		opts   = &bulk.Options{
+			MaxConcurrency: tracker.config.MaxConcurrentRequests,
+			Describe:       describeIssue,
+			Progress:       "Updating GitHub issues",
+		}
 	)
-	return updateIssues(client, owner, repo, issues, updateFunc, rollbackFunc)
+	return updateIssues(client, owner, repo, issues, updateFunc, rollbackFunc, opts)
 }
 
 // getOrCreateMilestone just calls ghissues.GetOrCreateMilestoneForTitle
@@ -1,8 +1,17 @@
 package github
 
-const MaxConcurrentRequests = 10
+// DefaultMaxConcurrentRequests is used unless max_concurrent_requests is configured.
+const DefaultMaxConcurrentRequests = 10
 
-var requestSemaphore = make(chan struct{}, MaxConcurrentRequests)
+var requestSemaphore = make(chan struct{}, DefaultMaxConcurrentRequests)
+
+// setMaxConcurrentRequests changes the number of concurrent requests allowed.
+// It is supposed to be called before any request is sent.
+func setMaxConcurrentRequests(n int) {
+	if n > 0 && n != cap(requestSemaphore) {
+		requestSemaphore = make(chan struct{}, n)
+	}
+}
 
 func withRequestAllocated(body func()) {
 	requestSemaphore <- struct{}{}
@@ -42,8 +42,12 @@ func (story *story) State() common.StoryState {
 }
 
 func (story *story) URL() string {
-	return fmt.Sprintf("https://github.com/%v/%v/issues/%v",
-		story.tracker.config.GitHubOwner, story.tracker.config.GitHubRepository, *story.issue.Number)
+	if story.issue.HTMLURL != nil {
+		return *story.issue.HTMLURL
+	}
+	config := story.tracker.config
+	return config.GitHubEndpoints.IssueURL(
+		config.GitHubOwner, config.GitHubRepository, *story.issue.Number)
 }
 
 func (story *story) Tag() string {
@@ -110,6 +114,15 @@ func (story *story) MarkAsImplemented() (action.Action, error) {
 	return act, nil
 }
 
+func (story *story) MarkAsReviewed() (action.Action, error) {
+	task := fmt.Sprintf("Mark GitHub issue %v as reviewed", story.ReadableId())
+	act, err := story.setStateLabel(story.tracker.config.ReviewedLabel)
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+	return act, nil
+}
+
 func (s *story) LessThan(otherStory common.Story) bool {
 	return *s.issue.Number < *otherStory.(*story).issue.Number
 }
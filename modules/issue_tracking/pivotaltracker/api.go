@@ -2,13 +2,13 @@ package pivotaltracker
 
 import (
 	// Stdlib
-	"bytes"
 	"errors"
 	"fmt"
-	"io"
 
 	// Internal
-	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/action"
+	"github.com/salsaflow/salsaflow/httputil"
+	"github.com/salsaflow/salsaflow/modules/common/bulk"
 
 	// Other
 	"gopkg.in/salsita/go-pivotaltracker.v1/v5/pivotal"
@@ -19,6 +19,15 @@ var (
 	ErrApiCall               = errors.New("Pivotal Tracker: API call failed")
 )
 
+// newClient returns a new Pivotal Tracker API client.
+//
+// The client library always uses http.DefaultClient, so the shared
+// retrying transport is installed into http.DefaultClient first.
+func newClient(token string) *pivotal.Client {
+	httputil.InstallDefaultTransport()
+	return pivotal.NewClient(token)
+}
+
 func addLabelFunc(label string) storyUpdateFunc {
 	return func(story *pivotal.Story) *pivotal.StoryRequest {
 		// Make sure the label is not already there.
@@ -61,133 +70,72 @@ func addLabel(
 	projectId int,
 	stories []*pivotal.Story,
 	label string,
-) ([]*pivotal.Story, error) {
+	opts *bulk.Options,
+) ([]*pivotal.Story, action.Action, error) {
 
-	return updateStories(client, projectId, stories, addLabelFunc(label), removeLabelFunc(label))
-}
-
-func removeLabel(
-	client *pivotal.Client,
-	projectId int,
-	stories []*pivotal.Story,
-	label string,
-) ([]*pivotal.Story, error) {
-
-	return updateStories(client, projectId, stories, removeLabelFunc(label), addLabelFunc(label))
+	return updateStories(
+		client, projectId, stories, addLabelFunc(label), removeLabelFunc(label), opts)
 }
 
 type storyUpdateFunc func(story *pivotal.Story) (updateRequest *pivotal.StoryRequest)
 
-type storyUpdateResult struct {
-	story *pivotal.Story
-	err   error
-}
-
+// updateStories applies the given update function to all the stories.
+//
+// In case there is an error, the stories that were updated successfully
+// are reverted using rollbackFunc. On success, the updated stories are returned
+// together with an action that reverts exactly the stories that were changed.
+// The stories for which updateFunc returns nil are left untouched.
 func updateStories(
 	client *pivotal.Client,
 	projectId int,
 	stories []*pivotal.Story,
 	updateFunc storyUpdateFunc,
 	rollbackFunc storyUpdateFunc,
-) ([]*pivotal.Story, error) {
+	opts *bulk.Options,
+) ([]*pivotal.Story, action.Action, error) {
 
-	// Allow just a single pending request at a time.
-	// This is because PT is lame and crashes otherwise.
-	semCh := make(chan struct{}, 1)
-	down := func() {
-		semCh <- struct{}{}
-	}
-	up := func() {
-		<-semCh
-	}
-
-	// Send all the request at once.
-	retCh := make(chan *storyUpdateResult, len(stories))
-	for _, story := range stories {
-		go func(story *pivotal.Story) {
-			down()
-			defer up()
+	// Turn the story update function into a bulk function.
+	bulkFunc := func(updateFunc storyUpdateFunc) bulk.Func {
+		if updateFunc == nil {
+			return nil
+		}
+		return func(item interface{}) (interface{}, error) {
+			story := item.(*pivotal.Story)
 
 			// Get the update request.
 			// Returning nil means that no request is sent.
 			updateRequest := updateFunc(story)
 			if updateRequest == nil {
-				retCh <- &storyUpdateResult{story, nil}
-				return
+				return nil, bulk.ErrSkipped
 			}
 
-			// Send the update request and collect the result.
+			// Send the update request.
 			updatedStory, _, err := client.Stories.Update(projectId, story.Id, updateRequest)
-			if err == nil {
-				// On success, return the updated story.
-				retCh <- &storyUpdateResult{updatedStory, nil}
-			} else {
-				// On error, keep the original story, add the error.
-				retCh <- &storyUpdateResult{story, err}
-			}
-		}(story)
+			return updatedStory, err
+		}
 	}
 
-	// Wait for the requests to complete.
-	var (
-		stderr          = bytes.NewBufferString("\nUpdate Errors\n-------------\n")
-		updatedStories  = make([]*pivotal.Story, 0, len(stories))
-		errUpdateFailed = errors.New("failed to update some Pivotal Tracker stories")
-		err             error
-	)
-	for _ = range stories {
-		if ret := <-retCh; ret.err != nil {
-			fmt.Fprintln(stderr, ret.err)
-			err = errUpdateFailed
-		} else {
-			updatedStories = append(updatedStories, ret.story)
-		}
+	items := make([]interface{}, 0, len(stories))
+	for _, story := range stories {
+		items = append(items, story)
 	}
-	fmt.Fprintln(stderr)
 
+	// Update the stories.
+	results, act, err := bulk.Update("Update Pivotal Tracker stories",
+		items, bulkFunc(updateFunc), bulkFunc(rollbackFunc), opts)
 	if err != nil {
-		if rollbackFunc != nil {
-			// Spawn the rollback goroutines.
-			// Basically the same thing as with the update requests.
-			retCh := make(chan *storyUpdateResult)
-			for _, story := range updatedStories {
-				go func(story *pivotal.Story) {
-					down()
-					defer up()
-
-					rollbackRequest := rollbackFunc(story)
-					if rollbackRequest == nil {
-						retCh <- &storyUpdateResult{story, nil}
-						return
-					}
-
-					updatedStory, _, err := client.Stories.Update(
-						projectId, story.Id, rollbackRequest)
-					if err == nil {
-						retCh <- &storyUpdateResult{updatedStory, nil}
-					} else {
-						retCh <- &storyUpdateResult{story, err}
-					}
-				}(story)
-			}
-
-			// Collect the rollback results.
-			rollbackStderr := bytes.NewBufferString("Rollback Errors\n---------------\n")
-			for _ = range updatedStories {
-				if ret := <-retCh; ret.err != nil {
-					fmt.Fprintln(rollbackStderr, ret.err)
-				}
-			}
-			fmt.Fprintln(stderr)
-
-			// Append the rollback error output to the update error output.
-			if _, err := io.Copy(stderr, rollbackStderr); err != nil {
-				panic(err)
-			}
-		}
+		return nil, nil, err
+	}
 
-		// Return the aggregate error.
-		return nil, errs.NewErrorWithHint("Update Pivotal Tracker stories", err, stderr.String())
+	updatedStories := make([]*pivotal.Story, 0, len(results))
+	for _, v := range results.Values() {
+		updatedStories = append(updatedStories, v.(*pivotal.Story))
 	}
-	return updatedStories, nil
+	return updatedStories, act, nil
+}
+
+// describeStory can be used as bulk.Options.Describe for Pivotal Tracker stories.
+func describeStory(item interface{}) string {
+	story := item.(*pivotal.Story)
+	return fmt.Sprintf("story %v", story.Id)
 }
@@ -28,6 +28,10 @@ type moduleConfig struct {
 	SkipTestingLabel string
 	SkipCheckLabels  []string
 	UserToken        string
+
+	// API request tuning.
+	MaxConcurrentRequests int
+	StoryBatchSize        int
 }
 
 func loadConfig() (*moduleConfig, error) {
@@ -42,6 +46,16 @@ func loadConfig() (*moduleConfig, error) {
 		local  = spec.local
 		global = spec.global
 	)
+
+	maxConcurrentRequests := local.MaxConcurrentRequests
+	if maxConcurrentRequests == 0 {
+		maxConcurrentRequests = DefaultMaxConcurrentRequests
+	}
+	storyBatchSize := local.StoryBatchSize
+	if storyBatchSize == 0 {
+		storyBatchSize = DefaultStoryBatchSize
+	}
+
 	return &moduleConfig{
 		ProjectId:        local.ProjectId,
 		ComponentLabel:   *local.ComponentLabel,
@@ -52,6 +66,9 @@ func loadConfig() (*moduleConfig, error) {
 		SkipTestingLabel: local.Labels.SkipTestingLabel,
 		SkipCheckLabels:  local.Labels.SkipCheckLabels,
 		UserToken:        global.UserToken,
+
+		MaxConcurrentRequests: maxConcurrentRequests,
+		StoryBatchSize:        storyBatchSize,
 	}, nil
 }
 
@@ -118,6 +135,15 @@ const (
 
 var DefaultSkipCheckLabels = []string{"dupe", "wontfix"}
 
+const (
+	// Pivotal Tracker tends to fail when there are more requests
+	// being processed at the same time, so one request it is by default.
+	DefaultMaxConcurrentRequests = 1
+
+	// The number of stories that are fetched using a single request.
+	DefaultStoryBatchSize = 50
+)
+
 // LocalConfig implements loader.ConfigContainer interface.
 type LocalConfig struct {
 	spec *configSpec
@@ -132,6 +158,9 @@ type LocalConfig struct {
 		SkipTestingLabel string   `json:"skip_testing"`
 		SkipCheckLabels  []string `json:"skip_release_check_labels"`
 	} `json:"workflow_labels"`
+
+	MaxConcurrentRequests int `json:"max_concurrent_requests,omitempty" optional:"true"`
+	StoryBatchSize        int `json:"story_batch_size,omitempty"        optional:"true"`
 }
 
 // PromptUserForConfig is a part of loader.ConfigContainer interface.
@@ -142,7 +171,7 @@ func (local *LocalConfig) PromptUserForConfig() error {
 	task := "Fetch available Pivotal Tracker projects"
 	log.Run(task)
 
-	client := pivotal.NewClient(local.spec.global.UserToken)
+	client := newClient(local.spec.global.UserToken)
 
 	projects, _, err := client.Projects.List()
 	if err != nil {
@@ -8,7 +8,10 @@ import (
 	"strings"
 
 	// Internal
+	"github.com/salsaflow/salsaflow/action"
+	"github.com/salsaflow/salsaflow/errs"
 	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/modules/common/bulk"
 	"github.com/salsaflow/salsaflow/version"
 
 	// Other
@@ -36,7 +39,7 @@ func (tracker *issueTracker) ServiceName() string {
 }
 
 func (tracker *issueTracker) CurrentUser() (common.User, error) {
-	client := pivotal.NewClient(tracker.config.UserToken)
+	client := newClient(tracker.config.UserToken)
 	me, _, err := client.Me.Get()
 	if err != nil {
 		return nil, err
@@ -44,6 +47,17 @@ func (tracker *issueTracker) CurrentUser() (common.User, error) {
 	return &user{me}, nil
 }
 
+// CheckHealth implements common.HealthChecker.
+// It makes sure the token is valid and the project is accessible.
+func (tracker *issueTracker) CheckHealth() error {
+	task := fmt.Sprintf("Get Pivotal Tracker project %v", tracker.config.ProjectId)
+	client := newClient(tracker.config.UserToken)
+	if _, _, err := client.Projects.Get(tracker.config.ProjectId); err != nil {
+		return errs.NewError(task, err)
+	}
+	return nil
+}
+
 func (tracker *issueTracker) StartableStories() ([]common.Story, error) {
 	// Fetch the stories with the right story type.
 	stories, err := tracker.searchStories(
@@ -152,7 +166,7 @@ func (tracker *issueTracker) searchStories(
 
 	// Get the client.
 	var (
-		client    = pivotal.NewClient(tracker.config.UserToken)
+		client    = newClient(tracker.config.UserToken)
 		projectId = tracker.config.ProjectId
 	)
 
@@ -180,28 +194,63 @@ func (tracker *issueTracker) updateStories(
 	stories []*pivotal.Story,
 	updateFunc storyUpdateFunc,
 	rollbackFunc storyUpdateFunc,
-) ([]*pivotal.Story, error) {
+) ([]*pivotal.Story, action.Action, error) {
 	var (
-		client    = pivotal.NewClient(tracker.config.UserToken)
+		client    = newClient(tracker.config.UserToken)
 		projectId = tracker.config.ProjectId
 	)
-	return updateStories(client, projectId, stories, updateFunc, rollbackFunc)
+	return updateStories(
+		client, projectId, stories, updateFunc, rollbackFunc, tracker.bulkOptions())
 }
 
+// bulkOptions returns the options to be used for bulk operations.
+func (tracker *issueTracker) bulkOptions() *bulk.Options {
+	return &bulk.Options{
+		MaxConcurrency: tracker.config.MaxConcurrentRequests,
+		Describe:       describeStory,
+		Progress:       "Updating Pivotal Tracker stories",
+	}
+}
+
+// storiesById fetches the stories with the given IDs.
+// The stories are fetched in batches, StoryBatchSize stories per request.
 func (tracker *issueTracker) storiesById(ids []string) ([]*pivotal.Story, error) {
 	if len(ids) == 0 {
 		return nil, nil
 	}
 
-	// Generate the query.
-	var filter bytes.Buffer
-	fmt.Fprintf(&filter, "id:%v", ids[0])
-	for _, id := range ids[1:] {
-		fmt.Fprintf(&filter, " OR id:%v", id)
+	items := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, id)
 	}
 
-	// Send the query.
-	return tracker.searchStories(filter.String())
+	// Fetch the batches.
+	fetchBatch := func(batch interface{}) (interface{}, error) {
+		ids := batch.([]interface{})
+
+		// Generate the query.
+		var filter bytes.Buffer
+		fmt.Fprintf(&filter, "id:%v", ids[0])
+		for _, id := range ids[1:] {
+			fmt.Fprintf(&filter, " OR id:%v", id)
+		}
+
+		// Send the query.
+		return tracker.searchStories(filter.String())
+	}
+	results := bulk.Do(bulk.Batches(items, tracker.config.StoryBatchSize), fetchBatch, &bulk.Options{
+		MaxConcurrency: tracker.config.MaxConcurrentRequests,
+	})
+
+	// Collect the stories.
+	var stories []*pivotal.Story
+	for _, result := range results {
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		stories = append(stories, result.Value.([]*pivotal.Story)...)
+	}
+	return stories, nil
 }
 
 func (tracker *issueTracker) storiesByIdOrdered(ids []string) ([]*pivotal.Story, error) {
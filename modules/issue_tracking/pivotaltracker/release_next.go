@@ -185,15 +185,15 @@ func (release *nextRelease) Start() (action.Action, error) {
 	// Add release labels to the relevant stories.
 	var (
 		config    = release.tracker.config
-		client    = pivotal.NewClient(config.UserToken)
+		client    = newClient(config.UserToken)
 		projectId = config.ProjectId
 	)
 
 	task := "Label the stories with the release label"
 	log.Run(task)
 	releaseLabel := getReleaseLabel(release.trunkVersion)
-	stories, err := addLabel(client, projectId,
-		release.additionalStories, releaseLabel)
+	_, act, err := addLabel(client, projectId,
+		release.additionalStories, releaseLabel, release.tracker.bulkOptions())
 	if err != nil {
 		return nil, errs.NewError(task, err)
 	}
@@ -202,8 +202,7 @@ func (release *nextRelease) Start() (action.Action, error) {
 	// Return the rollback action, which removes the release labels that were appended.
 	return action.ActionFunc(func() error {
 		log.Rollback(task)
-		_, err := removeLabel(client, projectId, stories, releaseLabel)
-		if err != nil {
+		if err := act.Rollback(); err != nil {
 			return errs.NewError("Remove the release label from the stories", err)
 		}
 		return nil
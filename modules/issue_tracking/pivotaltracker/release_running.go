@@ -141,7 +141,7 @@ func (release *runningRelease) Stage() (action.Action, error) {
 	}
 
 	// Update the stories.
-	updatedStories, err := release.tracker.updateStories(stories, updateFunc, rollbackFunc)
+	updatedStories, act, err := release.tracker.updateStories(stories, updateFunc, rollbackFunc)
 	if err != nil {
 		return nil, errs.NewError(stageTask, err)
 	}
@@ -152,11 +152,11 @@ func (release *runningRelease) Stage() (action.Action, error) {
 		// On error, set the states back to the original ones.
 		log.Rollback(stageTask)
 		task := fmt.Sprintf("Reset the story states back to %v", pivotal.StoryStateFinished)
-		updatedStories, err := release.tracker.updateStories(release.stories, rollbackFunc, nil)
-		if err != nil {
+		if err := act.Rollback(); err != nil {
 			return errs.NewError(task, err)
 		}
-		release.stories = updatedStories
+		// Make sure the stories are fetched again next time.
+		release.stories = nil
 		return nil
 	}), nil
 }
@@ -133,7 +133,7 @@ func (story *story) SetAssignees(users []common.User) error {
 
 	var (
 		config    = story.tracker.config
-		client    = pivotal.NewClient(config.UserToken)
+		client    = newClient(config.UserToken)
 		projectId = config.ProjectId
 	)
 	updateRequest := &pivotal.StoryRequest{OwnerIds: &ownerIds}
@@ -154,7 +154,7 @@ func (story *story) Start() error {
 
 	var (
 		config    = story.tracker.config
-		client    = pivotal.NewClient(config.UserToken)
+		client    = newClient(config.UserToken)
 		projectId = config.ProjectId
 	)
 	updateRequest := &pivotal.StoryRequest{State: pivotal.StoryStateStarted}
@@ -182,7 +182,7 @@ func (story *story) MarkAsImplemented() (action.Action, error) {
 	// Set the story state to finished.
 	var (
 		config    = story.tracker.config
-		client    = pivotal.NewClient(config.UserToken)
+		client    = newClient(config.UserToken)
 		projectId = config.ProjectId
 	)
 
@@ -207,6 +207,23 @@ func (story *story) MarkAsImplemented() (action.Action, error) {
 	}), nil
 }
 
+func (story *story) MarkAsReviewed() (action.Action, error) {
+	var (
+		config    = story.tracker.config
+		client    = newClient(config.UserToken)
+		projectId = config.ProjectId
+	)
+
+	task := fmt.Sprintf("Mark Pivotal Tracker story (id = %v) as reviewed", story.Story.Id)
+	updatedStories, act, err := addLabel(client, projectId,
+		[]*pivotal.Story{story.Story}, config.ReviewedLabel, story.tracker.bulkOptions())
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+	story.Story = updatedStories[0]
+	return act, nil
+}
+
 func (s *story) LessThan(otherStory common.Story) bool {
 	return s.CreatedAt.Before(*otherStory.(*story).CreatedAt)
 }
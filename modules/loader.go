@@ -3,12 +3,17 @@ package modules
 import (
 	// Stdlib
 	"fmt"
+	"io"
+	"regexp"
 
 	// Internal
 	"github.com/salsaflow/salsaflow/config"
 	"github.com/salsaflow/salsaflow/config/loader"
 	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/log"
 	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/modules/common/composite"
+	"github.com/salsaflow/salsaflow/modules/plugin"
 )
 
 var (
@@ -17,12 +22,50 @@ var (
 	releaseNotesManager common.ReleaseNotesManager
 )
 
+// pluginModules are the modules implemented by the plugins started,
+// indexed by the module ID.
+var pluginModules = make(map[string]loader.Module)
+
+// AvailableModules returns the modules compiled into SalsaFlow
+// followed by the modules implemented by the plugins available.
+//
+// Every plugin is only started to get the module description,
+// so the plugin modules returned can only be listed, not used.
 func AvailableModules() []loader.Module {
 	modules := make([]loader.Module, len(registeredModules))
 	copy(modules, registeredModules)
+
+PluginLoop:
+	for _, path := range plugin.Discover() {
+		module, err := plugin.LoadDescription(path)
+		if err != nil {
+			errs.Log(err)
+			continue
+		}
+		for _, registered := range registeredModules {
+			if registered.Id() == module.Id() {
+				log.Warn(fmt.Sprintf(
+					"Plugin '%v' skipped, module '%v' is already compiled in", path, module.Id()))
+				continue PluginLoop
+			}
+		}
+		modules = append(modules, module)
+	}
 	return modules
 }
 
+// ClosePlugins stops the plugins started to implement the active modules.
+func ClosePlugins() error {
+	var err error
+	for id, module := range pluginModules {
+		if ex := module.(io.Closer).Close(); ex != nil {
+			err = errs.LogError(fmt.Sprintf("Stop plugin for module '%v'", id), ex)
+		}
+		delete(pluginModules, id)
+	}
+	return err
+}
+
 func GetIssueTracker() (common.IssueTracker, error) {
 	if issueTracker != nil {
 		return issueTracker, nil
@@ -33,15 +76,19 @@ func GetIssueTracker() (common.IssueTracker, error) {
 		return nil, err
 	}
 
-	trackerModule, err := AsIssueTrackingModule(module)
+	implementation, err := newIssueTracker(module)
 	if err != nil {
 		return nil, err
 	}
 
-	implementation, err := trackerModule.NewIssueTracker()
+	// Combine the primary issue tracker with the additional ones, if any.
+	backends, err := loadAdditionalIssueTrackers()
 	if err != nil {
 		return nil, err
 	}
+	if len(backends) != 0 {
+		implementation = composite.NewIssueTracker(implementation, backends...)
+	}
 
 	issueTracker = implementation
 	return issueTracker, nil
@@ -95,6 +142,85 @@ func GetReleaseNotesManager() (common.ReleaseNotesManager, error) {
 	return releaseNotesManager, nil
 }
 
+// ConfigSpecs returns the config specs that are relevant for the current repository,
+// i.e. the core config specs as registered for `repo bootstrap`
+// followed by the config specs of the active modules.
+func ConfigSpecs() ([]loader.ConfigSpec, error) {
+	specs := loader.BootstrapConfigSpecs()
+
+	// Load local configuration.
+	localConfig, err := config.ReadLocalConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	// Append the specs for the active modules.
+	kinds := []loader.ModuleKind{
+		loader.ModuleKindIssueTracking,
+		loader.ModuleKindCodeReview,
+		loader.ModuleKindReleaseNotes,
+	}
+	for _, kind := range kinds {
+		for _, id := range loader.ActiveModules(localConfig, kind) {
+			if module := lookupModule(id); module != nil {
+				specs = append(specs, module.ConfigSpec())
+			}
+		}
+	}
+	return specs, nil
+}
+
+func newIssueTracker(module loader.Module) (common.IssueTracker, error) {
+	trackerModule, err := AsIssueTrackingModule(module)
+	if err != nil {
+		return nil, err
+	}
+	return trackerModule.NewIssueTracker()
+}
+
+func loadAdditionalIssueTrackers() ([]*composite.Backend, error) {
+	// Load local configuration.
+	localConfig, err := config.ReadLocalConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	bindings := localConfig.Modules.AdditionalIssueTracking
+	backends := make([]*composite.Backend, 0, len(bindings))
+	for _, binding := range bindings {
+		task := fmt.Sprintf("Load additional issue tracking module '%v'", binding.ModuleId)
+
+		module, err := findModule(loader.ModuleKindIssueTracking, binding.ModuleId)
+		if err != nil {
+			return nil, errs.NewError(task, err)
+		}
+
+		tracker, err := newIssueTracker(module)
+		if err != nil {
+			return nil, errs.NewError(task, err)
+		}
+
+		backend := &composite.Backend{
+			Tracker:        tracker,
+			StoryTagPrefix: binding.StoryTagPrefix,
+		}
+		if pattern := binding.StoryTagPattern; pattern != "" {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				hint := `
+The Story-Id tag pattern must be a valid regular expression.
+Please fix the pattern in the local configuration file.
+
+`
+				return nil, errs.NewErrorWithHint(task, err, hint)
+			}
+			backend.StoryTagPattern = re
+		}
+		backends = append(backends, backend)
+	}
+	return backends, nil
+}
+
 func loadActiveModule(kind loader.ModuleKind) (loader.Module, error) {
 	// Load local configuration.
 	localConfig, err := config.ReadLocalConfig()
@@ -111,24 +237,61 @@ func loadActiveModule(kind loader.ModuleKind) (loader.Module, error) {
 		return nil, errs.NewErrorWithHint(task, err, hint)
 	}
 
-	// Find the module among the registered modules.
-	for _, module := range registeredModules {
-		if module.Id() == activeModuleId {
-			return module, nil
-		}
+	return findModule(kind, activeModuleId)
+}
+
+func findModule(kind loader.ModuleKind, moduleId string) (loader.Module, error) {
+	// Find the module among the registered modules and plugins.
+	if module := lookupModule(moduleId); module != nil {
+		return module, nil
 	}
 
 	task := fmt.Sprintf("Load active module for module kind '%v'", kind)
-	err = &ErrModuleNotFound{activeModuleId}
+	err := &ErrModuleNotFound{moduleId}
 	hint := `
 The module for the given module ID was not found.
 This can happen for one of the following reasons:
 
   1. the module ID as stored in the local configuration file is mistyped, or
-  2. the module for the given module ID was not linked into your SalsaFlow.
+  2. the module for the given module ID was not linked into your SalsaFlow, or
+  3. the plugin implementing the module was not found on PATH
+     nor in .salsaflow/modules.
 
 Check the scenarios as mentioned above to fix the issue.
 
 `
 	return nil, errs.NewErrorWithHint(task, err, hint)
 }
+
+// lookupModule returns the module for the given module ID, or nil when not found.
+// The plugin implementing the module is only started when the module
+// is not compiled into SalsaFlow.
+func lookupModule(moduleId string) loader.Module {
+	for _, module := range registeredModules {
+		if module.Id() == moduleId {
+			return module
+		}
+	}
+
+	if module, ok := pluginModules[moduleId]; ok {
+		return module
+	}
+
+	path := plugin.Lookup(moduleId)
+	if path == "" {
+		return nil
+	}
+	module, err := plugin.Load(path)
+	if err != nil {
+		errs.Log(err)
+		return nil
+	}
+	if module.Id() != moduleId {
+		log.Warn(fmt.Sprintf(
+			"Plugin '%v' skipped, it implements module '%v'", path, module.Id()))
+		module.(io.Closer).Close()
+		return nil
+	}
+	pluginModules[moduleId] = module
+	return module
+}
@@ -3,17 +3,21 @@ package modules
 import (
 	// Internal
 	"github.com/salsaflow/salsaflow/config/loader"
+	bitbucketCodeReview "github.com/salsaflow/salsaflow/modules/code_review/bitbucket"
 	githubCodeReview "github.com/salsaflow/salsaflow/modules/code_review/github"
 	noopReview "github.com/salsaflow/salsaflow/modules/code_review/noop"
+	phabricatorCodeReview "github.com/salsaflow/salsaflow/modules/code_review/phabricator"
 	githubIssueTracking "github.com/salsaflow/salsaflow/modules/issue_tracking/github"
 	"github.com/salsaflow/salsaflow/modules/issue_tracking/pivotaltracker"
 	githubReleaseNotes "github.com/salsaflow/salsaflow/modules/release_notes/github"
 )
 
 var registeredModules = []loader.Module{
+	bitbucketCodeReview.NewModule(),
 	githubCodeReview.NewModule(),
 	githubIssueTracking.NewModule(),
 	githubReleaseNotes.NewModule(),
 	noopReview.NewModule(),
+	phabricatorCodeReview.NewModule(),
 	pivotaltracker.NewModule(),
 }
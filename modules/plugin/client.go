@@ -0,0 +1,109 @@
+package plugin
+
+import (
+	// Stdlib
+	"errors"
+	"fmt"
+	"io"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"os/exec"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/action"
+	"github.com/salsaflow/salsaflow/modules/common"
+)
+
+// ErrPluginTerminated is returned when the plugin process exits unexpectedly.
+var ErrPluginTerminated = errors.New("plugin process terminated")
+
+// client represents a running plugin process.
+type client struct {
+	path string
+	cmd  *exec.Cmd
+	rpc  *rpc.Client
+}
+
+// startClient starts the plugin located at the given path.
+func startClient(path string) (*client, error) {
+	cmd := exec.Command(path)
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	conn := &pipeConn{stdout, stdin}
+	return &client{path, cmd, jsonrpc.NewClient(conn)}, nil
+}
+
+// call calls the given plugin method and turns the error returned by the plugin
+// into the errors the rest of SalsaFlow understands.
+func (c *client) call(method string, args interface{}, reply interface{}) error {
+	err := c.rpc.Call(method, args, reply)
+	switch err := err.(type) {
+	case nil:
+		return nil
+	case rpc.ServerError:
+		switch string(err) {
+		case common.ErrNotStageable.Error():
+			return common.ErrNotStageable
+		case common.ErrNotClosable.Error():
+			return common.ErrNotClosable
+		}
+		return fmt.Errorf("%v: %v", method, string(err))
+	default:
+		if err == rpc.ErrShutdown || err == io.ErrUnexpectedEOF {
+			return fmt.Errorf("%v: %v", c.path, ErrPluginTerminated)
+		}
+		return err
+	}
+}
+
+// Close closes the standard input of the plugin process,
+// which makes the plugin exit, and waits for the process to exit.
+func (c *client) Close() error {
+	err := c.rpc.Close()
+	if c.cmd == nil {
+		return err
+	}
+	if ex := c.cmd.Wait(); ex != nil && err == nil {
+		err = ex
+	}
+	return err
+}
+
+// action returns the rollback action for the given action handle.
+func (c *client) action(handle Handle) action.Action {
+	if handle == "" {
+		return action.Noop
+	}
+	return action.ActionFunc(func() error {
+		return c.call("Action.Rollback", &HandleArgs{handle}, &Empty{})
+	})
+}
+
+// pipeConn joins the standard output and the standard input of the plugin
+// so that they can be used as a single connection.
+type pipeConn struct {
+	io.ReadCloser
+	io.WriteCloser
+}
+
+func (conn *pipeConn) Close() error {
+	werr := conn.WriteCloser.Close()
+	rerr := conn.ReadCloser.Close()
+	if werr != nil {
+		return werr
+	}
+	return rerr
+}
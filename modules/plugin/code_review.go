@@ -0,0 +1,127 @@
+package plugin
+
+import (
+	// Internal
+	"github.com/salsaflow/salsaflow/action"
+	"github.com/salsaflow/salsaflow/git"
+	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/version"
+)
+
+// codeReviewTool implements common.CodeReviewTool by calling the plugin.
+type codeReviewTool struct {
+	client *client
+}
+
+// NewRelease is a part of common.CodeReviewTool interface.
+func (tool *codeReviewTool) NewRelease(v *version.Version) common.Release {
+	return &release{client: tool.client, version: v}
+}
+
+// PostReviewRequests is a part of common.CodeReviewTool interface.
+func (tool *codeReviewTool) PostReviewRequests(
+	ctxs []*common.ReviewContext,
+	opts map[string]interface{},
+) error {
+
+	args := &PostReviewRequestsArgs{
+		Contexts: make([]*ReviewContext, 0, len(ctxs)),
+		Opts:     opts,
+	}
+	for _, ctx := range ctxs {
+		args.Contexts = append(args.Contexts, &ReviewContext{
+			Commit: toCommitData(ctx.Commit),
+			Story:  toStoryData(tool.client, ctx.Story),
+		})
+	}
+	return tool.client.call("CodeReviewTool.PostReviewRequests", args, &Empty{})
+}
+
+// PostReviewFollowupMessage is a part of common.CodeReviewTool interface.
+func (tool *codeReviewTool) PostReviewFollowupMessage() string {
+	var reply StringReply
+	err := tool.client.call("CodeReviewTool.PostReviewFollowupMessage", &Empty{}, &reply)
+	if err != nil {
+		return ""
+	}
+	return reply.Value
+}
+
+// release implements common.Release by calling the plugin.
+type release struct {
+	client  *client
+	version *version.Version
+	handle  Handle
+}
+
+func (r *release) getHandle() (Handle, error) {
+	if r.handle != "" {
+		return r.handle, nil
+	}
+	var reply HandleReply
+	err := r.client.call("CodeReviewTool.NewRelease", &VersionArgs{r.version.String()}, &reply)
+	if err != nil {
+		return "", err
+	}
+	r.handle = reply.Handle
+	return r.handle, nil
+}
+
+// Initialise is a part of common.Release interface.
+func (r *release) Initialise() (action.Action, error) {
+	handle, err := r.getHandle()
+	if err != nil {
+		return nil, err
+	}
+	return callAction(r.client, "Release.Initialise", &HandleArgs{handle})
+}
+
+// EnsureClosable is a part of common.Release interface.
+func (r *release) EnsureClosable() error {
+	handle, err := r.getHandle()
+	if err != nil {
+		return err
+	}
+	return r.client.call("Release.EnsureClosable", &HandleArgs{handle}, &Empty{})
+}
+
+// Close is a part of common.Release interface.
+func (r *release) Close() (action.Action, error) {
+	handle, err := r.getHandle()
+	if err != nil {
+		return nil, err
+	}
+	return callAction(r.client, "Release.Close", &HandleArgs{handle})
+}
+
+func toCommitData(commit *git.Commit) *Commit {
+	return &Commit{
+		SHA:          commit.SHA,
+		Source:       commit.Source,
+		Merge:        commit.Merge,
+		Author:       commit.Author,
+		AuthorDate:   commit.AuthorDate,
+		Committer:    commit.Committer,
+		CommitDate:   commit.CommitDate,
+		MessageTitle: commit.MessageTitle,
+		Message:      commit.Message,
+		ChangeIdTag:  commit.ChangeIdTag,
+		StoryIdTag:   commit.StoryIdTag,
+	}
+}
+
+func fromCommitData(commit *Commit) *git.Commit {
+	return &git.Commit{
+		SHA:          commit.SHA,
+		Source:       commit.Source,
+		Merge:        commit.Merge,
+		Author:       commit.Author,
+		AuthorDate:   commit.AuthorDate,
+		Committer:    commit.Committer,
+		CommitDate:   commit.CommitDate,
+		MessageTitle: commit.MessageTitle,
+		Message:      commit.Message,
+		ChangeIdTag:  commit.ChangeIdTag,
+		StoryIdTag:   commit.StoryIdTag,
+	}
+}
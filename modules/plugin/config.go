@@ -0,0 +1,133 @@
+package plugin
+
+import (
+	// Stdlib
+	"fmt"
+	"reflect"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/config"
+	"github.com/salsaflow/salsaflow/config/loader"
+	"github.com/salsaflow/salsaflow/prompt"
+)
+
+// Configuration spec ----------------------------------------------------------
+
+type configSpec struct {
+	desc   *DescribeReply
+	global *configContainer
+	local  *configContainer
+}
+
+func newConfigSpec(desc *DescribeReply) *configSpec {
+	return &configSpec{desc: desc}
+}
+
+// ConfigKey is a part of loader.ConfigSpec
+func (spec *configSpec) ConfigKey() string {
+	return spec.desc.ModuleId
+}
+
+// ModuleKind is a part of loader.ModuleConfigSpec
+func (spec *configSpec) ModuleKind() loader.ModuleKind {
+	return loader.ModuleKind(spec.desc.ModuleKind)
+}
+
+// GlobalConfig is a part of loader.ConfigSpec
+func (spec *configSpec) GlobalConfig() loader.ConfigContainer {
+	if len(spec.desc.GlobalConfig) == 0 {
+		return nil
+	}
+	spec.global = newConfigContainer(spec.desc.GlobalConfig)
+	return spec.global
+}
+
+// LocalConfig is a part of loader.ConfigSpec
+func (spec *configSpec) LocalConfig() loader.ConfigContainer {
+	if len(spec.desc.LocalConfig) == 0 {
+		return nil
+	}
+	spec.local = newConfigContainer(spec.desc.LocalConfig)
+	return spec.local
+}
+
+// Configuration container -----------------------------------------------------
+
+// configContainer implements loader.ConfigContainer for the fields
+// declared by the plugin. The values are kept in a struct assembled at runtime
+// so that the fields are treated the same way as the fields of the built-in modules.
+type configContainer struct {
+	fields []reflect.StructField
+	value  reflect.Value
+}
+
+func newConfigContainer(fields []*ConfigField) *configContainer {
+	structFields := make([]reflect.StructField, 0, len(fields))
+	for i, field := range fields {
+		tag := fmt.Sprintf(`json:%q prompt:%q`, field.Key, field.Prompt)
+		if field.Default != "" {
+			tag += fmt.Sprintf(` default:%q`, field.Default)
+		}
+		if field.Secret {
+			tag += ` secret:"true"`
+		}
+		if field.Optional {
+			tag += ` optional:"true"`
+		}
+		structFields = append(structFields, reflect.StructField{
+			Name: fmt.Sprintf("Field%v", i),
+			Type: reflect.TypeOf(""),
+			Tag:  reflect.StructTag(tag),
+		})
+	}
+	return &configContainer{
+		fields: structFields,
+		value:  reflect.New(reflect.StructOf(structFields)),
+	}
+}
+
+// ConfigFields is a part of loader.FieldLister interface.
+func (container *configContainer) ConfigFields() []reflect.StructField {
+	return container.fields
+}
+
+// PromptUserForConfig is a part of loader.ConfigContainer interface.
+func (container *configContainer) PromptUserForConfig() error {
+	v := reflect.New(container.value.Elem().Type())
+	if err := prompt.Dialog(v.Interface(), "Insert the"); err != nil {
+		return err
+	}
+	container.value = v
+	return nil
+}
+
+// Marshal is a part of loader.Marshaller interface.
+func (container *configContainer) Marshal() (interface{}, error) {
+	return container.value.Interface(), nil
+}
+
+// Unmarshal is a part of loader.Unmarshaller interface.
+func (container *configContainer) Unmarshal(unmarshal func(interface{}) error) error {
+	return unmarshal(container.value.Interface())
+}
+
+// Validate is a part of loader.Validator interface.
+func (container *configContainer) Validate(sectionPath string) error {
+	return config.EnsureValueFilled(container.value.Interface(), sectionPath)
+}
+
+// values returns the configuration values as passed to the plugin.
+// The container is nil in case the plugin declares no fields.
+func (container *configContainer) values() map[string]string {
+	if container == nil {
+		return map[string]string{}
+	}
+	var (
+		v  = container.value.Elem()
+		vs = make(map[string]string, len(container.fields))
+	)
+	for i, field := range container.fields {
+		vs[field.Tag.Get("json")] = v.Field(i).String()
+	}
+	return vs
+}
@@ -0,0 +1,91 @@
+package plugin
+
+import (
+	// Stdlib
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/config"
+)
+
+// ModulesDirname is the directory relative to the local configuration directory
+// that is searched for plugins before PATH is searched.
+const ModulesDirname = "modules"
+
+// Discover returns the paths of the plugins available, i.e. the executables
+// called salsaflow-module-ID placed in .salsaflow/modules or on PATH,
+// ID being the ID of the module implemented by the plugin.
+//
+// In case there are more plugins with the same name, the first one is used,
+// which means that the plugins in .salsaflow/modules take precedence.
+func Discover() []string {
+	var dirs []string
+	if configDir, err := config.LocalConfigDirectoryAbsolutePath(); err == nil {
+		dirs = append(dirs, filepath.Join(configDir, ModulesDirname))
+	}
+	dirs = append(dirs, filepath.SplitList(os.Getenv("PATH"))...)
+
+	var (
+		paths []string
+		seen  = make(map[string]struct{})
+	)
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		infos, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, info := range infos {
+			name := info.Name()
+			if !strings.HasPrefix(name, ExecutablePrefix) || !isExecutable(info) {
+				continue
+			}
+			if runtime.GOOS == "windows" {
+				name = strings.TrimSuffix(name, filepath.Ext(name))
+			}
+			if _, ok := seen[name]; ok {
+				continue
+			}
+			seen[name] = struct{}{}
+			paths = append(paths, filepath.Join(dir, info.Name()))
+		}
+	}
+	return paths
+}
+
+// Lookup returns the path of the plugin implementing the module
+// with the given module ID, an empty string when there is no such plugin.
+func Lookup(moduleId string) string {
+	for _, path := range Discover() {
+		if pluginName(path) == ExecutablePrefix+moduleId {
+			return path
+		}
+	}
+	return ""
+}
+
+// pluginName returns the name of the plugin located at the given path,
+// i.e. the executable name without the extension on Windows.
+func pluginName(path string) string {
+	name := filepath.Base(path)
+	if runtime.GOOS == "windows" {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name
+}
+
+func isExecutable(info os.FileInfo) bool {
+	if info.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return strings.ToLower(filepath.Ext(info.Name())) == ".exe"
+	}
+	return info.Mode()&0111 != 0
+}
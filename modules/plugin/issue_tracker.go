@@ -0,0 +1,253 @@
+package plugin
+
+import (
+	// Internal
+	"github.com/salsaflow/salsaflow/action"
+	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/version"
+)
+
+// issueTracker implements common.IssueTracker by calling the plugin.
+type issueTracker struct {
+	client *client
+}
+
+// ServiceName is a part of common.IssueTracker interface.
+func (tracker *issueTracker) ServiceName() string {
+	var reply StringReply
+	if err := tracker.client.call("IssueTracker.ServiceName", &Empty{}, &reply); err != nil {
+		return tracker.client.path
+	}
+	return reply.Value
+}
+
+// CurrentUser is a part of common.IssueTracker interface.
+func (tracker *issueTracker) CurrentUser() (common.User, error) {
+	var reply UserReply
+	if err := tracker.client.call("IssueTracker.CurrentUser", &Empty{}, &reply); err != nil {
+		return nil, err
+	}
+	return &user{reply.User}, nil
+}
+
+// StartableStories is a part of common.IssueTracker interface.
+func (tracker *issueTracker) StartableStories() ([]common.Story, error) {
+	return tracker.listStories("IssueTracker.StartableStories", &Empty{})
+}
+
+// ReviewableStories is a part of common.IssueTracker interface.
+func (tracker *issueTracker) ReviewableStories() ([]common.Story, error) {
+	return tracker.listStories("IssueTracker.ReviewableStories", &Empty{})
+}
+
+// ReviewedStories is a part of common.IssueTracker interface.
+func (tracker *issueTracker) ReviewedStories() ([]common.Story, error) {
+	return tracker.listStories("IssueTracker.ReviewedStories", &Empty{})
+}
+
+// ListStoriesByTag is a part of common.IssueTracker interface.
+func (tracker *issueTracker) ListStoriesByTag(tags []string) ([]common.Story, error) {
+	return tracker.listStories("IssueTracker.ListStoriesByTag", &TagsArgs{tags})
+}
+
+// ListStoriesByRelease is a part of common.IssueTracker interface.
+func (tracker *issueTracker) ListStoriesByRelease(v *version.Version) ([]common.Story, error) {
+	return tracker.listStories("IssueTracker.ListStoriesByRelease", &VersionArgs{v.String()})
+}
+
+// NextRelease is a part of common.IssueTracker interface.
+func (tracker *issueTracker) NextRelease(
+	trunkVersion *version.Version,
+	nextTrunkVersion *version.Version,
+) common.NextRelease {
+
+	return &nextRelease{
+		tracker: tracker,
+		args:    &NextReleaseArgs{trunkVersion.String(), nextTrunkVersion.String()},
+	}
+}
+
+// RunningRelease is a part of common.IssueTracker interface.
+func (tracker *issueTracker) RunningRelease(v *version.Version) common.RunningRelease {
+	return &runningRelease{tracker: tracker, version: v}
+}
+
+// OpenStory is a part of common.IssueTracker interface.
+func (tracker *issueTracker) OpenStory(storyId string) error {
+	return tracker.client.call("IssueTracker.OpenStory", &StringArgs{storyId}, &Empty{})
+}
+
+// StoryTagToReadableStoryId is a part of common.IssueTracker interface.
+func (tracker *issueTracker) StoryTagToReadableStoryId(tag string) (storyId string, err error) {
+	var reply StringReply
+	err = tracker.client.call("IssueTracker.StoryTagToReadableStoryId", &StringArgs{tag}, &reply)
+	if err != nil {
+		return "", err
+	}
+	return reply.Value, nil
+}
+
+func (tracker *issueTracker) listStories(method string, args interface{}) ([]common.Story, error) {
+	var reply StoriesReply
+	if err := tracker.client.call(method, args, &reply); err != nil {
+		return nil, err
+	}
+	return tracker.toStories(reply.Stories), nil
+}
+
+// toStories turns the stories as returned by the plugin into common.Story objects.
+// The stories that are null are kept as nil.
+func (tracker *issueTracker) toStories(data []*Story) []common.Story {
+	stories := make([]common.Story, len(data))
+	for i, s := range data {
+		if s != nil {
+			stories[i] = &story{tracker, s}
+		}
+	}
+	return stories
+}
+
+// user ------------------------------------------------------------------------
+
+type user struct {
+	data *User
+}
+
+func (u *user) Id() string {
+	return u.data.Id
+}
+
+func toUserData(users []common.User) []*User {
+	data := make([]*User, 0, len(users))
+	for _, u := range users {
+		data = append(data, &User{u.Id()})
+	}
+	return data
+}
+
+// nextRelease -----------------------------------------------------------------
+
+type nextRelease struct {
+	tracker *issueTracker
+	args    *NextReleaseArgs
+	handle  Handle
+}
+
+func (release *nextRelease) getHandle() (Handle, error) {
+	if release.handle != "" {
+		return release.handle, nil
+	}
+	var reply HandleReply
+	err := release.tracker.client.call("IssueTracker.NextRelease", release.args, &reply)
+	if err != nil {
+		return "", err
+	}
+	release.handle = reply.Handle
+	return release.handle, nil
+}
+
+// PromptUserToConfirmStart is a part of common.NextRelease interface.
+func (release *nextRelease) PromptUserToConfirmStart() (bool, error) {
+	handle, err := release.getHandle()
+	if err != nil {
+		return false, err
+	}
+	var reply BoolReply
+	err = release.tracker.client.call(
+		"NextRelease.PromptUserToConfirmStart", &HandleArgs{handle}, &reply)
+	if err != nil {
+		return false, err
+	}
+	return reply.Value, nil
+}
+
+// Start is a part of common.NextRelease interface.
+func (release *nextRelease) Start() (action.Action, error) {
+	handle, err := release.getHandle()
+	if err != nil {
+		return nil, err
+	}
+	return callAction(release.tracker.client, "NextRelease.Start", &HandleArgs{handle})
+}
+
+// runningRelease --------------------------------------------------------------
+
+type runningRelease struct {
+	tracker *issueTracker
+	version *version.Version
+	handle  Handle
+}
+
+func (release *runningRelease) getHandle() (Handle, error) {
+	if release.handle != "" {
+		return release.handle, nil
+	}
+	var reply HandleReply
+	err := release.tracker.client.call(
+		"IssueTracker.RunningRelease", &VersionArgs{release.version.String()}, &reply)
+	if err != nil {
+		return "", err
+	}
+	release.handle = reply.Handle
+	return release.handle, nil
+}
+
+// Version is a part of common.RunningRelease interface.
+func (release *runningRelease) Version() *version.Version {
+	return release.version
+}
+
+// Stories is a part of common.RunningRelease interface.
+func (release *runningRelease) Stories() ([]common.Story, error) {
+	handle, err := release.getHandle()
+	if err != nil {
+		return nil, err
+	}
+	return release.tracker.listStories("RunningRelease.Stories", &HandleArgs{handle})
+}
+
+// EnsureStageable is a part of common.RunningRelease interface.
+func (release *runningRelease) EnsureStageable() error {
+	return release.callHandle("RunningRelease.EnsureStageable")
+}
+
+// Stage is a part of common.RunningRelease interface.
+func (release *runningRelease) Stage() (action.Action, error) {
+	handle, err := release.getHandle()
+	if err != nil {
+		return nil, err
+	}
+	return callAction(release.tracker.client, "RunningRelease.Stage", &HandleArgs{handle})
+}
+
+// EnsureClosable is a part of common.RunningRelease interface.
+func (release *runningRelease) EnsureClosable() error {
+	return release.callHandle("RunningRelease.EnsureClosable")
+}
+
+// Close is a part of common.RunningRelease interface.
+func (release *runningRelease) Close() (action.Action, error) {
+	handle, err := release.getHandle()
+	if err != nil {
+		return nil, err
+	}
+	return callAction(release.tracker.client, "RunningRelease.Close", &HandleArgs{handle})
+}
+
+func (release *runningRelease) callHandle(method string) error {
+	handle, err := release.getHandle()
+	if err != nil {
+		return err
+	}
+	return release.tracker.client.call(method, &HandleArgs{handle}, &Empty{})
+}
+
+// callAction calls the given method that returns an action handle
+// and turns the handle into an action.
+func callAction(c *client, method string, args interface{}) (action.Action, error) {
+	var reply HandleReply
+	if err := c.call(method, args, &reply); err != nil {
+		return nil, err
+	}
+	return c.action(reply.Handle), nil
+}
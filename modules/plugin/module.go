@@ -0,0 +1,148 @@
+package plugin
+
+import (
+	// Stdlib
+	"fmt"
+	"io"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/config/loader"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/modules/common"
+)
+
+// Load starts the plugin located at the given path and returns
+// the module it implements. The module returned implements the interface
+// matching the module kind, e.g. common.IssueTrackingModule.
+// It also implements io.Closer, Close stops the plugin.
+func Load(path string) (mod loader.Module, err error) {
+	task := fmt.Sprintf("Load plugin '%v'", path)
+
+	c, err := startClient(path)
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+	defer func() {
+		// Stop the plugin in case it cannot be used.
+		if err != nil {
+			c.Close()
+		}
+	}()
+
+	var desc DescribeReply
+	if err := c.call("Module.Describe", &Empty{}, &desc); err != nil {
+		return nil, errs.NewError(task, err)
+	}
+	if desc.ProtocolVersion != ProtocolVersion {
+		err := fmt.Errorf(
+			"unsupported protocol version: %v (expected %v)", desc.ProtocolVersion, ProtocolVersion)
+		return nil, errs.NewError(task, err)
+	}
+
+	base := &module{client: c, desc: &desc}
+	switch kind := loader.ModuleKind(desc.ModuleKind); kind {
+	case loader.ModuleKindIssueTracking:
+		return &issueTrackingModule{base}, nil
+	case loader.ModuleKindCodeReview:
+		return &codeReviewModule{base}, nil
+	case loader.ModuleKindReleaseNotes:
+		return &releaseNotesModule{base}, nil
+	default:
+		return nil, errs.NewError(task, fmt.Errorf("unknown module kind: %v", kind))
+	}
+}
+
+// LoadDescription starts the plugin located at the given path, gets the module
+// description and stops the plugin again. The module returned can be used
+// to get the module ID, kind and config spec, the plugin is not running.
+func LoadDescription(path string) (loader.Module, error) {
+	mod, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := mod.(io.Closer).Close(); err != nil {
+		return nil, errs.NewError(fmt.Sprintf("Stop plugin '%v'", path), err)
+	}
+	return mod, nil
+}
+
+// module implements loader.Module for a plugin.
+type module struct {
+	client     *client
+	desc       *DescribeReply
+	configured bool
+}
+
+func (mod *module) Id() string {
+	return mod.desc.ModuleId
+}
+
+func (mod *module) Kind() loader.ModuleKind {
+	return loader.ModuleKind(mod.desc.ModuleKind)
+}
+
+// Close stops the plugin process.
+func (mod *module) Close() error {
+	return mod.client.Close()
+}
+
+func (mod *module) ConfigSpec() loader.ModuleConfigSpec {
+	return newConfigSpec(mod.desc)
+}
+
+// configure loads the module configuration and passes it to the plugin.
+func (mod *module) configure() error {
+	if mod.configured {
+		return nil
+	}
+
+	task := fmt.Sprintf("Load config for module '%v'", mod.Id())
+	spec := newConfigSpec(mod.desc)
+	if err := loader.LoadConfig(spec); err != nil {
+		return errs.NewError(task, err)
+	}
+
+	task = fmt.Sprintf("Configure module '%v'", mod.Id())
+	args := &ConfigureArgs{
+		GlobalConfig: spec.global.values(),
+		LocalConfig:  spec.local.values(),
+	}
+	if err := mod.client.call("Module.Configure", args, &Empty{}); err != nil {
+		return errs.NewError(task, err)
+	}
+	mod.configured = true
+	return nil
+}
+
+type issueTrackingModule struct {
+	*module
+}
+
+func (mod *issueTrackingModule) NewIssueTracker() (common.IssueTracker, error) {
+	if err := mod.configure(); err != nil {
+		return nil, err
+	}
+	return &issueTracker{mod.client}, nil
+}
+
+type codeReviewModule struct {
+	*module
+}
+
+func (mod *codeReviewModule) NewCodeReviewTool() (common.CodeReviewTool, error) {
+	if err := mod.configure(); err != nil {
+		return nil, err
+	}
+	return &codeReviewTool{mod.client}, nil
+}
+
+type releaseNotesModule struct {
+	*module
+}
+
+func (mod *releaseNotesModule) NewReleaseNotesManager() (common.ReleaseNotesManager, error) {
+	if err := mod.configure(); err != nil {
+		return nil, err
+	}
+	return &releaseNotesManager{mod.client}, nil
+}
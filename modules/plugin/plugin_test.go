@@ -0,0 +1,240 @@
+package plugin
+
+import (
+	// Stdlib
+	"errors"
+	"net"
+	"net/rpc/jsonrpc"
+	"os/exec"
+	"testing"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/action"
+	"github.com/salsaflow/salsaflow/config"
+	"github.com/salsaflow/salsaflow/config/loader"
+	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/version"
+
+	// Vendor - testing framework
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var (
+	BeforeEach = ginkgo.BeforeEach
+	Describe   = ginkgo.Describe
+	It         = ginkgo.It
+
+	BeNil        = gomega.BeNil
+	Equal        = gomega.Equal
+	Expect       = gomega.Expect
+	HaveLen      = gomega.HaveLen
+	HaveOccurred = gomega.HaveOccurred
+)
+
+func TestPlugin(t *testing.T) {
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "Module plugins")
+}
+
+// Fake issue tracker ----------------------------------------------------------
+
+type fakeTracker struct {
+	common.IssueTracker
+
+	stories    map[string]*fakeStory
+	log        []string
+	stageable  bool
+	configured map[string]string
+}
+
+func (tracker *fakeTracker) ServiceName() string {
+	return "Fake"
+}
+
+func (tracker *fakeTracker) ListStoriesByTag(tags []string) ([]common.Story, error) {
+	stories := make([]common.Story, len(tags))
+	for i, tag := range tags {
+		if s, ok := tracker.stories[tag]; ok {
+			stories[i] = s
+		}
+	}
+	return stories, nil
+}
+
+func (tracker *fakeTracker) RunningRelease(v *version.Version) common.RunningRelease {
+	return &fakeRelease{tracker, v}
+}
+
+type fakeStory struct {
+	common.Story
+
+	tag       string
+	assignees []common.User
+}
+
+func (s *fakeStory) Id() string               { return s.tag }
+func (s *fakeStory) ReadableId() string       { return s.tag }
+func (s *fakeStory) Type() string             { return "feature" }
+func (s *fakeStory) State() common.StoryState { return common.StoryStateBeingImplemented }
+func (s *fakeStory) URL() string              { return "https://example.com/" + s.tag }
+func (s *fakeStory) Tag() string              { return s.tag }
+func (s *fakeStory) Title() string            { return "Story " + s.tag }
+func (s *fakeStory) Assignees() []common.User { return s.assignees }
+func (s *fakeStory) AddAssignee(u common.User) error {
+	s.assignees = append(s.assignees, u)
+	return nil
+}
+
+type fakeRelease struct {
+	tracker *fakeTracker
+	version *version.Version
+}
+
+func (r *fakeRelease) Version() *version.Version        { return r.version }
+func (r *fakeRelease) Stories() ([]common.Story, error) { return nil, nil }
+func (r *fakeRelease) EnsureClosable() error            { return nil }
+func (r *fakeRelease) Close() (action.Action, error)    { return nil, nil }
+
+func (r *fakeRelease) EnsureStageable() error {
+	if !r.tracker.stageable {
+		return common.ErrNotStageable
+	}
+	return nil
+}
+
+func (r *fakeRelease) Stage() (action.Action, error) {
+	r.tracker.log = append(r.tracker.log, "stage "+r.version.String())
+	return action.ActionFunc(func() error {
+		r.tracker.log = append(r.tracker.log, "unstage "+r.version.String())
+		return errors.New("unstage failed")
+	}), nil
+}
+
+// Tests -----------------------------------------------------------------------
+
+var _ = Describe("plugin protocol", func() {
+
+	var (
+		fake    *fakeTracker
+		c       *client
+		tracker common.IssueTracker
+	)
+
+	BeforeEach(func() {
+		fake = &fakeTracker{
+			stories: map[string]*fakeStory{
+				"FAKE-1": &fakeStory{tag: "FAKE-1"},
+			},
+		}
+		mod := &Module{
+			Id:   "salsaflow.modules.issuetracking.fake",
+			Kind: loader.ModuleKindIssueTracking,
+			GlobalConfig: []*ConfigField{
+				{Key: "token", Prompt: "fake token", Secret: true},
+			},
+			NewIssueTracker: func(global, local map[string]string) (common.IssueTracker, error) {
+				fake.configured = global
+				return fake, nil
+			},
+		}
+
+		server, err := newServer(mod)
+		Expect(err).NotTo(HaveOccurred())
+
+		serverConn, clientConn := net.Pipe()
+		go server.ServeCodec(jsonrpc.NewServerCodec(serverConn))
+		c = &client{path: "salsaflow-module-fake", rpc: jsonrpc.NewClient(clientConn)}
+
+		var desc DescribeReply
+		Expect(c.call("Module.Describe", &Empty{}, &desc)).To(BeNil())
+		Expect(desc.ProtocolVersion).To(Equal(ProtocolVersion))
+		Expect(desc.ModuleId).To(Equal(mod.Id))
+
+		args := &ConfigureArgs{GlobalConfig: map[string]string{"token": "secret"}}
+		Expect(c.call("Module.Configure", args, &Empty{})).To(BeNil())
+		Expect(fake.configured).To(Equal(args.GlobalConfig))
+
+		tracker = &issueTracker{c}
+	})
+
+	It("lists stories by tag, keeping the stories not found as nil", func() {
+		stories, err := tracker.ListStoriesByTag([]string{"FAKE-2", "FAKE-1"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stories).To(HaveLen(2))
+		Expect(stories[0]).To(BeNil())
+		Expect(stories[1].Title()).To(Equal("Story FAKE-1"))
+		Expect(stories[1].IssueTracker()).To(Equal(tracker))
+	})
+
+	It("modifies the stories using the handles", func() {
+		stories, err := tracker.ListStoriesByTag([]string{"FAKE-1"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(stories[0].AddAssignee(&user{&User{"joe"}})).To(BeNil())
+		Expect(fake.stories["FAKE-1"].assignees).To(HaveLen(1))
+		Expect(stories[0].Assignees()[0].Id()).To(Equal("joe"))
+	})
+
+	It("keeps ErrNotStageable as the error returned", func() {
+		v, _ := version.Parse("1.2.0")
+		err := tracker.RunningRelease(v).EnsureStageable()
+		Expect(err).To(Equal(common.ErrNotStageable))
+	})
+
+	It("rolls back the actions using the action handles", func() {
+		v, _ := version.Parse("1.2.0")
+		act, err := tracker.RunningRelease(v).Stage()
+		Expect(err).NotTo(HaveOccurred())
+
+		err = act.Rollback()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Action.Rollback: unstage failed"))
+		Expect(fake.log).To(Equal([]string{"stage 1.2.0", "unstage 1.2.0"}))
+	})
+})
+
+var _ = Describe("plugin config spec", func() {
+
+	It("treats the fields declared by the plugin as regular config fields", func() {
+		spec := newConfigSpec(&DescribeReply{
+			ModuleId:   "salsaflow.modules.issuetracking.fake",
+			ModuleKind: string(loader.ModuleKindIssueTracking),
+			GlobalConfig: []*ConfigField{
+				{Key: "token", Prompt: "fake token", Secret: true},
+				{Key: "endpoint", Prompt: "fake endpoint", Optional: true},
+			},
+		})
+
+		keys := loader.SecretKeys(spec)
+		Expect(keys).To(HaveLen(1))
+		Expect(keys[0].String()).To(Equal("salsaflow.modules.issuetracking.fake.token"))
+		Expect(spec.LocalConfig()).To(BeNil())
+
+		container := spec.global
+		err := container.Unmarshal(func(v interface{}) error {
+			return config.Unmarshal([]byte(`{"token": "secret"}`), v)
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(container.Validate("fake")).To(BeNil())
+		Expect(container.values()).To(Equal(map[string]string{
+			"token":    "secret",
+			"endpoint": "",
+		}))
+	})
+})
+
+var _ = Describe("plugin client", func() {
+
+	It("stops the plugin process on close", func() {
+		path, err := exec.LookPath("cat")
+		if err != nil {
+			ginkgo.Skip("cat not available")
+		}
+
+		c, err := startClient(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Close()).To(BeNil())
+		Expect(c.cmd.ProcessState.Exited()).To(Equal(true))
+	})
+})
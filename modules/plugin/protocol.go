@@ -0,0 +1,195 @@
+/*
+Package plugin implements out-of-process SalsaFlow modules.
+
+A plugin is an executable called salsaflow-module-ID, ID being the ID of the module
+it implements, that is placed either into .salsaflow/modules in the repository
+or somewhere on PATH. SalsaFlow starts the plugin when the module with the given ID
+is needed and talks to it using JSON-RPC 1.0 over
+the standard input and output of the plugin, one JSON object per request
+or response. The standard error output is passed to the user as it is.
+The plugin is supposed to exit once its standard input is closed.
+
+Every method is called with a single parameter object and returns a single result
+object, the types are defined in this file. Stories, releases and rollback actions
+are referenced using opaque handles chosen by the plugin. An empty action handle
+means that there is nothing to roll back.
+
+The plugin must implement the Module service and the service for the module kind
+it declares, i.e. IssueTracker, Story, NextRelease, RunningRelease for issue tracking,
+CodeReviewTool and Release for code review, ReleaseNotesManager for release notes,
+plus the Action service for rolling back the changes made.
+Module.Configure is called before any other method except Module.Describe.
+
+EnsureStageable and EnsureClosable are supposed to return "release cannot be staged"
+and "release cannot be closed" error messages respectively in case the release
+cannot be staged or closed yet.
+
+Plugins written in Go can simply call Serve.
+*/
+package plugin
+
+import (
+	// Stdlib
+	"time"
+)
+
+// ProtocolVersion is the version of the plugin protocol implemented by this package.
+// The plugin is rejected in case it declares a different protocol version.
+const ProtocolVersion = 1
+
+// ExecutablePrefix is the prefix of the executables considered to be plugins.
+const ExecutablePrefix = "salsaflow-module-"
+
+// Handle references an object kept by the plugin.
+type Handle string
+
+// Common types ----------------------------------------------------------------
+
+// Empty is used for the methods that take or return nothing.
+type Empty struct{}
+
+type HandleArgs struct {
+	Handle Handle `json:"handle"`
+}
+
+type HandleReply struct {
+	Handle Handle `json:"handle"`
+}
+
+type BoolReply struct {
+	Value bool `json:"value"`
+}
+
+type StringArgs struct {
+	Value string `json:"value"`
+}
+
+type StringReply struct {
+	Value string `json:"value"`
+}
+
+type VersionArgs struct {
+	Version string `json:"version"`
+}
+
+// Module service --------------------------------------------------------------
+
+// DescribeReply is returned from Module.Describe.
+type DescribeReply struct {
+	ProtocolVersion int            `json:"protocol_version"`
+	ModuleId        string         `json:"module_id"`
+	ModuleKind      string         `json:"module_kind"`
+	GlobalConfig    []*ConfigField `json:"global_config,omitempty"`
+	LocalConfig     []*ConfigField `json:"local_config,omitempty"`
+}
+
+// ConfigField describes a configuration field of the plugin.
+// The values are stored in the configuration files as strings.
+type ConfigField struct {
+	Key      string `json:"key"`
+	Prompt   string `json:"prompt"`
+	Default  string `json:"default,omitempty"`
+	Secret   bool   `json:"secret,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+// ConfigureArgs is passed to Module.Configure.
+type ConfigureArgs struct {
+	GlobalConfig map[string]string `json:"global_config"`
+	LocalConfig  map[string]string `json:"local_config"`
+}
+
+// IssueTracker service --------------------------------------------------------
+
+type User struct {
+	Id string `json:"id"`
+}
+
+type Story struct {
+	Handle     Handle  `json:"handle,omitempty"`
+	Id         string  `json:"id"`
+	ReadableId string  `json:"readable_id"`
+	Type       string  `json:"type"`
+	State      string  `json:"state"`
+	URL        string  `json:"url"`
+	Tag        string  `json:"tag"`
+	Title      string  `json:"title"`
+	Assignees  []*User `json:"assignees"`
+}
+
+type UserReply struct {
+	User *User `json:"user"`
+}
+
+// StoriesReply contains the stories returned. In case of ListStoriesByTag,
+// the stories are in the same order as the tags, null meaning not found.
+type StoriesReply struct {
+	Stories []*Story `json:"stories"`
+}
+
+type TagsArgs struct {
+	Tags []string `json:"tags"`
+}
+
+type NextReleaseArgs struct {
+	TrunkVersion     string `json:"trunk_version"`
+	NextTrunkVersion string `json:"next_trunk_version"`
+}
+
+// Story service ---------------------------------------------------------------
+
+type AssigneesArgs struct {
+	Handle    Handle  `json:"handle"`
+	Assignees []*User `json:"assignees"`
+}
+
+// StoryReply contains the story state after the story was modified.
+type StoryReply struct {
+	Story *Story `json:"story"`
+}
+
+type LessThanArgs struct {
+	Handle Handle `json:"handle"`
+	Other  Handle `json:"other"`
+}
+
+// CodeReviewTool service ------------------------------------------------------
+
+type Commit struct {
+	SHA          string    `json:"sha"`
+	Source       string    `json:"source"`
+	Merge        string    `json:"merge,omitempty"`
+	Author       string    `json:"author"`
+	AuthorDate   time.Time `json:"author_date"`
+	Committer    string    `json:"committer"`
+	CommitDate   time.Time `json:"commit_date"`
+	MessageTitle string    `json:"message_title"`
+	Message      string    `json:"message"`
+	ChangeIdTag  string    `json:"change_id_tag"`
+	StoryIdTag   string    `json:"story_id_tag"`
+}
+
+// ReviewContext is the review context as passed to the plugin.
+// The story is null in case the commit is not associated with any story,
+// stories coming from another module are passed without a handle.
+type ReviewContext struct {
+	Commit *Commit `json:"commit"`
+	Story  *Story  `json:"story"`
+}
+
+type PostReviewRequestsArgs struct {
+	Contexts []*ReviewContext       `json:"contexts"`
+	Opts     map[string]interface{} `json:"opts"`
+}
+
+// ReleaseNotesManager service -------------------------------------------------
+
+type ReleaseNotes struct {
+	Version  string                 `json:"version"`
+	Sections []*ReleaseNotesSection `json:"sections"`
+}
+
+type ReleaseNotesSection struct {
+	StoryType string   `json:"story_type"`
+	Stories   []*Story `json:"stories"`
+}
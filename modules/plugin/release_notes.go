@@ -0,0 +1,34 @@
+package plugin
+
+import (
+	// Internal
+	"github.com/salsaflow/salsaflow/action"
+	"github.com/salsaflow/salsaflow/modules/common"
+)
+
+// releaseNotesManager implements common.ReleaseNotesManager by calling the plugin.
+type releaseNotesManager struct {
+	client *client
+}
+
+// PostReleaseNotes is a part of common.ReleaseNotesManager interface.
+func (manager *releaseNotesManager) PostReleaseNotes(
+	notes *common.ReleaseNotes,
+) (action.Action, error) {
+
+	args := &ReleaseNotes{
+		Version:  notes.Version.String(),
+		Sections: make([]*ReleaseNotesSection, 0, len(notes.Sections)),
+	}
+	for _, section := range notes.Sections {
+		stories := make([]*Story, 0, len(section.Stories))
+		for _, s := range section.Stories {
+			stories = append(stories, toStoryData(manager.client, s))
+		}
+		args.Sections = append(args.Sections, &ReleaseNotesSection{
+			StoryType: section.StoryType,
+			Stories:   stories,
+		})
+	}
+	return callAction(manager.client, "ReleaseNotesManager.PostReleaseNotes", args)
+}
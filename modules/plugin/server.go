@@ -0,0 +1,784 @@
+package plugin
+
+import (
+	// Stdlib
+	"errors"
+	"fmt"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"strconv"
+	"sync"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/action"
+	"github.com/salsaflow/salsaflow/config/loader"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/version"
+)
+
+// ErrNotConfigured is returned when a method is called before Module.Configure.
+var ErrNotConfigured = errors.New("module not configured")
+
+// Module describes a module implemented by a plugin written in Go.
+//
+// Only the constructor matching the module kind is used. The constructor
+// is passed the configuration values for the fields declared.
+type Module struct {
+	Id   string
+	Kind loader.ModuleKind
+
+	GlobalConfig []*ConfigField
+	LocalConfig  []*ConfigField
+
+	NewIssueTracker        func(global, local map[string]string) (common.IssueTracker, error)
+	NewCodeReviewTool      func(global, local map[string]string) (common.CodeReviewTool, error)
+	NewReleaseNotesManager func(global, local map[string]string) (common.ReleaseNotesManager, error)
+}
+
+// Serve serves the given module over the standard input and output
+// until the standard input is closed.
+//
+// The standard output is redirected to the standard error output
+// so that anything printed by the module does not break the protocol.
+func Serve(mod *Module) error {
+	var (
+		stdin  = os.Stdin
+		stdout = os.Stdout
+	)
+	os.Stdout = os.Stderr
+
+	server, err := newServer(mod)
+	if err != nil {
+		return err
+	}
+	server.ServeCodec(jsonrpc.NewServerCodec(&pipeConn{stdin, stdout}))
+	return nil
+}
+
+func newServer(mod *Module) (*rpc.Server, error) {
+	srv := &server{
+		mod:     mod,
+		objects: make(map[Handle]interface{}),
+	}
+
+	server := rpc.NewServer()
+	services := map[string]interface{}{
+		"Module":              &moduleService{srv},
+		"IssueTracker":        &issueTrackerService{srv},
+		"Story":               &storyService{srv},
+		"NextRelease":         &nextReleaseService{srv},
+		"RunningRelease":      &runningReleaseService{srv},
+		"CodeReviewTool":      &codeReviewToolService{srv},
+		"Release":             &releaseService{srv},
+		"ReleaseNotesManager": &releaseNotesManagerService{srv},
+		"Action":              &actionService{srv},
+	}
+	for name, service := range services {
+		if err := server.RegisterName(name, service); err != nil {
+			return nil, err
+		}
+	}
+	return server, nil
+}
+
+// server keeps the module instances and the objects referenced by handles.
+type server struct {
+	mod *Module
+
+	tracker common.IssueTracker
+	tool    common.CodeReviewTool
+	manager common.ReleaseNotesManager
+
+	objects map[Handle]interface{}
+	next    int
+	lock    sync.Mutex
+}
+
+// put stores the given object and returns the handle referencing it.
+func (srv *server) put(object interface{}) Handle {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+	srv.next++
+	handle := Handle(strconv.Itoa(srv.next))
+	srv.objects[handle] = object
+	return handle
+}
+
+// get returns the object referenced by the given handle.
+func (srv *server) get(handle Handle) (interface{}, error) {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+	object, ok := srv.objects[handle]
+	if !ok {
+		return nil, fmt.Errorf("invalid handle: %v", handle)
+	}
+	return object, nil
+}
+
+func (srv *server) putAction(act action.Action) Handle {
+	if act == nil {
+		return ""
+	}
+	return srv.put(act)
+}
+
+func (srv *server) storyData(story common.Story) *Story {
+	if story == nil {
+		return nil
+	}
+	if ds, ok := story.(*dataStory); ok {
+		return ds.data
+	}
+	users := story.Assignees()
+	assignees := make([]*User, 0, len(users))
+	for _, u := range users {
+		assignees = append(assignees, &User{u.Id()})
+	}
+	return &Story{
+		Handle:     srv.put(story),
+		Id:         story.Id(),
+		ReadableId: story.ReadableId(),
+		Type:       story.Type(),
+		State:      string(story.State()),
+		URL:        story.URL(),
+		Tag:        story.Tag(),
+		Title:      story.Title(),
+		Assignees:  assignees,
+	}
+}
+
+func (srv *server) storiesData(stories []common.Story) []*Story {
+	data := make([]*Story, 0, len(stories))
+	for _, story := range stories {
+		data = append(data, srv.storyData(story))
+	}
+	return data
+}
+
+// story returns the story for the given story data. The story kept by the server
+// is returned in case the handle is set, a read-only story is returned otherwise.
+func (srv *server) story(data *Story) (common.Story, error) {
+	if data == nil {
+		return nil, nil
+	}
+	if data.Handle == "" {
+		return &dataStory{data}, nil
+	}
+	object, err := srv.get(data.Handle)
+	if err != nil {
+		return nil, err
+	}
+	story, ok := object.(common.Story)
+	if !ok {
+		return nil, fmt.Errorf("not a story handle: %v", data.Handle)
+	}
+	return story, nil
+}
+
+func (srv *server) issueTracker() (common.IssueTracker, error) {
+	if srv.tracker == nil {
+		return nil, ErrNotConfigured
+	}
+	return srv.tracker, nil
+}
+
+// protocolError turns the given error into the error sent to SalsaFlow.
+func protocolError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch root := errs.RootCause(err); root {
+	case common.ErrNotStageable, common.ErrNotClosable:
+		return root
+	}
+	return err
+}
+
+// Module service --------------------------------------------------------------
+
+type moduleService struct {
+	srv *server
+}
+
+func (service *moduleService) Describe(args *Empty, reply *DescribeReply) error {
+	mod := service.srv.mod
+	*reply = DescribeReply{
+		ProtocolVersion: ProtocolVersion,
+		ModuleId:        mod.Id,
+		ModuleKind:      string(mod.Kind),
+		GlobalConfig:    mod.GlobalConfig,
+		LocalConfig:     mod.LocalConfig,
+	}
+	return nil
+}
+
+func (service *moduleService) Configure(args *ConfigureArgs, reply *Empty) error {
+	var (
+		srv    = service.srv
+		mod    = srv.mod
+		global = args.GlobalConfig
+		local  = args.LocalConfig
+		err    error
+	)
+	switch mod.Kind {
+	case loader.ModuleKindIssueTracking:
+		srv.tracker, err = mod.NewIssueTracker(global, local)
+	case loader.ModuleKindCodeReview:
+		srv.tool, err = mod.NewCodeReviewTool(global, local)
+	case loader.ModuleKindReleaseNotes:
+		srv.manager, err = mod.NewReleaseNotesManager(global, local)
+	default:
+		err = fmt.Errorf("unknown module kind: %v", mod.Kind)
+	}
+	return protocolError(err)
+}
+
+// IssueTracker service --------------------------------------------------------
+
+type issueTrackerService struct {
+	srv *server
+}
+
+func (service *issueTrackerService) ServiceName(args *Empty, reply *StringReply) error {
+	tracker, err := service.srv.issueTracker()
+	if err != nil {
+		return err
+	}
+	reply.Value = tracker.ServiceName()
+	return nil
+}
+
+func (service *issueTrackerService) CurrentUser(args *Empty, reply *UserReply) error {
+	tracker, err := service.srv.issueTracker()
+	if err != nil {
+		return err
+	}
+	user, err := tracker.CurrentUser()
+	if err != nil {
+		return protocolError(err)
+	}
+	reply.User = &User{user.Id()}
+	return nil
+}
+
+func (service *issueTrackerService) StartableStories(args *Empty, reply *StoriesReply) error {
+	return service.listStories(reply, func(tracker common.IssueTracker) ([]common.Story, error) {
+		return tracker.StartableStories()
+	})
+}
+
+func (service *issueTrackerService) ReviewableStories(args *Empty, reply *StoriesReply) error {
+	return service.listStories(reply, func(tracker common.IssueTracker) ([]common.Story, error) {
+		return tracker.ReviewableStories()
+	})
+}
+
+func (service *issueTrackerService) ReviewedStories(args *Empty, reply *StoriesReply) error {
+	return service.listStories(reply, func(tracker common.IssueTracker) ([]common.Story, error) {
+		return tracker.ReviewedStories()
+	})
+}
+
+func (service *issueTrackerService) ListStoriesByTag(args *TagsArgs, reply *StoriesReply) error {
+	return service.listStories(reply, func(tracker common.IssueTracker) ([]common.Story, error) {
+		return tracker.ListStoriesByTag(args.Tags)
+	})
+}
+
+func (service *issueTrackerService) ListStoriesByRelease(args *VersionArgs, reply *StoriesReply) error {
+	v, err := version.Parse(args.Version)
+	if err != nil {
+		return err
+	}
+	return service.listStories(reply, func(tracker common.IssueTracker) ([]common.Story, error) {
+		return tracker.ListStoriesByRelease(v)
+	})
+}
+
+func (service *issueTrackerService) NextRelease(args *NextReleaseArgs, reply *HandleReply) error {
+	tracker, err := service.srv.issueTracker()
+	if err != nil {
+		return err
+	}
+	trunkVersion, err := version.Parse(args.TrunkVersion)
+	if err != nil {
+		return err
+	}
+	nextTrunkVersion, err := version.Parse(args.NextTrunkVersion)
+	if err != nil {
+		return err
+	}
+	reply.Handle = service.srv.put(tracker.NextRelease(trunkVersion, nextTrunkVersion))
+	return nil
+}
+
+func (service *issueTrackerService) RunningRelease(args *VersionArgs, reply *HandleReply) error {
+	tracker, err := service.srv.issueTracker()
+	if err != nil {
+		return err
+	}
+	v, err := version.Parse(args.Version)
+	if err != nil {
+		return err
+	}
+	reply.Handle = service.srv.put(tracker.RunningRelease(v))
+	return nil
+}
+
+func (service *issueTrackerService) OpenStory(args *StringArgs, reply *Empty) error {
+	tracker, err := service.srv.issueTracker()
+	if err != nil {
+		return err
+	}
+	return protocolError(tracker.OpenStory(args.Value))
+}
+
+func (service *issueTrackerService) StoryTagToReadableStoryId(
+	args *StringArgs,
+	reply *StringReply,
+) error {
+
+	tracker, err := service.srv.issueTracker()
+	if err != nil {
+		return err
+	}
+	storyId, err := tracker.StoryTagToReadableStoryId(args.Value)
+	if err != nil {
+		return protocolError(err)
+	}
+	reply.Value = storyId
+	return nil
+}
+
+func (service *issueTrackerService) listStories(
+	reply *StoriesReply,
+	list func(common.IssueTracker) ([]common.Story, error),
+) error {
+
+	tracker, err := service.srv.issueTracker()
+	if err != nil {
+		return err
+	}
+	stories, err := list(tracker)
+	if err != nil {
+		return protocolError(err)
+	}
+	reply.Stories = service.srv.storiesData(stories)
+	return nil
+}
+
+// Story service ---------------------------------------------------------------
+
+type storyService struct {
+	srv *server
+}
+
+func (service *storyService) AddAssignee(args *AssigneesArgs, reply *StoryReply) error {
+	return service.updateAssignees(args, reply, func(story common.Story, users []common.User) error {
+		for _, u := range users {
+			if err := story.AddAssignee(u); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (service *storyService) SetAssignees(args *AssigneesArgs, reply *StoryReply) error {
+	return service.updateAssignees(args, reply, func(story common.Story, users []common.User) error {
+		return story.SetAssignees(users)
+	})
+}
+
+func (service *storyService) Start(args *HandleArgs, reply *Empty) error {
+	story, err := service.story(args.Handle)
+	if err != nil {
+		return err
+	}
+	return protocolError(story.Start())
+}
+
+func (service *storyService) MarkAsImplemented(args *HandleArgs, reply *HandleReply) error {
+	story, err := service.story(args.Handle)
+	if err != nil {
+		return err
+	}
+	act, err := story.MarkAsImplemented()
+	if err != nil {
+		return protocolError(err)
+	}
+	reply.Handle = service.srv.putAction(act)
+	return nil
+}
+
+func (service *storyService) LessThan(args *LessThanArgs, reply *BoolReply) error {
+	story, err := service.story(args.Handle)
+	if err != nil {
+		return err
+	}
+	other, err := service.story(args.Other)
+	if err != nil {
+		return err
+	}
+	reply.Value = story.LessThan(other)
+	return nil
+}
+
+func (service *storyService) story(handle Handle) (common.Story, error) {
+	return service.srv.story(&Story{Handle: handle})
+}
+
+func (service *storyService) updateAssignees(
+	args *AssigneesArgs,
+	reply *StoryReply,
+	update func(common.Story, []common.User) error,
+) error {
+
+	story, err := service.story(args.Handle)
+	if err != nil {
+		return err
+	}
+	users := make([]common.User, 0, len(args.Assignees))
+	for _, u := range args.Assignees {
+		users = append(users, &user{u})
+	}
+	if err := update(story, users); err != nil {
+		return protocolError(err)
+	}
+	reply.Story = service.srv.storyData(story)
+	return nil
+}
+
+// NextRelease service ---------------------------------------------------------
+
+type nextReleaseService struct {
+	srv *server
+}
+
+func (service *nextReleaseService) PromptUserToConfirmStart(args *HandleArgs, reply *BoolReply) error {
+	release, err := service.release(args.Handle)
+	if err != nil {
+		return err
+	}
+	confirmed, err := release.PromptUserToConfirmStart()
+	if err != nil {
+		return protocolError(err)
+	}
+	reply.Value = confirmed
+	return nil
+}
+
+func (service *nextReleaseService) Start(args *HandleArgs, reply *HandleReply) error {
+	release, err := service.release(args.Handle)
+	if err != nil {
+		return err
+	}
+	act, err := release.Start()
+	if err != nil {
+		return protocolError(err)
+	}
+	reply.Handle = service.srv.putAction(act)
+	return nil
+}
+
+func (service *nextReleaseService) release(handle Handle) (common.NextRelease, error) {
+	object, err := service.srv.get(handle)
+	if err != nil {
+		return nil, err
+	}
+	release, ok := object.(common.NextRelease)
+	if !ok {
+		return nil, fmt.Errorf("not a release handle: %v", handle)
+	}
+	return release, nil
+}
+
+// RunningRelease service ------------------------------------------------------
+
+type runningReleaseService struct {
+	srv *server
+}
+
+func (service *runningReleaseService) Stories(args *HandleArgs, reply *StoriesReply) error {
+	release, err := service.release(args.Handle)
+	if err != nil {
+		return err
+	}
+	stories, err := release.Stories()
+	if err != nil {
+		return protocolError(err)
+	}
+	reply.Stories = service.srv.storiesData(stories)
+	return nil
+}
+
+func (service *runningReleaseService) EnsureStageable(args *HandleArgs, reply *Empty) error {
+	release, err := service.release(args.Handle)
+	if err != nil {
+		return err
+	}
+	return protocolError(release.EnsureStageable())
+}
+
+func (service *runningReleaseService) Stage(args *HandleArgs, reply *HandleReply) error {
+	release, err := service.release(args.Handle)
+	if err != nil {
+		return err
+	}
+	act, err := release.Stage()
+	if err != nil {
+		return protocolError(err)
+	}
+	reply.Handle = service.srv.putAction(act)
+	return nil
+}
+
+func (service *runningReleaseService) EnsureClosable(args *HandleArgs, reply *Empty) error {
+	release, err := service.release(args.Handle)
+	if err != nil {
+		return err
+	}
+	return protocolError(release.EnsureClosable())
+}
+
+func (service *runningReleaseService) Close(args *HandleArgs, reply *HandleReply) error {
+	release, err := service.release(args.Handle)
+	if err != nil {
+		return err
+	}
+	act, err := release.Close()
+	if err != nil {
+		return protocolError(err)
+	}
+	reply.Handle = service.srv.putAction(act)
+	return nil
+}
+
+func (service *runningReleaseService) release(handle Handle) (common.RunningRelease, error) {
+	object, err := service.srv.get(handle)
+	if err != nil {
+		return nil, err
+	}
+	release, ok := object.(common.RunningRelease)
+	if !ok {
+		return nil, fmt.Errorf("not a release handle: %v", handle)
+	}
+	return release, nil
+}
+
+// CodeReviewTool service ------------------------------------------------------
+
+type codeReviewToolService struct {
+	srv *server
+}
+
+func (service *codeReviewToolService) NewRelease(args *VersionArgs, reply *HandleReply) error {
+	tool, err := service.tool()
+	if err != nil {
+		return err
+	}
+	v, err := version.Parse(args.Version)
+	if err != nil {
+		return err
+	}
+	reply.Handle = service.srv.put(tool.NewRelease(v))
+	return nil
+}
+
+func (service *codeReviewToolService) PostReviewRequests(
+	args *PostReviewRequestsArgs,
+	reply *Empty,
+) error {
+
+	tool, err := service.tool()
+	if err != nil {
+		return err
+	}
+	ctxs := make([]*common.ReviewContext, 0, len(args.Contexts))
+	for _, ctx := range args.Contexts {
+		story, err := service.srv.story(ctx.Story)
+		if err != nil {
+			return err
+		}
+		ctxs = append(ctxs, &common.ReviewContext{
+			Commit: fromCommitData(ctx.Commit),
+			Story:  story,
+		})
+	}
+	return protocolError(tool.PostReviewRequests(ctxs, args.Opts))
+}
+
+func (service *codeReviewToolService) PostReviewFollowupMessage(
+	args *Empty,
+	reply *StringReply,
+) error {
+
+	tool, err := service.tool()
+	if err != nil {
+		return err
+	}
+	reply.Value = tool.PostReviewFollowupMessage()
+	return nil
+}
+
+func (service *codeReviewToolService) tool() (common.CodeReviewTool, error) {
+	if service.srv.tool == nil {
+		return nil, ErrNotConfigured
+	}
+	return service.srv.tool, nil
+}
+
+// Release service -------------------------------------------------------------
+
+type releaseService struct {
+	srv *server
+}
+
+func (service *releaseService) Initialise(args *HandleArgs, reply *HandleReply) error {
+	release, err := service.release(args.Handle)
+	if err != nil {
+		return err
+	}
+	act, err := release.Initialise()
+	if err != nil {
+		return protocolError(err)
+	}
+	reply.Handle = service.srv.putAction(act)
+	return nil
+}
+
+func (service *releaseService) EnsureClosable(args *HandleArgs, reply *Empty) error {
+	release, err := service.release(args.Handle)
+	if err != nil {
+		return err
+	}
+	return protocolError(release.EnsureClosable())
+}
+
+func (service *releaseService) Close(args *HandleArgs, reply *HandleReply) error {
+	release, err := service.release(args.Handle)
+	if err != nil {
+		return err
+	}
+	act, err := release.Close()
+	if err != nil {
+		return protocolError(err)
+	}
+	reply.Handle = service.srv.putAction(act)
+	return nil
+}
+
+func (service *releaseService) release(handle Handle) (common.Release, error) {
+	object, err := service.srv.get(handle)
+	if err != nil {
+		return nil, err
+	}
+	release, ok := object.(common.Release)
+	if !ok {
+		return nil, fmt.Errorf("not a release handle: %v", handle)
+	}
+	return release, nil
+}
+
+// ReleaseNotesManager service -------------------------------------------------
+
+type releaseNotesManagerService struct {
+	srv *server
+}
+
+func (service *releaseNotesManagerService) PostReleaseNotes(
+	args *ReleaseNotes,
+	reply *HandleReply,
+) error {
+
+	manager := service.srv.manager
+	if manager == nil {
+		return ErrNotConfigured
+	}
+	v, err := version.Parse(args.Version)
+	if err != nil {
+		return err
+	}
+	notes := &common.ReleaseNotes{
+		Version:  v,
+		Sections: make([]*common.ReleaseNotesSection, 0, len(args.Sections)),
+	}
+	for _, section := range args.Sections {
+		stories := make([]common.Story, 0, len(section.Stories))
+		for _, data := range section.Stories {
+			story, err := service.srv.story(data)
+			if err != nil {
+				return err
+			}
+			stories = append(stories, story)
+		}
+		notes.Sections = append(notes.Sections, &common.ReleaseNotesSection{
+			StoryType: section.StoryType,
+			Stories:   stories,
+		})
+	}
+	act, err := manager.PostReleaseNotes(notes)
+	if err != nil {
+		return protocolError(err)
+	}
+	reply.Handle = service.srv.putAction(act)
+	return nil
+}
+
+// Action service --------------------------------------------------------------
+
+type actionService struct {
+	srv *server
+}
+
+func (service *actionService) Rollback(args *HandleArgs, reply *Empty) error {
+	object, err := service.srv.get(args.Handle)
+	if err != nil {
+		return err
+	}
+	act, ok := object.(action.Action)
+	if !ok {
+		return fmt.Errorf("not an action handle: %v", args.Handle)
+	}
+	return protocolError(act.Rollback())
+}
+
+// dataStory -------------------------------------------------------------------
+
+// dataStory is a read-only common.Story passed to the plugin by SalsaFlow,
+// i.e. a story that does not come from the plugin itself.
+type dataStory struct {
+	data *Story
+}
+
+var errReadOnlyStory = errors.New("story not managed by this module")
+
+func (s *dataStory) Id() string                        { return s.data.Id }
+func (s *dataStory) ReadableId() string                { return s.data.ReadableId }
+func (s *dataStory) Type() string                      { return s.data.Type }
+func (s *dataStory) State() common.StoryState          { return common.StoryState(s.data.State) }
+func (s *dataStory) URL() string                       { return s.data.URL }
+func (s *dataStory) Tag() string                       { return s.data.Tag }
+func (s *dataStory) Title() string                     { return s.data.Title }
+func (s *dataStory) AddAssignee(common.User) error     { return errReadOnlyStory }
+func (s *dataStory) SetAssignees([]common.User) error  { return errReadOnlyStory }
+func (s *dataStory) Start() error                      { return errReadOnlyStory }
+func (s *dataStory) IssueTracker() common.IssueTracker { return nil }
+
+func (s *dataStory) Assignees() []common.User {
+	users := make([]common.User, 0, len(s.data.Assignees))
+	for _, u := range s.data.Assignees {
+		users = append(users, &user{u})
+	}
+	return users
+}
+
+func (s *dataStory) MarkAsImplemented() (action.Action, error) {
+	return nil, errReadOnlyStory
+}
+
+func (s *dataStory) LessThan(other common.Story) bool {
+	return s.ReadableId() < other.ReadableId()
+}
@@ -0,0 +1,145 @@
+package plugin
+
+import (
+	// Stdlib
+	"errors"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/action"
+	"github.com/salsaflow/salsaflow/modules/common"
+)
+
+// story implements common.Story by calling the plugin.
+//
+// The story data are returned by the plugin together with the story handle,
+// so only the methods modifying the story actually call the plugin.
+type story struct {
+	tracker *issueTracker
+	data    *Story
+}
+
+func (s *story) Id() string {
+	return s.data.Id
+}
+
+func (s *story) ReadableId() string {
+	return s.data.ReadableId
+}
+
+func (s *story) Type() string {
+	return s.data.Type
+}
+
+func (s *story) State() common.StoryState {
+	return common.StoryState(s.data.State)
+}
+
+func (s *story) URL() string {
+	return s.data.URL
+}
+
+func (s *story) Tag() string {
+	return s.data.Tag
+}
+
+func (s *story) Title() string {
+	return s.data.Title
+}
+
+func (s *story) Assignees() []common.User {
+	users := make([]common.User, 0, len(s.data.Assignees))
+	for _, u := range s.data.Assignees {
+		users = append(users, &user{u})
+	}
+	return users
+}
+
+func (s *story) AddAssignee(u common.User) error {
+	return s.update("Story.AddAssignee", toUserData([]common.User{u}))
+}
+
+func (s *story) SetAssignees(users []common.User) error {
+	return s.update("Story.SetAssignees", toUserData(users))
+}
+
+func (s *story) Start() error {
+	if err := s.ensureHandle(); err != nil {
+		return err
+	}
+	return s.tracker.client.call("Story.Start", &HandleArgs{s.data.Handle}, &Empty{})
+}
+
+func (s *story) MarkAsImplemented() (action.Action, error) {
+	if err := s.ensureHandle(); err != nil {
+		return nil, err
+	}
+	return callAction(s.tracker.client, "Story.MarkAsImplemented", &HandleArgs{s.data.Handle})
+}
+
+// LessThan is a part of common.Story interface.
+//
+// The plugin is only asked when both stories come from the plugin,
+// the readable IDs are compared otherwise.
+func (s *story) LessThan(otherStory common.Story) bool {
+	other, ok := otherStory.(*story)
+	if !ok || other.tracker.client != s.tracker.client ||
+		s.data.Handle == "" || other.data.Handle == "" {
+
+		return s.ReadableId() < otherStory.ReadableId()
+	}
+
+	var reply BoolReply
+	args := &LessThanArgs{s.data.Handle, other.data.Handle}
+	if err := s.tracker.client.call("Story.LessThan", args, &reply); err != nil {
+		return s.ReadableId() < otherStory.ReadableId()
+	}
+	return reply.Value
+}
+
+func (s *story) IssueTracker() common.IssueTracker {
+	return s.tracker
+}
+
+// update calls the given method to modify the list of assignees
+// and updates the story data using the data returned.
+func (s *story) update(method string, users []*User) error {
+	if err := s.ensureHandle(); err != nil {
+		return err
+	}
+	var reply StoryReply
+	if err := s.tracker.client.call(method, &AssigneesArgs{s.data.Handle, users}, &reply); err != nil {
+		return err
+	}
+	if reply.Story != nil {
+		s.data = reply.Story
+	}
+	return nil
+}
+
+func (s *story) ensureHandle() error {
+	if s.data.Handle == "" {
+		return errors.New("story cannot be modified: no handle returned by the plugin")
+	}
+	return nil
+}
+
+// toStoryData turns the given story into the data to be sent to the plugin.
+// The handle is only set in case the story comes from the given plugin.
+func toStoryData(c *client, s common.Story) *Story {
+	if s == nil {
+		return nil
+	}
+	if ps, ok := s.(*story); ok && ps.tracker.client == c {
+		return ps.data
+	}
+	return &Story{
+		Id:         s.Id(),
+		ReadableId: s.ReadableId(),
+		Type:       s.Type(),
+		State:      string(s.State()),
+		URL:        s.URL(),
+		Tag:        s.Tag(),
+		Title:      s.Title(),
+		Assignees:  toUserData(s.Assignees()),
+	}
+}
@@ -9,6 +9,7 @@ import (
 // Configuration ===============================================================
 
 type moduleConfig struct {
+	*LocalConfig
 	*GlobalConfig
 }
 
@@ -20,12 +21,13 @@ func loadConfig() (*moduleConfig, error) {
 	}
 
 	// Assemble the config object.
-	return &moduleConfig{spec.global}, nil
+	return &moduleConfig{spec.local, spec.global}, nil
 }
 
 // Configuration spec ----------------------------------------------------------
 
 type configSpec struct {
+	local  *LocalConfig
 	global *GlobalConfig
 }
 
@@ -51,6 +53,24 @@ func (spec *configSpec) GlobalConfig() loader.ConfigContainer {
 
 // LocalConfig is a part of loader.ConfigSpec
 func (spec *configSpec) LocalConfig() loader.ConfigContainer {
+	spec.local = &LocalConfig{}
+	return spec.local
+}
+
+// Local configuration ---------------------------------------------------------
+
+type LocalConfig struct {
+	// GitHub Enterprise endpoints, derived from the upstream URL when not set.
+	GitHubAPIBaseURL string `json:"github_api_base_url,omitempty" optional:"true"`
+	GitHubUploadURL  string `json:"github_upload_url,omitempty"   optional:"true"`
+}
+
+// PromptUserForConfig is a part of loader.ConfigContainer
+//
+// There is nothing to be prompted for, the endpoints are derived
+// from the upstream URL unless set manually.
+func (local *LocalConfig) PromptUserForConfig() error {
+	*local = LocalConfig{}
 	return nil
 }
 
@@ -11,6 +11,7 @@ import (
 	"github.com/salsaflow/salsaflow/github"
 	"github.com/salsaflow/salsaflow/log"
 	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/output"
 	"github.com/salsaflow/salsaflow/releases/notes"
 
 	// Vendor
@@ -36,13 +37,16 @@ func (manager *releaseNotesManager) PostReleaseNotes(
 ) (action.Action, error) {
 
 	// Get the GitHub owner and repository from the upstream URL.
-	owner, repo, err := github.ParseUpstreamURL()
+	config := manager.config
+	upstream, endpoints, err := github.UpstreamEndpoints(
+		config.GitHubAPIBaseURL, config.GitHubUploadURL)
 	if err != nil {
 		return nil, err
 	}
+	owner, repo := upstream.Owner, upstream.Repo
 
 	// Instantiate the API client.
-	client := github.NewClient(manager.config.Token)
+	client := github.NewClientForEndpoints(config.Token, endpoints)
 
 	// Format the release notes.
 	task := "Format the release notes"
@@ -74,6 +78,9 @@ func (manager *releaseNotesManager) PostReleaseNotes(
 		return nil, err
 	}
 
+	// Record the release for the JSON output.
+	output.AddURL("release", *release.HTMLURL)
+
 	// Delete the GitHub release on rollback.
 	rollback := func() error {
 		log.Rollback(releaseTask)
@@ -0,0 +1,214 @@
+package output
+
+import (
+	// Stdlib
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app/appflags"
+	"github.com/salsaflow/salsaflow/log"
+
+	// Vendor
+	"github.com/fatih/color"
+	"github.com/shiena/ansicolor"
+)
+
+// Story is the interface the stories passed to AddStory must implement.
+// It is a subset of common.Story so that any story can be passed in directly.
+type Story interface {
+	Id() string
+	ReadableId() string
+	Title() string
+	URL() string
+}
+
+// StoryRecord represents a story affected by the command.
+type StoryRecord struct {
+	Id         string `json:"id"`
+	ReadableId string `json:"readable_id"`
+	Title      string `json:"title"`
+	URL        string `json:"url,omitempty"`
+}
+
+// NewStoryRecord turns the given story into a StoryRecord.
+func NewStoryRecord(story Story) *StoryRecord {
+	return &StoryRecord{
+		Id:         story.Id(),
+		ReadableId: story.ReadableId(),
+		Title:      story.Title(),
+		URL:        story.URL(),
+	}
+}
+
+// URLRecord represents a URL of a resource created or modified by the command,
+// e.g. a review issue or a release.
+type URLRecord struct {
+	Kind string `json:"kind"`
+	URL  string `json:"url"`
+}
+
+// ErrorFrame represents a single errs.Err in the error chain.
+type ErrorFrame struct {
+	Task string `json:"task"`
+	Hint string `json:"hint,omitempty"`
+}
+
+// Error is the JSON representation of the error that terminated the command.
+type Error struct {
+	Task      string        `json:"task,omitempty"`
+	Hint      string        `json:"hint,omitempty"`
+	Message   string        `json:"message"`
+	RootCause string        `json:"root_cause"`
+	Chain     []*ErrorFrame `json:"chain,omitempty"`
+	ExitCode  int           `json:"exit_code"`
+}
+
+// Result is the structured result emitted by every command
+// when the JSON output mode is active.
+type Result struct {
+	Args      []string          `json:"args"`
+	Success   bool              `json:"success"`
+	Stories   []*StoryRecord    `json:"stories,omitempty"`
+	Tags      []string          `json:"tags,omitempty"`
+	Branches  []string          `json:"branches,omitempty"`
+	URLs      []*URLRecord      `json:"urls,omitempty"`
+	Rollbacks []string          `json:"rollbacks,omitempty"`
+	Values    map[string]string `json:"values,omitempty"`
+	Data      interface{}       `json:"data,omitempty"`
+	Error     *Error            `json:"error,omitempty"`
+}
+
+var (
+	// stdout is the original standard output.
+	// It is saved here since os.Stdout is redirected in the JSON mode.
+	stdout io.Writer = os.Stdout
+
+	result = &Result{
+		Args:    os.Args[1:],
+		Success: true,
+	}
+	resultLock sync.Mutex
+
+	flushed  bool
+	initOnce sync.Once
+)
+
+// IsJSON returns true when the JSON output mode is active.
+func IsJSON() bool {
+	return appflags.FlagOutput.Value() == appflags.OutputJSON
+}
+
+// Init sets up the output according to the -output flag.
+//
+// In the JSON mode all human-readable output is redirected to stderr
+// so that the only thing printed to stdout is the final JSON result.
+func Init() {
+	initOnce.Do(func() {
+		if !IsJSON() {
+			return
+		}
+
+		os.Stdout = os.Stderr
+		color.Output = ansicolor.NewAnsiColorWriter(os.Stderr)
+
+		log.AddHook(func(tag log.Tag, msg string) {
+			if tag == log.TagRollback {
+				withResult(func(r *Result) {
+					r.Rollbacks = append(r.Rollbacks, msg)
+				})
+			}
+		})
+	})
+}
+
+// AddStory records a story affected by the command.
+func AddStory(story Story) {
+	withResult(func(r *Result) {
+		for _, s := range r.Stories {
+			if s.Id == story.Id() {
+				return
+			}
+		}
+		r.Stories = append(r.Stories, NewStoryRecord(story))
+	})
+}
+
+// AddTag records a git tag created by the command.
+func AddTag(tag string) {
+	withResult(func(r *Result) {
+		r.Tags = append(r.Tags, tag)
+	})
+}
+
+// AddBranch records a git branch created or modified by the command.
+func AddBranch(branch string) {
+	withResult(func(r *Result) {
+		for _, b := range r.Branches {
+			if b == branch {
+				return
+			}
+		}
+		r.Branches = append(r.Branches, branch)
+	})
+}
+
+// AddURL records a URL of a resource created or modified by the command.
+func AddURL(kind, url string) {
+	withResult(func(r *Result) {
+		r.URLs = append(r.URLs, &URLRecord{kind, url})
+	})
+}
+
+// SetValue records an arbitrary command-specific value, e.g. a version string.
+func SetValue(key, value string) {
+	withResult(func(r *Result) {
+		if r.Values == nil {
+			r.Values = make(map[string]string)
+		}
+		r.Values[key] = value
+	})
+}
+
+// SetData records the command-specific report, e.g. the review statistics.
+// The value is encoded using encoding/json.
+func SetData(data interface{}) {
+	withResult(func(r *Result) {
+		r.Data = data
+	})
+}
+
+// SetError marks the command as failed.
+func SetError(err *Error) {
+	withResult(func(r *Result) {
+		r.Success = false
+		r.Error = err
+	})
+}
+
+// Flush writes the result into stdout in case the JSON mode is active.
+// Only the first call has any effect.
+func Flush() error {
+	if !IsJSON() {
+		return nil
+	}
+
+	resultLock.Lock()
+	defer resultLock.Unlock()
+
+	if flushed {
+		return nil
+	}
+	flushed = true
+
+	encoder := json.NewEncoder(stdout)
+	return encoder.Encode(result)
+}
+
+func withResult(f func(*Result)) {
+	resultLock.Lock()
+	f(result)
+	resultLock.Unlock()
+}
@@ -0,0 +1,30 @@
+package output
+
+import (
+	// Stdlib
+	"testing"
+
+	// Vendor - testing framework
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var (
+	AfterEach  = ginkgo.AfterEach
+	BeforeEach = ginkgo.BeforeEach
+	Describe   = ginkgo.Describe
+	It         = ginkgo.It
+
+	BeEmpty = gomega.BeEmpty
+	BeFalse = gomega.BeFalse
+	BeNil   = gomega.BeNil
+	BeTrue  = gomega.BeTrue
+	Equal   = gomega.Equal
+	Expect  = gomega.Expect
+	HaveLen = gomega.HaveLen
+)
+
+func TestOutput(t *testing.T) {
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "Output")
+}
@@ -0,0 +1,115 @@
+package output
+
+import (
+	// Stdlib
+	"bytes"
+	"encoding/json"
+	"io"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/app/appflags"
+)
+
+type testStory struct {
+	id string
+}
+
+func (s *testStory) Id() string         { return s.id }
+func (s *testStory) ReadableId() string { return "#" + s.id }
+func (s *testStory) Title() string      { return "Story " + s.id }
+func (s *testStory) URL() string        { return "https://example.com/stories/" + s.id }
+
+var _ = Describe("JSON result", func() {
+
+	var (
+		buffer     *bytes.Buffer
+		origStdout io.Writer
+	)
+
+	BeforeEach(func() {
+		buffer = new(bytes.Buffer)
+		origStdout = stdout
+		stdout = buffer
+		result = &Result{Args: []string{"release", "deploy"}, Success: true}
+		flushed = false
+	})
+
+	AfterEach(func() {
+		stdout = origStdout
+		Expect(appflags.FlagOutput.Set(appflags.OutputText)).To(BeNil())
+	})
+
+	decode := func() *Result {
+		var r Result
+		Expect(json.Unmarshal(buffer.Bytes(), &r)).To(BeNil())
+		return &r
+	}
+
+	It("should print nothing in the text mode", func() {
+		AddBranch("stable")
+		Expect(Flush()).To(BeNil())
+		Expect(buffer.Len()).To(Equal(0))
+	})
+
+	It("should print the collected records exactly once", func() {
+		Expect(appflags.FlagOutput.Set(appflags.OutputJSON)).To(BeNil())
+
+		AddStory(&testStory{"1"})
+		AddStory(&testStory{"1"})
+		AddStory(&testStory{"2"})
+		AddBranch("stable")
+		AddBranch("stable")
+		AddTag("v1.2.0")
+		AddURL("release", "https://example.com/releases/1.2.0")
+		SetValue("deployed_version", "1.2.0")
+
+		Expect(Flush()).To(BeNil())
+		Expect(Flush()).To(BeNil())
+		Expect(bytes.Count(buffer.Bytes(), []byte("\n"))).To(Equal(1))
+
+		r := decode()
+		Expect(r.Success).To(BeTrue())
+		Expect(r.Args).To(Equal([]string{"release", "deploy"}))
+		Expect(r.Stories).To(HaveLen(2))
+		Expect(*r.Stories[0]).To(Equal(StoryRecord{
+			Id:         "1",
+			ReadableId: "#1",
+			Title:      "Story 1",
+			URL:        "https://example.com/stories/1",
+		}))
+		Expect(r.Branches).To(Equal([]string{"stable"}))
+		Expect(r.Tags).To(Equal([]string{"v1.2.0"}))
+		Expect(r.URLs).To(HaveLen(1))
+		Expect(r.Values["deployed_version"]).To(Equal("1.2.0"))
+		Expect(r.Data).To(BeNil())
+		Expect(r.Error).To(BeNil())
+	})
+
+	It("should include the command-specific report", func() {
+		Expect(appflags.FlagOutput.Set(appflags.OutputJSON)).To(BeNil())
+
+		SetData(map[string]int{"review_requests": 3})
+		Expect(Flush()).To(BeNil())
+
+		r := decode()
+		Expect(r.Data).To(Equal(map[string]interface{}{"review_requests": float64(3)}))
+	})
+
+	It("should mark the result as failed when the error is set", func() {
+		Expect(appflags.FlagOutput.Set(appflags.OutputJSON)).To(BeNil())
+
+		SetError(&Error{
+			Task:      "Push the changes",
+			Message:   "ref 'stable' is not up to date",
+			RootCause: "ref 'stable' is not up to date",
+			ExitCode:  4,
+		})
+		Expect(Flush()).To(BeNil())
+
+		r := decode()
+		Expect(r.Success).To(BeFalse())
+		Expect(r.Error.Task).To(Equal("Push the changes"))
+		Expect(r.Error.ExitCode).To(Equal(4))
+		Expect(r.Rollbacks).To(BeEmpty())
+	})
+})
@@ -7,7 +7,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"net/http"
 	"path/filepath"
 	"runtime"
 
@@ -15,6 +14,7 @@ import (
 	"github.com/salsaflow/salsaflow/action"
 	"github.com/salsaflow/salsaflow/errs"
 	"github.com/salsaflow/salsaflow/fileutil"
+	"github.com/salsaflow/salsaflow/httputil"
 	"github.com/salsaflow/salsaflow/log"
 
 	// Other
@@ -123,7 +123,7 @@ func downloadAndInstallAsset(assetName, assetURL, dstDir string) error {
 	// Download the asset.
 	task := "Download " + assetName
 	log.Run(task)
-	resp, err := http.Get(assetURL)
+	resp, err := httputil.DefaultClient.Get(assetURL)
 	if err != nil {
 		return errs.NewError(task, err)
 	}
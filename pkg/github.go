@@ -20,8 +20,15 @@ func newGitHubClient() (*gh.Client, error) {
 		return nil, errs.NewError(task, err)
 	}
 
+	// Get the API endpoints, github.com is used unless configured otherwise.
+	global := spec.global
+	endpoints, err := github.ResolveEndpoints("", global.GitHubAPIBaseURL, global.GitHubUploadURL)
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+
 	// Return a new API client.
-	return github.NewClient(spec.global.GitHubToken), nil
+	return github.NewClientForEndpoints(global.GitHubToken, endpoints), nil
 }
 
 // Configuration ===============================================================
@@ -55,6 +62,10 @@ func (spec *configSpec) LocalConfig() loader.ConfigContainer {
 
 type GlobalConfig struct {
 	GitHubToken string `prompt:"GitHub token to be used for SalsaFlow updater" secret:"true" json:"github_token"`
+
+	// GitHub Enterprise endpoints, github.com is used when not set.
+	GitHubAPIBaseURL string `json:"github_api_base_url,omitempty" optional:"true"`
+	GitHubUploadURL  string `json:"github_upload_url,omitempty"   optional:"true"`
 }
 
 func (global *GlobalConfig) PromptUserForConfig() error {
@@ -5,11 +5,16 @@ import (
 	"errors"
 
 	// Internal
+	"github.com/salsaflow/salsaflow/errs"
 	"github.com/salsaflow/salsaflow/log"
 )
 
 var ErrCanceled = errors.New("operation canceled")
 
+func init() {
+	errs.RegisterExitCodeForError(errs.ExitCodeCanceled, ErrCanceled)
+}
+
 func PanicCancel() {
 	panic(ErrCanceled)
 }
@@ -18,6 +23,7 @@ func RecoverCancel() {
 	if r := recover(); r != nil {
 		if r == ErrCanceled {
 			log.Println("\nOperation canceled. You are welcome to come back any time!")
+			errs.Terminate(ErrCanceled)
 		} else {
 			panic(r)
 		}
@@ -14,8 +14,10 @@ import (
 	"github.com/salsaflow/salsaflow/log"
 	"github.com/salsaflow/salsaflow/modules"
 	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/output"
 	"github.com/salsaflow/salsaflow/prompt"
 	"github.com/salsaflow/salsaflow/releases"
+	"github.com/salsaflow/salsaflow/scripts"
 	"github.com/salsaflow/salsaflow/version"
 )
 
@@ -104,6 +106,32 @@ func Stage(options *StageOptions) (act action.Action, err error) {
 		return nil, err
 	}
 
+	// Get the staging branch version.
+	stagingVersion, err := releaseVersion.ToStageVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	// Run the pre_release_stage hook.
+	hookCtx := scripts.NewHookContext()
+	hookCtx.SetVersion("release", releaseVersion)
+	hookCtx.SetVersion("staging", stagingVersion)
+	hookCtx.SetBranch("release", releaseBranch)
+	hookCtx.SetBranch("staging", stagingBranch)
+	hookCtx.Load = func(ctx *scripts.HookContext) error {
+		stories, err := release.Stories()
+		if err != nil {
+			return err
+		}
+		for _, story := range stories {
+			ctx.AddStory(story)
+		}
+		return nil
+	}
+	if err := scripts.RunHook(scripts.HookPreReleaseStage, hookCtx); err != nil {
+		return nil, err
+	}
+
 	// Reset the staging branch to point to the newly created tag.
 	task = fmt.Sprintf("Reset branch '%v' to point to branch '%v'", stagingBranch, releaseBranch)
 	log.Run(task)
@@ -143,11 +171,6 @@ func Stage(options *StageOptions) (act action.Action, err error) {
 	}))
 
 	// Update the version string on the staging branch.
-	stagingVersion, err := releaseVersion.ToStageVersion()
-	if err != nil {
-		return nil, err
-	}
-
 	task = fmt.Sprintf("Bump version (branch '%v' -> %v)", stagingBranch, stagingVersion)
 	log.Run(task)
 	act, err = version.SetForBranch(stagingVersion, stagingBranch)
@@ -173,6 +196,14 @@ func Stage(options *StageOptions) (act action.Action, err error) {
 	if err != nil {
 		return nil, err
 	}
+
+	// Record what happened for the JSON output.
+	output.SetValue("staged_version", stagingVersion.String())
+	output.AddBranch(stagingBranch)
+	releases.AddStoriesToOutput(release)
+
+	// Run the post_release_stage hook.
+	scripts.RunPostHook(scripts.HookPostReleaseStage, hookCtx)
 	return chain, nil
 }
 
@@ -0,0 +1,29 @@
+package releases
+
+import (
+	// Internal
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/modules/common"
+	"github.com/salsaflow/salsaflow/output"
+)
+
+// AddStoriesToOutput records the stories associated with the given release
+// for the JSON output. It does nothing unless the JSON output mode is active.
+//
+// It is called once the remote repository has been updated already,
+// so failing to collect the stories must not trigger the rollback.
+// That is why the error is only logged here and not returned.
+func AddStoriesToOutput(release common.RunningRelease) {
+	if !output.IsJSON() {
+		return
+	}
+
+	stories, err := release.Stories()
+	if err != nil {
+		errs.LogError("Collect the release stories for the JSON output", err)
+		return
+	}
+	for _, story := range stories {
+		output.AddStory(story)
+	}
+}
@@ -16,6 +16,10 @@ import (
 	"github.com/salsaflow/salsaflow/log"
 )
 
+// GitConfigKeyInitialised is the git config key storing the SalsaFlow version
+// the repository was initialised by.
+const GitConfigKeyInitialised = "salsaflow.initialised"
+
 var initHooks []InitHook
 
 type InitHook func() error
@@ -38,7 +42,7 @@ var ErrInitialised = errors.New("repository already initialised")
 func Init(force bool) error {
 	// Check whether the repository has been initialised yet.
 	task := "Check whether the repository has been initialised"
-	versionString, err := git.GetConfigString("salsaflow.initialised")
+	versionString, err := git.GetConfigString(GitConfigKeyInitialised)
 	if err != nil {
 		return errs.NewError(task, err)
 	}
@@ -55,34 +59,8 @@ func Init(force bool) error {
 	// version of git is installed, it most probably stays.
 	task = "Check the git version being used"
 	log.Run(task)
-	stdout, err := git.Run("--version")
-	if err != nil {
-		return errs.NewError(task, err)
-	}
-	pattern := regexp.MustCompile("^git version (([0-9]+)[.]([0-9]+).*)")
-	parts := pattern.FindStringSubmatch(stdout.String())
-	if len(parts) != 4 {
-		return errs.NewError(task, errors.New("unexpected git --version output"))
-	}
-	gitVersion := parts[1]
-	// This cannot fail since we matched the regexp.
-	major, _ := strconv.Atoi(parts[2])
-	minor, _ := strconv.Atoi(parts[3])
-	// We need Git version 1.8.5.4+, so let's require 1.9+.
-	switch {
-	case major >= 2:
-		// OK
-	case major == 1 && minor >= 9:
-		// OK
-	default:
-		hint := `
-You need Git version 1.9.0 or newer.
-
-`
-		return errs.NewErrorWithHint(
-			task,
-			errors.New("unsupported git version detected: "+gitVersion),
-			hint)
+	if _, err := CheckGitVersion(); err != nil {
+		return err
 	}
 
 	// Get hold of a git config instance.
@@ -157,7 +135,7 @@ You need Git version 1.9.0 or newer.
 
 	// Success! Mark the repository as initialised in git config.
 	task = "Mark the repository as initialised"
-	if err := git.SetConfigString("salsaflow.initialised", metadata.Version); err != nil {
+	if err := git.SetConfigString(GitConfigKeyInitialised, metadata.Version); err != nil {
 		return err
 	}
 	asciiart.PrintThumbsUp()
@@ -166,3 +144,39 @@ You need Git version 1.9.0 or newer.
 
 	return nil
 }
+
+// CheckGitVersion makes sure the git version installed is supported.
+// The version string is returned in any case it can be parsed.
+func CheckGitVersion() (gitVersion string, err error) {
+	task := "Check the git version being used"
+	stdout, err := git.Run("--version")
+	if err != nil {
+		return "", errs.NewError(task, err)
+	}
+	pattern := regexp.MustCompile("^git version (([0-9]+)[.]([0-9]+).*)")
+	parts := pattern.FindStringSubmatch(stdout.String())
+	if len(parts) != 4 {
+		return "", errs.NewError(task, errors.New("unexpected git --version output"))
+	}
+	gitVersion = parts[1]
+	// This cannot fail since we matched the regexp.
+	major, _ := strconv.Atoi(parts[2])
+	minor, _ := strconv.Atoi(parts[3])
+	// We need Git version 2.5+ for git worktree.
+	switch {
+	case major > 2:
+		// OK
+	case major == 2 && minor >= 5:
+		// OK
+	default:
+		hint := `
+You need Git version 2.5.0 or newer.
+
+`
+		return gitVersion, errs.NewErrorWithHint(
+			task,
+			errors.New("unsupported git version detected: "+gitVersion),
+			hint)
+	}
+	return gitVersion, nil
+}
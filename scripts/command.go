@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	// Internal
 	"github.com/salsaflow/salsaflow/config"
@@ -17,6 +18,18 @@ import (
 
 const ScriptDirname = "scripts"
 
+// The environment variables set for every script.
+const (
+	EnvScriptName     = "SALSAFLOW_SCRIPT"
+	EnvRepositoryRoot = "SALSAFLOW_REPOSITORY_ROOT"
+	EnvBranch         = "SALSAFLOW_BRANCH"
+	EnvArgs           = "SALSAFLOW_ARGS"
+)
+
+// EnvVersion is the environment variable containing the version string
+// being handled, when there is any, e.g. for the set_version script.
+const EnvVersion = "SALSAFLOW_VERSION"
+
 type ErrNotFound struct {
 	scriptName string
 }
@@ -29,23 +42,97 @@ func (err *ErrNotFound) ScriptName() string {
 	return err.scriptName
 }
 
+type ErrTimeout struct {
+	scriptName string
+	timeout    time.Duration
+}
+
+func (err *ErrTimeout) Error() string {
+	return fmt.Sprintf("custom SalsaFlow script '%v' timed out after %v", err.scriptName, err.timeout)
+}
+
+func (err *ErrTimeout) ScriptName() string {
+	return err.scriptName
+}
+
 // Commands returns *exec.Command for the given script name and args.
 func Command(scriptName string, args ...string) (*exec.Cmd, error) {
+	return CommandWithEnv(scriptName, nil, args...)
+}
+
+// CommandWithEnv is the same as Command, but the given variables
+// are added to the script environment as well.
+func CommandWithEnv(scriptName string, env map[string]string, args ...string) (*exec.Cmd, error) {
+	cmd, _, err := command(scriptName, env, args...)
+	return cmd, err
+}
+
+// command returns the command for the given script together with the timeout to be used.
+//
+// The script is looked up in the scripts directory first. In case it is not there,
+// the make target configured for the script name is used, if any.
+func command(
+	scriptName string,
+	env map[string]string,
+	args ...string,
+) (cmd *exec.Cmd, timeout time.Duration, err error) {
+
 	// Make sure this is a script name, not a path.
 	if strings.Contains(scriptName, "/") {
-		return nil, fmt.Errorf("not a script name: %v", scriptName)
+		return nil, 0, fmt.Errorf("not a script name: %v", scriptName)
+	}
+
+	// Read the script settings.
+	section, err := loadConfig()
+	if err != nil {
+		return nil, 0, err
+	}
+	timeout, err = scriptTimeout(section, scriptName)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	// Get the list of available scripts.
 	root, err := gitutil.RepositoryRootAbsolutePath()
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
+	// Find the script, then fall back to the make target.
+	cmd, err = scriptCommand(root, section, scriptName)
+	if err != nil {
+		return nil, 0, err
+	}
+	if cmd != nil {
+		cmd.Args = append(cmd.Args, args...)
+	} else {
+		// The arguments are available in SALSAFLOW_ARGS for the make targets.
+		cmd = makeCommand(root, section, scriptName)
+		if cmd == nil {
+			return nil, 0, &ErrNotFound{scriptName}
+		}
+	}
+
+	cmd.Dir = root
+	cmd.Env = scriptEnv(root, scriptName, env, args)
+	return cmd, timeout, nil
+}
+
+// scriptCommand returns the command for the script file for the given script name.
+// nil is returned in case there is no such script for the current platform.
+func scriptCommand(
+	root string,
+	section *config.ScriptsSection,
+	scriptName string,
+) (*exec.Cmd, error) {
+
+	// Get the list of available scripts.
 	scriptsDirPath := filepath.Join(root, config.LocalConfigDirname, ScriptDirname)
 
 	scriptsDir, err := os.Open(scriptsDirPath)
 	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
 		return nil, err
 	}
 	defer scriptsDir.Close()
@@ -98,16 +185,51 @@ func Command(scriptName string, args ...string) (*exec.Cmd, error) {
 		}
 
 		// Get the runner for the given file extension.
+		// The interpreter configured for the extension takes precedence.
 		// ext contains the dot, which we need to drop.
-		runner := runners.GetRunner(ext[1:], platformId)
+		var runner *runners.ScriptRunner
+		if interpreter, ok := section.Interpreters[ext[1:]]; ok {
+			runner = runners.NewInterpreterRunner(ext[1:], interpreter)
+		} else {
+			runner = runners.GetRunner(ext[1:], platformId)
+		}
 		if runner == nil {
 			continue
 		}
-		cmd := runner.NewCommand(filepath.Join(scriptsDirPath, script))
-		cmd.Args = append(cmd.Args, args...)
-		cmd.Dir = root
-		return cmd, nil
+		return runner.NewCommand(filepath.Join(scriptsDirPath, script)), nil
+	}
+
+	return nil, nil
+}
+
+// makeCommand returns the command for the make target configured
+// for the given script name, or nil in case there is no target configured.
+func makeCommand(root string, section *config.ScriptsSection, scriptName string) *exec.Cmd {
+	target, ok := section.MakeTargets[scriptName]
+	if !ok || target == "" {
+		return nil
 	}
 
-	return nil, &ErrNotFound{scriptName}
+	makefile := section.Makefile
+	if makefile == "" {
+		makefile = DefaultMakefile
+	}
+	return exec.Command("make", "--no-print-directory", "-f", filepath.Join(root, makefile), target)
+}
+
+// scriptEnv returns the environment for the given script.
+func scriptEnv(root, scriptName string, env map[string]string, args []string) []string {
+	vars := []string{
+		EnvScriptName + "=" + scriptName,
+		EnvRepositoryRoot + "=" + root,
+		EnvArgs + "=" + strings.Join(args, " "),
+	}
+	// The current branch is not available in the detached HEAD state.
+	if branch, err := gitutil.CurrentBranch(); err == nil && branch != "HEAD" {
+		vars = append(vars, EnvBranch+"="+branch)
+	}
+	for k, v := range env {
+		vars = append(vars, k+"="+v)
+	}
+	return append(os.Environ(), vars...)
 }
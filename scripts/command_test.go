@@ -0,0 +1,45 @@
+package scripts
+
+import (
+	// Internal
+	"github.com/salsaflow/salsaflow/config"
+)
+
+var _ = Describe("script command", func() {
+
+	Describe("make targets", func() {
+
+		It("should return nil when no target is configured", func() {
+			section := &config.ScriptsSection{
+				MakeTargets: map[string]string{ScriptNameSetVersion: ""},
+			}
+			Expect(makeCommand("/repo", section, ScriptNameGetVersion)).To(BeNil())
+			Expect(makeCommand("/repo", section, ScriptNameSetVersion)).To(BeNil())
+		})
+
+		It("should run the target using the configured Makefile", func() {
+			section := &config.ScriptsSection{
+				MakeTargets: map[string]string{ScriptNameGetVersion: "version"},
+			}
+			cmd := makeCommand("/repo", section, ScriptNameGetVersion)
+			Expect(cmd.Args).To(Equal([]string{
+				"make", "--no-print-directory", "-f", "/repo/Makefile", "version"}))
+
+			section.Makefile = "build/Makefile"
+			cmd = makeCommand("/repo", section, ScriptNameGetVersion)
+			Expect(cmd.Args[3]).To(Equal("/repo/build/Makefile"))
+		})
+	})
+
+	Describe("environment", func() {
+
+		It("should pass the script name, the repository root, the args and the extra variables", func() {
+			env := scriptEnv("/repo", ScriptNameSetVersion,
+				map[string]string{EnvVersion: "1.2.0"}, []string{"1.2.0", "--dry-run"})
+			Expect(env).To(ContainElement(EnvScriptName + "=" + ScriptNameSetVersion))
+			Expect(env).To(ContainElement(EnvRepositoryRoot + "=/repo"))
+			Expect(env).To(ContainElement(EnvArgs + "=1.2.0 --dry-run"))
+			Expect(env).To(ContainElement(EnvVersion + "=1.2.0"))
+		})
+	})
+})
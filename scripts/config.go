@@ -0,0 +1,52 @@
+package scripts
+
+import (
+	// Stdlib
+	"fmt"
+	"os"
+	"time"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/config"
+	"github.com/salsaflow/salsaflow/errs"
+)
+
+// DefaultTimeout is the script timeout used unless configured otherwise.
+// Setting the timeout to 0 in the local configuration file disables it.
+const DefaultTimeout = 10 * time.Minute
+
+// DefaultMakefile is the Makefile used for the make targets by default.
+const DefaultMakefile = "Makefile"
+
+// loadConfig reads the scripts section of the local configuration file.
+// The section is optional, an empty one is returned in case it is missing.
+func loadConfig() (*config.ScriptsSection, error) {
+	local, err := config.ReadLocalConfig()
+	if err != nil {
+		if os.IsNotExist(errs.RootCause(err)) {
+			return &config.ScriptsSection{}, nil
+		}
+		return nil, err
+	}
+	if local.Scripts == nil {
+		return &config.ScriptsSection{}, nil
+	}
+	return local.Scripts, nil
+}
+
+// scriptTimeout returns the timeout to be used for the given script.
+func scriptTimeout(section *config.ScriptsSection, scriptName string) (time.Duration, error) {
+	value := section.Timeout
+	if v, ok := section.Timeouts[scriptName]; ok {
+		value = v
+	}
+	if value == "" {
+		return DefaultTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		return 0, fmt.Errorf("invalid timeout for script '%v': %v", scriptName, value)
+	}
+	return timeout, nil
+}
@@ -0,0 +1,46 @@
+package scripts
+
+import (
+	// Stdlib
+	"time"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/config"
+)
+
+var _ = Describe("script timeout", func() {
+
+	It("should use the default timeout when not configured", func() {
+		timeout, err := scriptTimeout(&config.ScriptsSection{}, ScriptNameGetVersion)
+		Expect(err).To(BeNil())
+		Expect(timeout).To(Equal(DefaultTimeout))
+	})
+
+	It("should prefer the timeout configured for the script", func() {
+		section := &config.ScriptsSection{
+			Timeout:  "1m",
+			Timeouts: map[string]string{ScriptNameSetVersion: "30s"},
+		}
+
+		timeout, err := scriptTimeout(section, ScriptNameSetVersion)
+		Expect(err).To(BeNil())
+		Expect(timeout).To(Equal(30 * time.Second))
+
+		timeout, err = scriptTimeout(section, ScriptNameGetVersion)
+		Expect(err).To(BeNil())
+		Expect(timeout).To(Equal(time.Minute))
+	})
+
+	It("should allow the timeout to be disabled", func() {
+		timeout, err := scriptTimeout(&config.ScriptsSection{Timeout: "0"}, ScriptNameGetVersion)
+		Expect(err).To(BeNil())
+		Expect(timeout).To(Equal(time.Duration(0)))
+	})
+
+	It("should reject invalid timeouts", func() {
+		for _, value := range []string{"ten minutes", "-1m"} {
+			_, err := scriptTimeout(&config.ScriptsSection{Timeout: value}, ScriptNameGetVersion)
+			Expect(err).To(HaveOccurred())
+		}
+	})
+})
@@ -0,0 +1,160 @@
+package scripts
+
+import (
+	// Stdlib
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/output"
+)
+
+// Lifecycle hooks are custom scripts run at specific points of SalsaFlow commands.
+// They are resolved the same way as any other script, so pre_release_start_unix.bash
+// is run for the pre_release_start hook on Linux, for example.
+//
+// The pre hooks are run once the command checks pass, right before the command
+// starts modifying anything. A failing pre hook aborts the command.
+// The post hooks are run once the command is done. A failing post hook
+// is only reported since there is nothing to abort any more.
+const (
+	HookPreReleaseStart   = "pre_release_start"
+	HookPostReleaseStart  = "post_release_start"
+	HookPreReleaseStage   = "pre_release_stage"
+	HookPostReleaseStage  = "post_release_stage"
+	HookPreReleaseDeploy  = "pre_release_deploy"
+	HookPostReleaseDeploy = "post_release_deploy"
+	HookPreStoryStart     = "pre_story_start"
+	HookPostStoryStart    = "post_story_start"
+	HookPreReviewPost     = "pre_review_post"
+	HookPostReviewPost    = "post_review_post"
+)
+
+// HookEnvVariable is the environment variable containing the name of the hook
+// being run. This makes it possible to use a single script for multiple hooks.
+const HookEnvVariable = "SALSAFLOW_HOOK"
+
+// HookNames returns the names of all lifecycle hooks available.
+func HookNames() []string {
+	return []string{
+		HookPreReleaseStart,
+		HookPostReleaseStart,
+		HookPreReleaseStage,
+		HookPostReleaseStage,
+		HookPreReleaseDeploy,
+		HookPostReleaseDeploy,
+		HookPreStoryStart,
+		HookPostStoryStart,
+		HookPreReviewPost,
+		HookPostReviewPost,
+	}
+}
+
+// HookCommit represents a commit affected by the command.
+type HookCommit struct {
+	SHA        string `json:"sha"`
+	Title      string `json:"title"`
+	StoryIdTag string `json:"story_id_tag,omitempty"`
+}
+
+// HookContext is the JSON document passed to the hook script on stdin.
+type HookContext struct {
+	Hook     string                `json:"hook"`
+	Versions map[string]string     `json:"versions,omitempty"`
+	Branches map[string]string     `json:"branches,omitempty"`
+	Tag      string                `json:"tag,omitempty"`
+	Stories  []*output.StoryRecord `json:"stories,omitempty"`
+	Commits  []*HookCommit         `json:"commits,omitempty"`
+
+	// Load, when set, is called to complete the context right before
+	// the hook script is run. It is not called at all when there is no script
+	// for the hook, so this is the place to fetch the stories and such.
+	// Load is cleared once called so that the context can be reused.
+	Load func(ctx *HookContext) error `json:"-"`
+}
+
+// NewHookContext returns an empty context.
+func NewHookContext() *HookContext {
+	return &HookContext{
+		Versions: make(map[string]string),
+		Branches: make(map[string]string),
+	}
+}
+
+// SetVersion records the version under the given key, e.g. "release".
+func (ctx *HookContext) SetVersion(key string, version fmt.Stringer) {
+	ctx.Versions[key] = version.String()
+}
+
+// SetBranch records the branch under the given key, e.g. "trunk".
+func (ctx *HookContext) SetBranch(key, branch string) {
+	ctx.Branches[key] = branch
+}
+
+// AddStory records a story affected by the command.
+func (ctx *HookContext) AddStory(story output.Story) {
+	ctx.Stories = append(ctx.Stories, output.NewStoryRecord(story))
+}
+
+// AddCommit records a commit affected by the command.
+func (ctx *HookContext) AddCommit(sha, title, storyIdTag string) {
+	ctx.Commits = append(ctx.Commits, &HookCommit{sha, title, storyIdTag})
+}
+
+// RunHook runs the script for the given lifecycle hook, if there is any.
+// The context is written into the script's stdin as JSON.
+// The script output is passed through to the user.
+func RunHook(hookName string, ctx *HookContext) error {
+	task := fmt.Sprintf("Run the %v hook", hookName)
+
+	// Get the command. The hooks are optional, so it is fine when there is none.
+	cmd, timeout, err := command(hookName, map[string]string{HookEnvVariable: hookName})
+	if err != nil {
+		if _, ok := err.(*ErrNotFound); ok || os.IsNotExist(err) {
+			return nil
+		}
+		return errs.NewError(task, err)
+	}
+
+	log.Run(task)
+
+	// Complete and encode the context.
+	if ctx == nil {
+		ctx = NewHookContext()
+	}
+	ctx.Hook = hookName
+	if ctx.Load != nil {
+		if err := ctx.Load(ctx); err != nil {
+			return errs.NewError(task, err)
+		}
+		ctx.Load = nil
+	}
+	input, err := json.MarshalIndent(ctx, "", "  ")
+	if err != nil {
+		return errs.NewError(task, err)
+	}
+
+	// Run the script. os.Stdout is already redirected to stderr
+	// in case the JSON output mode is active.
+	cmd.Stdin = bytes.NewReader(append(input, '\n'))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := runWithTimeout(cmd, hookName, timeout); err != nil {
+		return errs.NewError(task, err)
+	}
+	return nil
+}
+
+// RunPostHook runs the given hook like RunHook, but the error is only logged.
+// It is meant for the post hooks, which are run when the command is done
+// and there is nothing to be rolled back any more.
+func RunPostHook(hookName string, ctx *HookContext) {
+	if err := RunHook(hookName, ctx); err != nil {
+		errs.Log(err)
+		log.Warn(fmt.Sprintf("The %v hook failed, continuing anyway ...", hookName))
+	}
+}
@@ -4,6 +4,8 @@ import (
 	// Stdlib
 	"bytes"
 	"fmt"
+	"os/exec"
+	"time"
 
 	// Internal
 	"github.com/salsaflow/salsaflow/errs"
@@ -15,10 +17,21 @@ const (
 )
 
 func Run(scriptName string, args ...string) (stdout *bytes.Buffer, err error) {
+	return RunWithEnv(scriptName, nil, args...)
+}
+
+// RunWithEnv is the same as Run, but the given variables
+// are added to the script environment as well.
+func RunWithEnv(
+	scriptName string,
+	env map[string]string,
+	args ...string,
+) (stdout *bytes.Buffer, err error) {
+
 	task := fmt.Sprintf("Run the %v script", scriptName)
 
 	// Get the command for the given script name and args.
-	cmd, err := Command(scriptName, args...)
+	cmd, timeout, err := command(scriptName, env, args...)
 	if err != nil {
 		return nil, errs.NewError(task, err)
 	}
@@ -30,8 +43,39 @@ func Run(scriptName string, args ...string) (stdout *bytes.Buffer, err error) {
 	)
 	cmd.Stdout = &sout
 	cmd.Stderr = &serr
-	if err := cmd.Run(); err != nil {
+	if err := runWithTimeout(cmd, scriptName, timeout); err != nil {
 		return nil, errs.NewErrorWithHint(task, err, serr.String())
 	}
 	return &sout, nil
 }
+
+// runWithTimeout runs the given command, killing it once the timeout elapses.
+// The timeout is disabled in case it is set to 0.
+//
+// The command is started in its own process group so that the processes
+// spawned by the script, e.g. the binary compiled by go run, are killed as well.
+func runWithTimeout(cmd *exec.Cmd, scriptName string, timeout time.Duration) error {
+	if timeout == 0 {
+		return cmd.Run()
+	}
+
+	startInProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		// Do not wait for the process to exit. The processes spawned by the script
+		// can still be holding the output pipes, so Wait could block forever.
+		killProcessGroup(cmd)
+		return &ErrTimeout{scriptName, timeout}
+	}
+}
@@ -0,0 +1,24 @@
+// +build !windows
+
+package scripts
+
+import (
+	// Stdlib
+	"os/exec"
+	"syscall"
+)
+
+// startInProcessGroup makes the command run in its own process group
+// so that the processes spawned by the script can be killed together with it.
+func startInProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+// killProcessGroup kills the process group started by startInProcessGroup.
+func killProcessGroup(cmd *exec.Cmd) error {
+	// The process group id equals the pid of the group leader.
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
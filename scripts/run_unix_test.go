@@ -0,0 +1,42 @@
+// +build !windows
+
+package scripts
+
+import (
+	// Stdlib
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+var _ = Describe("running scripts with a timeout", func() {
+
+	It("should kill the processes spawned by the script as well", func() {
+		tmpDir, err := ioutil.TempDir("", "salsaflow-scripts-")
+		Expect(err).To(BeNil())
+		defer os.RemoveAll(tmpDir)
+
+		// The script spawns a child process and waits for it,
+		// the same way go run waits for the compiled binary.
+		// The child keeps appending to a file as long as it is running.
+		ticks := filepath.Join(tmpDir, "ticks")
+		cmd := exec.Command("sh", "-c",
+			"(while true; do echo tick >> "+ticks+"; sleep 0.05; done) & wait")
+
+		err = runWithTimeout(cmd, ScriptNameGetVersion, 300*time.Millisecond)
+		_, ok := err.(*ErrTimeout)
+		Expect(ok).To(BeTrue())
+
+		size := func() int64 {
+			info, err := os.Stat(ticks)
+			Expect(err).To(BeNil())
+			return info.Size()
+		}
+		time.Sleep(100 * time.Millisecond)
+		killedAt := size()
+		time.Sleep(300 * time.Millisecond)
+		Expect(size()).To(Equal(killedAt))
+	})
+})
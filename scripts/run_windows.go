@@ -0,0 +1,20 @@
+package scripts
+
+import (
+	// Stdlib
+	"os/exec"
+	"strconv"
+)
+
+// startInProcessGroup does nothing on Windows, the process tree
+// is killed by killProcessGroup instead.
+func startInProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessGroup kills the command together with all its child processes.
+func killProcessGroup(cmd *exec.Cmd) error {
+	pid := strconv.Itoa(cmd.Process.Pid)
+	if err := exec.Command("taskkill", "/T", "/F", "/PID", pid).Run(); err != nil {
+		return cmd.Process.Kill()
+	}
+	return nil
+}
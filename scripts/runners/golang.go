@@ -0,0 +1,16 @@
+package runners
+
+import "os/exec"
+
+var goScriptRunner = &ScriptRunner{
+	Extension: "go",
+	SupportedPlatforms: []Platform{
+		PlatformUnix,
+		PlatformWindows,
+	},
+	NewCommand: func(script string) *exec.Cmd {
+		// The script is compiled on every run, so it is not exactly fast,
+		// but it works without any other dependency than Go itself.
+		return exec.Command("go", "run", script)
+	},
+}
@@ -0,0 +1,26 @@
+package runners
+
+import "os/exec"
+
+// Python 3 is usually not installed as python on Unix systems,
+// while on Windows there is no python3 executable at all.
+
+var pythonScriptRunner = &ScriptRunner{
+	Extension: "py",
+	SupportedPlatforms: []Platform{
+		PlatformUnix,
+	},
+	NewCommand: func(script string) *exec.Cmd {
+		return exec.Command("python3", script)
+	},
+}
+
+var pythonWindowsScriptRunner = &ScriptRunner{
+	Extension: "py",
+	SupportedPlatforms: []Platform{
+		PlatformWindows,
+	},
+	NewCommand: func(script string) *exec.Cmd {
+		return exec.Command("python", script)
+	},
+}
@@ -0,0 +1,14 @@
+package runners
+
+import "os/exec"
+
+var rubyScriptRunner = &ScriptRunner{
+	Extension: "rb",
+	SupportedPlatforms: []Platform{
+		PlatformUnix,
+		PlatformWindows,
+	},
+	NewCommand: func(script string) *exec.Cmd {
+		return exec.Command("ruby", script)
+	},
+}
@@ -2,6 +2,7 @@ package runners
 
 import (
 	"os/exec"
+	"strings"
 )
 
 type Platform int
@@ -21,8 +22,12 @@ type ScriptRunner struct {
 var runners = []*ScriptRunner{
 	bashScriptRunner,
 	batchScriptRunner,
+	goScriptRunner,
 	nodeScriptRunner,
 	powerShellScriptRunner,
+	pythonScriptRunner,
+	pythonWindowsScriptRunner,
+	rubyScriptRunner,
 }
 
 func ParsePlatform(platform string) Platform {
@@ -48,6 +53,25 @@ func ParsePlatform(platform string) Platform {
 	}
 }
 
+// NewInterpreterRunner returns a runner running the scripts using the given
+// interpreter command, which can contain arguments, e.g. "python3 -u".
+// It is used to override the built-in runners using the local configuration.
+func NewInterpreterRunner(ext string, interpreter string) *ScriptRunner {
+	args := strings.Fields(interpreter)
+	if len(args) == 0 {
+		return nil
+	}
+	return &ScriptRunner{
+		Extension:          ext,
+		SupportedPlatforms: []Platform{PlatformUnix, PlatformWindows},
+		NewCommand: func(script string) *exec.Cmd {
+			cmdArgs := make([]string, 0, len(args))
+			cmdArgs = append(cmdArgs, args[1:]...)
+			return exec.Command(args[0], append(cmdArgs, script)...)
+		},
+	}
+}
+
 func GetRunner(ext string, platform Platform) *ScriptRunner {
 	for _, runner := range runners {
 		if runner.Extension != ext {
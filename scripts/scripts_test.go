@@ -0,0 +1,28 @@
+package scripts
+
+import (
+	// Stdlib
+	"testing"
+
+	// Vendor - testing framework
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var (
+	Describe = ginkgo.Describe
+	It       = ginkgo.It
+
+	BeNil          = gomega.BeNil
+	BeTrue         = gomega.BeTrue
+	ContainElement = gomega.ContainElement
+	Equal          = gomega.Equal
+	Eventually     = gomega.Eventually
+	Expect         = gomega.Expect
+	HaveOccurred   = gomega.HaveOccurred
+)
+
+func TestScripts(t *testing.T) {
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "Custom scripts")
+}
@@ -0,0 +1,215 @@
+package secrets
+
+import (
+	// Stdlib
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// The agent is a background process caching the key derived from the passphrase.
+// It listens on a unix socket that is only accessible by the current user
+// and it exits once the timeout elapses, forgetting the key.
+//
+// The protocol is line-based. The client sends either "get", in which case
+// the agent replies with the secrets file id and the key, both hex-encoded
+// and separated by a space, or "stop", in which case the agent exits.
+
+const agentDialTimeout = time.Second
+
+// AgentSocketPath returns the path of the agent socket for the current user.
+func AgentSocketPath() string {
+	return filepath.Join(agentSocketDir(), "agent.sock")
+}
+
+// agentSocketDir returns the directory the agent socket is placed in.
+// XDG_RUNTIME_DIR is preferred since it is private to the user already,
+// the temporary directory shared by all users is used as the fallback.
+func agentSocketDir() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "salsaflow")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("salsaflow-%v", os.Getuid()))
+}
+
+// ensureAgentSocketDir creates the agent socket directory. In case the directory
+// exists already, it is only accepted when it is safe to be used, otherwise
+// another user could pre-create it and capture the key.
+func ensureAgentSocketDir(dir string) error {
+	if err := os.Mkdir(dir, 0700); err != nil && !os.IsExist(err) {
+		return err
+	}
+	return checkAgentSocketDir(dir)
+}
+
+// checkAgentSocketDir makes sure the given path is a real directory,
+// not a symlink, that is private to the current user.
+func checkAgentSocketDir(dir string) error {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("agent socket directory %v is not a directory", dir)
+	}
+	return checkAgentSocketDirAccess(dir, info)
+}
+
+// RunAgent reads the secrets file id and the key from the given reader
+// in the format the agent replies with, then it serves the key until
+// the timeout elapses or the agent is stopped.
+func RunAgent(input io.Reader, timeout time.Duration) error {
+	line, err := bufio.NewReader(input).ReadString('\n')
+	if err != nil {
+		return err
+	}
+	line = strings.TrimSpace(line)
+	if _, _, err := parseAgentReply(line); err != nil {
+		return err
+	}
+
+	// Listen on the socket, replacing any stale socket file.
+	path := AgentSocketPath()
+	if err := ensureAgentSocketDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	os.Remove(path)
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+	if err := os.Chmod(path, 0600); err != nil {
+		return err
+	}
+
+	// Stop once the timeout elapses.
+	timer := time.AfterFunc(timeout, func() {
+		listener.Close()
+	})
+	defer timer.Stop()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			// The listener has been closed.
+			return nil
+		}
+		if stop := serveAgentConn(conn, line); stop {
+			return nil
+		}
+	}
+}
+
+func serveAgentConn(conn net.Conn, reply string) (stop bool) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(agentDialTimeout))
+
+	request, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	switch strings.TrimSpace(request) {
+	case "get":
+		io.WriteString(conn, reply+"\n")
+		return false
+	case "stop":
+		io.WriteString(conn, "ok\n")
+		return true
+	default:
+		return false
+	}
+}
+
+// queryAgent asks the agent for the key.
+func queryAgent() (id string, key []byte, err error) {
+	reply, err := requestAgent("get")
+	if err != nil {
+		return "", nil, err
+	}
+	return parseAgentReply(reply)
+}
+
+// StopAgent makes the agent exit, which means that the passphrase
+// will be asked for next time the secrets are accessed.
+// Nil is returned in case the agent is not running.
+func StopAgent() error {
+	_, err := requestAgent("stop")
+	if err != nil {
+		if _, statErr := os.Stat(AgentSocketPath()); os.IsNotExist(statErr) {
+			return nil
+		}
+	}
+	return err
+}
+
+func requestAgent(request string) (string, error) {
+	// Never talk to an agent listening in a directory that is not safe.
+	path := AgentSocketPath()
+	if err := checkAgentSocketDir(filepath.Dir(path)); err != nil {
+		return "", err
+	}
+
+	conn, err := net.DialTimeout("unix", path, agentDialTimeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(agentDialTimeout))
+
+	if _, err := io.WriteString(conn, request+"\n"); err != nil {
+		return "", err
+	}
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(reply), nil
+}
+
+func parseAgentReply(reply string) (id string, key []byte, err error) {
+	parts := strings.Fields(reply)
+	if len(parts) != 2 {
+		return "", nil, errors.New("invalid agent reply")
+	}
+	key, err = hex.DecodeString(parts[1])
+	if err != nil {
+		return "", nil, err
+	}
+	return parts[0], key, nil
+}
+
+// startAgent starts the agent in the background, passing it the key.
+// The agent is run as 'config secrets agent' using the current executable.
+func startAgent(id string, key []byte, timeout time.Duration) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(executable, "config", "secrets", "agent", "-timeout="+timeout.String())
+	cmd.SysProcAttr = detachedProcAttr()
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(stdin, "%v %v\n", id, hex.EncodeToString(key))
+	stdin.Close()
+	if err != nil {
+		cmd.Process.Kill()
+		return err
+	}
+	return cmd.Process.Release()
+}
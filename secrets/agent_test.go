@@ -0,0 +1,82 @@
+package secrets
+
+import (
+	// Stdlib
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var _ = Describe("the secrets agent", func() {
+
+	var (
+		tmpDir        string
+		formerRuntime string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "salsaflow-agent-")
+		Expect(err).To(BeNil())
+		formerRuntime = os.Getenv("XDG_RUNTIME_DIR")
+		os.Setenv("XDG_RUNTIME_DIR", tmpDir)
+	})
+
+	AfterEach(func() {
+		os.Setenv("XDG_RUNTIME_DIR", formerRuntime)
+		os.RemoveAll(tmpDir)
+	})
+
+	startAgent := func(timeout time.Duration) chan error {
+		done := make(chan error, 1)
+		go func() {
+			done <- RunAgent(strings.NewReader("abcd 00ff\n"), timeout)
+		}()
+		Eventually(func() error {
+			_, err := os.Stat(AgentSocketPath())
+			return err
+		}).Should(BeNil())
+		return done
+	}
+
+	It("serves the key until stopped", func() {
+		done := startAgent(time.Minute)
+
+		id, key, err := queryAgent()
+		Expect(err).To(BeNil())
+		Expect(id).To(Equal("abcd"))
+		Expect(key).To(Equal([]byte{0x00, 0xff}))
+
+		Expect(StopAgent()).To(BeNil())
+		Eventually(done).Should(Receive(BeNil()))
+	})
+
+	It("exits when the timeout elapses", func() {
+		done := startAgent(100 * time.Millisecond)
+		Eventually(done).Should(Receive(BeNil()))
+	})
+
+	It("does not fail to stop when not running", func() {
+		Expect(StopAgent()).To(BeNil())
+	})
+
+	It("refuses the socket directory accessible by other users", func() {
+		dir := filepath.Dir(AgentSocketPath())
+		Expect(os.Mkdir(dir, 0700)).To(BeNil())
+		Expect(os.Chmod(dir, 0777)).To(BeNil())
+
+		Expect(RunAgent(strings.NewReader("abcd 00ff\n"), time.Minute)).To(HaveOccurred())
+		_, _, err := queryAgent()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("refuses the socket directory being a symlink", func() {
+		target := filepath.Join(tmpDir, "elsewhere")
+		Expect(os.Mkdir(target, 0700)).To(BeNil())
+		Expect(os.Symlink(target, filepath.Dir(AgentSocketPath()))).To(BeNil())
+
+		Expect(RunAgent(strings.NewReader("abcd 00ff\n"), time.Minute)).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,30 @@
+// +build !windows
+
+package secrets
+
+import (
+	// Stdlib
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// detachedProcAttr makes the agent run in a new session
+// so that it is not killed together with the terminal.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
+
+// checkAgentSocketDirAccess makes sure the directory is owned by the current user
+// and that it cannot be accessed by anybody else.
+func checkAgentSocketDirAccess(dir string, info os.FileInfo) error {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || int(stat.Uid) != os.Getuid() {
+		return fmt.Errorf("agent socket directory %v is not owned by the current user", dir)
+	}
+	if info.Mode().Perm() != 0700 {
+		return fmt.Errorf("agent socket directory %v must have mode 0700, has %v",
+			dir, info.Mode().Perm())
+	}
+	return nil
+}
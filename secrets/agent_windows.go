@@ -0,0 +1,18 @@
+package secrets
+
+import (
+	// Stdlib
+	"os"
+	"syscall"
+)
+
+// detachedProcAttr makes the agent run without a console window.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{HideWindow: true}
+}
+
+// checkAgentSocketDirAccess does nothing on Windows, the directory is created
+// in the temporary directory of the user, which is private to the user already.
+func checkAgentSocketDirAccess(dir string, info os.FileInfo) error {
+	return nil
+}
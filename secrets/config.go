@@ -0,0 +1,163 @@
+package secrets
+
+import (
+	// Stdlib
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/config"
+	"github.com/salsaflow/salsaflow/config/loader"
+	"github.com/salsaflow/salsaflow/errs"
+)
+
+func init() {
+	loader.RegisterBootstrapConfigSpec(newConfigSpec())
+}
+
+// Configuration ===============================================================
+
+const ConfigKey = "salsaflow.core.secrets"
+
+// Secret store backends.
+const (
+	// BackendNone keeps the secrets in the global configuration file.
+	BackendNone = ""
+
+	// BackendEncrypted keeps the secrets in a file encrypted using a passphrase.
+	BackendEncrypted = "encrypted"
+
+	// BackendHelper delegates storing the secrets to a helper command.
+	BackendHelper = "helper"
+)
+
+// DefaultAgentTimeout is the time the passphrase-derived key is cached for.
+const DefaultAgentTimeout = 15 * time.Minute
+
+// Config holds the secret store configuration.
+type Config struct {
+	Backend       string
+	HelperCommand string
+	SecretsFile   string
+	AgentTimeout  time.Duration
+}
+
+// LoadConfig reads the secret store configuration.
+//
+// The configuration record is read directly from the global configuration file
+// instead of using the loader, which is using the secret store itself.
+// A missing record means that the secret store is disabled.
+func LoadConfig() (*Config, error) {
+	task := "Load secret store configuration"
+
+	global, err := config.ReadGlobalConfig()
+	if err != nil {
+		if !os.IsNotExist(errs.RootCause(err)) {
+			return nil, errs.NewError(task, err)
+		}
+		global = config.NewEmptyGlobalConfig()
+	}
+
+	var container GlobalConfig
+	record, err := global.ConfigRecord(ConfigKey)
+	if err == nil {
+		if err := config.Unmarshal(record.RawConfig, &container); err != nil {
+			return nil, errs.NewError(task, err)
+		}
+		if err := container.Validate(record.Path()); err != nil {
+			return nil, errs.NewError(task, err)
+		}
+	}
+
+	// Use the default values where necessary.
+	secretsFile := container.SecretsFile
+	if secretsFile == "" {
+		path, err := config.GlobalConfigFileAbsolutePath()
+		if err != nil {
+			return nil, errs.NewError(task, err)
+		}
+		secretsFile = strings.TrimSuffix(path, filepath.Ext(path)) + ".secrets.json"
+	}
+
+	agentTimeout := DefaultAgentTimeout
+	if container.AgentTimeout != "" {
+		agentTimeout, _ = time.ParseDuration(container.AgentTimeout)
+	}
+
+	return &Config{
+		Backend:       container.Backend,
+		HelperCommand: container.HelperCommand,
+		SecretsFile:   secretsFile,
+		AgentTimeout:  agentTimeout,
+	}, nil
+}
+
+// Configuration spec ----------------------------------------------------------
+
+func newConfigSpec() *configSpec {
+	return &configSpec{}
+}
+
+// configSpec implements loader.ConfigSpec interface.
+type configSpec struct {
+	global *GlobalConfig
+}
+
+// ConfigKey is a part of loader.ConfigSpec interface.
+func (spec *configSpec) ConfigKey() string {
+	return ConfigKey
+}
+
+// GlobalConfig is a part of loader.ConfigSpec interface.
+func (spec *configSpec) GlobalConfig() loader.ConfigContainer {
+	spec.global = &GlobalConfig{}
+	return spec.global
+}
+
+// LocalConfig is a part of loader.ConfigSpec interface.
+func (spec *configSpec) LocalConfig() loader.ConfigContainer {
+	return nil
+}
+
+// Global config ---------------------------------------------------------------
+
+// GlobalConfig implements loader.ConfigContainer interface.
+//
+// All the fields are optional, the secrets are kept
+// in the global configuration file by default.
+type GlobalConfig struct {
+	Backend       string `json:"backend,omitempty"        optional:"true"`
+	HelperCommand string `json:"helper_command,omitempty" optional:"true"`
+	SecretsFile   string `json:"secrets_file,omitempty"   optional:"true"`
+	AgentTimeout  string `json:"agent_timeout,omitempty"  optional:"true"`
+}
+
+// PromptUserForConfig is a part of loader.ConfigContainer interface.
+//
+// There is nothing to prompt for, the secret store is configured
+// manually using 'config set', the defaults are used otherwise.
+func (global *GlobalConfig) PromptUserForConfig() error {
+	return nil
+}
+
+// Validate is a part of loader.Validator interface.
+func (global *GlobalConfig) Validate(sectionPath string) error {
+	switch global.Backend {
+	case BackendNone, BackendEncrypted:
+	case BackendHelper:
+		if global.HelperCommand == "" {
+			return &config.ErrKeyNotSet{Key: sectionPath + ".helper_command"}
+		}
+	default:
+		return &config.ErrKeyInvalid{Key: sectionPath + ".backend", Value: global.Backend}
+	}
+
+	if global.AgentTimeout != "" {
+		if _, err := time.ParseDuration(global.AgentTimeout); err != nil {
+			return &config.ErrKeyInvalid{Key: sectionPath + ".agent_timeout", Value: global.AgentTimeout}
+		}
+	}
+	return nil
+}
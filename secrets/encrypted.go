@@ -0,0 +1,265 @@
+package secrets
+
+import (
+	// Stdlib
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/log"
+
+	// Vendor
+	"github.com/bgentry/speakeasy"
+	"golang.org/x/crypto/scrypt"
+)
+
+// scrypt parameters used for new secrets files.
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+)
+
+// checkPlaintext is encrypted and stored in the secrets file
+// so that it is possible to tell whether the passphrase is correct.
+var checkPlaintext = []byte("salsaflow")
+
+// ErrWrongPassphrase is returned when the secrets file cannot be decrypted.
+var ErrWrongPassphrase = errors.New("wrong passphrase")
+
+// secretsFile represents the content of the encrypted secrets file.
+// Only the secrets are encrypted, the keys are stored as they are.
+type secretsFile struct {
+	KDF struct {
+		Name string `json:"name"`
+		Salt []byte `json:"salt"`
+		N    int    `json:"n"`
+		R    int    `json:"r"`
+		P    int    `json:"p"`
+	} `json:"kdf"`
+	Check   []byte            `json:"check"`
+	Secrets map[string][]byte `json:"secrets"`
+}
+
+// id returns the identifier used to tell the agent which key is needed.
+func (file *secretsFile) id() string {
+	return hex.EncodeToString(file.KDF.Salt)
+}
+
+// encryptedBackend keeps the secrets in a file, every secret being encrypted
+// using AES-GCM with a key derived from a passphrase using scrypt.
+// The key is cached by the agent so that the passphrase is not asked for
+// every time a command is run.
+type encryptedBackend struct {
+	path         string
+	agentTimeout time.Duration
+	file         *secretsFile
+	key          []byte
+}
+
+func newEncryptedBackend(path string, agentTimeout time.Duration) *encryptedBackend {
+	return &encryptedBackend{path: path, agentTimeout: agentTimeout}
+}
+
+func (backend *encryptedBackend) Get(key string) (string, error) {
+	if err := backend.unlock(false); err != nil {
+		return "", err
+	}
+	sealed, ok := backend.file.Secrets[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	secret, err := open(backend.key, sealed, []byte(key))
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
+
+func (backend *encryptedBackend) Set(key, secret string) error {
+	if err := backend.unlock(true); err != nil {
+		return err
+	}
+	sealed, err := seal(backend.key, []byte(secret), []byte(key))
+	if err != nil {
+		return err
+	}
+	backend.file.Secrets[key] = sealed
+	return backend.write()
+}
+
+// unlock reads the secrets file and gets the key, either from the agent
+// or by asking the user for the passphrase. In case the file does not exist
+// and create is true, a new passphrase is chosen and an empty file initialised.
+func (backend *encryptedBackend) unlock(create bool) error {
+	if backend.key != nil {
+		return nil
+	}
+
+	file, err := readSecretsFile(backend.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		if !create {
+			return ErrNotFound
+		}
+		return backend.initialise()
+	}
+	backend.file = file
+
+	// Try the agent first.
+	if id, key, err := queryAgent(); err == nil && id == file.id() {
+		if _, err := open(key, file.Check, nil); err == nil {
+			backend.key = key
+			return nil
+		}
+	}
+
+	// Ask the user for the passphrase.
+	passphrase, err := speakeasy.Ask(
+		fmt.Sprintf("Passphrase for secrets file %v: ", backend.path))
+	if err != nil {
+		return err
+	}
+	key, err := scrypt.Key(
+		[]byte(passphrase), file.KDF.Salt, file.KDF.N, file.KDF.R, file.KDF.P, scryptKeyLen)
+	if err != nil {
+		return err
+	}
+	if _, err := open(key, file.Check, nil); err != nil {
+		return ErrWrongPassphrase
+	}
+	backend.key = key
+	backend.cacheKey()
+	return nil
+}
+
+// initialise creates a new secrets file protected by a new passphrase.
+func (backend *encryptedBackend) initialise() error {
+	log.Log(fmt.Sprintf("Creating secrets file %v", backend.path))
+	passphrase, err := speakeasy.Ask("Choose a passphrase for the secrets file: ")
+	if err != nil {
+		return err
+	}
+	if passphrase == "" {
+		return errors.New("empty passphrase")
+	}
+	confirmation, err := speakeasy.Ask("Repeat the passphrase: ")
+	if err != nil {
+		return err
+	}
+	if passphrase != confirmation {
+		return errors.New("passphrases do not match")
+	}
+
+	file := &secretsFile{Secrets: make(map[string][]byte)}
+	file.KDF.Name = "scrypt"
+	file.KDF.Salt = make([]byte, 16)
+	file.KDF.N, file.KDF.R, file.KDF.P = scryptN, scryptR, scryptP
+	if _, err := io.ReadFull(rand.Reader, file.KDF.Salt); err != nil {
+		return err
+	}
+
+	key, err := scrypt.Key(
+		[]byte(passphrase), file.KDF.Salt, file.KDF.N, file.KDF.R, file.KDF.P, scryptKeyLen)
+	if err != nil {
+		return err
+	}
+	file.Check, err = seal(key, checkPlaintext, nil)
+	if err != nil {
+		return err
+	}
+
+	backend.file = file
+	backend.key = key
+	backend.cacheKey()
+	return backend.write()
+}
+
+// cacheKey starts the agent to cache the key. Failing to start the agent
+// is not fatal, the user will be asked for the passphrase next time again.
+func (backend *encryptedBackend) cacheKey() {
+	if backend.agentTimeout <= 0 {
+		return
+	}
+	if err := startAgent(backend.file.id(), backend.key, backend.agentTimeout); err != nil {
+		log.Warn(fmt.Sprintf("Failed to start the secrets agent: %v", err))
+	}
+}
+
+func (backend *encryptedBackend) write() error {
+	raw, err := json.MarshalIndent(backend.file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(backend.path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(backend.path, raw, 0600)
+}
+
+func readSecretsFile(path string) (*secretsFile, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file secretsFile
+	if err := json.NewDecoder(bytes.NewReader(raw)).Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse secrets file %v: %v", path, err)
+	}
+	if file.KDF.Name != "scrypt" {
+		return nil, fmt.Errorf("secrets file %v: unsupported key derivation function: %v",
+			path, file.KDF.Name)
+	}
+	if file.Secrets == nil {
+		file.Secrets = make(map[string][]byte)
+	}
+	return &file, nil
+}
+
+// seal encrypts plaintext using AES-GCM, the nonce is prepended to the result.
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// open decrypts the data encrypted using seal.
+func open(key, sealed, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("encrypted secret too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
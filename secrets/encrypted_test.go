@@ -0,0 +1,76 @@
+package secrets
+
+import (
+	// Stdlib
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+var _ = Describe("the encrypted backend", func() {
+
+	var (
+		dir     string
+		backend *encryptedBackend
+		key     = make([]byte, scryptKeyLen)
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "salsaflow-secrets-")
+		Expect(err).To(BeNil())
+
+		// Initialise the file without asking for the passphrase.
+		file := &secretsFile{Secrets: make(map[string][]byte)}
+		file.KDF.Name = "scrypt"
+		file.KDF.Salt = []byte("0123456789abcdef")
+		file.KDF.N, file.KDF.R, file.KDF.P = scryptN, scryptR, scryptP
+		file.Check, err = seal(key, checkPlaintext, nil)
+		Expect(err).To(BeNil())
+
+		backend = newEncryptedBackend(filepath.Join(dir, "secrets.json"), 0)
+		backend.file = file
+		backend.key = key
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("stores the secrets encrypted", func() {
+		Expect(backend.Set("salsaflow.modules.x.token", "s3cr3t")).To(BeNil())
+
+		raw, err := ioutil.ReadFile(backend.path)
+		Expect(err).To(BeNil())
+		Expect(string(raw)).NotTo(ContainSubstring("s3cr3t"))
+
+		secret, err := backend.Get("salsaflow.modules.x.token")
+		Expect(err).To(BeNil())
+		Expect(secret).To(Equal("s3cr3t"))
+	})
+
+	It("returns ErrNotFound for unknown keys", func() {
+		_, err := backend.Get("salsaflow.modules.x.token")
+		Expect(err).To(Equal(ErrNotFound))
+	})
+
+	It("binds the secrets to their keys", func() {
+		Expect(backend.Set("a", "secret")).To(BeNil())
+		backend.file.Secrets["b"] = backend.file.Secrets["a"]
+
+		_, err := backend.Get("b")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("reads the file written", func() {
+		Expect(backend.Set("a", "secret")).To(BeNil())
+
+		file, err := readSecretsFile(backend.path)
+		Expect(err).To(BeNil())
+		Expect(file.id()).To(Equal(backend.file.id()))
+
+		plaintext, err := open(key, file.Check, nil)
+		Expect(err).To(BeNil())
+		Expect(plaintext).To(Equal(checkPlaintext))
+	})
+})
@@ -0,0 +1,64 @@
+package secrets
+
+import (
+	// Stdlib
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// helperBackend delegates storing the secrets to a helper command,
+// similarly to git credential helpers.
+//
+// The helper is run using the shell with the action appended as an argument.
+// The action is either get or store, the input is passed on stdin as key=value
+// lines. For get, the key line is passed and the secret is expected on stdout,
+// empty output meaning that the secret is not known. For store, both key
+// and secret lines are passed.
+type helperBackend struct {
+	command string
+}
+
+func newHelperBackend(command string) *helperBackend {
+	return &helperBackend{command}
+}
+
+func (helper *helperBackend) Get(key string) (string, error) {
+	stdout, err := helper.run("get", fmt.Sprintf("key=%v\n", key))
+	if err != nil {
+		return "", err
+	}
+	secret := strings.TrimRight(stdout, "\r\n")
+	if secret == "" {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+func (helper *helperBackend) Set(key, secret string) error {
+	_, err := helper.run("store", fmt.Sprintf("key=%v\nsecret=%v\n", key, secret))
+	return err
+}
+
+func (helper *helperBackend) run(action, input string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", helper.command+" "+action)
+	} else {
+		cmd = exec.Command("sh", "-c", helper.command+` "$@"`, helper.command, action)
+	}
+
+	var stdout bytes.Buffer
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Stdout = &stdout
+	// The helper may need to interact with the user.
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("secret helper '%v %v' failed: %v", helper.command, action, err)
+	}
+	return stdout.String(), nil
+}
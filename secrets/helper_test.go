@@ -0,0 +1,57 @@
+package secrets
+
+import (
+	// Stdlib
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+var _ = Describe("the helper backend", func() {
+
+	var (
+		dir    string
+		helper *helperBackend
+	)
+
+	BeforeEach(func() {
+		if runtime.GOOS == "windows" {
+			Skip("the helper script requires sh")
+		}
+
+		var err error
+		dir, err = ioutil.TempDir("", "salsaflow-helper-")
+		Expect(err).To(BeNil())
+
+		// A helper storing a single secret in a file.
+		script := filepath.Join(dir, "helper.sh")
+		Expect(ioutil.WriteFile(script, []byte(`#!/bin/sh
+store="$(dirname "$0")/store"
+case "$1" in
+get)   test -f "$store" && sed -n 's/^secret=//p' "$store" ;;
+store) cat > "$store" ;;
+esac
+exit 0
+`), 0700)).To(BeNil())
+
+		helper = newHelperBackend(script)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("returns ErrNotFound when the helper prints nothing", func() {
+		_, err := helper.Get("a")
+		Expect(err).To(Equal(ErrNotFound))
+	})
+
+	It("stores and returns the secrets", func() {
+		Expect(helper.Set("a", "secret")).To(BeNil())
+
+		secret, err := helper.Get("a")
+		Expect(err).To(BeNil())
+		Expect(secret).To(Equal("secret"))
+	})
+})
@@ -0,0 +1,31 @@
+package secrets
+
+import (
+	// Stdlib
+	"testing"
+
+	// Vendor - testing framework
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var (
+	AfterEach  = ginkgo.AfterEach
+	BeforeEach = ginkgo.BeforeEach
+	Describe   = ginkgo.Describe
+	It         = ginkgo.It
+	Skip       = ginkgo.Skip
+
+	BeNil            = gomega.BeNil
+	ContainSubstring = gomega.ContainSubstring
+	Equal            = gomega.Equal
+	Eventually       = gomega.Eventually
+	Expect           = gomega.Expect
+	HaveOccurred     = gomega.HaveOccurred
+	Receive          = gomega.Receive
+)
+
+func TestSecrets(t *testing.T) {
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "Secrets")
+}
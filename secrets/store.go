@@ -0,0 +1,99 @@
+package secrets
+
+import (
+	// Stdlib
+	"errors"
+	"sync"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/config/loader"
+	"github.com/salsaflow/salsaflow/errs"
+)
+
+func init() {
+	loader.RegisterSecretStore(&store{})
+}
+
+// ErrNotFound is returned when there is no secret stored under the given key.
+var ErrNotFound = errors.New("secret not found")
+
+// ErrDisabled is returned when the secret store is not configured.
+var ErrDisabled = errors.New("secret store not enabled")
+
+// Backend keeps the secrets.
+type Backend interface {
+
+	// Get returns the secret stored under the given key.
+	// ErrNotFound is returned in case there is no such secret.
+	Get(key string) (string, error)
+
+	// Set stores the secret under the given key.
+	Set(key, secret string) error
+}
+
+// NewBackend returns the backend according to the given configuration.
+// ErrDisabled is returned in case no backend is configured.
+func NewBackend(cfg *Config) (Backend, error) {
+	switch cfg.Backend {
+	case BackendEncrypted:
+		return newEncryptedBackend(cfg.SecretsFile, cfg.AgentTimeout), nil
+	case BackendHelper:
+		return newHelperBackend(cfg.HelperCommand), nil
+	default:
+		return nil, ErrDisabled
+	}
+}
+
+// store implements loader.SecretStore using the configured backend.
+// The backend is initialised lazily so that the configuration is only read
+// when there is a secret to be resolved or stored.
+type store struct {
+	backend Backend
+	err     error
+	once    sync.Once
+}
+
+func (s *store) init() {
+	s.once.Do(func() {
+		cfg, err := LoadConfig()
+		if err != nil {
+			s.err = err
+			return
+		}
+		s.backend, s.err = NewBackend(cfg)
+	})
+}
+
+// Enabled is a part of loader.SecretStore interface.
+func (s *store) Enabled() (bool, error) {
+	s.init()
+	if s.err == ErrDisabled {
+		return false, nil
+	}
+	return s.err == nil, s.err
+}
+
+// Get is a part of loader.SecretStore interface.
+func (s *store) Get(key string) (string, error) {
+	s.init()
+	if s.err != nil {
+		return "", s.err
+	}
+	secret, err := s.backend.Get(key)
+	if err != nil {
+		return "", errs.NewError("Get secret "+key, err)
+	}
+	return secret, nil
+}
+
+// Set is a part of loader.SecretStore interface.
+func (s *store) Set(key, secret string) error {
+	s.init()
+	if s.err != nil {
+		return s.err
+	}
+	if err := s.backend.Set(key, secret); err != nil {
+		return errs.NewError("Store secret "+key, err)
+	}
+	return nil
+}
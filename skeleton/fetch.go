@@ -0,0 +1,202 @@
+package skeleton
+
+import (
+	// Stdlib
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"strings"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/git"
+	"github.com/salsaflow/salsaflow/log"
+)
+
+// CacheDirname is the directory in the current user's home directory
+// where the skeleton repositories are cached.
+const CacheDirname = ".salsaflow_skeletons"
+
+// Snapshot is a skeleton available locally at a particular commit.
+type Snapshot struct {
+	Source *Source
+
+	// Dir is the directory containing the skeleton files.
+	Dir string
+
+	// Commit is the skeleton commit hash.
+	// It is empty for a local directory that is not a git repository.
+	Commit string
+}
+
+// Fetch makes the given skeleton available locally.
+//
+// Git repositories are cloned into the local cache, or fetched in case
+// they are cached already, and the pinned ref is checked out.
+// Local directories are used directly unless a ref is specified,
+// in which case they are treated as any other git repository.
+func Fetch(src *Source) (*Snapshot, error) {
+	if src.Kind == SourceLocal && src.Ref == "" {
+		return fetchLocal(src)
+	}
+
+	// Make sure the cache directory exists.
+	task := "Make sure the local skeleton cache directory exists"
+	cacheDir, err := cacheDirectoryAbsolutePath()
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+	dir := filepath.Join(cacheDir, filepath.FromSlash(cacheKey(src)))
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return nil, errs.NewError(task, err)
+	}
+
+	// Clone or fetch the skeleton repository.
+	if _, err := os.Stat(dir); err != nil {
+		if !os.IsNotExist(err) {
+			return nil, errs.NewError(task, err)
+		}
+
+		task := fmt.Sprintf("Clone skeleton '%v'", src)
+		log.Run(task)
+		if _, err := git.Run("clone", "--no-checkout", src.CloneURL(), dir); err != nil {
+			return nil, errs.NewError(task, err)
+		}
+	} else {
+		task := fmt.Sprintf("Fetch skeleton '%v'", src)
+		log.Run(task)
+		_, err := git.Run("-C", dir, "fetch", "--tags", "--force",
+			"origin", "+refs/heads/*:refs/remotes/origin/*")
+		if err != nil {
+			return nil, errs.NewError(task, err)
+		}
+	}
+
+	// Check out the pinned commit.
+	commit, err := resolveRef(dir, src.Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	task = fmt.Sprintf("Check out skeleton commit %v", commit)
+	if _, err := git.Run("-C", dir, "checkout", "--force", "--detach", commit); err != nil {
+		return nil, errs.NewError(task, err)
+	}
+	if _, err := git.Run("-C", dir, "clean", "-ffdx"); err != nil {
+		return nil, errs.NewError(task, err)
+	}
+
+	return &Snapshot{src, dir, commit}, nil
+}
+
+func fetchLocal(src *Source) (*Snapshot, error) {
+	task := fmt.Sprintf("Check skeleton directory '%v'", src)
+	info, err := os.Stat(src.Location)
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+	if !info.IsDir() {
+		return nil, errs.NewError(task, fmt.Errorf("not a directory: %v", src.Location))
+	}
+
+	// Record the commit in case the directory is a git repository.
+	var commit string
+	stdout, err := git.Run("-C", src.Location, "rev-parse", "--verify", "--quiet", "HEAD")
+	if err == nil {
+		commit = strings.TrimSpace(stdout.String())
+	}
+	return &Snapshot{src, src.Location, commit}, nil
+}
+
+// resolveRef returns the commit hash for the given ref in the given repository.
+// The remote branches take precedence, then tags and commit hashes are tried.
+// The default branch is used when the ref is empty.
+func resolveRef(dir, ref string) (string, error) {
+	var candidates []string
+	if ref == "" {
+		// Old cached clones might be missing origin/HEAD.
+		if _, err := revParse(dir, "refs/remotes/origin/HEAD"); err != nil {
+			git.Run("-C", dir, "remote", "set-head", "origin", "--auto")
+		}
+		candidates = []string{"refs/remotes/origin/HEAD"}
+	} else {
+		candidates = []string{"refs/remotes/origin/" + ref, "refs/tags/" + ref, ref}
+	}
+
+	for _, candidate := range candidates {
+		if commit, err := revParse(dir, candidate); err == nil {
+			return commit, nil
+		}
+	}
+
+	task := "Resolve the skeleton ref"
+	if ref == "" {
+		return "", errs.NewError(task, errors.New("failed to resolve the default branch"))
+	}
+	return "", errs.NewError(task, fmt.Errorf("ref not found in the skeleton: %v", ref))
+}
+
+func revParse(dir, ref string) (string, error) {
+	stdout, err := git.Run("-C", dir, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// cacheKey returns the path relative to the cache directory
+// where the given skeleton is cached.
+func cacheKey(src *Source) string {
+	switch src.Kind {
+	case SourceGitHub:
+		return path.Join("github.com", src.Location)
+
+	case SourceLocal:
+		sum := sha1.Sum([]byte(src.Location))
+		name := filepath.Base(src.Location) + "-" + hex.EncodeToString(sum[:])[:8]
+		return path.Join("local", name)
+
+	default:
+		location := src.Location
+		if i := strings.Index(location, "://"); i != -1 {
+			location = location[i+3:]
+		}
+		if i := strings.Index(location, "@"); i != -1 && i < strings.IndexAny(location+"/", "/:") {
+			location = location[i+1:]
+		}
+		location = strings.Replace(location, ":", "/", -1)
+		location = strings.TrimSuffix(location, "/")
+		location = strings.TrimSuffix(location, ".git")
+
+		// Make sure the key cannot point outside of the cache.
+		parts := strings.Split(location, "/")
+		key := make([]string, 0, len(parts))
+		for _, part := range parts {
+			if part != "" && part != "." && part != ".." {
+				key = append(key, part)
+			}
+		}
+		return path.Join(key...)
+	}
+}
+
+func cacheDirectoryAbsolutePath() (string, error) {
+	home, err := homeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, CacheDirname), nil
+}
+
+func homeDir() (string, error) {
+	me, err := user.Current()
+	if err != nil {
+		return "", err
+	}
+	return me.HomeDir, nil
+}
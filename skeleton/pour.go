@@ -0,0 +1,409 @@
+package skeleton
+
+import (
+	// Stdlib
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/action"
+	"github.com/salsaflow/salsaflow/config"
+	"github.com/salsaflow/salsaflow/errs"
+	"github.com/salsaflow/salsaflow/git/gitutil"
+	"github.com/salsaflow/salsaflow/log"
+	"github.com/salsaflow/salsaflow/prompt"
+)
+
+const (
+	// ManifestFilename is the file in the skeleton root declaring the template variables.
+	ManifestFilename = "skeleton.json"
+
+	// TemplateSuffix marks the skeleton files to be rendered as templates.
+	TemplateSuffix = ".tmpl"
+
+	// VariableRepositoryName is the name of the built-in variable
+	// containing the name of the repository root directory.
+	VariableRepositoryName = "repository_name"
+)
+
+// Variable is a template variable declared in the skeleton manifest.
+type Variable struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Default     string `json:"default"`
+}
+
+// Manifest represents the skeleton manifest, skeleton.json.
+type Manifest struct {
+	Variables []*Variable `json:"variables"`
+}
+
+// Manifest reads the skeleton manifest.
+// An empty manifest is returned in case the skeleton does not contain any.
+func (snap *Snapshot) Manifest() (*Manifest, error) {
+	task := "Read the skeleton manifest"
+	content, err := ioutil.ReadFile(filepath.Join(snap.Dir, ManifestFilename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Manifest{}, nil
+		}
+		return nil, errs.NewError(task, err)
+	}
+
+	var manifest Manifest
+	if err := config.Unmarshal(content, &manifest); err != nil {
+		return nil, errs.NewError(task, err)
+	}
+	for _, v := range manifest.Variables {
+		if v.Name == "" {
+			return nil, errs.NewError(task, errors.New("variable name not set"))
+		}
+	}
+	return &manifest, nil
+}
+
+// Variables returns the values for the variables declared in the skeleton manifest.
+//
+// The values available in known are used as they are,
+// the user is prompted for the values of the remaining variables.
+func (snap *Snapshot) Variables(known map[string]string) (map[string]string, error) {
+	manifest, err := snap.Manifest()
+	if err != nil {
+		return nil, err
+	}
+
+	vars := make(map[string]string, len(manifest.Variables))
+	for _, v := range manifest.Variables {
+		if value, ok := known[v.Name]; ok {
+			vars[v.Name] = value
+			continue
+		}
+
+		question := v.Description
+		if question == "" {
+			question = v.Name
+		}
+		value, err := prompt.PromptDefault(question, v.Default)
+		if err != nil {
+			return nil, errs.NewError(fmt.Sprintf("Prompt for skeleton variable '%v'", v.Name), err)
+		}
+		vars[v.Name] = value
+	}
+	return vars, nil
+}
+
+// Record returns the local configuration record describing the snapshot.
+func (snap *Snapshot) Record(vars map[string]string) *config.SkeletonRecord {
+	record := &config.SkeletonRecord{
+		Source: snap.Source.Location,
+		Ref:    snap.Source.Ref,
+		Commit: snap.Commit,
+	}
+	if len(vars) != 0 {
+		record.Variables = vars
+	}
+	return record
+}
+
+// WriteRecord saves the given skeleton record into the local configuration file.
+func WriteRecord(record *config.SkeletonRecord) error {
+	task := "Record the skeleton in the local configuration file"
+	local, err := config.ReadLocalConfig()
+	if err != nil {
+		if !os.IsNotExist(errs.RootCause(err)) {
+			return errs.NewError(task, err)
+		}
+		local = config.NewEmptyLocalConfig()
+	}
+
+	// The local configuration file could have been shipped by the skeleton,
+	// in which case it does not need to be complete.
+	empty := config.NewEmptyLocalConfig()
+	if local.EnabledTimestamp == nil {
+		local.EnabledTimestamp = empty.EnabledTimestamp
+	}
+	if local.ConfigurationsSection == nil {
+		local.ConfigurationsSection = empty.ConfigurationsSection
+	}
+
+	local.Skeleton = record
+	if err := config.WriteLocalConfig(local); err != nil {
+		return errs.NewError(task, err)
+	}
+	return nil
+}
+
+// File is a file to be poured into the local configuration directory.
+type File struct {
+	// Path is the path relative to the local configuration directory.
+	Path    string
+	Mode    os.FileMode
+	Content []byte
+}
+
+// Files returns the files the skeleton consists of, with the templates rendered
+// using the given variables. The files are sorted by path.
+//
+// In case the skeleton contains .salsaflow directory, the content of the directory
+// is used. Otherwise the content of scripts directory is placed into scripts.
+func (snap *Snapshot) Files(vars map[string]string) ([]*File, error) {
+	task := "Collect the skeleton files"
+
+	root, prefix := filepath.Join(snap.Dir, config.LocalConfigDirname), ""
+	if !isDir(root) {
+		root, prefix = filepath.Join(snap.Dir, "scripts"), "scripts"
+		if !isDir(root) {
+			return nil, errs.NewError(task, fmt.Errorf(
+				"neither %v nor scripts directory found in the skeleton", config.LocalConfigDirname))
+		}
+		log.Warn(fmt.Sprintf(
+			"No %v directory found in the skeleton, using the scripts directory",
+			config.LocalConfigDirname))
+	}
+
+	data, err := templateData(vars)
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+
+	var files []*File
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if strings.HasSuffix(rel, TemplateSuffix) {
+			rel = strings.TrimSuffix(rel, TemplateSuffix)
+			content, err = render(rel, content, data)
+			if err != nil {
+				return err
+			}
+		}
+
+		files = append(files, &File{
+			Path:    filepath.Join(prefix, rel),
+			Mode:    info.Mode().Perm(),
+			Content: content,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, errs.NewError(task, err)
+	}
+
+	sort.Sort(filesByPath(files))
+	return files, nil
+}
+
+// Pour writes the skeleton files into the given local configuration directory.
+//
+// The files that exist already are overwritten, except for the local
+// configuration file, into which the skeleton configuration is merged.
+// The records specified by the skeleton replace the existing records,
+// the rest of the configuration is kept untouched.
+//
+// The action returned restores the directory to the original state.
+func Pour(files []*File, localConfigDir string) (act action.Action, err error) {
+	var undo []func() error
+	rollback := action.ActionFunc(func() error {
+		var ex error
+		for i := len(undo) - 1; i >= 0; i-- {
+			if err := undo[i](); err != nil {
+				ex = err
+			}
+		}
+		return ex
+	})
+	defer action.RollbackTaskOnError(&err, "Pour the skeleton", rollback)
+
+	for _, file := range files {
+		dst := filepath.Join(localConfigDir, file.Path)
+		task := fmt.Sprintf("Write %v", dst)
+
+		// Create the parent directories, remembering the ones created.
+		created, err := mkdirAll(filepath.Dir(dst))
+		if err != nil {
+			return nil, errs.NewError(task, err)
+		}
+		for _, dir := range created {
+			dir := dir
+			undo = append(undo, func() error {
+				return os.Remove(dir)
+			})
+		}
+
+		// Remember the original content.
+		original, err := ioutil.ReadFile(dst)
+		switch {
+		case err == nil:
+			info, err := os.Stat(dst)
+			if err != nil {
+				return nil, errs.NewError(task, err)
+			}
+			mode := info.Mode().Perm()
+			undo = append(undo, func() error {
+				return ioutil.WriteFile(dst, original, mode)
+			})
+		case os.IsNotExist(err):
+			undo = append(undo, func() error {
+				return os.Remove(dst)
+			})
+		default:
+			return nil, errs.NewError(task, err)
+		}
+
+		// Write the file.
+		content, mode := file.Content, file.Mode
+		if file.Path == config.LocalConfigFilename {
+			content, err = mergeLocalConfig(original, content)
+			if err != nil {
+				return nil, errs.NewError(task, err)
+			}
+			mode = 0600
+		}
+		if err := ioutil.WriteFile(dst, content, mode); err != nil {
+			return nil, errs.NewError(task, err)
+		}
+		if err := os.Chmod(dst, mode); err != nil {
+			return nil, errs.NewError(task, err)
+		}
+	}
+
+	return rollback, nil
+}
+
+// mergeLocalConfig merges the local configuration file shipped by the skeleton
+// into the current local configuration file content, which can be empty.
+func mergeLocalConfig(current, incoming []byte) ([]byte, error) {
+	var cur, in config.LocalConfig
+	if len(current) != 0 {
+		if err := config.Unmarshal(current, &cur); err != nil {
+			return nil, err
+		}
+	}
+	if err := config.Unmarshal(incoming, &in); err != nil {
+		return nil, fmt.Errorf("invalid skeleton %v: %v", config.LocalConfigFilename, err)
+	}
+
+	if cur.EnabledTimestamp == nil {
+		cur.EnabledTimestamp = in.EnabledTimestamp
+	}
+
+	// Active modules.
+	if v := in.Modules.IssueTracking; v != "" {
+		cur.Modules.IssueTracking = v
+	}
+	if v := in.Modules.CodeReview; v != "" {
+		cur.Modules.CodeReview = v
+	}
+	if v := in.Modules.ReleaseNotes; v != "" {
+		cur.Modules.ReleaseNotes = v
+	}
+	if v := in.Modules.AdditionalIssueTracking; v != nil {
+		cur.Modules.AdditionalIssueTracking = v
+	}
+
+	// Script settings.
+	if in.Scripts != nil {
+		cur.Scripts = in.Scripts
+	}
+
+	// Configuration records.
+	if cur.ConfigurationsSection == nil {
+		cur.ConfigurationsSection = &config.ConfigurationsSection{}
+	}
+	if cur.Records == nil {
+		cur.Records = make(map[string]*json.RawMessage)
+	}
+	if in.ConfigurationsSection != nil {
+		for key, record := range in.Records {
+			cur.Records[key] = record
+			cur.SetSchemaVersion(key, in.SchemaVersion(key))
+		}
+	}
+
+	return config.Marshal(&cur)
+}
+
+func templateData(vars map[string]string) (map[string]string, error) {
+	data := make(map[string]string, len(vars)+1)
+	root, err := gitutil.RepositoryRootAbsolutePath()
+	if err != nil {
+		return nil, err
+	}
+	data[VariableRepositoryName] = filepath.Base(root)
+	for k, v := range vars {
+		data[k] = v
+	}
+	return data, nil
+}
+
+func render(name string, content []byte, data map[string]string) ([]byte, error) {
+	t, err := template.New(name).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	if err := t.Execute(&buffer, data); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// mkdirAll works like os.MkdirAll, but it returns the directories created,
+// the deepest one being the last.
+func mkdirAll(dir string) ([]string, error) {
+	var missing []string
+	for d := dir; !exists(d); d = filepath.Dir(d) {
+		missing = append([]string{d}, missing...)
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return missing, nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+type filesByPath []*File
+
+func (files filesByPath) Len() int           { return len(files) }
+func (files filesByPath) Less(i, j int) bool { return files[i].Path < files[j].Path }
+func (files filesByPath) Swap(i, j int)      { files[i], files[j] = files[j], files[i] }
@@ -0,0 +1,175 @@
+package skeleton
+
+import (
+	// Stdlib
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/config"
+
+	// Vendor - testing framework
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var (
+	AfterEach  = ginkgo.AfterEach
+	BeforeEach = ginkgo.BeforeEach
+	Describe   = ginkgo.Describe
+	It         = ginkgo.It
+
+	ContainSubstring = gomega.ContainSubstring
+	Equal            = gomega.Equal
+	Expect           = gomega.Expect
+	HaveLen          = gomega.HaveLen
+	HaveOccurred     = gomega.HaveOccurred
+	Succeed          = gomega.Succeed
+)
+
+func TestSkeleton(t *testing.T) {
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "Skeleton")
+}
+
+var _ = Describe("ParseSource", func() {
+
+	parse := func(spec string) *Source {
+		src, err := ParseSource(spec)
+		Expect(err).NotTo(HaveOccurred())
+		return src
+	}
+
+	It("treats OWNER/REPO as a GitHub repository", func() {
+		src := parse("salsaflow/skeleton-golang@v1.0.0")
+		Expect(src.Kind).To(Equal(SourceGitHub))
+		Expect(src.Location).To(Equal("salsaflow/skeleton-golang"))
+		Expect(src.Ref).To(Equal("v1.0.0"))
+		Expect(src.CloneURL()).To(Equal("https://github.com/salsaflow/skeleton-golang"))
+		Expect(cacheKey(src)).To(Equal("github.com/salsaflow/skeleton-golang"))
+	})
+
+	It("does not mistake the user in a URL for the ref", func() {
+		src := parse("https://joe@example.com/team/skeleton.git")
+		Expect(src.Kind).To(Equal(SourceGitURL))
+		Expect(src.Location).To(Equal("https://joe@example.com/team/skeleton.git"))
+		Expect(src.Ref).To(Equal(""))
+		Expect(cacheKey(src)).To(Equal("example.com/team/skeleton"))
+
+		src = parse("git@example.com:team/skeleton.git@release/1.0")
+		Expect(src.Kind).To(Equal(SourceGitURL))
+		Expect(src.Location).To(Equal("git@example.com:team/skeleton.git"))
+		Expect(src.Ref).To(Equal("release/1.0"))
+		Expect(cacheKey(src)).To(Equal("example.com/team/skeleton"))
+	})
+
+	It("turns local paths into absolute paths", func() {
+		src := parse("./testdata@master")
+		wd, _ := os.Getwd()
+		Expect(src.Kind).To(Equal(SourceLocal))
+		Expect(src.Location).To(Equal(filepath.Join(wd, "testdata")))
+		Expect(src.Ref).To(Equal("master"))
+	})
+
+	It("rejects invalid specifications", func() {
+		for _, spec := range []string{"", "skeleton", "owner/repo@", "@v1"} {
+			_, err := ParseSource(spec)
+			Expect(err).To(HaveOccurred())
+		}
+	})
+})
+
+var _ = Describe("pouring the skeleton", func() {
+
+	var (
+		skeletonDir string
+		targetDir   string
+		snap        *Snapshot
+	)
+
+	write := func(path, content string) {
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(path, []byte(content), 0644)).To(Succeed())
+	}
+
+	read := func(path string) string {
+		content, err := ioutil.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		return string(content)
+	}
+
+	BeforeEach(func() {
+		var err error
+		skeletonDir, err = ioutil.TempDir("", "salsaflow-skeleton-")
+		Expect(err).NotTo(HaveOccurred())
+		targetDir, err = ioutil.TempDir("", "salsaflow-target-")
+		Expect(err).NotTo(HaveOccurred())
+
+		write(filepath.Join(skeletonDir, ManifestFilename),
+			`{"variables": [{"name": "project", "default": "SF"}]}`)
+		write(filepath.Join(skeletonDir, ".salsaflow", "scripts", "get_version.bash"), "echo 1.0.0")
+		write(filepath.Join(skeletonDir, ".salsaflow", "config.json.tmpl"), `{
+  "active_modules": {"issue_tracking": "salsaflow.modules.issuetracking.jira"},
+  "configuration": {"salsaflow.modules.issuetracking.jira": {"project_key": "{{.project}}"}}
+}`)
+
+		snap = &Snapshot{Source: &Source{Kind: SourceLocal, Location: skeletonDir}, Dir: skeletonDir}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(skeletonDir)
+		os.RemoveAll(targetDir)
+	})
+
+	It("renders the templates and merges the local config file", func() {
+		write(filepath.Join(targetDir, "config.json"), `{
+  "active_modules": {"code_review": "salsaflow.modules.codereview.noop"},
+  "configuration": {"salsaflow.modules.issuetracking.jira": {"project_key": "OLD"}, "other": 1}
+}`)
+
+		vars, err := snap.Variables(map[string]string{"project": "SALSA"})
+		Expect(err).NotTo(HaveOccurred())
+		files, err := snap.Files(vars)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(HaveLen(2))
+		Expect(files[0].Path).To(Equal("config.json"))
+		Expect(files[1].Path).To(Equal(filepath.Join("scripts", "get_version.bash")))
+
+		act, err := Pour(files, targetDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(read(filepath.Join(targetDir, "scripts", "get_version.bash"))).To(Equal("echo 1.0.0"))
+
+		var local config.LocalConfig
+		Expect(config.Unmarshal([]byte(read(filepath.Join(targetDir, "config.json"))), &local)).
+			To(Succeed())
+		Expect(local.Modules.IssueTracking).To(Equal("salsaflow.modules.issuetracking.jira"))
+		Expect(local.Modules.CodeReview).To(Equal("salsaflow.modules.codereview.noop"))
+		Expect(local.Records).To(HaveLen(2))
+
+		var jira struct {
+			ProjectKey string `json:"project_key"`
+		}
+		Expect(json.Unmarshal(*local.Records["salsaflow.modules.issuetracking.jira"], &jira)).
+			To(Succeed())
+		Expect(jira.ProjectKey).To(Equal("SALSA"))
+
+		// Rolling back restores the original state.
+		Expect(act.Rollback()).To(Succeed())
+		Expect(read(filepath.Join(targetDir, "config.json"))).To(ContainSubstring("OLD"))
+		_, err = os.Stat(filepath.Join(targetDir, "scripts"))
+		Expect(os.IsNotExist(err)).To(Equal(true))
+	})
+
+	It("falls back to the scripts directory", func() {
+		os.RemoveAll(filepath.Join(skeletonDir, ".salsaflow"))
+		write(filepath.Join(skeletonDir, "scripts", "get_version.bash"), "echo 2.0.0")
+
+		files, err := snap.Files(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(HaveLen(1))
+		Expect(files[0].Path).To(Equal(filepath.Join("scripts", "get_version.bash")))
+	})
+})
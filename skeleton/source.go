@@ -0,0 +1,163 @@
+/*
+Package skeleton implements repository skeletons.
+
+A skeleton is a directory or a git repository containing the files
+to be poured into the local configuration directory, .salsaflow.
+The skeleton can ship a complete .salsaflow tree, in which case the content
+of the .salsaflow directory in the skeleton is used. Otherwise the scripts
+directory is copied into .salsaflow/scripts, which is the legacy skeleton layout.
+
+Files ending with .tmpl are rendered using text/template and saved without
+the suffix. The template variables can be declared in skeleton.json placed
+in the skeleton root:
+
+  {
+    "variables": [
+      {"name": "jira_project", "description": "JIRA project key", "default": "SF"}
+    ]
+  }
+
+The values for the declared variables are prompted for unless specified
+otherwise. Variable repository_name is always available and contains
+the name of the repository root directory.
+*/
+package skeleton
+
+import (
+	// Stdlib
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// SourceKind specifies the kind of location a skeleton is fetched from.
+type SourceKind int
+
+const (
+	// SourceGitHub is a GitHub repository specified as OWNER/REPO.
+	SourceGitHub SourceKind = iota
+	// SourceGitURL is a git repository specified by its URL.
+	SourceGitURL
+	// SourceLocal is a local directory.
+	SourceLocal
+)
+
+var (
+	shorthandRegexp = regexp.MustCompile(`^[\w.-]+/[\w.-]+$`)
+	scpLikeRegexp   = regexp.MustCompile(`^[\w.-]+@[\w.-]+:`)
+)
+
+// Source represents a skeleton location as specified by the user.
+type Source struct {
+	Kind     SourceKind
+	Location string
+	Ref      string
+}
+
+// ParseSource parses the skeleton specification, which is SOURCE[@REF].
+//
+// SOURCE can be a GitHub repository in the form of OWNER/REPO,
+// any git URL accepted by git clone, or a local directory.
+// REF is a branch, a tag or a commit the skeleton is pinned to.
+// In case no ref is specified, the default branch is used.
+func ParseSource(spec string) (*Source, error) {
+	if spec == "" {
+		return nil, fmt.Errorf("invalid skeleton specification: empty string")
+	}
+
+	var (
+		location = spec
+		ref      string
+	)
+	if i := refSeparatorIndex(spec); i != -1 {
+		location, ref = spec[:i], spec[i+1:]
+		if location == "" || ref == "" {
+			return nil, fmt.Errorf("invalid skeleton specification: %v", spec)
+		}
+	}
+
+	src := &Source{Location: location, Ref: ref}
+	switch {
+	case isURL(location):
+		src.Kind = SourceGitURL
+
+	case isLocalPath(location):
+		src.Kind = SourceLocal
+		path, err := expandPath(location)
+		if err != nil {
+			return nil, err
+		}
+		src.Location = path
+
+	case shorthandRegexp.MatchString(location):
+		src.Kind = SourceGitHub
+
+	default:
+		return nil, fmt.Errorf("invalid skeleton specification: %v", spec)
+	}
+	return src, nil
+}
+
+// CloneURL returns the URL to be passed to git clone.
+func (src *Source) CloneURL() string {
+	if src.Kind == SourceGitHub {
+		return fmt.Sprintf("https://github.com/%v", src.Location)
+	}
+	return src.Location
+}
+
+// String returns the source location without the ref.
+func (src *Source) String() string {
+	return src.Location
+}
+
+// refSeparatorIndex returns the index of the @ separating the ref,
+// making sure the @ separating the user in a URL is not mistaken for it.
+func refSeparatorIndex(spec string) int {
+	var offset int
+	switch {
+	case strings.Contains(spec, "://"):
+		offset = strings.Index(spec, "://") + 3
+		i := strings.Index(spec[offset:], "/")
+		if i == -1 {
+			return -1
+		}
+		offset += i
+
+	case scpLikeRegexp.MatchString(spec):
+		offset = strings.Index(spec, ":")
+	}
+
+	i := strings.LastIndex(spec[offset:], "@")
+	if i == -1 {
+		return -1
+	}
+	return offset + i
+}
+
+func isURL(location string) bool {
+	return strings.Contains(location, "://") || scpLikeRegexp.MatchString(location)
+}
+
+func isLocalPath(location string) bool {
+	if filepath.IsAbs(location) ||
+		strings.HasPrefix(location, ".") || strings.HasPrefix(location, "~") {
+
+		return true
+	}
+	info, err := os.Stat(location)
+	return err == nil && info.IsDir()
+}
+
+func expandPath(path string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := homeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, path[1:])
+	}
+	return filepath.Abs(path)
+}
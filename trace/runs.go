@@ -0,0 +1,91 @@
+package trace
+
+import (
+	// Stdlib
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Run represents a single command invocation recorded in the trace log directory.
+type Run struct {
+	Path   string
+	Events []*Event
+}
+
+// Name returns the base name of the log file without the extension.
+func (run *Run) Name() string {
+	base := filepath.Base(run.Path)
+	return base[:len(base)-len(LogFileExtension)]
+}
+
+// Start returns the start event, if available.
+func (run *Run) Start() *Event {
+	for _, event := range run.Events {
+		if event.Type == EventTypeStart {
+			return event
+		}
+	}
+	return nil
+}
+
+// Exit returns the exit event, if available.
+// The exit event is missing in case the process was killed.
+func (run *Run) Exit() *Event {
+	for i := len(run.Events) - 1; i >= 0; i-- {
+		if event := run.Events[i]; event.Type == EventTypeExit {
+			return event
+		}
+	}
+	return nil
+}
+
+// ListRuns returns the runs recorded in the given directory, the most recent first.
+func ListRuns(logDir string) ([]*Run, error) {
+	files, err := logFiles(logDir)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]*Run, 0, len(files))
+	for i := len(files) - 1; i >= 0; i-- {
+		run, err := ReadRun(files[i])
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// ReadRun reads and parses the given trace log file.
+func ReadRun(path string) (*Run, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var (
+		events  []*Event
+		lineNum int
+		scanner = bufio.NewScanner(file)
+	)
+	// Some lines can be pretty long, e.g. the git args.
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lineNum++
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("%v:%v: %v", path, lineNum, err)
+		}
+		events = append(events, &event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return &Run{path, events}, nil
+}
@@ -0,0 +1,248 @@
+package trace
+
+import (
+	// Stdlib
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	// Internal
+	"github.com/salsaflow/salsaflow/log"
+)
+
+// LogDirname is the path of the trace log directory relative to the .git directory.
+var LogDirname = filepath.Join("salsaflow", "logs")
+
+// MaxRuns is the number of the most recent trace log files being kept.
+const MaxRuns = 100
+
+// LogFileExtension is the extension used for the trace log files.
+const LogFileExtension = ".jsonl"
+
+const (
+	EventTypeStart = "start"
+	EventTypeGit   = "git"
+	EventTypeHTTP  = "http"
+	EventTypeTask  = "task"
+	EventTypeExit  = "exit"
+)
+
+// Event represents a single line in the trace log file.
+type Event struct {
+	Time     time.Time `json:"time"`
+	Type     string    `json:"type"`
+	Args     []string  `json:"args,omitempty"`
+	Version  string    `json:"version,omitempty"`
+	Method   string    `json:"method,omitempty"`
+	URL      string    `json:"url,omitempty"`
+	Status   int       `json:"status,omitempty"`
+	Tag      string    `json:"tag,omitempty"`
+	Message  string    `json:"message,omitempty"`
+	Duration *int64    `json:"duration_ms,omitempty"`
+	ExitCode *int      `json:"exit_code,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+var (
+	file    *os.File
+	encoder *json.Encoder
+	lock    sync.Mutex
+)
+
+// LogDirectory returns the trace log directory for the given .git directory.
+func LogDirectory(gitDir string) string {
+	return filepath.Join(gitDir, LogDirname)
+}
+
+// Enable starts writing the trace log for the current command invocation.
+//
+// A new file is created in the given directory for every invocation,
+// the oldest files are removed so that there are at most MaxRuns files.
+// Enable also starts tracing HTTP requests by wrapping http.DefaultTransport.
+func Enable(logDir string, args []string, version string) error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if file != nil {
+		return nil
+	}
+
+	// Make sure the log directory exists.
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		return err
+	}
+
+	// Remove the old log files.
+	if err := prune(logDir, MaxRuns-1); err != nil {
+		return err
+	}
+
+	// Open a new log file.
+	now := time.Now()
+	filename := fmt.Sprintf("%v-%v%v", now.Format("20060102T150405.000"), os.Getpid(), LogFileExtension)
+	f, err := os.OpenFile(
+		filepath.Join(logDir, filename), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	file = f
+	encoder = json.NewEncoder(f)
+
+	// Write the start event.
+	unsafeWrite(&Event{
+		Time:    now,
+		Type:    EventTypeStart,
+		Args:    redactArgs(args),
+		Version: version,
+	})
+
+	// Record the tasks being logged.
+	log.AddHook(func(tag log.Tag, msg string) {
+		write(&Event{
+			Time:    time.Now(),
+			Type:    EventTypeTask,
+			Tag:     string(tag),
+			Message: msg,
+		})
+	})
+
+	// Record all HTTP requests going through the default transport.
+	http.DefaultTransport = NewTransport(http.DefaultTransport)
+	return nil
+}
+
+// CurrentLogFile returns the path of the log file being written,
+// or an empty string when the trace log is not enabled.
+func CurrentLogFile() string {
+	lock.Lock()
+	defer lock.Unlock()
+	if file == nil {
+		return ""
+	}
+	return file.Name()
+}
+
+// Enabled returns true in case the trace log is being written.
+func Enabled() bool {
+	lock.Lock()
+	defer lock.Unlock()
+	return file != nil
+}
+
+// Git records a git invocation. The arguments containing URLs,
+// e.g. the repository being cloned, are redacted before being written.
+func Git(args []string, duration time.Duration, exitCode int) {
+	write(&Event{
+		Time:     time.Now(),
+		Type:     EventTypeGit,
+		Args:     redactArgs(args),
+		Duration: milliseconds(duration),
+		ExitCode: &exitCode,
+	})
+}
+
+// HTTP records an HTTP request. The URL is redacted before being written.
+func HTTP(method, url string, status int, latency time.Duration, err error) {
+	event := &Event{
+		Time:     time.Now(),
+		Type:     EventTypeHTTP,
+		Method:   method,
+		URL:      RedactURL(url),
+		Status:   status,
+		Duration: milliseconds(latency),
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	write(event)
+}
+
+// redactArgs runs the arguments that contain a URL through RedactURL.
+// The URL can also be the value of an argument, e.g. --option=URL.
+func redactArgs(args []string) []string {
+	redactedArgs := make([]string, 0, len(args))
+	for _, arg := range args {
+		i := strings.Index(arg, "://")
+		if i == -1 {
+			redactedArgs = append(redactedArgs, arg)
+			continue
+		}
+		prefix := arg[:strings.LastIndex(arg[:i], "=")+1]
+		redactedArgs = append(redactedArgs, prefix+RedactURL(arg[len(prefix):]))
+	}
+	return redactedArgs
+}
+
+// Close writes the exit event and closes the trace log file.
+func Close(exitCode int) error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if file == nil {
+		return nil
+	}
+
+	unsafeWrite(&Event{
+		Time:     time.Now(),
+		Type:     EventTypeExit,
+		ExitCode: &exitCode,
+	})
+
+	err := file.Close()
+	file = nil
+	encoder = nil
+	return err
+}
+
+func write(event *Event) {
+	lock.Lock()
+	defer lock.Unlock()
+	unsafeWrite(event)
+}
+
+func unsafeWrite(event *Event) {
+	if encoder == nil {
+		return
+	}
+	// Tracing must never break the command, so the error is ignored.
+	encoder.Encode(event)
+}
+
+func milliseconds(d time.Duration) *int64 {
+	ms := int64(d / time.Millisecond)
+	return &ms
+}
+
+// prune removes the oldest log files so that only keep files are left.
+func prune(logDir string, keep int) error {
+	files, err := logFiles(logDir)
+	if err != nil {
+		return err
+	}
+	if len(files) <= keep {
+		return nil
+	}
+	for _, f := range files[:len(files)-keep] {
+		if err := os.Remove(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// logFiles returns the log files in the given directory, the oldest first.
+// The file names start with a timestamp, so sorting them is enough.
+func logFiles(logDir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(logDir, "*"+LogFileExtension))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,23 @@
+package trace
+
+import (
+	// Stdlib
+	"testing"
+
+	// Vendor - testing framework
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var (
+	Describe = ginkgo.Describe
+	It       = ginkgo.It
+
+	Equal  = gomega.Equal
+	Expect = gomega.Expect
+)
+
+func TestTrace(t *testing.T) {
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "Trace log")
+}
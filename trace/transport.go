@@ -0,0 +1,71 @@
+package trace
+
+import (
+	// Stdlib
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+)
+
+// Transport is an http.RoundTripper that records every request in the trace log.
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// NewTransport returns a new Transport wrapping the given http.RoundTripper.
+func NewTransport(base http.RoundTripper) *Transport {
+	return &Transport{base}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	latency := time.Since(start)
+
+	var status int
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	HTTP(req.Method, req.URL.String(), status, latency, err)
+	return resp, err
+}
+
+var sensitiveQueryKeyPattern = regexp.MustCompile("(?i)token|secret|password|key|signature")
+
+const redacted = "REDACTED"
+
+// RedactURL removes secrets from the given URL, i.e. the user info password
+// and the values of query parameters that look like they contain secrets.
+func RedactURL(rawurl string) string {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return rawurl
+	}
+
+	if u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), redacted)
+		}
+	}
+
+	query := u.Query()
+	changed := false
+	for key := range query {
+		if sensitiveQueryKeyPattern.MatchString(key) {
+			query.Set(key, redacted)
+			changed = true
+		}
+	}
+	if changed {
+		u.RawQuery = query.Encode()
+	}
+
+	return u.String()
+}
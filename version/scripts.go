@@ -23,6 +23,8 @@ func Get() (*Version, error) {
 // Set runs the set_version script.
 func Set(ver *Version) error {
 	// Run the set_version script.
-	_, err := scripts.Run(scripts.ScriptNameSetVersion, ver.String())
+	_, err := scripts.RunWithEnv(scripts.ScriptNameSetVersion, map[string]string{
+		scripts.EnvVersion: ver.String(),
+	}, ver.String())
 	return err
 }